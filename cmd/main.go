@@ -2,25 +2,56 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/encomos/api-encomos/customer-service/internal/config"
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
 	"github.com/encomos/api-encomos/customer-service/internal/domain/service"
+	"github.com/encomos/api-encomos/customer-service/internal/identity"
+	"github.com/encomos/api-encomos/customer-service/internal/importer"
 	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/grpc"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/metrics"
 	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/persistence/postgres"
+	"github.com/encomos/api-encomos/customer-service/internal/requestid"
+	"github.com/encomos/api-encomos/customer-service/internal/tenancy"
 )
 
+// serviceVersion es la versión expuesta en /info y en las métricas
+const serviceVersion = "1.0.0"
+
 func main() {
 	// Configurar el logger
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	// Subcomando de migraciones: customer-service migrate up|down [n]|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatalf("Error en migraciones: %v", err)
+		}
+		return
+	}
+
+	// Importación masiva: customer-service import --tenant <id> [opciones] <archivo>
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:]); err != nil {
+			log.Fatalf("Error en la importación: %v", err)
+		}
+		return
+	}
+
 	log.Println("Iniciando Customer Service...")
+	startedAt := time.Now()
 
 	// Cargar configuración
 	cfg, err := loadConfig()
@@ -38,21 +69,53 @@ func main() {
 	defer db.Close()
 	log.Println("✓ Conexión a PostgreSQL establecida")
 
+	// Verificar que el esquema esté al día
+	if cfg.Database.CheckSchema {
+		migrator, err := postgres.NewMigrator(db)
+		if err != nil {
+			log.Fatalf("Error al cargar migraciones: %v", err)
+		}
+		if err := migrator.CheckSchema(context.Background()); err != nil {
+			log.Fatalf("Esquema de base de datos desactualizado (ejecute 'migrate up'): %v", err)
+		}
+		log.Println("✓ Esquema de base de datos al día")
+	}
+
 	// Crear repositorios
 	customerRepo := postgres.NewCustomerRepository(db)
 	vehicleRepo := postgres.NewVehicleRepository(db)
 	customerNoteRepo := postgres.NewCustomerNoteRepository(db)
+	customerStatsRepo := postgres.NewCustomerStatsRepository(db)
+	customerHistoryRepo := postgres.NewCustomerHistoryRepository(db)
+	customerEventRepo := postgres.NewCustomerEventRepository(db)
+	auditLogRepo := postgres.NewAuditLogRepository(db)
+	transactor := postgres.NewTransactor(db)
 
 	log.Println("✓ Repositorios inicializados")
 
 	// Crear servicios de dominio
-	customerService := service.NewCustomerService(customerRepo, vehicleRepo, customerNoteRepo)
-	vehicleService := service.NewVehicleService(vehicleRepo, customerRepo)
+	duplicatePolicy := model.DuplicatePolicy{
+		WarnThreshold:  cfg.Duplicates.WarnThreshold,
+		BlockThreshold: cfg.Duplicates.BlockThreshold,
+	}
+	customerService := service.NewCustomerService(customerRepo, vehicleRepo, customerNoteRepo, customerStatsRepo, customerHistoryRepo, customerEventRepo, auditLogRepo, transactor, duplicatePolicy, cfg.Trash.Retention)
+	vehicleService := service.NewVehicleService(vehicleRepo, customerRepo, auditLogRepo, transactor)
 
 	log.Println("✓ Servicios de dominio inicializados")
 
+	// Registrar métricas (Prometheus, formato texto en /metrics)
+	metricsRegistry := metrics.NewRegistry(logger.NewWithService("customer-service"))
+	grpcMetrics := metrics.NewGRPCMetrics(metricsRegistry)
+	metrics.RegisterProcessMetrics(metricsRegistry, serviceVersion, startedAt)
+	metrics.RegisterDBStats(metricsRegistry, db.Stats)
+	if db.HasSystemRole() {
+		metrics.RegisterTenantCounts(metricsRegistry, postgres.NewTenantMetricsRepository(db), metrics.TenantCountsTTL)
+	} else {
+		log.Println("⚠️  DB_SYSTEM_USER no configurado: sin métricas de clientes por tenant")
+	}
+
 	// Crear servidor gRPC
-	grpcServer, err := grpc.NewServer(&cfg.GRPC)
+	grpcServer, err := grpc.NewServer(cfg, grpcMetrics)
 	if err != nil {
 		log.Fatalf("Error al crear servidor gRPC: %v", err)
 	}
@@ -69,10 +132,26 @@ func main() {
 
 	log.Printf("✓ Servidor gRPC iniciado en puerto %d", cfg.GRPC.Port)
 
-	// Configurar servidor HTTP para health checks
-	httpServer := setupHTTPServer(cfg.HTTP.Port, db, grpcServer)
+	// Crear gateway REST/JSON sobre los mismos handlers gRPC
+	restGateway, err := grpcServer.NewGateway(cfg.HTTP.CORSAllowedOrigins, cfg.HTTP.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Error al crear gateway REST: %v", err)
+	}
+
+	// Configurar servidor HTTP para health checks y API REST
+	httpServer := setupHTTPServer(&cfg.HTTP, db, grpcServer, restGateway, metricsRegistry.Handler())
 
 	log.Printf("✓ Servidor HTTP iniciado en puerto %d", cfg.HTTP.Port)
+
+	// Purgar periódicamente los clientes que superan la retención de la papelera
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	if cfg.Trash.PurgeInterval > 0 && !db.HasSystemRole() {
+		log.Println("⚠️  DB_SYSTEM_USER no configurado: la purga de la papelera queda desactivada")
+	} else if cfg.Trash.PurgeInterval > 0 {
+		go runTrashPurge(purgeCtx, cfg.Trash.PurgeInterval, postgres.NewTrashRepository(db), customerService)
+		log.Printf("✓ Purga de la papelera cada %v (retención: %v)", cfg.Trash.PurgeInterval, cfg.Trash.Retention)
+	}
 	log.Println("🚀 Customer Service completamente inicializado")
 
 	// Capturar señales para shutdown graceful
@@ -84,6 +163,7 @@ func main() {
 	log.Printf("⚠️  Señal de terminación recibida: %v", sig)
 
 	// Shutdown graceful
+	stopPurge()
 	shutdownGracefully(httpServer, grpcServer, cfg.Server.ShutdownTime)
 }
 
@@ -122,10 +202,87 @@ func loadConfig() (*config.Config, error) {
 	return cfg, nil
 }
 
-// setupHTTPServer configura un servidor HTTP para health checks y métricas
-func setupHTTPServer(port int, db *postgres.DB, grpcServer *grpc.Server) *http.Server {
+// runMigrate ejecuta el subcomando migrate (up, down [n], status)
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: migrate up|down [n]|status")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := postgres.NewDB(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("error al conectar a PostgreSQL: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := postgres.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Printf("✓ Aplicada %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Println("El esquema ya está al día")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("número de pasos inválido: %s", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			log.Printf("✓ Revertida %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			log.Println("No hay migraciones aplicadas")
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			if st.Applied {
+				fmt.Printf("%04d_%s\taplicada %s\n", st.Version, st.Name, st.AppliedAt.UTC().Format(time.RFC3339))
+			} else {
+				fmt.Printf("%04d_%s\tpendiente\n", st.Version, st.Name)
+			}
+		}
+	default:
+		return fmt.Errorf("subcomando desconocido %q (uso: migrate up|down [n]|status)", args[0])
+	}
+
+	return nil
+}
+
+// setupHTTPServer configura un servidor HTTP para health checks, métricas y la API REST
+func setupHTTPServer(httpCfg *config.HTTPConfig, db *postgres.DB, grpcServer *grpc.Server, restGateway, metricsHandler http.Handler) *http.Server {
+	port := httpCfg.Port
 	mux := http.NewServeMux()
 
+	// API REST (/v1/...) traducida a CustomerService
+	mux.Handle("/v1/", restGateway)
+
 	// Ruta para health check general
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		healthStatus := make(map[string]interface{})
@@ -201,26 +358,16 @@ func setupHTTPServer(port int, db *postgres.DB, grpcServer *grpc.Server) *http.S
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(fmt.Sprintf(`{
 			"service": "customer-service",
-			"version": "1.0.0",
+			"version": "%s",
 			"grpc_port": %d,
 			"http_port": %d,
 			"status": "running",
 			"timestamp": "%s"
-		}`, grpcServer.GetPort(), port, time.Now().UTC().Format(time.RFC3339))))
+		}`, serviceVersion, grpcServer.GetPort(), port, time.Now().UTC().Format(time.RFC3339))))
 	})
 
-	// Ruta para métricas básicas
-	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{
-			"service": "customer-service",
-			"metrics": {
-				"uptime": "` + time.Since(time.Now()).String() + `",
-				"status": "healthy"
-			}
-		}`))
-	})
+	// Métricas en formato texto de Prometheus
+	mux.Handle("/metrics", metricsHandler)
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", port),
@@ -233,8 +380,14 @@ func setupHTTPServer(port int, db *postgres.DB, grpcServer *grpc.Server) *http.S
 
 	// Iniciar servidor HTTP en goroutine
 	go func() {
-		log.Printf("Servidor HTTP iniciado en puerto %d", port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("Servidor HTTP iniciado en puerto %d (TLS: %v)", port, httpCfg.TLSEnabled)
+		var err error
+		if httpCfg.TLSEnabled {
+			err = server.ListenAndServeTLS(httpCfg.TLSCertFile, httpCfg.TLSKeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Error al iniciar servidor HTTP: %v", err)
 		}
 	}()
@@ -295,3 +448,169 @@ func formatHealthStatus(status map[string]interface{}) string {
 	// Implementación simple para evitar dependencias adicionales
 	return `{"status":"partial"}`
 }
+
+// runImport ejecuta el subcomando import: importa clientes (y un vehículo por fila) desde un
+// archivo CSV o NDJSON, o desde la entrada estándar con "-", e imprime el resultado de cada fila
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	tenantID := flags.String("tenant", "", "ID del tenant (requerido)")
+	format := flags.String("format", "", "formato del archivo: csv o ndjson (por defecto según la extensión)")
+	mapping := flags.String("map", "", "mapeo de columnas columna:campo separado por comas, p. ej. Nombre:first_name,Correo:email")
+	upsertKey := flags.String("upsert-by", "", "actualiza el cliente existente con el mismo email o tax_id")
+	dryRun := flags.Bool("dry-run", false, "aplica cada lote y lo revierte")
+	batchSize := flags.Int("batch-size", model.DefaultImportBatchSize, "filas por transacción")
+	staffID := flags.String("staff-id", "", "actor registrado en la auditoría")
+	staffName := flags.String("staff-name", "", "nombre del actor registrado en la auditoría")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *tenantID == "" || flags.NArg() != 1 {
+		return fmt.Errorf("uso: import --tenant <id> [--format csv|ndjson] [--map columna:campo,...] [--upsert-by email|tax_id] [--dry-run] [--batch-size n] <archivo|->")
+	}
+
+	tenant, err := tenancy.New(*tenantID, "", "", "")
+	if err != nil {
+		return err
+	}
+
+	options := model.CustomerImportOptions{
+		Format:        *format,
+		ColumnMapping: map[string]string{},
+		UpsertKey:     *upsertKey,
+		DryRun:        *dryRun,
+		BatchSize:     *batchSize,
+	}
+	for _, pair := range strings.Split(*mapping, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		column, field, ok := strings.Cut(pair, ":")
+		if !ok {
+			return fmt.Errorf("mapeo inválido %q: se espera columna:campo", pair)
+		}
+		options.ColumnMapping[strings.TrimSpace(column)] = strings.TrimSpace(field)
+	}
+
+	path := flags.Arg(0)
+	if options.Format == "" {
+		options.Format = model.ImportFormatCSV
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".ndjson" || ext == ".jsonl" {
+			options.Format = model.ImportFormatNDJSON
+		}
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	input := os.Stdin
+	if path != "-" {
+		input, err = os.Open(path)
+		if err != nil {
+			return fmt.Errorf("error al abrir el archivo: %w", err)
+		}
+		defer input.Close()
+	}
+
+	rows, err := importer.NewReader(input, options.Format, options.ColumnMapping)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := postgres.NewDB(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("error al conectar a PostgreSQL: %w", err)
+	}
+	defer db.Close()
+
+	duplicatePolicy := model.DuplicatePolicy{
+		WarnThreshold:  cfg.Duplicates.WarnThreshold,
+		BlockThreshold: cfg.Duplicates.BlockThreshold,
+	}
+	customerService := service.NewCustomerService(
+		postgres.NewCustomerRepository(db),
+		postgres.NewVehicleRepository(db),
+		postgres.NewCustomerNoteRepository(db),
+		postgres.NewCustomerStatsRepository(db),
+		postgres.NewCustomerHistoryRepository(db),
+		postgres.NewCustomerEventRepository(db),
+		postgres.NewAuditLogRepository(db),
+		postgres.NewTransactor(db),
+		duplicatePolicy,
+		cfg.Trash.Retention,
+	)
+
+	ctx := tenancy.WithTenant(context.Background(), tenant)
+	if *staffID != "" {
+		ctx = identity.WithStaff(ctx, &identity.Staff{ID: *staffID, Name: *staffName, TenantID: tenant.ID})
+	}
+	ctx = requestid.WithRequestID(ctx, requestid.New())
+
+	summary, err := customerService.ImportCustomers(ctx, options, rows, func(results []*model.CustomerImportResult) error {
+		for _, result := range results {
+			fmt.Printf("%d\t%s\t%s\t%s\t%s\n", result.Line, result.Status, result.CustomerID, result.Field, result.Message)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	mode := "importadas"
+	if options.DryRun {
+		mode = "validadas (dry-run, sin cambios)"
+	}
+	log.Printf("✓ %d filas %s: %d creadas, %d actualizadas, %d omitidas, %d con error",
+		summary.Rows, mode, summary.Created, summary.Updated, summary.Skipped, summary.Errors)
+	return nil
+}
+
+// runTrashPurge purga la papelera de todos los tenants al arrancar y luego cada interval,
+// hasta que ctx se cancela
+func runTrashPurge(ctx context.Context, interval time.Duration, trash *postgres.TrashRepository, customerService *service.CustomerService) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeTrash(ctx, trash, customerService)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeTrash borra definitivamente los clientes que superaron la retención de la papelera;
+// el error de un tenant no detiene la purga del resto
+func purgeTrash(ctx context.Context, trash *postgres.TrashRepository, customerService *service.CustomerService) {
+	cutoff := customerService.PurgeCutoff(time.Now())
+
+	tenantIDs, err := trash.TenantsWithDeletedBefore(ctx, cutoff)
+	if err != nil {
+		log.Printf("❌ Error al buscar clientes a purgar: %v", err)
+		return
+	}
+
+	for _, tenantID := range tenantIDs {
+		tenant, err := tenancy.New(tenantID, "", "", "")
+		if err != nil {
+			log.Printf("❌ Tenant inválido en la papelera %q: %v", tenantID, err)
+			continue
+		}
+
+		tenantCtx := requestid.WithRequestID(tenancy.WithTenant(ctx, tenant), requestid.New())
+		purged, err := customerService.PurgeDeletedCustomers(tenantCtx, cutoff)
+		if purged > 0 {
+			log.Printf("✓ %d clientes purgados de la papelera del tenant %s", purged, tenantID)
+		}
+		if err != nil {
+			log.Printf("❌ Error al purgar la papelera del tenant %s: %v", tenantID, err)
+		}
+	}
+}
@@ -9,11 +9,16 @@ import (
 
 // Config representa la configuración del servicio
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	GRPC     GRPCConfig
-	HTTP     HTTPConfig
-	Log      LogConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	GRPC       GRPCConfig
+	HTTP       HTTPConfig
+	Auth       AuthConfig
+	Authz      AuthzConfig
+	Duplicates DuplicatesConfig
+	Pagination PaginationConfig
+	Trash      TrashConfig
+	Log        LogConfig
 }
 
 // ServerConfig representa la configuración del servidor
@@ -33,6 +38,12 @@ type DatabaseConfig struct {
 	MaxOpenConns int
 	MaxIdleConns int
 	MaxLifetime  time.Duration
+	CheckSchema  bool // Negarse a arrancar si hay migraciones pendientes
+
+	// Rol con BYPASSRLS para los procesos de sistema (métricas por tenant, purga de la
+	// papelera); vacío = esos procesos quedan desactivados
+	SystemUser     string
+	SystemPassword string
 }
 
 // PostgresURL devuelve la URL de conexión a PostgreSQL
@@ -55,11 +66,45 @@ type GRPCConfig struct {
 type HTTPConfig struct {
 	Port               int
 	CORSAllowedOrigins []string
+	TrustedProxies     []string // IPs o CIDRs que pueden enviar las cabeceras X-Staff-*
 	TLSEnabled         bool
 	TLSCertFile        string
 	TLSKeyFile         string
 }
 
+// AuthConfig representa la configuración de autenticación JWT
+type AuthConfig struct {
+	Enabled   bool
+	JWTSecret string // Clave HS256
+	JWKSFile  string // Archivo JWKS con claves públicas RS256
+	Issuer    string
+	Audience  string
+	Leeway    time.Duration
+}
+
+// AuthzConfig representa la configuración de autorización por roles
+type AuthzConfig struct {
+	Enabled    bool
+	PolicyFile string // Archivo JSON con los roles permitidos por método
+}
+
+// DuplicatesConfig representa los umbrales de la detección de clientes duplicados (puntuación 0-1)
+type DuplicatesConfig struct {
+	WarnThreshold  float64 // Desde esta puntuación CreateCustomer avisa del posible duplicado
+	BlockThreshold float64 // Desde esta puntuación CreateCustomer rechaza el alta (0 = nunca)
+}
+
+// TrashConfig representa la retención de la papelera de clientes y su purga
+type TrashConfig struct {
+	Retention     time.Duration // Tiempo en la papelera antes de borrar definitivamente
+	PurgeInterval time.Duration // Cada cuánto se ejecuta la purga (0 = desactivada)
+}
+
+// PaginationConfig representa la configuración de los tokens de página
+type PaginationConfig struct {
+	TokenSecret string // Clave HMAC de los page_token; vacía = aleatoria por proceso (no permitido en producción)
+}
+
 // LogConfig representa la configuración de logging
 type LogConfig struct {
 	Level string
@@ -123,6 +168,9 @@ func bindEnvVars(v *viper.Viper) {
 	v.BindEnv("database.maxopenconns", "DB_MAX_OPEN_CONNS")
 	v.BindEnv("database.maxidleconns", "DB_MAX_IDLE_CONNS")
 	v.BindEnv("database.maxlifetime", "DB_MAX_LIFETIME_SECONDS")
+	v.BindEnv("database.checkschema", "DB_CHECK_SCHEMA")
+	v.BindEnv("database.systemuser", "DB_SYSTEM_USER")
+	v.BindEnv("database.systempassword", "DB_SYSTEM_PASSWORD")
 
 	// GRPC
 	v.BindEnv("grpc.port", "GRPC_PORT")
@@ -133,10 +181,34 @@ func bindEnvVars(v *viper.Viper) {
 	// HTTP
 	v.BindEnv("http.port", "HTTP_PORT")
 	v.BindEnv("http.corsallowedorigins", "HTTP_CORS_ALLOWED_ORIGINS")
+	v.BindEnv("http.trustedproxies", "HTTP_TRUSTED_PROXIES")
 	v.BindEnv("http.tlsenabled", "HTTP_TLS_ENABLED")
 	v.BindEnv("http.tlscertfile", "HTTP_TLS_CERT_FILE")
 	v.BindEnv("http.tlskeyfile", "HTTP_TLS_KEY_FILE")
 
+	// Auth
+	v.BindEnv("auth.enabled", "AUTH_ENABLED")
+	v.BindEnv("auth.jwtsecret", "AUTH_JWT_SECRET")
+	v.BindEnv("auth.jwksfile", "AUTH_JWKS_FILE")
+	v.BindEnv("auth.issuer", "AUTH_ISSUER")
+	v.BindEnv("auth.audience", "AUTH_AUDIENCE")
+	v.BindEnv("auth.leeway", "AUTH_LEEWAY")
+
+	// Authz
+	v.BindEnv("authz.enabled", "AUTHZ_ENABLED")
+	v.BindEnv("authz.policyfile", "AUTHZ_POLICY_FILE")
+
+	// Duplicates
+	v.BindEnv("duplicates.warnthreshold", "DUPLICATES_WARN_THRESHOLD")
+	v.BindEnv("duplicates.blockthreshold", "DUPLICATES_BLOCK_THRESHOLD")
+
+	// Pagination
+	v.BindEnv("pagination.tokensecret", "PAGINATION_TOKEN_SECRET")
+
+	// Trash
+	v.BindEnv("trash.retention", "TRASH_RETENTION")
+	v.BindEnv("trash.purgeinterval", "TRASH_PURGE_INTERVAL")
+
 	// Log
 	v.BindEnv("log.level", "LOG_LEVEL")
 	v.BindEnv("log.json", "LOG_JSON")
@@ -159,6 +231,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.maxopenconns", 25)
 	v.SetDefault("database.maxidleconns", 5)
 	v.SetDefault("database.maxlifetime", 30*time.Minute)
+	v.SetDefault("database.checkschema", false)
 
 	// GRPC defaults
 	v.SetDefault("grpc.port", 50055) // Puerto específico para customer-service
@@ -173,6 +246,29 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("http.tlscertfile", "")
 	v.SetDefault("http.tlskeyfile", "")
 
+	// Auth defaults
+	v.SetDefault("auth.enabled", true)
+	v.SetDefault("auth.jwtsecret", "")
+	v.SetDefault("auth.jwksfile", "")
+	v.SetDefault("auth.issuer", "")
+	v.SetDefault("auth.audience", "")
+	v.SetDefault("auth.leeway", 30*time.Second)
+
+	// Authz defaults
+	v.SetDefault("authz.enabled", true)
+	v.SetDefault("authz.policyfile", "")
+
+	// Duplicates defaults
+	v.SetDefault("duplicates.warnthreshold", 0.6)
+	v.SetDefault("duplicates.blockthreshold", 0.95)
+
+	// Pagination defaults
+	v.SetDefault("pagination.tokensecret", "")
+
+	// Trash defaults
+	v.SetDefault("trash.retention", 30*24*time.Hour)
+	v.SetDefault("trash.purgeinterval", time.Hour)
+
 	// Log defaults
 	v.SetDefault("log.level", "info")
 	v.SetDefault("log.json", false)
@@ -199,6 +295,38 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("nombre de la base de datos es requerido")
 	}
 
+	// Validar autenticación
+	if config.Auth.Enabled && config.Auth.JWTSecret == "" && config.Auth.JWKSFile == "" {
+		return fmt.Errorf("AUTH_JWT_SECRET o AUTH_JWKS_FILE es requerido cuando la autenticación está habilitada")
+	}
+
+	// Validar autorización
+	if config.Authz.Enabled && config.Authz.PolicyFile == "" {
+		return fmt.Errorf("AUTHZ_POLICY_FILE es requerido cuando la autorización está habilitada")
+	}
+
+	// Validar umbrales de duplicados
+	if config.Duplicates.WarnThreshold <= 0 || config.Duplicates.WarnThreshold > 1 {
+		return fmt.Errorf("DUPLICATES_WARN_THRESHOLD debe estar entre 0 y 1: %v", config.Duplicates.WarnThreshold)
+	}
+	if config.Duplicates.BlockThreshold != 0 &&
+		(config.Duplicates.BlockThreshold < config.Duplicates.WarnThreshold || config.Duplicates.BlockThreshold > 1) {
+		return fmt.Errorf("DUPLICATES_BLOCK_THRESHOLD debe ser 0 o estar entre DUPLICATES_WARN_THRESHOLD y 1: %v", config.Duplicates.BlockThreshold)
+	}
+
+	// En producción hay varias réplicas: una clave aleatoria por proceso invalidaría los page_token entre ellas
+	if config.IsProduction() && config.Pagination.TokenSecret == "" {
+		return fmt.Errorf("PAGINATION_TOKEN_SECRET es requerido en producción")
+	}
+
+	// Validar la papelera
+	if config.Trash.Retention <= 0 {
+		return fmt.Errorf("TRASH_RETENTION debe ser positivo: %v", config.Trash.Retention)
+	}
+	if config.Trash.PurgeInterval < 0 {
+		return fmt.Errorf("TRASH_PURGE_INTERVAL no puede ser negativo: %v", config.Trash.PurgeInterval)
+	}
+
 	return nil
 }
 
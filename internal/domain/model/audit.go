@@ -0,0 +1,202 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// Tipos de recurso auditados
+const (
+	AuditResourceCustomer = "customer"
+	AuditResourceVehicle  = "vehicle"
+)
+
+// Acciones registradas en el log de auditoría
+const (
+	AuditActionCustomerCreated        = "customer.created"
+	AuditActionCustomerUpdated        = "customer.updated"
+	AuditActionCustomerActivated      = "customer.activated"
+	AuditActionCustomerDeactivated    = "customer.deactivated"
+	AuditActionCustomerDeleted        = "customer.deleted"
+	AuditActionCustomerPreferenceSet  = "customer.preference_set"
+	AuditActionCustomerNoteAdded      = "customer.note_added"
+	AuditActionCustomerEventsIngested = "customer.events_ingested"
+	AuditActionCustomerMerged         = "customer.merged"
+	AuditActionCustomerRestored       = "customer.restored"
+	AuditActionCustomerPurged         = "customer.purged"
+	AuditActionCustomerAnonymized     = "customer.anonymized"
+	AuditActionCustomerDataExported   = "customer.data_exported"
+	AuditActionVehicleCreated         = "vehicle.created"
+	AuditActionVehicleUpdated         = "vehicle.updated"
+	AuditActionVehicleActivated       = "vehicle.activated"
+	AuditActionVehicleDeactivated     = "vehicle.deactivated"
+	AuditActionVehicleDeleted         = "vehicle.deleted"
+)
+
+// AuditEntry representa un cambio sobre un cliente o uno de sus vehículos
+type AuditEntry struct {
+	ID           string                 `db:"id" json:"id"`
+	TenantID     string                 `db:"tenant_id" json:"tenant_id"`
+	CustomerID   string                 `db:"customer_id" json:"customer_id"` // Cliente afectado (también en cambios de vehículos)
+	ResourceType string                 `db:"resource_type" json:"resource_type"`
+	ResourceID   string                 `db:"resource_id" json:"resource_id"`
+	Action       string                 `db:"action" json:"action"`
+	ActorID      string                 `db:"actor_id" json:"actor_id"` // Vacío si la petición no identificó al staff
+	ActorName    string                 `db:"actor_name" json:"actor_name"`
+	RequestID    string                 `db:"request_id" json:"request_id"`
+	Changes      map[string]FieldChange `db:"changes" json:"changes"`
+	CreatedAt    time.Time              `db:"created_at" json:"created_at"`
+}
+
+// FieldChange es el valor de un campo antes y después del cambio (nil si no existía)
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditFilter representa los filtros del log de auditoría
+type AuditFilter struct {
+	CustomerID string
+	ActorID    string
+	Action     string
+	DateFrom   *time.Time
+	DateTo     *time.Time
+	Page       int
+	Limit      int
+}
+
+// AuditSnapshot es el estado auditable de un recurso, con valores normalizados a tipos JSON
+type AuditSnapshot map[string]interface{}
+
+// NewAuditEntry crea una entrada de auditoría; el actor y el ID de petición los completa el servicio
+func NewAuditEntry(action, resourceType, resourceID, customerID string, changes map[string]FieldChange) *AuditEntry {
+	if changes == nil {
+		changes = map[string]FieldChange{}
+	}
+	return &AuditEntry{
+		CustomerID:   customerID,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Action:       action,
+		Changes:      changes,
+	}
+}
+
+// AuditSnapshot returns the audited fields of the customer
+func (c *Customer) AuditSnapshot() AuditSnapshot {
+	var birthday *string
+	if c.Birthday != nil {
+		formatted := c.Birthday.Format("2006-01-02")
+		birthday = &formatted
+	}
+
+	return newAuditSnapshot(map[string]interface{}{
+		"first_name":    c.FirstName,
+		"last_name":     c.LastName,
+		"email":         c.Email,
+		"phone":         c.Phone,
+		"customer_type": c.CustomerType,
+		"company_name":  c.CompanyName,
+		"tax_id":        c.TaxID,
+		"address":       c.Address,
+		"birthday":      birthday,
+		"notes":         c.Notes,
+		"preferences":   c.Preferences,
+		"is_active":     c.IsActive,
+	})
+}
+
+// AuditSnapshot returns the audited fields of the vehicle
+func (v *Vehicle) AuditSnapshot() AuditSnapshot {
+	return newAuditSnapshot(map[string]interface{}{
+		"customer_id":   v.CustomerID,
+		"make":          v.Make,
+		"model":         v.Model,
+		"year":          v.Year,
+		"vin":           v.VIN,
+		"license_plate": v.LicensePlate,
+		"color":         v.Color,
+		"engine":        v.Engine,
+		"notes":         v.Notes,
+		"is_active":     v.IsActive,
+		"metadata":      v.Metadata,
+	})
+}
+
+// newAuditSnapshot copies fields through JSON, detaching pointers and maps from the resource
+// (which is still modified afterwards) and normalizing values for DiffSnapshots
+func newAuditSnapshot(fields map[string]interface{}) AuditSnapshot {
+	snapshot := AuditSnapshot{}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return snapshot
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return AuditSnapshot{}
+	}
+	return snapshot
+}
+
+// DiffSnapshots returns the fields whose value differs between before and after.
+// A nil before describes a creation and a nil after a deletion.
+func DiffSnapshots(before, after AuditSnapshot) map[string]FieldChange {
+	changes := map[string]FieldChange{}
+	for field, beforeValue := range before {
+		afterValue := after[field]
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			changes[field] = FieldChange{Before: beforeValue, After: afterValue}
+		}
+	}
+	for field, afterValue := range after {
+		if _, seen := before[field]; !seen && afterValue != nil {
+			changes[field] = FieldChange{After: afterValue}
+		}
+	}
+	return changes
+}
+
+// Validate valida el filtro del log de auditoría
+func (f *AuditFilter) Validate() error {
+	if f.Action != "" && !isValidAuditAction(f.Action) {
+		return &ValidationError{Field: "action", Message: "acción de auditoría inválida"}
+	}
+	if f.DateFrom != nil && f.DateTo != nil && f.DateFrom.After(*f.DateTo) {
+		return &ValidationError{Field: "date_from", Message: "la fecha inicial no puede ser posterior a la final"}
+	}
+	return nil
+}
+
+// GetValidAuditActions retorna las acciones de auditoría válidas
+func GetValidAuditActions() []string {
+	return []string{
+		AuditActionCustomerCreated,
+		AuditActionCustomerUpdated,
+		AuditActionCustomerActivated,
+		AuditActionCustomerDeactivated,
+		AuditActionCustomerDeleted,
+		AuditActionCustomerPreferenceSet,
+		AuditActionCustomerNoteAdded,
+		AuditActionCustomerEventsIngested,
+		AuditActionCustomerMerged,
+		AuditActionCustomerRestored,
+		AuditActionCustomerPurged,
+		AuditActionCustomerAnonymized,
+		AuditActionCustomerDataExported,
+		AuditActionVehicleCreated,
+		AuditActionVehicleUpdated,
+		AuditActionVehicleActivated,
+		AuditActionVehicleDeactivated,
+		AuditActionVehicleDeleted,
+	}
+}
+
+// isValidAuditAction verifica si la acción de auditoría es válida
+func isValidAuditAction(action string) bool {
+	for _, validAction := range GetValidAuditActions() {
+		if action == validAction {
+			return true
+		}
+	}
+	return false
+}
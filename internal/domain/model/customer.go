@@ -32,6 +32,9 @@ type Customer struct {
 	IsActive     bool                `db:"is_active" json:"is_active"`
 	CreatedAt    time.Time           `db:"created_at" json:"created_at"`
 	UpdatedAt    time.Time           `db:"updated_at" json:"updated_at"`
+	Version      int64               `db:"version" json:"version"`                       // Se incrementa en cada actualización
+	DeletedAt    *time.Time          `db:"deleted_at" json:"deleted_at,omitempty"`       // En la papelera desde; nil si no está eliminado
+	AnonymizedAt *time.Time          `db:"anonymized_at" json:"anonymized_at,omitempty"` // Anonimizado en (derecho de supresión); nil si conserva sus datos
 
 	// Campos no persistidos (relaciones)
 	Vehicles      []*Vehicle      `db:"-" json:"vehicles,omitempty"`
@@ -79,9 +82,11 @@ type CustomerCreate struct {
 	Birthday     *time.Time
 	Notes        *string
 	Preferences  CustomerPreferences
+	Vehicles     []VehicleCreate // Vehículos creados junto con el cliente (CustomerID se asigna al crear)
 }
 
-// CustomerUpdate representa los datos para actualizar un cliente
+// CustomerUpdate representa los datos para actualizar un cliente. Los campos nil no se
+// modifican; un campo opcional con cadena vacía se pone en NULL.
 type CustomerUpdate struct {
 	ID           string
 	FirstName    *string
@@ -96,6 +101,9 @@ type CustomerUpdate struct {
 	Notes        *string
 	Preferences  CustomerPreferences
 	IsActive     *bool
+
+	ClearBirthday   bool  // Poner el cumpleaños en NULL
+	ExpectedVersion int64 // Versión leída por el cliente; 0 omite la verificación
 }
 
 // CustomerFilter representa los filtros para búsqueda de clientes
@@ -105,14 +113,66 @@ type CustomerFilter struct {
 	ActiveOnly   bool
 	Page         int
 	Limit        int
-	SortBy       string // name, created_at, last_visit, total_spent
+	SortBy       string // name, created_at, company_name, last_visit, total_spent, relevance
 	SortOrder    string // asc, desc
+
+	// Filtros sobre las estadísticas; excluyen a los clientes sin estadísticas
+	MinTotalSpent *float64
+	Level         string // Bronze, Silver, Gold, Premium, VIP
+	LastVisitFrom *time.Time
+	LastVisitTo   *time.Time
+
+	// Cursor continúa tras la página anterior (sustituye a Page); TotalMode: exact, estimate, none
+	Cursor    *PageCursor
+	TotalMode string
+
+	// Deleted lista la papelera en lugar de los clientes no eliminados
+	Deleted bool
+}
+
+// Validate valida y normaliza el filtro de clientes
+func (f *CustomerFilter) Validate() error {
+	var errs ValidationErrors
+	validatePagination(&errs, f.Page, f.Limit, f.TotalMode)
+	if f.SortBy != "" && !isValidCustomerSort(f.SortBy) {
+		errs.Add("sort_by", "orden inválido (name, created_at, company_name, last_visit, total_spent, relevance)")
+	}
+	if f.MinTotalSpent != nil && *f.MinTotalSpent < 0 {
+		errs.Add("min_total_spent", "no puede ser negativo")
+	}
+	if f.Level != "" {
+		level, ok := normalizeCustomerLevel(f.Level)
+		if !ok {
+			errs.Add("level", "nivel de cliente inválido")
+		}
+		f.Level = level
+	}
+	if f.LastVisitFrom != nil && f.LastVisitTo != nil && f.LastVisitFrom.After(*f.LastVisitTo) {
+		errs.Add("last_visit_from", "la fecha inicial no puede ser posterior a la final")
+	}
+	return errs.Err()
+}
+
+// GetValidCustomerSorts retorna los órdenes válidos de un listado de clientes
+func GetValidCustomerSorts() []string {
+	return []string{"name", "created_at", "company_name", "last_visit", "total_spent", "relevance"}
+}
+
+// isValidCustomerSort verifica si el orden de clientes es válido
+func isValidCustomerSort(sortBy string) bool {
+	for _, validSort := range GetValidCustomerSorts() {
+		if sortBy == validSort {
+			return true
+		}
+	}
+	return false
 }
 
 // CustomerSearchFilter representa los filtros para búsqueda avanzada
 type CustomerSearchFilter struct {
 	Query        string
-	SearchFields []string // name, email, phone, tax_id
+	SearchFields []string // name, company_name, email, phone, tax_id; vacío = todos
+	Page         int
 	Limit        int
 }
 
@@ -192,28 +252,31 @@ func (c *Customer) UpdateFromUpdate(update CustomerUpdate) {
 		c.LastName = *update.LastName
 	}
 	if update.Email != nil {
-		c.Email = update.Email
+		c.Email = nilIfEmpty(update.Email)
 	}
 	if update.Phone != nil {
-		c.Phone = update.Phone
+		c.Phone = nilIfEmpty(update.Phone)
 	}
 	if update.CustomerType != nil {
 		c.CustomerType = *update.CustomerType
 	}
 	if update.CompanyName != nil {
-		c.CompanyName = update.CompanyName
+		c.CompanyName = nilIfEmpty(update.CompanyName)
 	}
 	if update.TaxID != nil {
-		c.TaxID = update.TaxID
+		c.TaxID = nilIfEmpty(update.TaxID)
 	}
 	if update.Address != nil {
-		c.Address = update.Address
+		c.Address = nilIfEmpty(update.Address)
 	}
 	if update.Birthday != nil {
 		c.Birthday = update.Birthday
 	}
+	if update.ClearBirthday {
+		c.Birthday = nil
+	}
 	if update.Notes != nil {
-		c.Notes = update.Notes
+		c.Notes = nilIfEmpty(update.Notes)
 	}
 	if update.Preferences != nil {
 		c.Preferences = update.Preferences
@@ -287,27 +350,28 @@ func (c *Customer) Age() *int {
 	return &age
 }
 
-// Validate valida los datos del cliente
+// Validate valida los datos del cliente y devuelve todos los campos inválidos como ValidationErrors
 func (c *Customer) Validate() error {
+	var errs ValidationErrors
 	if c.FirstName == "" {
-		return &ValidationError{Field: "first_name", Message: "el nombre es requerido"}
+		errs.Add("first_name", "el nombre es requerido")
 	}
 	if c.LastName == "" {
-		return &ValidationError{Field: "last_name", Message: "el apellido es requerido"}
+		errs.Add("last_name", "el apellido es requerido")
 	}
 	if c.CustomerType != CustomerTypeIndividual && c.CustomerType != CustomerTypeBusiness {
-		return &ValidationError{Field: "customer_type", Message: "tipo de cliente inválido"}
+		errs.Add("customer_type", "tipo de cliente inválido")
 	}
 	if c.CustomerType == CustomerTypeBusiness && (c.CompanyName == nil || *c.CompanyName == "") {
-		return &ValidationError{Field: "company_name", Message: "el nombre de la empresa es requerido para clientes empresariales"}
+		errs.Add("company_name", "el nombre de la empresa es requerido para clientes empresariales")
 	}
 	if c.Email != nil && *c.Email != "" {
 		// Validación básica de email
 		if !isValidEmail(*c.Email) {
-			return &ValidationError{Field: "email", Message: "formato de email inválido"}
+			errs.Add("email", "formato de email inválido")
 		}
 	}
-	return nil
+	return errs.Err()
 }
 
 // isValidEmail realiza una validación básica de email
@@ -319,6 +383,14 @@ func isValidEmail(email string) bool {
 		containsChar(email, '.')
 }
 
+// nilIfEmpty convierte una cadena vacía en nil para poner el campo en NULL
+func nilIfEmpty(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
+
 // containsChar verifica si un string contiene un carácter específico
 func containsChar(s string, char rune) bool {
 	for _, c := range s {
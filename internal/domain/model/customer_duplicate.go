@@ -0,0 +1,325 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Motivos por los que un cliente se considera un posible duplicado
+const (
+	DuplicateReasonTaxID          = "tax_id"
+	DuplicateReasonEmail          = "email"
+	DuplicateReasonEmailLocalPart = "email_local_part"
+	DuplicateReasonPhone          = "phone"
+	DuplicateReasonName           = "name"
+	DuplicateReasonCompanyName    = "company_name"
+)
+
+// Peso de cada motivo en la puntuación. Los motivos se combinan como probabilidades
+// independientes: score = 1 - Π(1 - peso × similitud).
+var duplicateReasonWeights = map[string]float64{
+	DuplicateReasonTaxID:          1.0,
+	DuplicateReasonEmail:          0.95,
+	DuplicateReasonPhone:          0.85,
+	DuplicateReasonName:           0.7,
+	DuplicateReasonCompanyName:    0.7,
+	DuplicateReasonEmailLocalPart: 0.5,
+}
+
+// Límites de la detección de duplicados
+const (
+	DefaultDuplicateWarnThreshold  = 0.6
+	DefaultDuplicateBlockThreshold = 0.95
+	DefaultDuplicateLimit          = 10
+	MaxDuplicateLimit              = 50
+
+	// MinNameSimilarity es la similitud por trigramas desde la que dos nombres coinciden
+	MinNameSimilarity = 0.5
+	// minEmailLocalPartLength evita coincidencias por partes locales genéricas ("info", "ventas"...)
+	minEmailLocalPartLength = 5
+	// phoneKeyDigits y minPhoneDigits coinciden con app_phone_key (migración 0007)
+	phoneKeyDigits = 9
+	minPhoneDigits = 7
+)
+
+// DuplicatePolicy decide qué hacer con los posibles duplicados al crear un cliente
+type DuplicatePolicy struct {
+	WarnThreshold  float64 // Puntuación mínima para informar un candidato
+	BlockThreshold float64 // Puntuación desde la que se rechaza el alta (0 = nunca se bloquea)
+}
+
+// DefaultDuplicatePolicy returns the policy used when none is configured
+func DefaultDuplicatePolicy() DuplicatePolicy {
+	return DuplicatePolicy{WarnThreshold: DefaultDuplicateWarnThreshold, BlockThreshold: DefaultDuplicateBlockThreshold}
+}
+
+// Blocks reports whether a candidate with this score prevents creating the customer
+func (p DuplicatePolicy) Blocks(score float64) bool {
+	return p.BlockThreshold > 0 && score >= p.BlockThreshold
+}
+
+// DuplicateProbe son los datos que se comparan con los clientes existentes
+type DuplicateProbe struct {
+	ExcludeID   string // Cliente que no debe aparecer entre los candidatos (el propio cliente)
+	FirstName   string
+	LastName    string
+	CompanyName string
+	Email       string
+	Phone       string
+	TaxID       string
+}
+
+// DuplicateQuery representa una búsqueda de posibles duplicados: de un cliente existente
+// (CustomerID) o de datos sueltos, p. ej. antes de dar de alta un cliente
+type DuplicateQuery struct {
+	CustomerID string
+	Probe      DuplicateProbe
+	MinScore   float64 // 0 = umbral de aviso configurado
+	Limit      int
+}
+
+// DuplicateMatchReason explica por qué un candidato coincide
+type DuplicateMatchReason struct {
+	Reason      string  // DuplicateReason*
+	Similarity  float64 // 1 = coincidencia exacta de los valores normalizados
+	Description string
+}
+
+// DuplicateCandidate es un cliente existente que puede ser un duplicado
+type DuplicateCandidate struct {
+	Customer *Customer
+	Score    float64 // Entre 0 y 1
+	Reasons  []DuplicateMatchReason
+}
+
+// DuplicateCustomerError is returned when a new customer is too similar to existing ones
+type DuplicateCustomerError struct {
+	Candidates []*DuplicateCandidate // Ordenados por puntuación, el primero supera el umbral
+	Threshold  float64
+}
+
+func (e *DuplicateCustomerError) Error() string {
+	top := e.Candidates[0]
+	return fmt.Sprintf("customer is a likely duplicate of %s (score %.2f, threshold %.2f)", top.Customer.ID, top.Score, e.Threshold)
+}
+
+// Is makes errors.Is(err, ErrConflict) match any DuplicateCustomerError
+func (e *DuplicateCustomerError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// NewDuplicateProbe returns the probe of a customer, excluding the customer itself
+func NewDuplicateProbe(c *Customer) DuplicateProbe {
+	return DuplicateProbe{
+		ExcludeID:   c.ID,
+		FirstName:   c.FirstName,
+		LastName:    c.LastName,
+		CompanyName: stringValue(c.CompanyName),
+		Email:       stringValue(c.Email),
+		Phone:       stringValue(c.Phone),
+		TaxID:       stringValue(c.TaxID),
+	}
+}
+
+// IsEmpty reports whether the probe has no data to compare
+func (p DuplicateProbe) IsEmpty() bool {
+	return p.FullName() == "" && NormalizeName(p.CompanyName) == "" && EmailLocalPart(p.Email) == "" &&
+		PhoneKey(p.Phone) == "" && NormalizeTaxID(p.TaxID) == ""
+}
+
+// FullName returns the normalized full name of the probe
+func (p DuplicateProbe) FullName() string {
+	return NormalizeName(p.FirstName + " " + p.LastName)
+}
+
+// Validate valida la búsqueda de duplicados
+func (q *DuplicateQuery) Validate() error {
+	var errs ValidationErrors
+
+	if q.CustomerID == "" && q.Probe.IsEmpty() {
+		errs.Add("customer_id", "indique un cliente o al menos un nombre, email, teléfono o identificador fiscal")
+	}
+	if q.MinScore < 0 || q.MinScore > 1 {
+		errs.Add("min_score", "debe estar entre 0 y 1")
+	}
+	if q.Limit < 0 || q.Limit > MaxDuplicateLimit {
+		errs.Add("limit", fmt.Sprintf("no puede ser negativo ni superar %d", MaxDuplicateLimit))
+	}
+
+	return errs.Err()
+}
+
+// ScoreDuplicate compares the probe with an existing customer
+func ScoreDuplicate(probe DuplicateProbe, candidate *Customer) *DuplicateCandidate {
+	result := &DuplicateCandidate{Customer: candidate}
+
+	if taxID := NormalizeTaxID(probe.TaxID); taxID != "" && taxID == NormalizeTaxID(stringValue(candidate.TaxID)) {
+		result.addReason(DuplicateReasonTaxID, 1, "mismo identificador fiscal")
+	}
+
+	if local := EmailLocalPart(probe.Email); local != "" && local == EmailLocalPart(stringValue(candidate.Email)) {
+		if emailDomain(probe.Email) == emailDomain(stringValue(candidate.Email)) {
+			result.addReason(DuplicateReasonEmail, 1, "mismo email")
+		} else if len(local) >= minEmailLocalPartLength {
+			result.addReason(DuplicateReasonEmailLocalPart, 1, "mismo usuario de email en otro dominio")
+		}
+	}
+
+	if phone := PhoneKey(probe.Phone); phone != "" && phone == PhoneKey(stringValue(candidate.Phone)) {
+		result.addReason(DuplicateReasonPhone, 1, "mismo teléfono")
+	}
+
+	if similarity := TrigramSimilarity(probe.FullName(), NormalizeName(candidate.FullName())); similarity >= MinNameSimilarity {
+		description := "mismo nombre"
+		if similarity < 1 {
+			description = "nombre similar"
+		}
+		result.addReason(DuplicateReasonName, similarity, description)
+	}
+
+	if similarity := TrigramSimilarity(NormalizeName(probe.CompanyName), NormalizeName(stringValue(candidate.CompanyName))); similarity >= MinNameSimilarity {
+		description := "misma empresa"
+		if similarity < 1 {
+			description = "empresa similar"
+		}
+		result.addReason(DuplicateReasonCompanyName, similarity, description)
+	}
+
+	return result
+}
+
+// addReason records a matching reason and updates the score
+func (c *DuplicateCandidate) addReason(reason string, similarity float64, description string) {
+	c.Reasons = append(c.Reasons, DuplicateMatchReason{Reason: reason, Similarity: similarity, Description: description})
+
+	miss := 1.0
+	for _, r := range c.Reasons {
+		miss *= 1 - duplicateReasonWeights[r.Reason]*r.Similarity
+	}
+	c.Score = 1 - miss
+}
+
+// RankDuplicates scores the candidates and returns those reaching minScore, best first,
+// at most limit of them
+func RankDuplicates(probe DuplicateProbe, candidates []*Customer, minScore float64, limit int) []*DuplicateCandidate {
+	ranked := make([]*DuplicateCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.ID == probe.ExcludeID && probe.ExcludeID != "" {
+			continue
+		}
+		if scored := ScoreDuplicate(probe, candidate); len(scored.Reasons) > 0 && scored.Score >= minScore {
+			ranked = append(ranked, scored)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// accentFolder removes diacritics (á → a, ñ → n, ü → u)
+var accentFolder = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// NormalizeName lowercases s, removes accents and reduces everything that is not a letter
+// or a digit to single spaces, like app_normalize_name
+func NormalizeName(s string) string {
+	folded, _, err := transform.String(accentFolder, s)
+	if err != nil {
+		folded = s
+	}
+	return strings.Join(strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// EmailLocalPart returns the lowercase local part of an email without its "+tag", like
+// app_email_local_part
+func EmailLocalPart(email string) string {
+	local, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	local, _, _ = strings.Cut(local, "+")
+	return local
+}
+
+// emailDomain returns the lowercase domain of an email
+func emailDomain(email string) string {
+	_, domain, _ := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	return domain
+}
+
+// PhoneKey returns the last digits of a phone, ignoring formatting and country prefixes, or
+// an empty string when it is too short to compare. It matches app_phone_key.
+func PhoneKey(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	if len(digits) < minPhoneDigits {
+		return ""
+	}
+	if len(digits) > phoneKeyDigits {
+		digits = digits[len(digits)-phoneKeyDigits:]
+	}
+	return digits
+}
+
+// NormalizeTaxID keeps the letters and digits of a tax ID in uppercase, like app_normalize_tax_id
+func NormalizeTaxID(taxID string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, taxID)
+}
+
+// TrigramSimilarity returns the share of trigrams two strings have in common, computed
+// like pg_trgm's similarity(): each word is padded with two spaces before and one after.
+func TrigramSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	trigramsA, trigramsB := trigrams(a), trigrams(b)
+	common := 0
+	for trigram := range trigramsA {
+		if trigramsB[trigram] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(trigramsA)+len(trigramsB)-common)
+}
+
+// trigrams returns the set of trigrams of the words of s
+func trigrams(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.Fields(s) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// stringValue returns the value of an optional string, or an empty string
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
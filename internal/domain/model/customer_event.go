@@ -0,0 +1,100 @@
+package model
+
+import (
+	"time"
+)
+
+// CustomerEvent representa un evento de actividad enviado por otro servicio (ventas, citas)
+type CustomerEvent struct {
+	EventID     string                 `json:"event_id"` // único por tenant, garantiza idempotencia
+	CustomerID  string                 `json:"customer_id"`
+	Type        string                 `json:"type"`   // order, appointment, payment
+	Source      string                 `json:"source"` // sales, appointments
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Amount      float64                `json:"amount"`
+	Status      string                 `json:"status"`
+	Data        map[string]interface{} `json:"data"`
+	OccurredAt  time.Time              `json:"occurred_at"`
+}
+
+// CustomerEventIngestResult resume el resultado de la ingesta de un lote de eventos
+type CustomerEventIngestResult struct {
+	Received   int `json:"received"`
+	Ingested   int `json:"ingested"`
+	Duplicates int `json:"duplicates"`
+
+	// IngestedEvents son los eventos aplicados en este lote (sin los duplicados)
+	IngestedEvents []*CustomerEvent `json:"-"`
+}
+
+// Validate valida el evento
+func (e *CustomerEvent) Validate() error {
+	if e.EventID == "" {
+		return &ValidationError{Field: "event_id", Message: "ID de evento es requerido"}
+	}
+	if e.CustomerID == "" {
+		return &ValidationError{Field: "customer_id", Message: "ID de cliente es requerido"}
+	}
+	if e.Type != HistoryTypeOrder && e.Type != HistoryTypeAppointment && e.Type != HistoryTypePayment {
+		return &ValidationError{Field: "type", Message: "tipo de evento inválido"}
+	}
+	if e.Amount < 0 {
+		return &ValidationError{Field: "amount", Message: "el monto no puede ser negativo"}
+	}
+	return nil
+}
+
+// ToHistoryItem convierte el evento en un item del historial del cliente
+func (e *CustomerEvent) ToHistoryItem() *CustomerHistoryItem {
+	title := e.Title
+	if title == "" {
+		title = defaultEventTitle(e.Type)
+	}
+
+	data := make(map[string]interface{}, len(e.Data)+1)
+	for key, value := range e.Data {
+		data[key] = value
+	}
+	if e.Source != "" {
+		data["source"] = e.Source
+	}
+
+	return &CustomerHistoryItem{
+		CustomerID:  e.CustomerID,
+		Type:        e.Type,
+		Title:       title,
+		Description: e.Description,
+		Amount:      e.Amount,
+		Status:      e.Status,
+		Data:        data,
+		ReferenceID: e.EventID,
+		CreatedAt:   e.OccurredAt,
+	}
+}
+
+// ApplyToStats actualiza incrementalmente las estadísticas con el evento.
+// Los pedidos suman al total gastado, las citas cuentan como visita y los
+// pagos solo quedan en el historial para no duplicar el importe del pedido.
+func (e *CustomerEvent) ApplyToStats(stats *CustomerStats) {
+	switch e.Type {
+	case HistoryTypeOrder:
+		stats.AddOrder(e.Amount, e.OccurredAt)
+	case HistoryTypeAppointment:
+		stats.AddVisit(e.OccurredAt)
+	}
+}
+
+// defaultEventTitle devuelve un título por defecto según el tipo de evento
+func defaultEventTitle(eventType string) string {
+	switch eventType {
+	case HistoryTypeOrder:
+		return "Pedido"
+	case HistoryTypeAppointment:
+		return "Cita"
+	case HistoryTypePayment:
+		return "Pago"
+	default:
+		return "Evento"
+	}
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Formatos de archivo de exportación
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatVCard  = "vcard"
+)
+
+// ExportColumnVehicles es la columna con los vehículos de cada cliente
+const ExportColumnVehicles = "vehicles"
+
+// customerExportColumns son las columnas con los datos del cliente, en su orden por defecto
+var customerExportColumns = []string{
+	"id", "first_name", "last_name", "email", "phone", "customer_type", "company_name",
+	"tax_id", "address", "birthday", "notes", "is_active", "created_at", "updated_at",
+}
+
+// statsExportColumns son las columnas con las estadísticas del cliente
+var statsExportColumns = []string{
+	"total_orders", "total_spent", "average_order_value", "last_visit", "visits_count", "level",
+}
+
+// CustomerExportOptions configura una exportación de clientes
+type CustomerExportOptions struct {
+	Format          string   // csv, ndjson, vcard; vacío = csv
+	Columns         []string // Vacío = las del cliente, más estadísticas y vehículos si se piden
+	IncludeVehicles bool
+	IncludeStats    bool
+	ExcludedColumns []string // Columnas que el llamante no puede leer; se quitan de la selección
+}
+
+// GetValidExportColumns retorna las columnas exportables, en su orden por defecto
+func GetValidExportColumns() []string {
+	columns := make([]string, 0, len(customerExportColumns)+len(statsExportColumns)+1)
+	columns = append(columns, customerExportColumns...)
+	columns = append(columns, statsExportColumns...)
+	return append(columns, ExportColumnVehicles)
+}
+
+// IsValidExportColumn verifica si la columna es exportable
+func IsValidExportColumn(column string) bool {
+	return containsString(GetValidExportColumns(), column)
+}
+
+// IsStatsExportColumn verifica si la columna sale de las estadísticas del cliente
+func IsStatsExportColumn(column string) bool {
+	return containsString(statsExportColumns, column)
+}
+
+// Validate valida las opciones y resuelve las columnas a exportar. Sin columnas explícitas
+// se exportan todas las del cliente, más las estadísticas y los vehículos si se piden; las
+// columnas excluidas se quitan en ambos casos. IncludeStats e IncludeVehicles quedan
+// reflejando las columnas resueltas.
+func (o *CustomerExportOptions) Validate() error {
+	var errs ValidationErrors
+
+	o.Format = strings.ToLower(strings.TrimSpace(o.Format))
+	if o.Format == "" {
+		o.Format = ExportFormatCSV
+	}
+	if o.Format != ExportFormatCSV && o.Format != ExportFormatNDJSON && o.Format != ExportFormatVCard {
+		errs.Add("format", "formato inválido (csv, ndjson, vcard)")
+	}
+
+	columns := o.Columns
+	if len(columns) == 0 {
+		columns = append([]string(nil), customerExportColumns...)
+		if o.IncludeStats {
+			columns = append(columns, statsExportColumns...)
+		}
+		if o.IncludeVehicles {
+			columns = append(columns, ExportColumnVehicles)
+		}
+	}
+
+	resolved := make([]string, 0, len(columns))
+	for _, column := range columns {
+		column = strings.ToLower(strings.TrimSpace(column))
+		switch {
+		case !IsValidExportColumn(column):
+			errs.Add("columns", fmt.Sprintf("columna inválida: %q", column))
+		case containsString(resolved, column):
+			errs.Add("columns", fmt.Sprintf("columna repetida: %q", column))
+		case !containsString(o.ExcludedColumns, column):
+			resolved = append(resolved, column)
+		}
+	}
+	if len(resolved) == 0 && len(errs) == 0 {
+		errs.Add("columns", "no queda ninguna columna que exportar")
+	}
+
+	o.Columns = resolved
+	o.IncludeStats, o.IncludeVehicles = false, false
+	for _, column := range resolved {
+		o.IncludeStats = o.IncludeStats || IsStatsExportColumn(column)
+		o.IncludeVehicles = o.IncludeVehicles || column == ExportColumnVehicles
+	}
+
+	return errs.Err()
+}
+
+// containsString verifica si values contiene value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Constantes de tipo de item del historial
+const (
+	HistoryTypeOrder       = "order"
+	HistoryTypeAppointment = "appointment"
+	HistoryTypeNote        = "note"
+	HistoryTypePayment     = "payment"
+	HistoryTypeMerge       = "merge"
+)
+
+// CustomerHistoryItem representa un item del historial del cliente
+type CustomerHistoryItem struct {
+	ID          string                 `db:"id" json:"id"`
+	TenantID    string                 `db:"tenant_id" json:"tenant_id"`
+	CustomerID  string                 `db:"customer_id" json:"customer_id"`
+	Type        string                 `db:"type" json:"type"` // order, appointment, note, payment, merge
+	Title       string                 `db:"title" json:"title"`
+	Description string                 `db:"description" json:"description"`
+	Amount      float64                `db:"amount" json:"amount"`
+	Status      string                 `db:"status" json:"status"`
+	Data        map[string]interface{} `db:"data" json:"data"`
+	ReferenceID string                 `db:"reference_id" json:"reference_id"` // ID del registro origen (nota, orden, cita...)
+	CreatedAt   time.Time              `db:"created_at" json:"created_at"`
+}
+
+// CustomerHistoryFilter representa los filtros para el historial del cliente
+type CustomerHistoryFilter struct {
+	CustomerID string
+	Type       string // order, appointment, note, payment, merge
+	DateFrom   *time.Time
+	DateTo     *time.Time
+	Page       int
+	Limit      int
+
+	// ExcludeNoteTypes oculta las notas de estos tipos (restringidas para el solicitante)
+	ExcludeNoteTypes []string
+}
+
+// NewNoteHistoryItem crea el item del historial correspondiente a una nota
+func NewNoteHistoryItem(note *CustomerNote) *CustomerHistoryItem {
+	return &CustomerHistoryItem{
+		CustomerID:  note.CustomerID,
+		Type:        HistoryTypeNote,
+		Title:       fmt.Sprintf("Nota (%s) de %s", note.Type, note.StaffName),
+		Description: note.Note,
+		Status:      note.Type,
+		Data: map[string]interface{}{
+			"note_type":  note.Type,
+			"staff_id":   note.StaffID,
+			"staff_name": note.StaffName,
+		},
+		ReferenceID: note.ID,
+		CreatedAt:   note.CreatedAt,
+	}
+}
+
+// Validate valida el item del historial
+func (h *CustomerHistoryItem) Validate() error {
+	if h.CustomerID == "" {
+		return &ValidationError{Field: "customer_id", Message: "ID de cliente es requerido"}
+	}
+	if !isValidHistoryType(h.Type) {
+		return &ValidationError{Field: "type", Message: "tipo de historial inválido"}
+	}
+	if h.Title == "" {
+		return &ValidationError{Field: "title", Message: "el título es requerido"}
+	}
+	if h.Amount < 0 {
+		return &ValidationError{Field: "amount", Message: "el monto no puede ser negativo"}
+	}
+	return nil
+}
+
+// Validate valida y normaliza el filtro del historial
+func (f *CustomerHistoryFilter) Validate() error {
+	if f.CustomerID == "" {
+		return &ValidationError{Field: "customer_id", Message: "ID de cliente es requerido"}
+	}
+	if f.Type != "" {
+		f.Type = NormalizeHistoryType(f.Type)
+		if !isValidHistoryType(f.Type) {
+			return &ValidationError{Field: "type", Message: "tipo de historial inválido"}
+		}
+	}
+	if f.DateFrom != nil && f.DateTo != nil && f.DateFrom.After(*f.DateTo) {
+		return &ValidationError{Field: "date_from", Message: "la fecha inicial no puede ser posterior a la final"}
+	}
+	return nil
+}
+
+// NormalizeHistoryType acepta tipos en plural (orders, appointments, notes, payments)
+// y los convierte al tipo almacenado en singular
+func NormalizeHistoryType(historyType string) string {
+	historyType = strings.ToLower(strings.TrimSpace(historyType))
+	if isValidHistoryType(historyType) {
+		return historyType
+	}
+	return strings.TrimSuffix(historyType, "s")
+}
+
+// GetValidHistoryTypes retorna los tipos de historial válidos
+func GetValidHistoryTypes() []string {
+	return []string{
+		HistoryTypeOrder,
+		HistoryTypeAppointment,
+		HistoryTypeNote,
+		HistoryTypePayment,
+		HistoryTypeMerge,
+	}
+}
+
+// isValidHistoryType verifica si el tipo de historial es válido
+func isValidHistoryType(historyType string) bool {
+	for _, validType := range GetValidHistoryTypes() {
+		if historyType == validType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,302 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatos de archivo de importación
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+// Claves por las que una fila actualiza un cliente existente en lugar de crearlo
+const (
+	ImportUpsertByEmail = "email"
+	ImportUpsertByTaxID = "tax_id"
+)
+
+// Resultado de cada fila importada
+const (
+	ImportStatusCreated = "created"
+	ImportStatusUpdated = "updated"
+	ImportStatusSkipped = "skipped"
+	ImportStatusError   = "error"
+)
+
+// Límites de la importación
+const (
+	DefaultImportBatchSize = 100
+	MaxImportBatchSize     = 1000
+)
+
+// importVehiclePrefix precede a los campos del vehículo de una fila (vehicle_make, vehicle_vin...)
+const importVehiclePrefix = "vehicle_"
+
+// importFields son los campos de destino de una fila: los del cliente y los de un vehículo opcional
+var importFields = []string{
+	"first_name", "last_name", "email", "phone", "customer_type", "company_name",
+	"tax_id", "address", "birthday", "notes",
+	"vehicle_make", "vehicle_model", "vehicle_year", "vehicle_vin", "vehicle_license_plate",
+	"vehicle_color", "vehicle_engine", "vehicle_notes",
+}
+
+// CustomerImportOptions configura una importación de clientes
+type CustomerImportOptions struct {
+	Format        string            // csv, ndjson
+	ColumnMapping map[string]string // Columna del archivo -> campo; las columnas con nombre de campo no necesitan mapeo
+	UpsertKey     string            // email, tax_id; vacío = cada fila crea un cliente
+	DryRun        bool              // Valida y aplica cada lote y lo revierte
+	BatchSize     int               // Filas por transacción
+}
+
+// CustomerImportRow es una fila del archivo con sus valores por campo de destino
+type CustomerImportRow struct {
+	Line   int               // Línea del archivo donde empieza la fila
+	Values map[string]string // Campo -> valor, sin espacios alrededor
+	Err    error             // La fila no se pudo leer (JSON o CSV mal formado)
+}
+
+// CustomerImportRowReader lee las filas de un archivo de importación; devuelve io.EOF tras la última
+type CustomerImportRowReader interface {
+	Read() (*CustomerImportRow, error)
+}
+
+// CustomerImportResult es el resultado de importar una fila
+type CustomerImportResult struct {
+	Line       int
+	Status     string // created, updated, skipped, error
+	CustomerID string
+	VehicleID  string
+	Field      string // Campo con el problema cuando Status es error
+	Message    string
+}
+
+// CustomerImportSummary acumula los resultados de una importación
+type CustomerImportSummary struct {
+	Rows    int
+	Created int
+	Updated int
+	Skipped int
+	Errors  int
+}
+
+// GetValidImportFields retorna los campos de destino de una fila
+func GetValidImportFields() []string {
+	return append([]string(nil), importFields...)
+}
+
+// IsValidImportField verifica si el campo es un destino válido de una columna
+func IsValidImportField(field string) bool {
+	for _, validField := range importFields {
+		if field == validField {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate valida y normaliza las opciones de importación
+func (o *CustomerImportOptions) Validate() error {
+	var errs ValidationErrors
+
+	o.Format = strings.ToLower(strings.TrimSpace(o.Format))
+	if o.Format != ImportFormatCSV && o.Format != ImportFormatNDJSON {
+		errs.Add("format", "formato inválido (csv, ndjson)")
+	}
+	for column, field := range o.ColumnMapping {
+		if strings.TrimSpace(column) == "" {
+			errs.Add("column_mapping", "el nombre de la columna no puede estar vacío")
+		}
+		if !IsValidImportField(field) {
+			errs.Add("column_mapping", fmt.Sprintf("campo de destino inválido para la columna %q: %q", column, field))
+		}
+	}
+	if o.UpsertKey != "" && o.UpsertKey != ImportUpsertByEmail && o.UpsertKey != ImportUpsertByTaxID {
+		errs.Add("upsert_key", "clave inválida (email, tax_id)")
+	}
+	if o.BatchSize < 0 || o.BatchSize > MaxImportBatchSize {
+		errs.Add("batch_size", fmt.Sprintf("no puede ser negativo ni superar %d", MaxImportBatchSize))
+	}
+	if o.BatchSize == 0 {
+		o.BatchSize = DefaultImportBatchSize
+	}
+
+	return errs.Err()
+}
+
+// IsEmpty indica si la fila no tiene ningún valor (líneas en blanco de una hoja de cálculo)
+func (r *CustomerImportRow) IsEmpty() bool {
+	for _, value := range r.Values {
+		if value != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// HasVehicle indica si la fila incluye campos del vehículo
+func (r *CustomerImportRow) HasVehicle() bool {
+	for field, value := range r.Values {
+		if strings.HasPrefix(field, importVehiclePrefix) && value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// UpsertValue retorna el valor de la clave de upsert en la fila
+func (r *CustomerImportRow) UpsertValue(upsertKey string) string {
+	return r.Values[upsertKey]
+}
+
+// CustomerCreate retorna el cliente descrito por la fila y su vehículo si la fila tiene uno.
+// Los valores que no se pueden convertir (fechas, años) se informan como errores de validación.
+func (r *CustomerImportRow) CustomerCreate() (CustomerCreate, *VehicleCreate, error) {
+	var errs ValidationErrors
+
+	create := CustomerCreate{
+		FirstName:    r.Values["first_name"],
+		LastName:     r.Values["last_name"],
+		Email:        r.optional("email"),
+		Phone:        r.optional("phone"),
+		CustomerType: strings.ToLower(r.Values["customer_type"]),
+		CompanyName:  r.optional("company_name"),
+		TaxID:        r.optional("tax_id"),
+		Address:      r.optional("address"),
+		Notes:        r.optional("notes"),
+	}
+	if create.CustomerType == "" {
+		create.CustomerType = CustomerTypeIndividual
+	}
+	if value := r.Values["birthday"]; value != "" {
+		birthday, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			errs.Add("birthday", "fecha inválida (AAAA-MM-DD)")
+		} else {
+			create.Birthday = &birthday
+		}
+	}
+
+	var vehicle *VehicleCreate
+	if r.HasVehicle() {
+		vehicle = &VehicleCreate{
+			Make:         r.Values["vehicle_make"],
+			Model:        r.Values["vehicle_model"],
+			VIN:          r.optional("vehicle_vin"),
+			LicensePlate: r.optional("vehicle_license_plate"),
+			Color:        r.optional("vehicle_color"),
+			Engine:       r.optional("vehicle_engine"),
+			Notes:        r.optional("vehicle_notes"),
+		}
+		if value := r.Values["vehicle_year"]; value != "" {
+			year, err := strconv.Atoi(value)
+			if err != nil {
+				errs.Add("vehicle_year", "año inválido")
+			}
+			vehicle.Year = year
+		}
+	}
+
+	return create, vehicle, errs.Err()
+}
+
+// CustomerUpdate retorna la actualización de un cliente existente con los valores de la fila.
+// Las celdas vacías conservan el valor actual en lugar de borrarlo.
+func (r *CustomerImportRow) CustomerUpdate(id string, create CustomerCreate) CustomerUpdate {
+	update := CustomerUpdate{
+		ID:          id,
+		Email:       create.Email,
+		Phone:       create.Phone,
+		CompanyName: create.CompanyName,
+		TaxID:       create.TaxID,
+		Address:     create.Address,
+		Birthday:    create.Birthday,
+		Notes:       create.Notes,
+	}
+	if create.FirstName != "" {
+		update.FirstName = &create.FirstName
+	}
+	if create.LastName != "" {
+		update.LastName = &create.LastName
+	}
+	if r.Values["customer_type"] != "" {
+		update.CustomerType = &create.CustomerType
+	}
+	return update
+}
+
+// optional retorna el valor de field, o nil si la celda está vacía
+func (r *CustomerImportRow) optional(field string) *string {
+	value := r.Values[field]
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// NewCustomerImportError retorna el resultado de error de una fila. Los errores de validación
+// y de conflicto informan su campo; los del vehículo llevan el prefijo vehicle_ de la fila.
+func NewCustomerImportError(line int, err error) *CustomerImportResult {
+	result := &CustomerImportResult{Line: line, Status: ImportStatusError, Message: err.Error()}
+
+	var validationErrs ValidationErrors
+	var validationErr *ValidationError
+	var conflictErr *ConflictError
+	var report *VehicleValidationReport
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]string, 0, len(validationErrs))
+		messages := make([]string, 0, len(validationErrs))
+		for _, e := range validationErrs {
+			fields = append(fields, e.Field)
+			messages = append(messages, fmt.Sprintf("%s: %s", e.Field, e.Message))
+		}
+		result.Field = strings.Join(fields, ",")
+		result.Message = strings.Join(messages, "; ")
+	case errors.As(err, &validationErr):
+		result.Field, result.Message = validationErr.Field, validationErr.Message
+	case errors.As(err, &report):
+		fields := make([]string, 0, len(report.Issues))
+		messages := make([]string, 0, len(report.Issues))
+		for _, issue := range report.Issues {
+			field := importVehiclePrefix + issue.Field
+			fields = append(fields, field)
+			messages = append(messages, fmt.Sprintf("%s: %s", field, issue.Message))
+		}
+		result.Field = strings.Join(fields, ",")
+		result.Message = strings.Join(messages, "; ")
+	case errors.As(err, &conflictErr):
+		result.Field, result.Message = conflictErr.Field, conflictErr.Message
+		if conflictErr.Resource == "vehicle" {
+			result.Field = importVehiclePrefix + conflictErr.Field
+		}
+	}
+
+	return result
+}
+
+// IsCustomerImportRowError indica si err solo afecta a su fila (datos inválidos o conflicto
+// con clientes existentes), de modo que el resto del lote se puede importar
+func IsCustomerImportRowError(err error) bool {
+	return errors.Is(err, ErrValidation) || errors.Is(err, ErrConflict) || errors.Is(err, ErrVersionConflict)
+}
+
+// Add cuenta el resultado de una fila
+func (s *CustomerImportSummary) Add(result *CustomerImportResult) {
+	s.Rows++
+	switch result.Status {
+	case ImportStatusCreated:
+		s.Created++
+	case ImportStatusUpdated:
+		s.Updated++
+	case ImportStatusSkipped:
+		s.Skipped++
+	default:
+		s.Errors++
+	}
+}
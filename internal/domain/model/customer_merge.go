@@ -0,0 +1,233 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxMergeDuplicates limita los duplicados que se fusionan en una sola operación
+const MaxMergeDuplicates = 10
+
+// CustomerMergeFields son los campos del perfil que se pueden elegir en FieldChoices
+var CustomerMergeFields = []string{
+	"first_name", "last_name", "email", "phone", "customer_type", "company_name",
+	"tax_id", "address", "birthday", "notes",
+}
+
+// CustomerMerge representa la fusión de perfiles duplicados en un cliente superviviente
+type CustomerMerge struct {
+	SurvivorID   string
+	DuplicateIDs []string
+	// FieldChoices indica, por campo, el ID del cliente (superviviente o duplicado) cuyo valor se conserva.
+	// Los campos sin elección conservan el valor del superviviente o, si está vacío, el del primer duplicado que lo tenga.
+	FieldChoices    map[string]string
+	ExpectedVersion int64 // Versión esperada del superviviente (0 = sin verificación)
+	DryRun          bool
+}
+
+// CustomerMergeResult describe el perfil resultante de una fusión
+type CustomerMergeResult struct {
+	Customer      *Customer // Superviviente con sus vehículos y estadísticas combinadas
+	MergedIDs     []string
+	VehiclesMoved int
+	NotesMoved    int
+	DryRun        bool
+}
+
+// Validate valida la solicitud de fusión
+func (m *CustomerMerge) Validate() error {
+	var errs ValidationErrors
+
+	if m.SurvivorID == "" {
+		errs.Add("survivor_id", "ID del cliente superviviente es requerido")
+	}
+	if len(m.DuplicateIDs) == 0 {
+		errs.Add("duplicate_ids", "debe indicar al menos un duplicado")
+	}
+	if len(m.DuplicateIDs) > MaxMergeDuplicates {
+		errs.Add("duplicate_ids", fmt.Sprintf("no se pueden fusionar más de %d duplicados a la vez", MaxMergeDuplicates))
+	}
+
+	participants := map[string]bool{m.SurvivorID: true}
+	for i, id := range m.DuplicateIDs {
+		field := fmt.Sprintf("duplicate_ids[%d]", i)
+		switch {
+		case id == "":
+			errs.Add(field, "ID de cliente es requerido")
+		case id == m.SurvivorID:
+			errs.Add(field, "el superviviente no puede ser también un duplicado")
+		case participants[id]:
+			errs.Add(field, "ID de cliente repetido")
+		}
+		participants[id] = true
+	}
+
+	for field, customerID := range m.FieldChoices {
+		if !isCustomerMergeField(field) {
+			errs.Add("field_choices", fmt.Sprintf("campo desconocido o no seleccionable: %q", field))
+			continue
+		}
+		if !participants[customerID] {
+			errs.Add("field_choices."+field, "el cliente elegido no participa en la fusión")
+		}
+	}
+
+	return errs.Err()
+}
+
+// MergeFrom combines the duplicates into the customer (the survivor): chosen fields are
+// copied from the chosen customer, empty fields are filled from the first duplicate that
+// has a value, preferences are combined (the survivor's keys win) and the customer stays
+// active if any of the profiles was active.
+func (c *Customer) MergeFrom(duplicates []*Customer, choices map[string]string) {
+	byID := make(map[string]*Customer, len(duplicates)+1)
+	byID[c.ID] = c
+	for _, duplicate := range duplicates {
+		byID[duplicate.ID] = duplicate
+	}
+
+	for _, field := range CustomerMergeFields {
+		if chosen, ok := byID[choices[field]]; ok {
+			if chosen != c {
+				copyCustomerField(c, chosen, field)
+			}
+			continue
+		}
+		if !customerFieldEmpty(c, field) {
+			continue
+		}
+		for _, duplicate := range duplicates {
+			if !customerFieldEmpty(duplicate, field) {
+				copyCustomerField(c, duplicate, field)
+				break
+			}
+		}
+	}
+
+	for _, duplicate := range duplicates {
+		for key, value := range duplicate.Preferences {
+			if _, exists := c.GetPreference(key); !exists {
+				c.SetPreference(key, value)
+			}
+		}
+		c.IsActive = c.IsActive || duplicate.IsActive
+	}
+}
+
+// Merge folds the stats of a merged duplicate into these stats
+func (cs *CustomerStats) Merge(other *CustomerStats) {
+	cs.TotalOrders += other.TotalOrders
+	cs.TotalSpent += other.TotalSpent
+	cs.VisitsCount += other.VisitsCount
+	if other.LastVisit.After(cs.LastVisit) {
+		cs.LastVisit = other.LastVisit
+	}
+	if cs.FavoriteCategory == "" {
+		cs.FavoriteCategory = other.FavoriteCategory
+	}
+
+	known := make(map[string]bool, len(cs.FavoriteProducts))
+	for _, product := range cs.FavoriteProducts {
+		known[product] = true
+	}
+	for _, product := range other.FavoriteProducts {
+		if !known[product] {
+			cs.FavoriteProducts = append(cs.FavoriteProducts, product)
+			known[product] = true
+		}
+	}
+
+	cs.RecalculateAverageOrderValue()
+	cs.UpdateCalculatedAt()
+}
+
+// NewMergeHistoryItem crea el item del historial que registra una fusión en el superviviente
+func NewMergeHistoryItem(survivor *Customer, duplicates []*Customer) *CustomerHistoryItem {
+	names := make([]string, 0, len(duplicates))
+	ids := make([]interface{}, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		names = append(names, fmt.Sprintf("%s (%s)", duplicate.DisplayName(), duplicate.ID))
+		ids = append(ids, duplicate.ID)
+	}
+
+	return &CustomerHistoryItem{
+		CustomerID:  survivor.ID,
+		Type:        HistoryTypeMerge,
+		Title:       fmt.Sprintf("Fusión de %d perfil(es) duplicado(s)", len(duplicates)),
+		Description: "Perfiles fusionados: " + strings.Join(names, ", "),
+		Data: map[string]interface{}{
+			"merged_ids": ids,
+		},
+	}
+}
+
+// isCustomerMergeField verifica si el campo se puede elegir en una fusión
+func isCustomerMergeField(field string) bool {
+	for _, mergeField := range CustomerMergeFields {
+		if field == mergeField {
+			return true
+		}
+	}
+	return false
+}
+
+// customerFieldEmpty reports whether a merge field has no value
+func customerFieldEmpty(c *Customer, field string) bool {
+	switch field {
+	case "first_name":
+		return c.FirstName == ""
+	case "last_name":
+		return c.LastName == ""
+	case "customer_type":
+		return c.CustomerType == ""
+	case "birthday":
+		return c.Birthday == nil
+	}
+	value := customerOptionalField(c, field)
+	return value == nil || *value == nil || **value == ""
+}
+
+// copyCustomerField copies a merge field from src to dst
+func copyCustomerField(dst, src *Customer, field string) {
+	switch field {
+	case "first_name":
+		dst.FirstName = src.FirstName
+	case "last_name":
+		dst.LastName = src.LastName
+	case "customer_type":
+		dst.CustomerType = src.CustomerType
+	case "birthday":
+		dst.Birthday = nil
+		if src.Birthday != nil {
+			birthday := *src.Birthday
+			dst.Birthday = &birthday
+		}
+	default:
+		target := customerOptionalField(dst, field)
+		value := customerOptionalField(src, field)
+		*target = nil
+		if value != nil && *value != nil {
+			copied := **value
+			*target = &copied
+		}
+	}
+}
+
+// customerOptionalField returns the address of an optional string field
+func customerOptionalField(c *Customer, field string) **string {
+	switch field {
+	case "email":
+		return &c.Email
+	case "phone":
+		return &c.Phone
+	case "company_name":
+		return &c.CompanyName
+	case "tax_id":
+		return &c.TaxID
+	case "address":
+		return &c.Address
+	case "notes":
+		return &c.Notes
+	}
+	return nil
+}
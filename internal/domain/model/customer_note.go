@@ -46,6 +46,26 @@ type CustomerNoteFilter struct {
 	DateTo     *time.Time
 	Page       int
 	Limit      int
+
+	// ExcludeTypes oculta las notas de estos tipos (restringidas para el solicitante)
+	ExcludeTypes []string
+
+	// Cursor continúa tras la página anterior (sustituye a Page); TotalMode: exact, estimate, none
+	Cursor    *PageCursor
+	TotalMode string
+}
+
+// Validate valida el filtro de notas
+func (f *CustomerNoteFilter) Validate() error {
+	var errs ValidationErrors
+	if f.Type != "" && !isValidNoteType(f.Type) {
+		errs.Add("type", "tipo de nota inválido")
+	}
+	if f.DateFrom != nil && f.DateTo != nil && f.DateFrom.After(*f.DateTo) {
+		errs.Add("date_from", "la fecha inicial no puede ser posterior a la final")
+	}
+	validatePagination(&errs, f.Page, f.Limit, f.TotalMode)
+	return errs.Err()
 }
 
 // NewCustomerNote crea una nueva nota desde CustomerNoteCreate
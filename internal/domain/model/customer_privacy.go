@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// Valores que sustituyen a los datos personales de un cliente anonimizado. Nombre y
+// apellido (y el nombre de empresa de un cliente empresarial) son obligatorios, así que
+// se sustituyen en lugar de vaciarse.
+const (
+	AnonymizedFirstName   = "Cliente"
+	AnonymizedLastName    = "Anonimizado"
+	AnonymizedCompanyName = "Empresa anonimizada"
+	AnonymizedText        = "[anonimizado]" // Textos libres (notas) y valores anteriores en la auditoría
+)
+
+// CustomerPersonalFields son los campos auditados con datos personales: los que se
+// suprimen al anonimizar, en el cliente y en las entradas de auditoría (notes también en
+// las de sus vehículos)
+var CustomerPersonalFields = []string{
+	"first_name", "last_name", "email", "phone", "company_name",
+	"tax_id", "address", "birthday", "notes", "preferences",
+}
+
+// AnonymizedHistoryTypes son los tipos de historial cuya descripción copia datos personales
+// (el texto de la nota, los nombres de los perfiles fusionados)
+var AnonymizedHistoryTypes = []string{HistoryTypeNote, HistoryTypeMerge}
+
+// CustomerAnonymization describe el resultado de anonimizar un cliente
+type CustomerAnonymization struct {
+	Customer       *Customer
+	Notes          int64 // Notas cuyo texto se sustituyó
+	HistoryEntries int64 // Entradas del historial cuya descripción se sustituyó
+	VehicleNotes   int64 // Vehículos cuyas notas se vaciaron
+	AuditEntries   int64 // Entradas de auditoría anteriores con valores suprimidos
+}
+
+// CustomerDataExport es el paquete de datos de un cliente entregado al ejercer su derecho
+// de acceso: el perfil (con sus preferencias), vehículos, notas y estadísticas
+type CustomerDataExport struct {
+	ExportedAt time.Time       `json:"exported_at"`
+	Customer   *Customer       `json:"customer"`
+	Vehicles   []*Vehicle      `json:"vehicles"`
+	Notes      []*CustomerNote `json:"notes"`
+	Stats      *CustomerStats  `json:"stats"` // nil si el cliente no tiene estadísticas
+}
+
+// Anonymize sustituye los datos personales del cliente de forma irreversible. Conserva el
+// tipo, el estado y las fechas, de los que dependen las estadísticas y la contabilidad.
+func (c *Customer) Anonymize(at time.Time) {
+	c.FirstName = AnonymizedFirstName
+	c.LastName = AnonymizedLastName
+	c.Email = nil
+	c.Phone = nil
+	c.CompanyName = nil
+	if c.IsBusiness() {
+		companyName := AnonymizedCompanyName
+		c.CompanyName = &companyName
+	}
+	c.TaxID = nil
+	c.Address = nil
+	c.Birthday = nil
+	c.Notes = nil
+	c.Preferences = CustomerPreferences{}
+	c.UpdatedAt = at
+	c.AnonymizedAt = &at
+}
+
+// AnonymizationChanges returns the audited changes of an anonymization without the personal
+// data it removed: previous values are replaced with AnonymizedText
+func AnonymizationChanges(before, after AuditSnapshot) map[string]FieldChange {
+	changes := DiffSnapshots(before, after)
+	for _, field := range CustomerPersonalFields {
+		if change, ok := changes[field]; ok && change.Before != nil {
+			change.Before = AnonymizedText
+			changes[field] = change
+		}
+	}
+	return changes
+}
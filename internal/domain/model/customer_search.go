@@ -0,0 +1,253 @@
+package model
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/transform"
+)
+
+// Campos buscables de un cliente
+const (
+	SearchFieldName        = "name"
+	SearchFieldCompanyName = "company_name"
+	SearchFieldEmail       = "email"
+	SearchFieldPhone       = "phone"
+	SearchFieldTaxID       = "tax_id"
+)
+
+// Marcas que rodean los fragmentos coincidentes en SearchHighlight.Value
+const (
+	HighlightPreTag  = "<em>"
+	HighlightPostTag = "</em>"
+)
+
+// Límites de la búsqueda
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 50
+
+	// minSearchDigits es la longitud mínima de una búsqueda solo numérica (teléfonos)
+	minSearchDigits = 3
+)
+
+// CustomerSearchResult es un cliente encontrado con su relevancia y los campos que coinciden
+type CustomerSearchResult struct {
+	Customer   *Customer
+	Score      float64 // Relevancia entre 0 y 1
+	Highlights []SearchHighlight
+}
+
+// SearchHighlight es el valor de un campo con los fragmentos coincidentes marcados
+type SearchHighlight struct {
+	Field string // first_name, last_name, company_name, email, phone, tax_id
+	Value string // HTML escapado, con los fragmentos entre HighlightPreTag y HighlightPostTag
+}
+
+// GetValidSearchFields retorna los campos buscables
+func GetValidSearchFields() []string {
+	return []string{SearchFieldName, SearchFieldCompanyName, SearchFieldEmail, SearchFieldPhone, SearchFieldTaxID}
+}
+
+// Validate valida la búsqueda
+func (f *CustomerSearchFilter) Validate() error {
+	var errs ValidationErrors
+
+	for _, field := range f.SearchFields {
+		if !isValidSearchField(field) {
+			errs.Add("search_fields", fmt.Sprintf("campo de búsqueda inválido: %q", field))
+		}
+	}
+	if f.Page < 0 {
+		errs.Add("page", "no puede ser negativa")
+	}
+	if f.Limit < 0 || f.Limit > MaxSearchLimit {
+		errs.Add("limit", fmt.Sprintf("no puede ser negativo ni superar %d", MaxSearchLimit))
+	}
+
+	return errs.Err()
+}
+
+// SearchTerms splits a search query into normalized terms (lowercase, without accents). A
+// query without letters is taken as a phone number and returns its digits as a single term.
+func SearchTerms(query string) []string {
+	hasLetters := strings.IndexFunc(query, unicode.IsLetter) >= 0
+	if !hasLetters {
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, query)
+		if len(digits) >= minSearchDigits {
+			return []string{digits}
+		}
+	}
+	return strings.Fields(NormalizeName(query))
+}
+
+// HighlightCustomer returns the fields of the customer that match the terms, restricted to
+// the search fields (all when empty). A term matches where it appears ignoring case and
+// accents (the digits of a phone, ignoring its formatting), or a whole word when it is
+// similar enough to the term (typos).
+func HighlightCustomer(c *Customer, terms []string, searchFields []string) []SearchHighlight {
+	searched := func(field string) bool {
+		if len(searchFields) == 0 {
+			return true
+		}
+		for _, searchField := range searchFields {
+			if searchField == field {
+				return true
+			}
+		}
+		return false
+	}
+
+	values := []struct {
+		field, searchField, value string
+	}{
+		{"first_name", SearchFieldName, c.FirstName},
+		{"last_name", SearchFieldName, c.LastName},
+		{"company_name", SearchFieldCompanyName, stringValue(c.CompanyName)},
+		{"email", SearchFieldEmail, stringValue(c.Email)},
+		{"phone", SearchFieldPhone, stringValue(c.Phone)},
+		{"tax_id", SearchFieldTaxID, stringValue(c.TaxID)},
+	}
+
+	var highlights []SearchHighlight
+	for _, v := range values {
+		if v.value == "" || !searched(v.searchField) {
+			continue
+		}
+		if highlighted, ok := highlightValue(v.value, terms, v.field == "phone"); ok {
+			highlights = append(highlights, SearchHighlight{Field: v.field, Value: highlighted})
+		}
+	}
+	return highlights
+}
+
+// highlightValue marks the terms in value; digitsOnly compares only the digits of value
+func highlightValue(value string, terms []string, digitsOnly bool) (string, bool) {
+	runes := []rune(value)
+	marked := make([]bool, len(runes))
+	mark := func(from, to int) {
+		for i := from; i <= to; i++ {
+			marked[i] = true
+		}
+	}
+
+	// Texto comparable (minúsculas, sin acentos) y posición de cada runa en el original
+	var folded []rune
+	var positions []int
+	for i, r := range runes {
+		if digitsOnly && (r < '0' || r > '9') {
+			continue
+		}
+		folded = append(folded, foldRune(r))
+		positions = append(positions, i)
+	}
+
+	found := false
+	for _, term := range terms {
+		termRunes := []rune(term)
+		matched := false
+		for from := 0; ; {
+			start := indexRunes(folded, termRunes, from)
+			if start < 0 {
+				break
+			}
+			end := start + len(termRunes) - 1
+			mark(positions[start], positions[end])
+			matched = true
+			from = end + 1
+		}
+		if matched || digitsOnly {
+			found = found || matched
+			continue
+		}
+
+		// Sin coincidencia literal: palabras parecidas al término (erratas)
+		for _, word := range searchWords(folded) {
+			if TrigramSimilarity(term, string(folded[word[0]:word[1]])) >= MinNameSimilarity {
+				mark(positions[word[0]], positions[word[1]-1])
+				found = true
+			}
+		}
+	}
+	if !found {
+		return "", false
+	}
+
+	var b strings.Builder
+	for i, r := range runes {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			b.WriteString(HighlightPreTag)
+		}
+		b.WriteString(html.EscapeString(string(r)))
+		if marked[i] && (i == len(runes)-1 || !marked[i+1]) {
+			b.WriteString(HighlightPostTag)
+		}
+	}
+	return b.String(), true
+}
+
+// indexRunes returns the index of the first occurrence of needle in s at or after from, or -1
+func indexRunes(s, needle []rune, from int) int {
+	if len(needle) == 0 {
+		return -1
+	}
+	for i := from; i+len(needle) <= len(s); i++ {
+		match := true
+		for j, r := range needle {
+			if s[i+j] != r {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// searchWords returns the [start, end) ranges of the letter and digit runs of s
+func searchWords(s []rune) [][2]int {
+	var words [][2]int
+	start := -1
+	for i, r := range s {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if inWord && start < 0 {
+			start = i
+		}
+		if !inWord && start >= 0 {
+			words = append(words, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, [2]int{start, len(s)})
+	}
+	return words
+}
+
+// foldRune lowercases r and removes its accent, keeping one rune per rune
+func foldRune(r rune) rune {
+	folded, _, err := transform.String(accentFolder, string(r))
+	if err != nil || folded == "" {
+		return unicode.ToLower(r)
+	}
+	return unicode.ToLower([]rune(folded)[0])
+}
+
+// isValidSearchField verifica si el campo es buscable
+func isValidSearchField(field string) bool {
+	for _, validField := range GetValidSearchFields() {
+		if field == validField {
+			return true
+		}
+	}
+	return false
+}
@@ -2,12 +2,23 @@ package model
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
+// Niveles de cliente según sus estadísticas
+const (
+	CustomerLevelBronze  = "Bronze"
+	CustomerLevelSilver  = "Silver"
+	CustomerLevelGold    = "Gold"
+	CustomerLevelPremium = "Premium"
+	CustomerLevelVIP     = "VIP"
+)
+
 // CustomerStats representa las estadísticas calculadas de un cliente
 type CustomerStats struct {
-	CustomerID        int64     `db:"customer_id" json:"customer_id"`
+	CustomerID        string    `db:"customer_id" json:"customer_id"`
+	TenantID          string    `db:"tenant_id" json:"tenant_id"`
 	TotalOrders       int32     `db:"total_orders" json:"total_orders"`
 	TotalSpent        float64   `db:"total_spent" json:"total_spent"`
 	AverageOrderValue float64   `db:"average_order_value" json:"average_order_value"`
@@ -23,7 +34,8 @@ type CustomerStats struct {
 
 // CustomerStatsCreate representa los datos para crear estadísticas de cliente
 type CustomerStatsCreate struct {
-	CustomerID        int64
+	CustomerID        string
+	TenantID          string
 	TotalOrders       int32
 	TotalSpent        float64
 	AverageOrderValue float64
@@ -33,34 +45,13 @@ type CustomerStatsCreate struct {
 	FavoriteProducts  []string
 }
 
-// CustomerHistoryItem representa un item del historial del cliente
-type CustomerHistoryItem struct {
-	ID          int64                  `json:"id"`
-	Type        string                 `json:"type"` // order, appointment, note, payment
-	Title       string                 `json:"title"`
-	Description string                 `json:"description"`
-	Amount      float64                `json:"amount"`
-	Status      string                 `json:"status"`
-	Data        map[string]interface{} `json:"data"`
-	CreatedAt   time.Time              `json:"created_at"`
-}
-
-// CustomerHistoryFilter representa los filtros para el historial del cliente
-type CustomerHistoryFilter struct {
-	CustomerID int64
-	Type       string // order, appointment, note, payment
-	DateFrom   *time.Time
-	DateTo     *time.Time
-	Page       int
-	Limit      int
-}
-
 // NewCustomerStats crea nuevas estadísticas desde CustomerStatsCreate
 func NewCustomerStats(create CustomerStatsCreate) *CustomerStats {
 	now := time.Now()
 
 	stats := &CustomerStats{
 		CustomerID:        create.CustomerID,
+		TenantID:          create.TenantID,
 		TotalOrders:       create.TotalOrders,
 		TotalSpent:        create.TotalSpent,
 		AverageOrderValue: create.AverageOrderValue,
@@ -98,27 +89,48 @@ func (cs *CustomerStats) IsHighValueCustomer() bool {
 // GetCustomerLevel devuelve el nivel del cliente basado en sus estadísticas
 func (cs *CustomerStats) GetCustomerLevel() string {
 	if cs.TotalSpent >= 5000 {
-		return "VIP"
+		return CustomerLevelVIP
 	} else if cs.TotalSpent >= 2000 {
-		return "Premium"
+		return CustomerLevelPremium
 	} else if cs.TotalSpent >= 500 {
-		return "Gold"
+		return CustomerLevelGold
 	} else if cs.TotalOrders >= 5 {
-		return "Silver"
+		return CustomerLevelSilver
 	}
-	return "Bronze"
+	return CustomerLevelBronze
+}
+
+// GetValidCustomerLevels devuelve todos los niveles de cliente válidos
+func GetValidCustomerLevels() []string {
+	return []string{
+		CustomerLevelBronze,
+		CustomerLevelSilver,
+		CustomerLevelGold,
+		CustomerLevelPremium,
+		CustomerLevelVIP,
+	}
+}
+
+// normalizeCustomerLevel returns the level with its canonical casing ("vip" → "VIP")
+func normalizeCustomerLevel(level string) (string, bool) {
+	for _, validLevel := range GetValidCustomerLevels() {
+		if strings.EqualFold(level, validLevel) {
+			return validLevel, true
+		}
+	}
+	return level, false
 }
 
 // GetCustomerLevelEmoji devuelve un emoji para el nivel del cliente
 func (cs *CustomerStats) GetCustomerLevelEmoji() string {
 	switch cs.GetCustomerLevel() {
-	case "VIP":
+	case CustomerLevelVIP:
 		return "💎"
-	case "Premium":
+	case CustomerLevelPremium:
 		return "🥇"
-	case "Gold":
+	case CustomerLevelGold:
 		return "🥈"
-	case "Silver":
+	case CustomerLevelSilver:
 		return "🥉"
 	default:
 		return "⭐"
@@ -224,9 +236,20 @@ func (cs *CustomerStats) AddOrder(amount float64, visitDate time.Time) {
 	cs.UpdateCalculatedAt()
 }
 
+// AddVisit actualiza las estadísticas con una visita sin pedido (por ejemplo una cita)
+func (cs *CustomerStats) AddVisit(visitDate time.Time) {
+	cs.VisitsCount++
+
+	if visitDate.After(cs.LastVisit) {
+		cs.LastVisit = visitDate
+	}
+
+	cs.UpdateCalculatedAt()
+}
+
 // Validate valida las estadísticas del cliente
 func (cs *CustomerStats) Validate() error {
-	if cs.CustomerID <= 0 {
+	if cs.CustomerID == "" {
 		return &ValidationError{Field: "customer_id", Message: "ID de cliente es requerido"}
 	}
 	if cs.TotalOrders < 0 {
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// CustomerDeleteImpact describe lo que arrastra eliminar un cliente: lo que queda oculto con
+// él en la papelera y se borra definitivamente en la purga
+type CustomerDeleteImpact struct {
+	CustomerID     string
+	Vehicles       int64
+	Notes          int64
+	HistoryEntries int64
+	HasStats       bool
+	PurgeAt        time.Time // Cuándo la purga lo borra si no se restaura
+}
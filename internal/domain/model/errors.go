@@ -0,0 +1,133 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors matched with errors.Is by the transport layer
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation error")
+
+	// ErrVersionConflict is matched by VersionConflictError
+	ErrVersionConflict = errors.New("version conflict")
+)
+
+// NotFoundError is returned when a resource does not exist for the tenant
+type NotFoundError struct {
+	Resource string // customer, vehicle, customer_note, customer_stats...
+	Key      string // Campo usado en la búsqueda (email, tax_id, vin...); vacío equivale a ID
+	Value    string
+}
+
+// NewNotFoundError builds a NotFoundError for a lookup by ID
+func NewNotFoundError(resource, id string) *NotFoundError {
+	return &NotFoundError{Resource: resource, Value: id}
+}
+
+func (e *NotFoundError) Error() string {
+	key := strings.ReplaceAll(e.Key, "_", " ")
+	if key == "" {
+		key = "ID"
+	}
+	return fmt.Sprintf("%s with %s %s not found", strings.ReplaceAll(e.Resource, "_", " "), key, e.Value)
+}
+
+// Is makes errors.Is(err, ErrNotFound) match any NotFoundError
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ConflictError is returned when a unique field is already taken by another resource
+type ConflictError struct {
+	Resource string
+	Field    string // Campo en conflicto, p. ej. email o vin
+	Value    string // Puede ir vacío si la base de datos no lo informa
+	Message  string // Mensaje para el usuario final
+}
+
+// NewConflictError builds a ConflictError for a value already registered in field
+func NewConflictError(resource, field, value, message string) *ConflictError {
+	return &ConflictError{Resource: resource, Field: field, Value: value, Message: message}
+}
+
+func (e *ConflictError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s with the same %s already exists", strings.ReplaceAll(e.Resource, "_", " "), e.Field)
+	}
+	return fmt.Sprintf("%s with %s %s already exists", strings.ReplaceAll(e.Resource, "_", " "), e.Field, e.Value)
+}
+
+// Is makes errors.Is(err, ErrConflict) match any ConflictError
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// VersionConflictError is returned when a resource changed after the caller read it
+type VersionConflictError struct {
+	Resource        string
+	ID              string
+	ExpectedVersion int64
+	CurrentVersion  int64 // 0 si se desconoce
+	// Concurrent indica que la versión cambió entre la lectura y la escritura del propio
+	// servicio y se agotaron los reintentos; si es false, la versión enviada por el cliente está vencida
+	Concurrent bool
+}
+
+func (e *VersionConflictError) Error() string {
+	resource := strings.ReplaceAll(e.Resource, "_", " ")
+	if e.CurrentVersion == 0 {
+		return fmt.Sprintf("%s %s was modified concurrently (expected version %d)", resource, e.ID, e.ExpectedVersion)
+	}
+	return fmt.Sprintf("%s %s is at version %d, expected %d", resource, e.ID, e.CurrentVersion, e.ExpectedVersion)
+}
+
+// Is makes errors.Is(err, ErrVersionConflict) match any VersionConflictError
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// Is makes errors.Is(err, ErrValidation) match any ValidationError
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ValidationErrors collects every invalid field of a request
+type ValidationErrors []*ValidationError
+
+// Add records an invalid field
+func (v *ValidationErrors) Add(field, message string) {
+	*v = append(*v, &ValidationError{Field: field, Message: message})
+}
+
+// Err returns nil when there are no errors, so callers can return it directly
+func (v ValidationErrors) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+func (v ValidationErrors) Error() string {
+	parts := make([]string, 0, len(v))
+	for _, err := range v {
+		parts = append(parts, fmt.Sprintf("%s: %s", err.Field, err.Message))
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(parts, "; "))
+}
+
+// Is makes errors.Is(err, ErrValidation) match any ValidationErrors
+func (v ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Is makes errors.Is(err, ErrValidation) or errors.Is(err, ErrConflict) match the report
+func (r *VehicleValidationReport) Is(target error) bool {
+	if target == ErrConflict {
+		return r.OnlyDuplicates()
+	}
+	return target == ErrValidation && !r.OnlyDuplicates()
+}
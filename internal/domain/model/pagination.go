@@ -0,0 +1,49 @@
+package model
+
+// Cálculo del total en los listados paginados
+const (
+	TotalModeExact    = "exact"    // COUNT(*) sobre el filtro (por defecto)
+	TotalModeEstimate = "estimate" // Estimación del planificador, sin recorrer la tabla
+	TotalModeNone     = "none"     // Sin total
+)
+
+// PageCursor es la posición tras la última fila de una página: los valores de la clave de
+// orden activa, terminada siempre en el id, en su representación de texto
+type PageCursor struct {
+	Key []string
+}
+
+// PageInfo describe la página devuelta por un listado
+type PageInfo struct {
+	Total           int         // 0 con TotalModeNone
+	TotalIsEstimate bool        // Total es una estimación (TotalModeEstimate)
+	Next            *PageCursor // nil en la última página
+}
+
+// GetValidTotalModes retorna los modos de cálculo del total válidos
+func GetValidTotalModes() []string {
+	return []string{TotalModeExact, TotalModeEstimate, TotalModeNone}
+}
+
+// validatePagination valida los campos comunes de paginación de un filtro
+func validatePagination(errs *ValidationErrors, page, limit int, totalMode string) {
+	if page < 0 {
+		errs.Add("page", "no puede ser negativa")
+	}
+	if limit < 0 {
+		errs.Add("limit", "no puede ser negativo")
+	}
+	if totalMode != "" && !isValidTotalMode(totalMode) {
+		errs.Add("total_mode", "modo de total inválido (exact, estimate, none)")
+	}
+}
+
+// isValidTotalMode verifica si el modo de cálculo del total es válido
+func isValidTotalMode(mode string) bool {
+	for _, validMode := range GetValidTotalModes() {
+		if mode == validMode {
+			return true
+		}
+	}
+	return false
+}
@@ -25,6 +25,7 @@ type Vehicle struct {
 	Metadata     VehicleMetadata `db:"metadata" json:"metadata"`
 	CreatedAt    time.Time       `db:"created_at" json:"created_at"`
 	UpdatedAt    time.Time       `db:"updated_at" json:"updated_at"`
+	Version      int64           `db:"version" json:"version"` // Se incrementa en cada actualización
 
 	// Campos no persistidos (relaciones)
 	Customer *Customer `db:"-" json:"customer,omitempty"`
@@ -70,7 +71,8 @@ type VehicleCreate struct {
 	Metadata     VehicleMetadata
 }
 
-// VehicleUpdate representa los datos para actualizar un vehículo
+// VehicleUpdate representa los datos para actualizar un vehículo. Los campos nil no se
+// modifican; un campo opcional con cadena vacía se pone en NULL.
 type VehicleUpdate struct {
 	ID           string
 	Make         *string
@@ -83,6 +85,8 @@ type VehicleUpdate struct {
 	Notes        *string
 	IsActive     *bool
 	Metadata     VehicleMetadata
+
+	ExpectedVersion int64 // Versión leída por el cliente; 0 omite la verificación
 }
 
 // VehicleFilter representa los filtros para búsqueda de vehículos
@@ -92,6 +96,17 @@ type VehicleFilter struct {
 	ActiveOnly bool
 	Page       int
 	Limit      int
+
+	// Cursor continúa tras la página anterior (sustituye a Page); TotalMode: exact, estimate, none
+	Cursor    *PageCursor
+	TotalMode string
+}
+
+// Validate valida el filtro de vehículos
+func (f *VehicleFilter) Validate() error {
+	var errs ValidationErrors
+	validatePagination(&errs, f.Page, f.Limit, f.TotalMode)
+	return errs.Err()
 }
 
 // NewVehicle crea un nuevo vehículo desde VehicleCreate
@@ -168,19 +183,19 @@ func (v *Vehicle) UpdateFromUpdate(update VehicleUpdate) {
 		v.Year = *update.Year
 	}
 	if update.VIN != nil {
-		v.VIN = update.VIN
+		v.VIN = nilIfEmpty(update.VIN)
 	}
 	if update.LicensePlate != nil {
-		v.LicensePlate = update.LicensePlate
+		v.LicensePlate = nilIfEmpty(update.LicensePlate)
 	}
 	if update.Color != nil {
-		v.Color = update.Color
+		v.Color = nilIfEmpty(update.Color)
 	}
 	if update.Engine != nil {
-		v.Engine = update.Engine
+		v.Engine = nilIfEmpty(update.Engine)
 	}
 	if update.Notes != nil {
-		v.Notes = update.Notes
+		v.Notes = nilIfEmpty(update.Notes)
 	}
 	if update.IsActive != nil {
 		v.IsActive = *update.IsActive
@@ -277,6 +292,11 @@ func (v *Vehicle) Validate() error {
 	if v.CustomerID == "" {
 		return &ValidationError{Field: "customer_id", Message: "ID de cliente es requerido"}
 	}
+	return v.validateDetails()
+}
+
+// validateDetails valida los datos propios del vehículo, sin el cliente
+func (v *Vehicle) validateDetails() error {
 	if v.Make == "" {
 		return &ValidationError{Field: "make", Message: "la marca es requerida"}
 	}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// VehicleIssue describes a problem with one vehicle of a batch
+type VehicleIssue struct {
+	Index     int    // Posición del vehículo en la solicitud
+	Field     string // Campo con el problema
+	Message   string
+	Duplicate bool // El VIN o la placa ya están registrados
+}
+
+// VehicleValidationReport collects the issues of every vehicle in a batch
+type VehicleValidationReport struct {
+	Issues []VehicleIssue
+}
+
+// Add records an issue for the vehicle at index
+func (r *VehicleValidationReport) Add(index int, field, message string) {
+	r.Issues = append(r.Issues, VehicleIssue{Index: index, Field: field, Message: message})
+}
+
+// AddDuplicate records a VIN or license plate that is already registered
+func (r *VehicleValidationReport) AddDuplicate(index int, field, message string) {
+	r.Issues = append(r.Issues, VehicleIssue{Index: index, Field: field, Message: message, Duplicate: true})
+}
+
+// AddError records a validation error for the vehicle at index
+func (r *VehicleValidationReport) AddError(index int, err error) {
+	var validationErrs ValidationErrors
+	if errors.As(err, &validationErrs) {
+		for _, validationErr := range validationErrs {
+			r.Add(index, validationErr.Field, validationErr.Message)
+		}
+		return
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		r.Add(index, validationErr.Field, validationErr.Message)
+		return
+	}
+	r.Add(index, "", err.Error())
+}
+
+// HasIssues reports whether any vehicle has an issue
+func (r *VehicleValidationReport) HasIssues() bool {
+	return len(r.Issues) > 0
+}
+
+// OnlyDuplicates reports whether every issue is an already registered VIN or plate
+func (r *VehicleValidationReport) OnlyDuplicates() bool {
+	for _, issue := range r.Issues {
+		if !issue.Duplicate {
+			return false
+		}
+	}
+	return r.HasIssues()
+}
+
+func (r *VehicleValidationReport) Error() string {
+	parts := make([]string, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		parts = append(parts, issue.String())
+	}
+	return fmt.Sprintf("vehicle validation failed: %s", strings.Join(parts, "; "))
+}
+
+// FieldPath returns the request path of the field, e.g. vehicles[2].vin
+func (i VehicleIssue) FieldPath() string {
+	if i.Field == "" {
+		return fmt.Sprintf("vehicles[%d]", i.Index)
+	}
+	return fmt.Sprintf("vehicles[%d].%s", i.Index, i.Field)
+}
+
+// String formats the issue as vehicles[i].field: message
+func (i VehicleIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.FieldPath(), i.Message)
+}
+
+// ValidateNewVehicles validates a batch of vehicles before they are created, including
+// VINs and license plates repeated within the batch. The customer ID is not checked so
+// the batch can be validated before its customer exists. Returns nil if every vehicle is valid.
+func ValidateNewVehicles(vehicles []*Vehicle) *VehicleValidationReport {
+	report := &VehicleValidationReport{}
+	seenVINs := make(map[string]int)
+	seenPlates := make(map[string]int)
+
+	for i, vehicle := range vehicles {
+		if err := vehicle.validateDetails(); err != nil {
+			report.AddError(i, err)
+		} else if err := vehicle.ValidateVIN(); err != nil {
+			report.AddError(i, err)
+		}
+
+		if vehicle.VIN != nil && *vehicle.VIN != "" {
+			key := strings.ToUpper(*vehicle.VIN)
+			if first, ok := seenVINs[key]; ok {
+				report.Add(i, "vin", fmt.Sprintf("VIN repetido en la solicitud (vehículo %d)", first))
+			} else {
+				seenVINs[key] = i
+			}
+		}
+
+		if vehicle.LicensePlate != nil && *vehicle.LicensePlate != "" {
+			key := strings.ToUpper(*vehicle.LicensePlate)
+			if first, ok := seenPlates[key]; ok {
+				report.Add(i, "license_plate", fmt.Sprintf("placa repetida en la solicitud (vehículo %d)", first))
+			} else {
+				seenPlates[key] = i
+			}
+		}
+	}
+
+	if !report.HasIssues() {
+		return nil
+	}
+	return report
+}
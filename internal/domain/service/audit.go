@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/identity"
+	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+	"github.com/encomos/api-encomos/customer-service/internal/requestid"
+)
+
+// auditLog records the audit entries of the mutations. It must be called with the context
+// of the transaction that applies the change, so both are committed or discarded together.
+type auditLog struct {
+	repo repository.AuditLogRepository
+}
+
+// record completes entry with the actor and request ID of ctx and stores it
+func (a auditLog) record(ctx context.Context, entry *model.AuditEntry) error {
+	if staff, ok := identity.StaffFromContext(ctx); ok {
+		entry.ActorID = staff.ID
+		entry.ActorName = staff.DisplayName()
+	}
+	entry.RequestID = requestid.FromContext(ctx)
+
+	if err := a.repo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// customerChange records a change of a customer between two snapshots
+func (a auditLog) customerChange(ctx context.Context, action, customerID string, before, after model.AuditSnapshot) error {
+	return a.record(ctx, model.NewAuditEntry(action, model.AuditResourceCustomer, customerID, customerID, model.DiffSnapshots(before, after)))
+}
+
+// vehicleChange records a change of a vehicle between two snapshots
+func (a auditLog) vehicleChange(ctx context.Context, action string, vehicle *model.Vehicle, before, after model.AuditSnapshot) error {
+	return a.record(ctx, model.NewAuditEntry(action, model.AuditResourceVehicle, vehicle.ID, vehicle.CustomerID, model.DiffSnapshots(before, after)))
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// maxVersionAttempts bounds the read-modify-write attempts when a concurrent write wins
+const maxVersionAttempts = 3
+
+// checkExpectedVersion fails when the caller sent a version (non-zero) that is no longer current
+func checkExpectedVersion(resource, id string, expected, current int64) error {
+	if expected == 0 || expected == current {
+		return nil
+	}
+	return &model.VersionConflictError{
+		Resource:        resource,
+		ID:              id,
+		ExpectedVersion: expected,
+		CurrentVersion:  current,
+	}
+}
+
+// retryOnVersionConflict runs attempt again, re-reading the resource, while it fails because
+// another request wrote the same row between the read and the write. When the caller sent an
+// expected version the conflict is returned at once: retrying cannot make its copy current.
+func retryOnVersionConflict(expectedVersion int64, attempt func() error) error {
+	var err error
+	for i := 0; i < maxVersionAttempts; i++ {
+		err = attempt()
+
+		var conflict *model.VersionConflictError
+		if !errors.As(err, &conflict) || expectedVersion != 0 {
+			return err
+		}
+	}
+
+	// Se agotaron los reintentos: el cliente puede repetir la operación completa
+	var conflict *model.VersionConflictError
+	if errors.As(err, &conflict) {
+		conflict.Concurrent = true
+	}
+	return err
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,6 +15,14 @@ type CustomerService struct {
 	customerRepo     repository.CustomerRepository
 	vehicleRepo      repository.VehicleRepository
 	customerNoteRepo repository.CustomerNoteRepository
+	statsRepo        repository.CustomerStatsRepository
+	historyRepo      repository.CustomerHistoryRepository
+	eventRepo        repository.CustomerEventRepository
+	auditRepo        repository.AuditLogRepository
+	transactor       repository.Transactor
+	audit            auditLog
+	duplicates       model.DuplicatePolicy
+	trashRetention   time.Duration
 }
 
 // NewCustomerService creates a new customer service
@@ -21,30 +30,67 @@ func NewCustomerService(
 	customerRepo repository.CustomerRepository,
 	vehicleRepo repository.VehicleRepository,
 	customerNoteRepo repository.CustomerNoteRepository,
+	statsRepo repository.CustomerStatsRepository,
+	historyRepo repository.CustomerHistoryRepository,
+	eventRepo repository.CustomerEventRepository,
+	auditRepo repository.AuditLogRepository,
+	transactor repository.Transactor,
+	duplicates model.DuplicatePolicy,
+	trashRetention time.Duration,
 ) *CustomerService {
 	return &CustomerService{
 		customerRepo:     customerRepo,
 		vehicleRepo:      vehicleRepo,
 		customerNoteRepo: customerNoteRepo,
+		statsRepo:        statsRepo,
+		historyRepo:      historyRepo,
+		eventRepo:        eventRepo,
+		auditRepo:        auditRepo,
+		transactor:       transactor,
+		audit:            auditLog{repo: auditRepo},
+		duplicates:       duplicates,
+		trashRetention:   trashRetention,
 	}
 }
 
-// CreateCustomer creates a new customer with validation
-func (s *CustomerService) CreateCustomer(ctx context.Context, create model.CustomerCreate) (*model.Customer, error) {
+// CreateCustomer creates a new customer with validation. It also returns the existing
+// customers that look like duplicates of the new one; if the best candidate reaches the
+// block threshold of the duplicate policy the customer is not created (DuplicateCustomerError).
+func (s *CustomerService) CreateCustomer(ctx context.Context, create model.CustomerCreate) (*model.Customer, []*model.DuplicateCandidate, error) {
+	customer, vehicles, duplicates, err := s.newCustomer(ctx, create)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Cliente, vehículos y auditoría en una sola transacción (todo o nada)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.createAuditedCustomer(ctx, customer, vehicles)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return customer, duplicates, nil
+}
+
+// newCustomer validates a new customer and its vehicles, checks that its email and tax ID
+// are free and applies the duplicate policy. It returns the customer and vehicles to store
+// and the duplicate candidates below the block threshold.
+func (s *CustomerService) newCustomer(ctx context.Context, create model.CustomerCreate) (*model.Customer, []*model.Vehicle, []*model.DuplicateCandidate, error) {
 	// Validar datos de entrada
 	customer := model.NewCustomer(create)
 	if err := customer.Validate(); err != nil {
-		return nil, fmt.Errorf("validation error: %w", err)
+		return nil, nil, nil, fmt.Errorf("validation error: %w", err)
 	}
 
 	// Verificar unicidad de email si está presente
 	if customer.Email != nil && *customer.Email != "" {
 		exists, err := s.customerRepo.ExistsByEmail(ctx, *customer.Email, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to check email uniqueness: %w", err)
+			return nil, nil, nil, fmt.Errorf("failed to check email uniqueness: %w", err)
 		}
 		if exists {
-			return nil, fmt.Errorf("customer with email %s already exists", *customer.Email)
+			return nil, nil, nil, model.NewConflictError("customer", "email", *customer.Email, "ya existe un cliente con este email")
 		}
 	}
 
@@ -52,27 +98,84 @@ func (s *CustomerService) CreateCustomer(ctx context.Context, create model.Custo
 	if customer.TaxID != nil && *customer.TaxID != "" {
 		exists, err := s.customerRepo.ExistsByTaxID(ctx, *customer.TaxID, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to check tax ID uniqueness: %w", err)
+			return nil, nil, nil, fmt.Errorf("failed to check tax ID uniqueness: %w", err)
 		}
 		if exists {
-			return nil, fmt.Errorf("customer with tax ID %s already exists", *customer.TaxID)
+			return nil, nil, nil, model.NewConflictError("customer", "tax_id", *customer.TaxID, "ya existe un cliente con este identificador fiscal")
 		}
 	}
 
-	// Crear el cliente
-	if err := s.customerRepo.Create(ctx, customer); err != nil {
-		return nil, fmt.Errorf("failed to create customer: %w", err)
+	// Posibles duplicados: desde el umbral de bloqueo se rechaza el alta; por debajo solo se avisa
+	duplicates, err := s.findDuplicates(ctx, model.NewDuplicateProbe(customer), s.duplicates.WarnThreshold, model.DefaultDuplicateLimit)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(duplicates) > 0 && s.duplicates.Blocks(duplicates[0].Score) {
+		return nil, nil, nil, &model.DuplicateCustomerError{Candidates: duplicates, Threshold: s.duplicates.BlockThreshold}
 	}
 
-	return customer, nil
+	// Validar todos los vehículos antes de abrir la transacción
+	var vehicles []*model.Vehicle
+	if len(create.Vehicles) > 0 {
+		vehicles = make([]*model.Vehicle, 0, len(create.Vehicles))
+		for _, vehicleCreate := range create.Vehicles {
+			vehicles = append(vehicles, model.NewVehicle(vehicleCreate))
+		}
+		if report := model.ValidateNewVehicles(vehicles); report != nil {
+			return nil, nil, nil, report
+		}
+	}
+
+	return customer, vehicles, duplicates, nil
 }
 
-// GetCustomer retrieves a customer by ID with optional related data
-func (s *CustomerService) GetCustomer(ctx context.Context, id string, includeVehicles, includeNotes bool) (*model.Customer, error) {
-	customer, err := s.customerRepo.GetByID(ctx, id)
+// createAuditedCustomer stores a customer prepared by newCustomer and audits it; it must run
+// inside a transaction
+func (s *CustomerService) createAuditedCustomer(ctx context.Context, customer *model.Customer, vehicles []*model.Vehicle) error {
+	if err := s.createCustomer(ctx, customer, vehicles); err != nil {
+		return err
+	}
+
+	if err := s.audit.customerChange(ctx, model.AuditActionCustomerCreated, customer.ID, nil, customer.AuditSnapshot()); err != nil {
+		return err
+	}
+	for _, vehicle := range vehicles {
+		if err := s.audit.vehicleChange(ctx, model.AuditActionVehicleCreated, vehicle, nil, vehicle.AuditSnapshot()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createCustomer stores the customer, together with its vehicles when there are any
+func (s *CustomerService) createCustomer(ctx context.Context, customer *model.Customer, vehicles []*model.Vehicle) error {
+	// Sin vehículos: creación simple
+	if len(vehicles) == 0 {
+		if err := s.customerRepo.Create(ctx, customer); err != nil {
+			return fmt.Errorf("failed to create customer: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.customerRepo.CreateWithVehicles(ctx, customer, vehicles); err != nil {
+		var report *model.VehicleValidationReport
+		if errors.As(err, &report) {
+			return report
+		}
+		return fmt.Errorf("failed to create customer with vehicles: %w", err)
+	}
+
+	return nil
+}
+
+// GetCustomer retrieves a customer by ID with optional related data. The ID of a merged
+// customer returns the customer it was merged into.
+func (s *CustomerService) GetCustomer(ctx context.Context, id string, includeVehicles, includeNotes, includeStats bool) (*model.Customer, error) {
+	customer, err := resolveCustomer(ctx, s.customerRepo, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get customer: %w", err)
 	}
+	id = customer.ID
 
 	// Cargar vehículos si se solicita
 	if includeVehicles {
@@ -92,17 +195,70 @@ func (s *CustomerService) GetCustomer(ctx context.Context, id string, includeVeh
 		customer.CustomerNotes = notes
 	}
 
+	// Cargar estadísticas si se solicita
+	if includeStats {
+		stats, err := s.getCustomerStats(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load customer stats: %w", err)
+		}
+		customer.Stats = stats
+	}
+
 	return customer, nil
 }
 
-// UpdateCustomer updates an existing customer
+// getCustomerStats returns the stats of a customer, (re)calculating them when missing or outdated
+func (s *CustomerService) getCustomerStats(ctx context.Context, customerID string) (*model.CustomerStats, error) {
+	exists, err := s.statsRepo.Exists(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return s.statsRepo.CalculateAndSave(ctx, customerID)
+	}
+
+	stats, err := s.statsRepo.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if stats.IsStatsOutdated() {
+		return s.statsRepo.CalculateAndSave(ctx, customerID)
+	}
+
+	return stats, nil
+}
+
+// UpdateCustomer updates an existing customer. If update.ExpectedVersion is set and the
+// customer is at another version nothing is written; otherwise the update is retried when a
+// concurrent write changes the customer between the read and the write.
 func (s *CustomerService) UpdateCustomer(ctx context.Context, update model.CustomerUpdate) (*model.Customer, error) {
+	var customer *model.Customer
+	err := retryOnVersionConflict(update.ExpectedVersion, func() error {
+		return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			customer, err = s.updateCustomer(ctx, update)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// updateCustomer performs one read-modify-write attempt of UpdateCustomer and audits it
+func (s *CustomerService) updateCustomer(ctx context.Context, update model.CustomerUpdate) (*model.Customer, error) {
 	// Obtener el cliente actual
 	customer, err := s.customerRepo.GetByID(ctx, update.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get customer for update: %w", err)
 	}
 
+	if err := checkExpectedVersion("customer", customer.ID, update.ExpectedVersion, customer.Version); err != nil {
+		return nil, err
+	}
+
 	// Verificar unicidad de email si se está cambiando
 	if update.Email != nil && *update.Email != "" {
 		if customer.Email == nil || *customer.Email != *update.Email {
@@ -111,7 +267,7 @@ func (s *CustomerService) UpdateCustomer(ctx context.Context, update model.Custo
 				return nil, fmt.Errorf("failed to check email uniqueness: %w", err)
 			}
 			if exists {
-				return nil, fmt.Errorf("customer with email %s already exists", *update.Email)
+				return nil, model.NewConflictError("customer", "email", *update.Email, "ya existe un cliente con este email")
 			}
 		}
 	}
@@ -124,12 +280,13 @@ func (s *CustomerService) UpdateCustomer(ctx context.Context, update model.Custo
 				return nil, fmt.Errorf("failed to check tax ID uniqueness: %w", err)
 			}
 			if exists {
-				return nil, fmt.Errorf("customer with tax ID %s already exists", *update.TaxID)
+				return nil, model.NewConflictError("customer", "tax_id", *update.TaxID, "ya existe un cliente con este identificador fiscal")
 			}
 		}
 	}
 
 	// Aplicar cambios
+	before := customer.AuditSnapshot()
 	customer.UpdateFromUpdate(update)
 
 	// Validar después de los cambios
@@ -142,62 +299,102 @@ func (s *CustomerService) UpdateCustomer(ctx context.Context, update model.Custo
 		return nil, fmt.Errorf("failed to update customer: %w", err)
 	}
 
+	if err := s.audit.customerChange(ctx, model.AuditActionCustomerUpdated, customer.ID, before, customer.AuditSnapshot()); err != nil {
+		return nil, err
+	}
+
 	return customer, nil
 }
 
-// DeleteCustomer deletes a customer (soft delete by deactivating)
-func (s *CustomerService) DeleteCustomer(ctx context.Context, id string) error {
+// DeleteCustomer moves a customer to the trash, hiding it with its vehicles and notes until
+// it is restored or purged after the trash retention. It returns what the deletion affects;
+// with dryRun nothing is deleted. A non-zero expectedVersion must match the current version
+// of the customer.
+func (s *CustomerService) DeleteCustomer(ctx context.Context, id string, expectedVersion int64, dryRun bool) (*model.CustomerDeleteImpact, error) {
+	var impact *model.CustomerDeleteImpact
+	err := retryOnVersionConflict(expectedVersion, func() error {
+		return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			impact, err = s.deleteCustomer(ctx, id, expectedVersion, dryRun)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return impact, nil
+}
+
+// deleteCustomer performs one attempt of DeleteCustomer and audits it
+func (s *CustomerService) deleteCustomer(ctx context.Context, id string, expectedVersion int64, dryRun bool) (*model.CustomerDeleteImpact, error) {
 	// Verificar que el cliente existe
 	customer, err := s.customerRepo.GetByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to get customer for deletion: %w", err)
+		return nil, fmt.Errorf("failed to get customer for deletion: %w", err)
 	}
 
-	// Verificar si el cliente tiene vehículos activos
-	vehicles, err := s.vehicleRepo.ListActiveByCustomer(ctx, id)
+	if err := checkExpectedVersion("customer", id, expectedVersion, customer.Version); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	impact, err := s.deleteImpact(ctx, id, now)
 	if err != nil {
-		return fmt.Errorf("failed to check customer vehicles: %w", err)
+		return nil, err
+	}
+	if dryRun {
+		return impact, nil
 	}
 
-	if len(vehicles) > 0 {
-		// Soft delete - desactivar en lugar de eliminar
-		customer.Deactivate()
-		if err := s.customerRepo.Update(ctx, customer); err != nil {
-			return fmt.Errorf("failed to deactivate customer: %w", err)
-		}
-		return nil
+	if _, err := s.customerRepo.SoftDelete(ctx, id, customer.Version, now); err != nil {
+		return nil, fmt.Errorf("failed to delete customer: %w", err)
 	}
 
-	// Hard delete si no tiene vehículos
-	if err := s.customerRepo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete customer: %w", err)
+	if err := s.audit.customerChange(ctx, model.AuditActionCustomerDeleted, id, customer.AuditSnapshot(), nil); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return impact, nil
 }
 
-// ListCustomers lists customers with filtering and pagination
-func (s *CustomerService) ListCustomers(ctx context.Context, filter model.CustomerFilter) ([]*model.Customer, int, error) {
-	customers, total, err := s.customerRepo.List(ctx, filter)
+// ListCustomers lists customers with filtering and pagination. The page continues after
+// filter.Cursor when set, and PageInfo.Next is the cursor of the following page.
+func (s *CustomerService) ListCustomers(ctx context.Context, filter model.CustomerFilter) ([]*model.Customer, model.PageInfo, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, model.PageInfo{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	customers, page, err := s.customerRepo.List(ctx, filter)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list customers: %w", err)
+		return nil, model.PageInfo{}, fmt.Errorf("failed to list customers: %w", err)
 	}
 
-	return customers, total, nil
+	return customers, page, nil
 }
 
-// SearchCustomers performs advanced search on customers
-func (s *CustomerService) SearchCustomers(ctx context.Context, filter model.CustomerSearchFilter) ([]*model.Customer, error) {
-	if filter.Query == "" {
-		return []*model.Customer{}, nil
+// SearchCustomers performs a ranked search on customers. It returns a page of results with
+// their relevance and highlighted matching fields, and the total number of matches.
+func (s *CustomerService) SearchCustomers(ctx context.Context, filter model.CustomerSearchFilter) ([]*model.CustomerSearchResult, int, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("validation error: %w", err)
+	}
+
+	terms := model.SearchTerms(filter.Query)
+	if len(terms) == 0 {
+		return []*model.CustomerSearchResult{}, 0, nil
 	}
 
-	customers, err := s.customerRepo.Search(ctx, filter)
+	results, total, err := s.customerRepo.Search(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search customers: %w", err)
+		return nil, 0, fmt.Errorf("failed to search customers: %w", err)
+	}
+
+	for _, result := range results {
+		result.Highlights = model.HighlightCustomer(result.Customer, terms, filter.SearchFields)
 	}
 
-	return customers, nil
+	return results, total, nil
 }
 
 // GetCustomerByEmail retrieves a customer by email
@@ -222,34 +419,40 @@ func (s *CustomerService) GetCustomerByTaxID(ctx context.Context, taxID string)
 
 // ActivateCustomer activates a customer
 func (s *CustomerService) ActivateCustomer(ctx context.Context, id string) error {
-	customer, err := s.customerRepo.GetByID(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to get customer: %w", err)
-	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		customer, err := s.customerRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get customer: %w", err)
+		}
 
-	customer.Activate()
+		before := customer.AuditSnapshot()
+		customer.Activate()
 
-	if err := s.customerRepo.Update(ctx, customer); err != nil {
-		return fmt.Errorf("failed to activate customer: %w", err)
-	}
+		if err := s.customerRepo.Update(ctx, customer); err != nil {
+			return fmt.Errorf("failed to activate customer: %w", err)
+		}
 
-	return nil
+		return s.audit.customerChange(ctx, model.AuditActionCustomerActivated, id, before, customer.AuditSnapshot())
+	})
 }
 
 // DeactivateCustomer deactivates a customer
 func (s *CustomerService) DeactivateCustomer(ctx context.Context, id string) error {
-	customer, err := s.customerRepo.GetByID(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to get customer: %w", err)
-	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		customer, err := s.customerRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get customer: %w", err)
+		}
 
-	customer.Deactivate()
+		before := customer.AuditSnapshot()
+		customer.Deactivate()
 
-	if err := s.customerRepo.Update(ctx, customer); err != nil {
-		return fmt.Errorf("failed to deactivate customer: %w", err)
-	}
+		if err := s.customerRepo.Update(ctx, customer); err != nil {
+			return fmt.Errorf("failed to deactivate customer: %w", err)
+		}
 
-	return nil
+		return s.audit.customerChange(ctx, model.AuditActionCustomerDeactivated, id, before, customer.AuditSnapshot())
+	})
 }
 
 // GetCustomerStats retrieves statistics for customers
@@ -300,20 +503,91 @@ func (s *CustomerService) AddCustomerNote(ctx context.Context, create model.Cust
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
 
-	if err := s.customerNoteRepo.Create(ctx, note); err != nil {
-		return nil, fmt.Errorf("failed to create customer note: %w", err)
+	// Nota, historial y auditoría en una sola transacción
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.customerNoteRepo.Create(ctx, note); err != nil {
+			return fmt.Errorf("failed to create customer note: %w", err)
+		}
+
+		// Registrar la nota en el historial del cliente
+		if err := s.historyRepo.Create(ctx, model.NewNoteHistoryItem(note)); err != nil {
+			return fmt.Errorf("failed to record customer note in history: %w", err)
+		}
+
+		// El texto de la nota no se copia a la auditoría: puede ser de un tipo restringido
+		return s.audit.record(ctx, model.NewAuditEntry(model.AuditActionCustomerNoteAdded, model.AuditResourceCustomer, note.CustomerID, note.CustomerID,
+			map[string]model.FieldChange{
+				"note_id":   {After: note.ID},
+				"note_type": {After: note.Type},
+			}))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return note, nil
 }
 
+// GetCustomerHistory retrieves the unified timeline of a customer
+func (s *CustomerService) GetCustomerHistory(ctx context.Context, filter model.CustomerHistoryFilter) ([]*model.CustomerHistoryItem, int, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("validation error: %w", err)
+	}
+
+	// Verificar que el cliente existe (un ID fusionado lleva al superviviente)
+	customer, err := resolveCustomer(ctx, s.customerRepo, filter.CustomerID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("customer not found: %w", err)
+	}
+	filter.CustomerID = customer.ID
+
+	return s.historyRepo.List(ctx, filter)
+}
+
+// GetCustomerAuditLog lists the audit entries matching the filter. The customer is not
+// required to exist: entries of deleted customers remain available.
+func (s *CustomerService) GetCustomerAuditLog(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, int, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("validation error: %w", err)
+	}
+
+	entries, total, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit log: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// ListCustomerNotes lists the notes of a customer, newest first, with cursor pagination
+func (s *CustomerService) ListCustomerNotes(ctx context.Context, filter model.CustomerNoteFilter) ([]*model.CustomerNote, model.PageInfo, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, model.PageInfo{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	// Verificar que el cliente existe (un ID fusionado lleva al superviviente)
+	customer, err := resolveCustomer(ctx, s.customerRepo, filter.CustomerID)
+	if err != nil {
+		return nil, model.PageInfo{}, fmt.Errorf("customer not found: %w", err)
+	}
+	filter.CustomerID = customer.ID
+
+	notes, page, err := s.customerNoteRepo.List(ctx, filter)
+	if err != nil {
+		return nil, model.PageInfo{}, fmt.Errorf("failed to list customer notes: %w", err)
+	}
+
+	return notes, page, nil
+}
+
 // GetCustomerNotes retrieves notes for a customer
 func (s *CustomerService) GetCustomerNotes(ctx context.Context, customerID string, noteType string, limit int) ([]*model.CustomerNote, error) {
-	// Verificar que el cliente existe
-	_, err := s.customerRepo.GetByID(ctx, customerID)
+	// Verificar que el cliente existe (un ID fusionado lleva al superviviente)
+	customer, err := resolveCustomer(ctx, s.customerRepo, customerID)
 	if err != nil {
 		return nil, fmt.Errorf("customer not found: %w", err)
 	}
+	customerID = customer.ID
 
 	if noteType != "" {
 		return s.customerNoteRepo.ListByCustomerAndType(ctx, customerID, noteType)
@@ -326,22 +600,87 @@ func (s *CustomerService) GetCustomerNotes(ctx context.Context, customerID strin
 	return s.customerNoteRepo.ListByCustomer(ctx, customerID)
 }
 
-// SetCustomerPreference sets a preference for a customer
-func (s *CustomerService) SetCustomerPreference(ctx context.Context, customerID string, key string, value interface{}) error {
-	customer, err := s.customerRepo.GetByID(ctx, customerID)
+// IngestCustomerEvents records activity pushed by other services (sales, appointments)
+// into the customer history and stats. The whole batch is applied atomically and
+// events already processed are skipped.
+func (s *CustomerService) IngestCustomerEvents(ctx context.Context, events []*model.CustomerEvent) (*model.CustomerEventIngestResult, error) {
+	now := time.Now()
+	for i, event := range events {
+		if err := event.Validate(); err != nil {
+			var validationErr *model.ValidationError
+			if errors.As(err, &validationErr) {
+				return nil, &model.ValidationError{Field: fmt.Sprintf("events[%d].%s", i, validationErr.Field), Message: validationErr.Message}
+			}
+			return nil, fmt.Errorf("validation error in event %d: %w", i, err)
+		}
+		if event.OccurredAt.IsZero() {
+			event.OccurredAt = now
+		}
+	}
+
+	if len(events) == 0 {
+		return &model.CustomerEventIngestResult{}, nil
+	}
+
+	var result *model.CustomerEventIngestResult
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.eventRepo.Ingest(ctx, events)
+		if err != nil {
+			return fmt.Errorf("failed to ingest customer events: %w", err)
+		}
+		return s.auditIngestedEvents(ctx, result.IngestedEvents)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get customer: %w", err)
+		return nil, err
 	}
 
-	customer.SetPreference(key, value)
+	return result, nil
+}
+
+// auditIngestedEvents records one entry per customer with the IDs of the events applied to it
+func (s *CustomerService) auditIngestedEvents(ctx context.Context, events []*model.CustomerEvent) error {
+	var customerIDs []string
+	eventIDs := make(map[string][]string)
+	for _, event := range events {
+		if _, seen := eventIDs[event.CustomerID]; !seen {
+			customerIDs = append(customerIDs, event.CustomerID)
+		}
+		eventIDs[event.CustomerID] = append(eventIDs[event.CustomerID], event.EventID)
+	}
 
-	if err := s.customerRepo.Update(ctx, customer); err != nil {
-		return fmt.Errorf("failed to update customer preference: %w", err)
+	for _, customerID := range customerIDs {
+		entry := model.NewAuditEntry(model.AuditActionCustomerEventsIngested, model.AuditResourceCustomer, customerID, customerID,
+			map[string]model.FieldChange{"event_ids": {After: eventIDs[customerID]}})
+		if err := s.audit.record(ctx, entry); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// SetCustomerPreference sets a preference for a customer
+func (s *CustomerService) SetCustomerPreference(ctx context.Context, customerID string, key string, value interface{}) error {
+	return retryOnVersionConflict(0, func() error {
+		return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			customer, err := s.customerRepo.GetByID(ctx, customerID)
+			if err != nil {
+				return fmt.Errorf("failed to get customer: %w", err)
+			}
+
+			before := customer.AuditSnapshot()
+			customer.SetPreference(key, value)
+
+			if err := s.customerRepo.Update(ctx, customer); err != nil {
+				return fmt.Errorf("failed to update customer preference: %w", err)
+			}
+
+			return s.audit.customerChange(ctx, model.AuditActionCustomerPreferenceSet, customerID, before, customer.AuditSnapshot())
+		})
+	})
+}
+
 // GetCustomerPreference gets a preference for a customer
 func (s *CustomerService) GetCustomerPreference(ctx context.Context, customerID string, key string) (interface{}, error) {
 	customer, err := s.customerRepo.GetByID(ctx, customerID)
@@ -351,7 +690,7 @@ func (s *CustomerService) GetCustomerPreference(ctx context.Context, customerID
 
 	value, exists := customer.GetPreference(key)
 	if !exists {
-		return nil, fmt.Errorf("preference %s not found for customer", key)
+		return nil, &model.NotFoundError{Resource: "customer_preference", Key: "key", Value: key}
 	}
 
 	return value, nil
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// duplicateCandidatesLimit bounds the customers preselected by the repository before scoring
+const duplicateCandidatesLimit = 100
+
+// FindDuplicateCustomers returns the customers that may duplicate an existing customer
+// (query.CustomerID, whose data replaces query.Probe) or the given data, best first. Without
+// MinScore the warn threshold of the duplicate policy applies.
+func (s *CustomerService) FindDuplicateCustomers(ctx context.Context, query model.DuplicateQuery) ([]*model.DuplicateCandidate, error) {
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	probe := query.Probe
+	if query.CustomerID != "" {
+		customer, err := resolveCustomer(ctx, s.customerRepo, query.CustomerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get customer: %w", err)
+		}
+		probe = model.NewDuplicateProbe(customer)
+	}
+
+	minScore := query.MinScore
+	if minScore == 0 {
+		minScore = s.duplicates.WarnThreshold
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = model.DefaultDuplicateLimit
+	}
+
+	return s.findDuplicates(ctx, probe, minScore, limit)
+}
+
+// findDuplicates preselects candidates in the repository and ranks them
+func (s *CustomerService) findDuplicates(ctx context.Context, probe model.DuplicateProbe, minScore float64, limit int) ([]*model.DuplicateCandidate, error) {
+	if probe.IsEmpty() {
+		return nil, nil
+	}
+
+	candidates, err := s.customerRepo.FindDuplicateCandidates(ctx, probe, duplicateCandidatesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate customers: %w", err)
+	}
+
+	return model.RankDuplicates(probe, candidates, minScore, limit), nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// exportPageSize is the number of customers read per query during an export
+const exportPageSize = 500
+
+// ExportCustomers reads the customers matching filter page by page, following the keyset
+// cursor of the listing, and emits every page before reading the next one, so memory stays
+// bounded by one page whatever the size of the tenant. Pages carry the stats of each
+// customer and, with options.IncludeVehicles, their vehicles. Page and Limit of the filter
+// are ignored and no total is computed.
+func (s *CustomerService) ExportCustomers(ctx context.Context, filter model.CustomerFilter, options model.CustomerExportOptions, emit func([]*model.Customer) error) error {
+	if err := options.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	filter.Page, filter.Limit, filter.Cursor = 0, exportPageSize, nil
+	filter.TotalMode = model.TotalModeNone
+	if err := filter.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	for {
+		customers, page, err := s.customerRepo.List(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list customers: %w", err)
+		}
+
+		if options.IncludeVehicles {
+			if err := s.attachVehicles(ctx, customers); err != nil {
+				return err
+			}
+		}
+
+		if err := emit(customers); err != nil {
+			return err
+		}
+
+		if page.Next == nil {
+			return nil
+		}
+		filter.Cursor = page.Next
+	}
+}
+
+// attachVehicles loads the vehicles of a page of customers with a single query
+func (s *CustomerService) attachVehicles(ctx context.Context, customers []*model.Customer) error {
+	if len(customers) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(customers))
+	byID := make(map[string]*model.Customer, len(customers))
+	for i, customer := range customers {
+		ids[i] = customer.ID
+		byID[customer.ID] = customer
+		customer.Vehicles = []*model.Vehicle{}
+	}
+
+	vehicles, err := s.vehicleRepo.ListByCustomers(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list vehicles: %w", err)
+	}
+	for _, vehicle := range vehicles {
+		if customer, ok := byID[vehicle.CustomerID]; ok {
+			customer.Vehicles = append(customer.Vehicles, vehicle)
+		}
+	}
+
+	return nil
+}
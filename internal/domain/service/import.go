@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// errImportDryRun rolls back the transaction of a dry-run batch once its results are built
+var errImportDryRun = errors.New("import dry run")
+
+// ImportCustomers imports the rows of a file in batches of options.BatchSize, each one in
+// its own tenant transaction, and reports the results of every batch once it is applied.
+// Each row creates a customer, with its vehicle when the row has one, or with
+// options.UpsertKey updates the customer with the same email or tax ID. Invalid or
+// conflicting rows are reported as errors without affecting the rest of their batch; any
+// other error stops the import, keeping the batches already reported. With DryRun every
+// batch is applied and rolled back, so rows do not see the ones of earlier batches.
+func (s *CustomerService) ImportCustomers(ctx context.Context, options model.CustomerImportOptions, rows model.CustomerImportRowReader, report func([]*model.CustomerImportResult) error) (*model.CustomerImportSummary, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	summary := &model.CustomerImportSummary{}
+	batch := make([]*model.CustomerImportRow, 0, options.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		results, err := s.importBatch(ctx, options, batch)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			summary.Add(result)
+		}
+		batch = batch[:0]
+		return report(results)
+	}
+
+	for {
+		row, err := rows.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		batch = append(batch, row)
+		if len(batch) == options.BatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// importBatch imports a batch of rows in one transaction
+func (s *CustomerService) importBatch(ctx context.Context, options model.CustomerImportOptions, rows []*model.CustomerImportRow) ([]*model.CustomerImportResult, error) {
+	var results []*model.CustomerImportResult
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		results = make([]*model.CustomerImportResult, 0, len(rows))
+		for _, row := range rows {
+			result, err := s.importCustomerRow(ctx, options.UpsertKey, row)
+			if err != nil {
+				return fmt.Errorf("failed to import row at line %d: %w", row.Line, err)
+			}
+			results = append(results, result)
+		}
+		if options.DryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportDryRun) {
+		return nil, err
+	}
+
+	// Los IDs generados en un ensayo se han revertido
+	if options.DryRun {
+		for _, result := range results {
+			if result.Status == model.ImportStatusCreated {
+				result.CustomerID = ""
+			}
+			result.VehicleID = ""
+		}
+	}
+
+	return results, nil
+}
+
+// importCustomerRow imports one row. Row-level problems are returned as an error result;
+// the returned error is reserved for failures that abort the batch.
+func (s *CustomerService) importCustomerRow(ctx context.Context, upsertKey string, row *model.CustomerImportRow) (*model.CustomerImportResult, error) {
+	if row.Err != nil {
+		return model.NewCustomerImportError(row.Line, row.Err), nil
+	}
+	if row.IsEmpty() {
+		return &model.CustomerImportResult{Line: row.Line, Status: model.ImportStatusSkipped, Message: "fila vacía"}, nil
+	}
+
+	// Cada fila va en su propio savepoint: si falla, incluso por una violación de unicidad
+	// detectada por la base de datos, solo se revierte lo que escribió la fila
+	var result *model.CustomerImportResult
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.importCustomer(ctx, upsertKey, row)
+		return err
+	})
+	if err != nil {
+		if model.IsCustomerImportRowError(err) {
+			return model.NewCustomerImportError(row.Line, err), nil
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// importCustomer creates or updates the customer of a row. Every check runs before the
+// first write; a row that still fails while writing is undone by its savepoint.
+func (s *CustomerService) importCustomer(ctx context.Context, upsertKey string, row *model.CustomerImportRow) (*model.CustomerImportResult, error) {
+	create, vehicleCreate, err := row.CustomerCreate()
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findImportTarget(ctx, upsertKey, row.UpsertValue(upsertKey))
+	if err != nil {
+		return nil, err
+	}
+
+	// El vehículo ya registrado para este mismo cliente no se vuelve a crear
+	var vehicle *model.Vehicle
+	if vehicleCreate != nil {
+		vehicle = model.NewVehicle(*vehicleCreate)
+		if report := model.ValidateNewVehicles([]*model.Vehicle{vehicle}); report != nil {
+			return nil, report
+		}
+		registered, err := s.checkImportVehicle(ctx, vehicle, existing)
+		if err != nil {
+			return nil, err
+		}
+		if registered {
+			vehicle = nil
+		}
+	}
+
+	result := &model.CustomerImportResult{Line: row.Line}
+
+	if existing == nil {
+		if vehicle != nil {
+			create.Vehicles = []model.VehicleCreate{*vehicleCreate}
+		}
+		customer, vehicles, _, err := s.newCustomer(ctx, create)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.createAuditedCustomer(ctx, customer, vehicles); err != nil {
+			return nil, err
+		}
+
+		result.Status, result.CustomerID = model.ImportStatusCreated, customer.ID
+		if len(vehicles) > 0 {
+			result.VehicleID = vehicles[0].ID
+		}
+		return result, nil
+	}
+
+	result.Status, result.CustomerID = model.ImportStatusSkipped, existing.ID
+
+	// Solo se actualiza si la fila cambia algún dato del cliente
+	update := row.CustomerUpdate(existing.ID, create)
+	updated := *existing
+	updated.UpdateFromUpdate(update)
+	if len(model.DiffSnapshots(existing.AuditSnapshot(), updated.AuditSnapshot())) > 0 {
+		if _, err := s.updateCustomer(ctx, update); err != nil {
+			return nil, err
+		}
+		result.Status = model.ImportStatusUpdated
+	}
+
+	if vehicle != nil {
+		vehicle.CustomerID = existing.ID
+		if err := s.createImportVehicle(ctx, vehicle); err != nil {
+			return nil, err
+		}
+		result.Status, result.VehicleID = model.ImportStatusUpdated, vehicle.ID
+	}
+
+	return result, nil
+}
+
+// findImportTarget returns the customer a row updates, or nil when the row creates one
+func (s *CustomerService) findImportTarget(ctx context.Context, upsertKey, value string) (*model.Customer, error) {
+	if upsertKey == "" || value == "" {
+		return nil, nil
+	}
+
+	var customer *model.Customer
+	var err error
+	switch upsertKey {
+	case model.ImportUpsertByEmail:
+		customer, err = s.customerRepo.GetByEmail(ctx, value)
+	case model.ImportUpsertByTaxID:
+		customer, err = s.customerRepo.GetByTaxID(ctx, value)
+	}
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find customer by %s: %w", upsertKey, err)
+	}
+	return customer, nil
+}
+
+// checkImportVehicle checks that the VIN and license plate of a row vehicle are free. It
+// reports whether the vehicle is already registered for the customer being updated, which
+// requires every identifier of the row to match the same vehicle of that customer; a row that
+// shares only some of them with a vehicle of the customer is a conflict.
+func (s *CustomerService) checkImportVehicle(ctx context.Context, vehicle *model.Vehicle, existing *model.Customer) (bool, error) {
+	lookups := []struct {
+		field, message, mismatch string
+		value                    *string
+		get                      func(context.Context, string) (*model.Vehicle, error)
+		exists                   func(context.Context, string, *string) (bool, error)
+	}{
+		{"vin", "ya existe un vehículo con este VIN", "el vehículo registrado del cliente tiene otro VIN",
+			vehicle.VIN, s.vehicleRepo.GetByVIN, s.vehicleRepo.ExistsByVIN},
+		{"license_plate", "ya existe un vehículo con esta placa", "el vehículo registrado del cliente tiene otra placa",
+			vehicle.LicensePlate, s.vehicleRepo.GetByLicensePlate, s.vehicleRepo.ExistsByLicensePlate},
+	}
+
+	// Se comprueban ambos valores: un VIN propio no oculta una placa de otro cliente
+	var registered *model.Vehicle
+	var freeField, freeValue, freeMismatch string
+	for _, lookup := range lookups {
+		if lookup.value == nil || *lookup.value == "" {
+			continue
+		}
+		found, err := lookup.get(ctx, *lookup.value)
+		if errors.Is(err, model.ErrNotFound) {
+			// Los vehículos de clientes en la papelera no se encuentran, pero siguen reservando el valor
+			reserved, err := lookup.exists(ctx, *lookup.value, nil)
+			if err != nil {
+				return false, fmt.Errorf("failed to check vehicle %s: %w", lookup.field, err)
+			}
+			if reserved {
+				return false, model.NewConflictError("vehicle", lookup.field, *lookup.value, lookup.message)
+			}
+			if freeField == "" {
+				freeField, freeValue, freeMismatch = lookup.field, *lookup.value, lookup.mismatch
+			}
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to check vehicle %s: %w", lookup.field, err)
+		}
+		if existing == nil || found.CustomerID != existing.ID {
+			return false, model.NewConflictError("vehicle", lookup.field, *lookup.value, lookup.message)
+		}
+		// El VIN y la placa de dos vehículos distintos del cliente no describen un vehículo
+		if registered != nil && registered.ID != found.ID {
+			return false, model.NewConflictError("vehicle", lookup.field, *lookup.value, lookup.mismatch)
+		}
+		registered = found
+	}
+
+	// Un valor propio con otro libre no es el mismo vehículo: no se omite ni se crea un segundo
+	if registered != nil && freeField != "" {
+		return false, model.NewConflictError("vehicle", freeField, freeValue, freeMismatch)
+	}
+
+	return registered != nil, nil
+}
+
+// createImportVehicle adds the vehicle of a row to an existing customer and audits it
+func (s *CustomerService) createImportVehicle(ctx context.Context, vehicle *model.Vehicle) error {
+	if err := s.vehicleRepo.CreateBatch(ctx, []*model.Vehicle{vehicle}); err != nil {
+		var report *model.VehicleValidationReport
+		if errors.As(err, &report) {
+			return report
+		}
+		return fmt.Errorf("failed to create vehicle: %w", err)
+	}
+	return s.audit.vehicleChange(ctx, model.AuditActionVehicleCreated, vehicle, nil, vehicle.AuditSnapshot())
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+)
+
+// fakeVehicleRepo finds vehicles by VIN and license plate
+type fakeVehicleRepo struct {
+	repository.VehicleRepository
+	vehicles []*model.Vehicle
+	trashed  []*model.Vehicle // Vehículos de clientes en la papelera: solo los ven los Exists*
+}
+
+func (r *fakeVehicleRepo) GetByVIN(ctx context.Context, vin string) (*model.Vehicle, error) {
+	for _, vehicle := range r.vehicles {
+		if vehicle.VIN != nil && *vehicle.VIN == vin {
+			return vehicle, nil
+		}
+	}
+	return nil, &model.NotFoundError{Resource: "vehicle", Key: "vin", Value: vin}
+}
+
+func (r *fakeVehicleRepo) GetByLicensePlate(ctx context.Context, licensePlate string) (*model.Vehicle, error) {
+	for _, vehicle := range r.vehicles {
+		if vehicle.LicensePlate != nil && *vehicle.LicensePlate == licensePlate {
+			return vehicle, nil
+		}
+	}
+	return nil, &model.NotFoundError{Resource: "vehicle", Key: "license_plate", Value: licensePlate}
+}
+
+func (r *fakeVehicleRepo) ExistsByVIN(ctx context.Context, vin string, excludeID *string) (bool, error) {
+	for _, vehicle := range append(r.vehicles, r.trashed...) {
+		if vehicle.VIN != nil && *vehicle.VIN == vin {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeVehicleRepo) ExistsByLicensePlate(ctx context.Context, licensePlate string, excludeID *string) (bool, error) {
+	for _, vehicle := range append(r.vehicles, r.trashed...) {
+		if vehicle.LicensePlate != nil && *vehicle.LicensePlate == licensePlate {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestCheckImportVehicle(t *testing.T) {
+	vin, otherVIN, secondVIN := "1HGCM82633A004352", "2HGCM82633A004353", "4HGCM82633A004355"
+	plate, otherPlate, secondPlate := "ABC-123", "XYZ-789", "SEC-456"
+	existing := &model.Customer{ID: "c-1"}
+
+	trashedPlate := "DEL-404"
+	s := &CustomerService{vehicleRepo: &fakeVehicleRepo{
+		vehicles: []*model.Vehicle{
+			{ID: "v-1", CustomerID: "c-1", VIN: &vin, LicensePlate: &plate},
+			{ID: "v-2", CustomerID: "c-2", VIN: &otherVIN, LicensePlate: &otherPlate},
+			{ID: "v-4", CustomerID: "c-1", VIN: &secondVIN, LicensePlate: &secondPlate},
+		},
+		trashed: []*model.Vehicle{{ID: "v-3", CustomerID: "c-3", LicensePlate: &trashedPlate}},
+	}}
+
+	newVIN, newPlate := "3HGCM82633A004354", "NEW-001"
+	tests := []struct {
+		name       string
+		vin, plate *string
+		existing   *model.Customer
+		registered bool
+		conflict   string
+	}{
+		{"free values", &newVIN, &newPlate, existing, false, ""},
+		{"same vehicle of the customer", &vin, &plate, existing, true, ""},
+		{"own VIN without a plate", &vin, nil, existing, true, ""},
+		{"own VIN with a new free plate", &vin, &newPlate, existing, false, "license_plate"},
+		{"own plate with a new free VIN", &newVIN, &plate, existing, false, "vin"},
+		{"VIN and plate of two vehicles of the customer", &vin, &secondPlate, existing, false, "license_plate"},
+		{"own VIN with the plate of another customer", &vin, &otherPlate, existing, false, "license_plate"},
+		{"free VIN with the plate of another customer", &newVIN, &otherPlate, existing, false, "license_plate"},
+		{"VIN of another customer", &otherVIN, &newPlate, existing, false, "vin"},
+		{"new customer with a registered plate", nil, &plate, nil, false, "license_plate"},
+		{"plate of a customer in the trash", &newVIN, &trashedPlate, existing, false, "license_plate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registered, err := s.checkImportVehicle(context.Background(), &model.Vehicle{VIN: tt.vin, LicensePlate: tt.plate}, tt.existing)
+			if tt.conflict != "" {
+				var conflictErr *model.ConflictError
+				if !errors.As(err, &conflictErr) || conflictErr.Field != tt.conflict {
+					t.Fatalf("expected a %s conflict, got %v", tt.conflict, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if registered != tt.registered {
+				t.Errorf("expected registered=%v, got %v", tt.registered, registered)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+)
+
+// errMergeDryRun rolls back the transaction of a dry-run merge once the result is built
+var errMergeDryRun = errors.New("merge dry run")
+
+// MergeCustomers consolidates duplicate profiles into the survivor in one tenant transaction:
+// vehicles, notes, history and events move to the survivor, preferences and stats are
+// combined, the duplicates are deleted and their IDs redirected to the survivor. With
+// DryRun the whole merge is performed and rolled back, returning the resulting profile.
+func (s *CustomerService) MergeCustomers(ctx context.Context, merge model.CustomerMerge) (*model.CustomerMergeResult, error) {
+	if err := merge.Validate(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	var result *model.CustomerMergeResult
+	err := retryOnVersionConflict(merge.ExpectedVersion, func() error {
+		return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			result, err = s.mergeCustomers(ctx, merge)
+			if err == nil && merge.DryRun {
+				return errMergeDryRun
+			}
+			return err
+		})
+	})
+	if err != nil && !errors.Is(err, errMergeDryRun) {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// mergeCustomers performs one attempt of MergeCustomers and audits it
+func (s *CustomerService) mergeCustomers(ctx context.Context, merge model.CustomerMerge) (*model.CustomerMergeResult, error) {
+	survivor, err := s.customerRepo.GetByID(ctx, merge.SurvivorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get surviving customer: %w", err)
+	}
+	if err := checkExpectedVersion("customer", survivor.ID, merge.ExpectedVersion, survivor.Version); err != nil {
+		return nil, err
+	}
+	readVersion := survivor.Version
+
+	result := &model.CustomerMergeResult{MergedIDs: merge.DuplicateIDs, DryRun: merge.DryRun}
+
+	duplicates := make([]*model.Customer, 0, len(merge.DuplicateIDs))
+	for _, id := range merge.DuplicateIDs {
+		duplicate, err := s.customerRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get duplicate customer: %w", err)
+		}
+		duplicates = append(duplicates, duplicate)
+
+		notes, err := s.customerNoteRepo.CountByCustomer(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count duplicate notes: %w", err)
+		}
+		result.NotesMoved += int(notes)
+	}
+
+	// Todos los vehículos, también los inactivos: Merge los mueve todos (ListByCustomer se limita a 100 activos)
+	movedVehicles, err := s.vehicleRepo.ListByCustomers(ctx, merge.DuplicateIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list duplicate vehicles: %w", err)
+	}
+	result.VehiclesMoved = len(movedVehicles)
+
+	// Estadísticas combinadas; se leen antes de que el borrado de los duplicados elimine las suyas
+	stats, survivorHasStats, err := s.mergedStats(ctx, survivor.ID, merge.DuplicateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	before := survivor.AuditSnapshot()
+	survivor.MergeFrom(duplicates, merge.FieldChoices)
+	if err := survivor.Validate(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	// Los duplicados se borran antes de actualizar al superviviente: así puede tomar su email o identificador fiscal
+	if err := s.customerRepo.Merge(ctx, survivor.ID, duplicates); err != nil {
+		return nil, fmt.Errorf("failed to merge customers: %w", err)
+	}
+	if err := s.customerRepo.Update(ctx, survivor); err != nil {
+		return nil, fmt.Errorf("failed to update surviving customer: %w", err)
+	}
+
+	if stats != nil {
+		stats.CustomerID = survivor.ID
+		if survivorHasStats {
+			err = s.statsRepo.Update(ctx, stats)
+		} else {
+			err = s.statsRepo.Create(ctx, stats)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to save merged stats: %w", err)
+		}
+	}
+
+	if err := s.historyRepo.Create(ctx, model.NewMergeHistoryItem(survivor, duplicates)); err != nil {
+		return nil, fmt.Errorf("failed to record merge in history: %w", err)
+	}
+
+	if err := s.auditMerge(ctx, survivor, before, duplicates, movedVehicles); err != nil {
+		return nil, err
+	}
+
+	survivor.Vehicles, err = s.vehicleRepo.ListByCustomers(ctx, []string{survivor.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load merged vehicles: %w", err)
+	}
+	survivor.Stats = stats
+
+	// En simulación nada se confirma: la versión sigue siendo la leída
+	if merge.DryRun {
+		survivor.Version = readVersion
+	}
+
+	result.Customer = survivor
+	return result, nil
+}
+
+// mergedStats folds the stats of the duplicates into the survivor's. It returns nil when no
+// profile has stats, and whether the survivor already had a stats row.
+func (s *CustomerService) mergedStats(ctx context.Context, survivorID string, duplicateIDs []string) (*model.CustomerStats, bool, error) {
+	stats, err := s.statsRepo.GetByCustomerID(ctx, survivorID)
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		return nil, false, fmt.Errorf("failed to get customer stats: %w", err)
+	}
+	survivorHasStats := err == nil
+
+	for _, id := range duplicateIDs {
+		duplicateStats, err := s.statsRepo.GetByCustomerID(ctx, id)
+		if errors.Is(err, model.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get duplicate stats: %w", err)
+		}
+		if stats == nil {
+			stats = &model.CustomerStats{CustomerID: survivorID}
+		}
+		stats.Merge(duplicateStats)
+	}
+
+	return stats, survivorHasStats, nil
+}
+
+// auditMerge records the merge on the survivor, on each duplicate and on each moved vehicle
+func (s *CustomerService) auditMerge(ctx context.Context, survivor *model.Customer, before model.AuditSnapshot, duplicates []*model.Customer, movedVehicles []*model.Vehicle) error {
+	mergedIDs := make([]string, len(duplicates))
+	for i, duplicate := range duplicates {
+		mergedIDs[i] = duplicate.ID
+	}
+
+	changes := model.DiffSnapshots(before, survivor.AuditSnapshot())
+	changes["merged_customer_ids"] = model.FieldChange{After: mergedIDs}
+	if err := s.audit.record(ctx, model.NewAuditEntry(model.AuditActionCustomerMerged, model.AuditResourceCustomer, survivor.ID, survivor.ID, changes)); err != nil {
+		return err
+	}
+
+	for _, duplicate := range duplicates {
+		changes := model.DiffSnapshots(duplicate.AuditSnapshot(), nil)
+		changes["survivor_id"] = model.FieldChange{After: survivor.ID}
+		if err := s.audit.record(ctx, model.NewAuditEntry(model.AuditActionCustomerMerged, model.AuditResourceCustomer, duplicate.ID, duplicate.ID, changes)); err != nil {
+			return err
+		}
+	}
+
+	for _, vehicle := range movedVehicles {
+		before := vehicle.AuditSnapshot()
+		vehicle.CustomerID = survivor.ID
+		if err := s.audit.vehicleChange(ctx, model.AuditActionVehicleUpdated, vehicle, before, vehicle.AuditSnapshot()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveCustomer loads a customer following the redirect of merged IDs, so lookups of a
+// profile merged into another one return the survivor
+func resolveCustomer(ctx context.Context, customerRepo repository.CustomerRepository, id string) (*model.Customer, error) {
+	customer, err := customerRepo.GetByID(ctx, id)
+	if !errors.Is(err, model.ErrNotFound) {
+		return customer, err
+	}
+
+	survivorID, resolveErr := customerRepo.ResolveMergedID(ctx, id)
+	if resolveErr != nil {
+		if errors.Is(resolveErr, model.ErrNotFound) {
+			return nil, err
+		}
+		return nil, resolveErr
+	}
+
+	return customerRepo.GetByID(ctx, survivorID)
+}
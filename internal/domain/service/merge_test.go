@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+)
+
+// fakeMergeCustomerRepo holds the customers of a merge and moves the vehicles of the
+// duplicates to the survivor as the PostgreSQL repository does
+type fakeMergeCustomerRepo struct {
+	repository.CustomerRepository
+	customers map[string]*model.Customer
+	vehicles  *fakeVehicleRepo
+}
+
+func (r *fakeMergeCustomerRepo) GetByID(ctx context.Context, id string) (*model.Customer, error) {
+	if customer, ok := r.customers[id]; ok {
+		return customer, nil
+	}
+	return nil, model.NewNotFoundError("customer", id)
+}
+
+func (r *fakeMergeCustomerRepo) Merge(ctx context.Context, survivorID string, duplicates []*model.Customer) error {
+	for _, duplicate := range duplicates {
+		for _, vehicle := range r.vehicles.vehicles {
+			if vehicle.CustomerID == duplicate.ID {
+				vehicle.CustomerID = survivorID
+			}
+		}
+		delete(r.customers, duplicate.ID)
+	}
+	return nil
+}
+
+func (r *fakeMergeCustomerRepo) Update(ctx context.Context, customer *model.Customer) error {
+	customer.Version++
+	return nil
+}
+
+// ListByCustomers returns every vehicle of the customers, active or not
+func (r *fakeVehicleRepo) ListByCustomers(ctx context.Context, customerIDs []string) ([]*model.Vehicle, error) {
+	var vehicles []*model.Vehicle
+	for _, id := range customerIDs {
+		for _, vehicle := range r.vehicles {
+			if vehicle.CustomerID == id {
+				copied := *vehicle
+				vehicles = append(vehicles, &copied)
+			}
+		}
+	}
+	return vehicles, nil
+}
+
+// fakeMergeNoteRepo has no notes
+type fakeMergeNoteRepo struct {
+	repository.CustomerNoteRepository
+}
+
+func (r *fakeMergeNoteRepo) CountByCustomer(ctx context.Context, customerID string) (int64, error) {
+	return 0, nil
+}
+
+// fakeMergeStatsRepo has no stats
+type fakeMergeStatsRepo struct {
+	repository.CustomerStatsRepository
+}
+
+func (r *fakeMergeStatsRepo) GetByCustomerID(ctx context.Context, customerID string) (*model.CustomerStats, error) {
+	return nil, model.NewNotFoundError("customer_stats", customerID)
+}
+
+// fakeMergeHistoryRepo accepts the timeline items
+type fakeMergeHistoryRepo struct {
+	repository.CustomerHistoryRepository
+}
+
+func (r *fakeMergeHistoryRepo) Create(ctx context.Context, item *model.CustomerHistoryItem) error {
+	return nil
+}
+
+// fakeMergeAuditRepo keeps the recorded entries
+type fakeMergeAuditRepo struct {
+	repository.AuditLogRepository
+	entries []*model.AuditEntry
+}
+
+func (r *fakeMergeAuditRepo) Create(ctx context.Context, entry *model.AuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// fakeMergeTransactor runs fn without a transaction
+type fakeMergeTransactor struct{}
+
+func (fakeMergeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// TestMergeCustomersMovesEveryVehicle checks that a merge reports, audits and returns every
+// vehicle of the duplicates, beyond the first 100 and including the inactive ones
+func TestMergeCustomersMovesEveryVehicle(t *testing.T) {
+	vehicles := &fakeVehicleRepo{vehicles: []*model.Vehicle{{ID: "v-survivor", CustomerID: "c-1", IsActive: true}}}
+	for i := 0; i < 150; i++ {
+		vehicles.vehicles = append(vehicles.vehicles, &model.Vehicle{ID: fmt.Sprintf("v-%d", i), CustomerID: "c-2", IsActive: i%10 != 0})
+	}
+	customers := &fakeMergeCustomerRepo{
+		customers: map[string]*model.Customer{
+			"c-1": {ID: "c-1", FirstName: "Ana", LastName: "García", CustomerType: model.CustomerTypeIndividual, Version: 1},
+			"c-2": {ID: "c-2", FirstName: "Ana", LastName: "Garcia", CustomerType: model.CustomerTypeIndividual, Version: 1},
+		},
+		vehicles: vehicles,
+	}
+	audit := &fakeMergeAuditRepo{}
+	s := NewCustomerService(customers, vehicles, &fakeMergeNoteRepo{}, &fakeMergeStatsRepo{}, &fakeMergeHistoryRepo{}, nil, audit, fakeMergeTransactor{}, model.DuplicatePolicy{}, 0)
+
+	result, err := s.MergeCustomers(context.Background(), model.CustomerMerge{SurvivorID: "c-1", DuplicateIDs: []string{"c-2"}})
+	if err != nil {
+		t.Fatalf("MergeCustomers: %v", err)
+	}
+
+	if result.VehiclesMoved != 150 {
+		t.Errorf("expected 150 vehicles moved, got %d", result.VehiclesMoved)
+	}
+	if len(result.Customer.Vehicles) != 151 {
+		t.Errorf("expected the survivor with 151 vehicles, got %d", len(result.Customer.Vehicles))
+	}
+
+	audited := 0
+	for _, entry := range audit.entries {
+		if entry.ResourceType == model.AuditResourceVehicle {
+			if entry.CustomerID != "c-1" {
+				t.Errorf("expected the vehicle %s audited on the survivor, got %s", entry.ResourceID, entry.CustomerID)
+			}
+			audited++
+		}
+	}
+	if audited != 150 {
+		t.Errorf("expected 150 vehicle audit entries, got %d", audited)
+	}
+}
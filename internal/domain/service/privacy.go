@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// AnonymizeCustomer irreversibly replaces the personal data of a customer (right to erasure):
+// its profile, the text of its notes and of its note and merge history, the notes of its
+// vehicles and the personal values recorded in its audit entries and in those of the
+// duplicates merged into it. The customer keeps its ID,
+// type, stats, events and order history for accounting. A non-zero expectedVersion must
+// match the current version of the customer.
+func (s *CustomerService) AnonymizeCustomer(ctx context.Context, id string, expectedVersion int64) (*model.CustomerAnonymization, error) {
+	var result *model.CustomerAnonymization
+	err := retryOnVersionConflict(expectedVersion, func() error {
+		return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			result, err = s.anonymizeCustomer(ctx, id, expectedVersion)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// anonymizeCustomer performs one attempt of AnonymizeCustomer and audits it
+func (s *CustomerService) anonymizeCustomer(ctx context.Context, id string, expectedVersion int64) (*model.CustomerAnonymization, error) {
+	customer, err := s.customerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer for anonymization: %w", err)
+	}
+
+	if err := checkExpectedVersion("customer", id, expectedVersion, customer.Version); err != nil {
+		return nil, err
+	}
+
+	before := customer.AuditSnapshot()
+	customer.Anonymize(time.Now())
+	if err := s.customerRepo.Update(ctx, customer); err != nil {
+		return nil, fmt.Errorf("failed to anonymize customer: %w", err)
+	}
+
+	result := &model.CustomerAnonymization{Customer: customer}
+	if result.Notes, err = s.customerNoteRepo.ReplaceTextByCustomer(ctx, id, model.AnonymizedText); err != nil {
+		return nil, fmt.Errorf("failed to anonymize customer notes: %w", err)
+	}
+	if result.HistoryEntries, err = s.historyRepo.ReplaceDescriptions(ctx, id, model.AnonymizedHistoryTypes, model.AnonymizedText); err != nil {
+		return nil, fmt.Errorf("failed to anonymize customer history: %w", err)
+	}
+	if result.VehicleNotes, err = s.vehicleRepo.ClearNotesByCustomer(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to clear vehicle notes: %w", err)
+	}
+
+	// Las entradas anteriores se suprimen antes de registrar la nueva, que ya no contiene los datos
+	if result.AuditEntries, err = s.auditRepo.RedactCustomer(ctx, id, model.CustomerPersonalFields, model.AnonymizedText); err != nil {
+		return nil, fmt.Errorf("failed to redact customer audit entries: %w", err)
+	}
+
+	changes := model.AnonymizationChanges(before, customer.AuditSnapshot())
+	changes["anonymized_notes"] = model.FieldChange{After: result.Notes}
+	changes["anonymized_history_entries"] = model.FieldChange{After: result.HistoryEntries}
+	changes["cleared_vehicle_notes"] = model.FieldChange{After: result.VehicleNotes}
+	changes["redacted_audit_entries"] = model.FieldChange{After: result.AuditEntries}
+	if err := s.audit.record(ctx, model.NewAuditEntry(model.AuditActionCustomerAnonymized, model.AuditResourceCustomer, id, id, changes)); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ExportCustomerData gathers the personal data held about a customer (right of access): its
+// profile with preferences, all its vehicles and notes, and its stats. Notes of the types in
+// excludeNoteTypes (restricted for the caller) are left out. The ID of a merged customer
+// returns the data of the customer it was merged into. The export is audited.
+func (s *CustomerService) ExportCustomerData(ctx context.Context, id string, excludeNoteTypes []string) (*model.CustomerDataExport, error) {
+	var export *model.CustomerDataExport
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		export, err = s.exportCustomerData(ctx, id, excludeNoteTypes)
+		if err != nil {
+			return err
+		}
+
+		return s.audit.record(ctx, model.NewAuditEntry(model.AuditActionCustomerDataExported, model.AuditResourceCustomer, export.Customer.ID, export.Customer.ID,
+			map[string]model.FieldChange{
+				"vehicles": {After: len(export.Vehicles)},
+				"notes":    {After: len(export.Notes)},
+			}))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// exportCustomerData reads the data of ExportCustomerData
+func (s *CustomerService) exportCustomerData(ctx context.Context, id string, excludeNoteTypes []string) (*model.CustomerDataExport, error) {
+	customer, err := resolveCustomer(ctx, s.customerRepo, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	id = customer.ID
+
+	export := &model.CustomerDataExport{
+		ExportedAt: time.Now().UTC(),
+		Customer:   customer,
+		Vehicles:   []*model.Vehicle{},
+		Notes:      []*model.CustomerNote{},
+	}
+
+	// ListByCustomers no limita el número de vehículos, a diferencia de ListByCustomer
+	vehicles, err := s.vehicleRepo.ListByCustomers(ctx, []string{id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load customer vehicles: %w", err)
+	}
+	export.Vehicles = append(export.Vehicles, vehicles...)
+
+	// Todas las notas, página a página (ListByCustomer se limita a 100)
+	filter := model.CustomerNoteFilter{CustomerID: id, ExcludeTypes: excludeNoteTypes, Limit: exportPageSize, TotalMode: model.TotalModeNone}
+	for {
+		notes, page, err := s.customerNoteRepo.List(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load customer notes: %w", err)
+		}
+		export.Notes = append(export.Notes, notes...)
+
+		if page.Next == nil {
+			break
+		}
+		filter.Cursor = page.Next
+	}
+
+	// Las estadísticas se exportan tal como están guardadas, sin recalcularlas
+	hasStats, err := s.statsRepo.Exists(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check customer stats: %w", err)
+	}
+	if hasStats {
+		if export.Stats, err = s.statsRepo.GetByCustomerID(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to load customer stats: %w", err)
+		}
+	}
+
+	return export, nil
+}
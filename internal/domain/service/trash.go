@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// purgeBatchSize is the number of customers purged per transaction
+const purgeBatchSize = 100
+
+// PurgeAt returns when the purge permanently deletes a customer that went to the trash at deletedAt
+func (s *CustomerService) PurgeAt(deletedAt time.Time) time.Time {
+	return deletedAt.Add(s.trashRetention)
+}
+
+// PurgeCutoff returns the deletion time before which the customers in the trash are due for purge at now
+func (s *CustomerService) PurgeCutoff(now time.Time) time.Time {
+	return now.Add(-s.trashRetention)
+}
+
+// deleteImpact counts what deleting a customer at deletedAt hides and later purges
+func (s *CustomerService) deleteImpact(ctx context.Context, id string, deletedAt time.Time) (*model.CustomerDeleteImpact, error) {
+	impact := &model.CustomerDeleteImpact{CustomerID: id, PurgeAt: s.PurgeAt(deletedAt)}
+
+	var err error
+	if impact.Vehicles, err = s.vehicleRepo.CountByCustomer(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to count customer vehicles: %w", err)
+	}
+	if impact.Notes, err = s.customerNoteRepo.CountByCustomer(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to count customer notes: %w", err)
+	}
+	if impact.HistoryEntries, err = s.historyRepo.CountByCustomer(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to count customer history: %w", err)
+	}
+	if impact.HasStats, err = s.statsRepo.Exists(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to check customer stats: %w", err)
+	}
+
+	return impact, nil
+}
+
+// RestoreCustomer takes a customer out of the trash with its vehicles and notes. It fails
+// with a ConflictError if another customer registered its email or tax ID meanwhile. A
+// non-zero expectedVersion must match the current version of the deleted customer.
+func (s *CustomerService) RestoreCustomer(ctx context.Context, id string, expectedVersion int64) (*model.Customer, error) {
+	var customer *model.Customer
+	err := retryOnVersionConflict(expectedVersion, func() error {
+		return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			customer, err = s.restoreCustomer(ctx, id, expectedVersion)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// restoreCustomer performs one attempt of RestoreCustomer and audits it
+func (s *CustomerService) restoreCustomer(ctx context.Context, id string, expectedVersion int64) (*model.Customer, error) {
+	deleted, err := s.customerRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted customer: %w", err)
+	}
+
+	if err := checkExpectedVersion("customer", id, expectedVersion, deleted.Version); err != nil {
+		return nil, err
+	}
+
+	// El email y el identificador fiscal solo son únicos fuera de la papelera
+	if deleted.Email != nil && *deleted.Email != "" {
+		exists, err := s.customerRepo.ExistsByEmail(ctx, *deleted.Email, &id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check email existence: %w", err)
+		}
+		if exists {
+			return nil, model.NewConflictError("customer", "email", *deleted.Email, "ya existe un cliente con este email")
+		}
+	}
+	if deleted.TaxID != nil && *deleted.TaxID != "" {
+		exists, err := s.customerRepo.ExistsByTaxID(ctx, *deleted.TaxID, &id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check tax ID existence: %w", err)
+		}
+		if exists {
+			return nil, model.NewConflictError("customer", "tax_id", *deleted.TaxID, "ya existe un cliente con este identificador fiscal")
+		}
+	}
+
+	customer, err := s.customerRepo.Restore(ctx, id, deleted.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore customer: %w", err)
+	}
+
+	if err := s.audit.customerChange(ctx, model.AuditActionCustomerRestored, id, nil, customer.AuditSnapshot()); err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// ListDeletedCustomers lists the customers in the trash, most recently deleted first unless
+// the filter sorts them otherwise
+func (s *CustomerService) ListDeletedCustomers(ctx context.Context, filter model.CustomerFilter) ([]*model.Customer, model.PageInfo, error) {
+	filter.Deleted = true
+	if err := filter.Validate(); err != nil {
+		return nil, model.PageInfo{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	customers, page, err := s.customerRepo.List(ctx, filter)
+	if err != nil {
+		return nil, model.PageInfo{}, fmt.Errorf("failed to list deleted customers: %w", err)
+	}
+
+	return customers, page, nil
+}
+
+// PurgeDeletedCustomers permanently deletes the customers of the tenant of ctx that went to
+// the trash before before, with their vehicles, notes, stats and history, in batches of one
+// transaction each. Every purged customer is audited. It returns how many were purged.
+func (s *CustomerService) PurgeDeletedCustomers(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+	for {
+		var ids []string
+		err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			ids, err = s.customerRepo.PurgeDeleted(ctx, before, purgeBatchSize)
+			if err != nil {
+				return fmt.Errorf("failed to purge deleted customers: %w", err)
+			}
+
+			for _, id := range ids {
+				if err := s.audit.customerChange(ctx, model.AuditActionCustomerPurged, id, nil, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return purged, err
+		}
+
+		purged += len(ids)
+		if len(ids) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
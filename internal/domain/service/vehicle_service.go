@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
@@ -12,16 +13,22 @@ import (
 type VehicleService struct {
 	vehicleRepo  repository.VehicleRepository
 	customerRepo repository.CustomerRepository
+	transactor   repository.Transactor
+	audit        auditLog
 }
 
 // NewVehicleService creates a new vehicle service
 func NewVehicleService(
 	vehicleRepo repository.VehicleRepository,
 	customerRepo repository.CustomerRepository,
+	auditRepo repository.AuditLogRepository,
+	transactor repository.Transactor,
 ) *VehicleService {
 	return &VehicleService{
 		vehicleRepo:  vehicleRepo,
 		customerRepo: customerRepo,
+		transactor:   transactor,
+		audit:        auditLog{repo: auditRepo},
 	}
 }
 
@@ -51,7 +58,7 @@ func (s *VehicleService) CreateVehicle(ctx context.Context, create model.Vehicle
 			return nil, fmt.Errorf("failed to check VIN uniqueness: %w", err)
 		}
 		if exists {
-			return nil, fmt.Errorf("vehicle with VIN %s already exists", *vehicle.VIN)
+			return nil, model.NewConflictError("vehicle", "vin", *vehicle.VIN, "ya existe un vehículo con este VIN")
 		}
 	}
 
@@ -62,13 +69,19 @@ func (s *VehicleService) CreateVehicle(ctx context.Context, create model.Vehicle
 			return nil, fmt.Errorf("failed to check license plate uniqueness: %w", err)
 		}
 		if exists {
-			return nil, fmt.Errorf("vehicle with license plate %s already exists", *vehicle.LicensePlate)
+			return nil, model.NewConflictError("vehicle", "license_plate", *vehicle.LicensePlate, "ya existe un vehículo con esta placa")
 		}
 	}
 
 	// Crear el vehículo
-	if err := s.vehicleRepo.Create(ctx, vehicle); err != nil {
-		return nil, fmt.Errorf("failed to create vehicle: %w", err)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.vehicleRepo.Create(ctx, vehicle); err != nil {
+			return fmt.Errorf("failed to create vehicle: %w", err)
+		}
+		return s.audit.vehicleChange(ctx, model.AuditActionVehicleCreated, vehicle, nil, vehicle.AuditSnapshot())
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return vehicle, nil
@@ -76,7 +89,7 @@ func (s *VehicleService) CreateVehicle(ctx context.Context, create model.Vehicle
 
 // GetVehicle retrieves a vehicle by ID
 func (s *VehicleService) GetVehicle(ctx context.Context, id string) (*model.Vehicle, error) {
-	vehicle, err := s.vehicleRepo.GetByID(ctx, id)
+	vehicle, err := s.getOwnedVehicle(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get vehicle: %w", err)
 	}
@@ -84,14 +97,56 @@ func (s *VehicleService) GetVehicle(ctx context.Context, id string) (*model.Vehi
 	return vehicle, nil
 }
 
-// UpdateVehicle updates an existing vehicle
+// getOwnedVehicle loads a vehicle and verifies that its customer belongs to the calling tenant.
+// Vehicle requests only carry the vehicle ID, so ownership is resolved through the customer.
+func (s *VehicleService) getOwnedVehicle(ctx context.Context, id string) (*model.Vehicle, error) {
+	vehicle, err := s.vehicleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Un vehículo de otro tenant se reporta como inexistente para no revelar su existencia
+	if _, err := s.customerRepo.GetByID(ctx, vehicle.CustomerID); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.NewNotFoundError("vehicle", id)
+		}
+		return nil, err
+	}
+
+	return vehicle, nil
+}
+
+// UpdateVehicle updates an existing vehicle. If update.ExpectedVersion is set and the vehicle
+// is at another version nothing is written; otherwise the update is retried when a concurrent
+// write changes the vehicle between the read and the write.
 func (s *VehicleService) UpdateVehicle(ctx context.Context, update model.VehicleUpdate) (*model.Vehicle, error) {
-	// Obtener el vehículo actual
-	vehicle, err := s.vehicleRepo.GetByID(ctx, update.ID)
+	var vehicle *model.Vehicle
+	err := retryOnVersionConflict(update.ExpectedVersion, func() error {
+		return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			vehicle, err = s.updateVehicle(ctx, update)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return vehicle, nil
+}
+
+// updateVehicle performs one read-modify-write attempt of UpdateVehicle and audits it
+func (s *VehicleService) updateVehicle(ctx context.Context, update model.VehicleUpdate) (*model.Vehicle, error) {
+	// Obtener el vehículo actual verificando que pertenezca al tenant
+	vehicle, err := s.getOwnedVehicle(ctx, update.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get vehicle for update: %w", err)
 	}
 
+	if err := checkExpectedVersion("vehicle", vehicle.ID, update.ExpectedVersion, vehicle.Version); err != nil {
+		return nil, err
+	}
+
 	// Verificar unicidad de VIN si se está cambiando
 	if update.VIN != nil && *update.VIN != "" {
 		if vehicle.VIN == nil || *vehicle.VIN != *update.VIN {
@@ -100,7 +155,7 @@ func (s *VehicleService) UpdateVehicle(ctx context.Context, update model.Vehicle
 				return nil, fmt.Errorf("failed to check VIN uniqueness: %w", err)
 			}
 			if exists {
-				return nil, fmt.Errorf("vehicle with VIN %s already exists", *update.VIN)
+				return nil, model.NewConflictError("vehicle", "vin", *update.VIN, "ya existe un vehículo con este VIN")
 			}
 		}
 	}
@@ -113,12 +168,13 @@ func (s *VehicleService) UpdateVehicle(ctx context.Context, update model.Vehicle
 				return nil, fmt.Errorf("failed to check license plate uniqueness: %w", err)
 			}
 			if exists {
-				return nil, fmt.Errorf("vehicle with license plate %s already exists", *update.LicensePlate)
+				return nil, model.NewConflictError("vehicle", "license_plate", *update.LicensePlate, "ya existe un vehículo con esta placa")
 			}
 		}
 	}
 
 	// Aplicar cambios
+	before := vehicle.AuditSnapshot()
 	vehicle.UpdateFromUpdate(update)
 
 	// Validar después de los cambios
@@ -136,44 +192,71 @@ func (s *VehicleService) UpdateVehicle(ctx context.Context, update model.Vehicle
 		return nil, fmt.Errorf("failed to update vehicle: %w", err)
 	}
 
+	if err := s.audit.vehicleChange(ctx, model.AuditActionVehicleUpdated, vehicle, before, vehicle.AuditSnapshot()); err != nil {
+		return nil, err
+	}
+
 	return vehicle, nil
 }
 
-// DeleteVehicle deletes a vehicle
-func (s *VehicleService) DeleteVehicle(ctx context.Context, id string) error {
-	// Verificar que el vehículo existe
-	_, err := s.vehicleRepo.GetByID(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to get vehicle for deletion: %w", err)
-	}
+// DeleteVehicle deletes a vehicle. A non-zero expectedVersion must match the current
+// version of the vehicle.
+func (s *VehicleService) DeleteVehicle(ctx context.Context, id string, expectedVersion int64) error {
+	return retryOnVersionConflict(expectedVersion, func() error {
+		return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			// Verificar que el vehículo existe y pertenece al tenant
+			vehicle, err := s.getOwnedVehicle(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get vehicle for deletion: %w", err)
+			}
 
-	// Eliminar el vehículo
-	if err := s.vehicleRepo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete vehicle: %w", err)
-	}
+			if err := checkExpectedVersion("vehicle", id, expectedVersion, vehicle.Version); err != nil {
+				return err
+			}
 
-	return nil
+			// Eliminar el vehículo
+			if err := s.vehicleRepo.Delete(ctx, id, vehicle.Version); err != nil {
+				return fmt.Errorf("failed to delete vehicle: %w", err)
+			}
+
+			return s.audit.vehicleChange(ctx, model.AuditActionVehicleDeleted, vehicle, vehicle.AuditSnapshot(), nil)
+		})
+	})
 }
 
-// ListVehicles lists vehicles with filtering and pagination
-func (s *VehicleService) ListVehicles(ctx context.Context, filter model.VehicleFilter) ([]*model.Vehicle, int, error) {
-	vehicles, total, err := s.vehicleRepo.List(ctx, filter)
+// ListVehicles lists vehicles with filtering and pagination. The page continues after
+// filter.Cursor when set, and PageInfo.Next is the cursor of the following page.
+func (s *VehicleService) ListVehicles(ctx context.Context, filter model.VehicleFilter) ([]*model.Vehicle, model.PageInfo, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, model.PageInfo{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	// Verificar que el cliente filtrado pertenece al tenant (un ID fusionado lleva al superviviente)
+	if filter.CustomerID != "" {
+		customer, err := resolveCustomer(ctx, s.customerRepo, filter.CustomerID)
+		if err != nil {
+			return nil, model.PageInfo{}, fmt.Errorf("customer not found: %w", err)
+		}
+		filter.CustomerID = customer.ID
+	}
+
+	vehicles, page, err := s.vehicleRepo.List(ctx, filter)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list vehicles: %w", err)
+		return nil, model.PageInfo{}, fmt.Errorf("failed to list vehicles: %w", err)
 	}
 
-	return vehicles, total, nil
+	return vehicles, page, nil
 }
 
 // ListVehiclesByCustomer lists all vehicles for a customer
 func (s *VehicleService) ListVehiclesByCustomer(ctx context.Context, customerID string) ([]*model.Vehicle, error) {
-	// Verificar que el cliente existe
-	_, err := s.customerRepo.GetByID(ctx, customerID)
+	// Verificar que el cliente existe (un ID fusionado lleva al superviviente)
+	customer, err := resolveCustomer(ctx, s.customerRepo, customerID)
 	if err != nil {
 		return nil, fmt.Errorf("customer not found: %w", err)
 	}
 
-	vehicles, err := s.vehicleRepo.ListByCustomer(ctx, customerID)
+	vehicles, err := s.vehicleRepo.ListByCustomer(ctx, customer.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list vehicles by customer: %w", err)
 	}
@@ -226,7 +309,7 @@ func (s *VehicleService) FindCompatibleVehicles(ctx context.Context, make, model
 
 // GetVehicleCompatibilityInfo returns compatibility information for a vehicle
 func (s *VehicleService) GetVehicleCompatibilityInfo(ctx context.Context, id string) (map[string]interface{}, error) {
-	vehicle, err := s.vehicleRepo.GetByID(ctx, id)
+	vehicle, err := s.getOwnedVehicle(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get vehicle: %w", err)
 	}
@@ -256,34 +339,40 @@ func (s *VehicleService) GetVehicleCompatibilityInfo(ctx context.Context, id str
 
 // ActivateVehicle activates a vehicle
 func (s *VehicleService) ActivateVehicle(ctx context.Context, id string) error {
-	vehicle, err := s.vehicleRepo.GetByID(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to get vehicle: %w", err)
-	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		vehicle, err := s.getOwnedVehicle(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get vehicle: %w", err)
+		}
 
-	vehicle.Activate()
+		before := vehicle.AuditSnapshot()
+		vehicle.Activate()
 
-	if err := s.vehicleRepo.Update(ctx, vehicle); err != nil {
-		return fmt.Errorf("failed to activate vehicle: %w", err)
-	}
+		if err := s.vehicleRepo.Update(ctx, vehicle); err != nil {
+			return fmt.Errorf("failed to activate vehicle: %w", err)
+		}
 
-	return nil
+		return s.audit.vehicleChange(ctx, model.AuditActionVehicleActivated, vehicle, before, vehicle.AuditSnapshot())
+	})
 }
 
 // DeactivateVehicle deactivates a vehicle
 func (s *VehicleService) DeactivateVehicle(ctx context.Context, id string) error {
-	vehicle, err := s.vehicleRepo.GetByID(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to get vehicle: %w", err)
-	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		vehicle, err := s.getOwnedVehicle(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get vehicle: %w", err)
+		}
 
-	vehicle.Deactivate()
+		before := vehicle.AuditSnapshot()
+		vehicle.Deactivate()
 
-	if err := s.vehicleRepo.Update(ctx, vehicle); err != nil {
-		return fmt.Errorf("failed to deactivate vehicle: %w", err)
-	}
+		if err := s.vehicleRepo.Update(ctx, vehicle); err != nil {
+			return fmt.Errorf("failed to deactivate vehicle: %w", err)
+		}
 
-	return nil
+		return s.audit.vehicleChange(ctx, model.AuditActionVehicleDeactivated, vehicle, before, vehicle.AuditSnapshot())
+	})
 }
 
 // CreateVehiclesForCustomer creates multiple vehicles for a customer in a batch
@@ -297,45 +386,33 @@ func (s *VehicleService) CreateVehiclesForCustomer(ctx context.Context, customer
 	var vehicles []*model.Vehicle
 	for _, create := range vehicleCreates {
 		create.CustomerID = customerID // Asegurar que el customer ID esté configurado
+		vehicles = append(vehicles, model.NewVehicle(create))
+	}
 
-		vehicle := model.NewVehicle(create)
-		if err := vehicle.Validate(); err != nil {
-			return nil, fmt.Errorf("validation error for vehicle %s %s: %w", vehicle.Make, vehicle.Model, err)
-		}
-
-		if err := vehicle.ValidateVIN(); err != nil {
-			return nil, fmt.Errorf("VIN validation error for vehicle %s %s: %w", vehicle.Make, vehicle.Model, err)
-		}
-
-		vehicles = append(vehicles, vehicle)
+	// Reporte de validación por vehículo
+	if report := model.ValidateNewVehicles(vehicles); report != nil {
+		return nil, report
 	}
 
-	// Verificar unicidad de VINs y placas antes de crear
-	for _, vehicle := range vehicles {
-		if vehicle.VIN != nil && *vehicle.VIN != "" {
-			exists, err := s.vehicleRepo.ExistsByVIN(ctx, *vehicle.VIN, nil)
-			if err != nil {
-				return nil, fmt.Errorf("failed to check VIN uniqueness: %w", err)
-			}
-			if exists {
-				return nil, fmt.Errorf("vehicle with VIN %s already exists", *vehicle.VIN)
+	// Crear todos los vehículos en una transacción (VIN y placa se verifican dentro de ella)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.vehicleRepo.CreateBatch(ctx, vehicles); err != nil {
+			var report *model.VehicleValidationReport
+			if errors.As(err, &report) {
+				return report
 			}
+			return fmt.Errorf("failed to create vehicles batch: %w", err)
 		}
 
-		if vehicle.LicensePlate != nil && *vehicle.LicensePlate != "" {
-			exists, err := s.vehicleRepo.ExistsByLicensePlate(ctx, *vehicle.LicensePlate, nil)
-			if err != nil {
-				return nil, fmt.Errorf("failed to check license plate uniqueness: %w", err)
-			}
-			if exists {
-				return nil, fmt.Errorf("vehicle with license plate %s already exists", *vehicle.LicensePlate)
+		for _, vehicle := range vehicles {
+			if err := s.audit.vehicleChange(ctx, model.AuditActionVehicleCreated, vehicle, nil, vehicle.AuditSnapshot()); err != nil {
+				return err
 			}
 		}
-	}
-
-	// Crear todos los vehículos en una transacción
-	if err := s.vehicleRepo.CreateBatch(ctx, vehicles); err != nil {
-		return nil, fmt.Errorf("failed to create vehicles batch: %w", err)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return vehicles, nil
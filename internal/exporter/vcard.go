@@ -0,0 +1,167 @@
+package exporter
+
+import (
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// vcardLineOctets is the longest content line before folding (RFC 6350, 3.2)
+const vcardLineOctets = 75
+
+// vcardWriter writes a vCard 4.0 per customer. Columns with a standard property use it
+// (N, EMAIL, TEL, ORG, ADR, BDAY, NOTE, REV...); the rest become X- extension properties,
+// with an X-VEHICLE per vehicle.
+type vcardWriter struct {
+	w       io.Writer
+	columns []string
+}
+
+func (w *vcardWriter) Write(customer *model.Customer) error {
+	card := &vcard{}
+	card.add("BEGIN", "VCARD")
+	card.add("VERSION", "4.0")
+
+	selected := make(map[string]bool, len(w.columns))
+	for _, column := range w.columns {
+		selected[column] = true
+	}
+
+	// FN es obligatorio: nombre, empresa o, en último caso, el ID
+	var name []string
+	if selected["first_name"] {
+		name = append(name, customer.FirstName)
+	}
+	if selected["last_name"] {
+		name = append(name, customer.LastName)
+	}
+	fn := strings.TrimSpace(strings.Join(name, " "))
+	if fn == "" && selected["company_name"] && customer.CompanyName != nil {
+		fn = *customer.CompanyName
+	}
+	if fn == "" {
+		fn = customer.ID
+	}
+	card.addText("FN", fn)
+	if selected["first_name"] || selected["last_name"] {
+		var first, last string
+		if selected["first_name"] {
+			first = customer.FirstName
+		}
+		if selected["last_name"] {
+			last = customer.LastName
+		}
+		card.addComponents("N", last, first, "", "", "")
+	}
+
+	for _, column := range w.columns {
+		switch column {
+		case "first_name", "last_name":
+		case "id":
+			card.add("UID", "urn:uuid:"+customer.ID)
+		case "customer_type":
+			if customer.IsBusiness() {
+				card.add("KIND", "org")
+			} else {
+				card.add("KIND", "individual")
+			}
+		case "email":
+			card.addOptional("EMAIL", customer.Email)
+		case "phone":
+			card.addOptional("TEL", customer.Phone)
+		case "company_name":
+			if customer.CompanyName != nil && *customer.CompanyName != "" {
+				card.addComponents("ORG", *customer.CompanyName)
+			}
+		case "address":
+			if customer.Address != nil && *customer.Address != "" {
+				card.addComponents("ADR", "", "", *customer.Address, "", "", "", "")
+			}
+		case "birthday":
+			if customer.Birthday != nil {
+				card.add("BDAY", customer.Birthday.Format("20060102"))
+			}
+		case "notes":
+			card.addOptional("NOTE", customer.Notes)
+		case "updated_at":
+			card.add("REV", customer.UpdatedAt.UTC().Format("20060102T150405Z"))
+		case model.ExportColumnVehicles:
+			for _, vehicle := range customer.Vehicles {
+				card.addText("X-VEHICLE", vehicleDescription(vehicle))
+			}
+		default:
+			value := columnValue(customer, column)
+			if t, ok := value.(time.Time); ok {
+				value = t.Format("20060102T150405Z")
+			}
+			if value != nil {
+				card.addText("X-"+strings.ToUpper(strings.ReplaceAll(column, "_", "-")), formatValue(value))
+			}
+		}
+	}
+
+	card.add("END", "VCARD")
+	_, err := io.WriteString(w.w, card.String())
+	return err
+}
+
+func (w *vcardWriter) Flush() error {
+	return nil
+}
+
+// vcard builds the folded content lines of a vCard
+type vcard struct {
+	strings.Builder
+}
+
+// add writes a property whose value is already escaped
+func (c *vcard) add(name, value string) {
+	line := name + ":" + value
+
+	// Las líneas largas se parten en octetos sin cortar caracteres UTF-8; cada
+	// continuación empieza con un espacio
+	limit := vcardLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		c.WriteString(line[:cut])
+		c.WriteString("\r\n ")
+		line = line[cut:]
+		limit = vcardLineOctets - 1
+	}
+	c.WriteString(line)
+	c.WriteString("\r\n")
+}
+
+// addText writes a text property
+func (c *vcard) addText(name, value string) {
+	c.add(name, escapeVCard(value))
+}
+
+// addOptional writes a text property when the value is set
+func (c *vcard) addOptional(name string, value *string) {
+	if value != nil && *value != "" {
+		c.addText(name, *value)
+	}
+}
+
+// addComponents writes a structured property (N, ADR, ORG) from its components
+func (c *vcard) addComponents(name string, components ...string) {
+	escaped := make([]string, len(components))
+	for i, component := range components {
+		escaped[i] = escapeVCard(component)
+	}
+	c.add(name, strings.Join(escaped, ";"))
+}
+
+// vcardEscaper escapes the characters with a meaning in vCard text values
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeVCard(value string) string {
+	return vcardEscaper.Replace(value)
+}
@@ -0,0 +1,301 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// Writer writes exported customers to a file one at a time, so an export never holds more
+// than the page being written
+type Writer interface {
+	Write(customer *model.Customer) error
+	// Flush writes any buffered data to the underlying writer
+	Flush() error
+}
+
+// NewWriter creates a writer of the format and columns of validated options. The writers
+// issue many small writes, so w should be buffered.
+func NewWriter(w io.Writer, options model.CustomerExportOptions) (Writer, error) {
+	switch options.Format {
+	case model.ExportFormatCSV:
+		return newCSVWriter(w, options.Columns)
+	case model.ExportFormatNDJSON:
+		return &ndjsonWriter{w: w, columns: options.Columns}, nil
+	case model.ExportFormatVCard:
+		return &vcardWriter{w: w, columns: options.Columns}, nil
+	default:
+		return nil, &model.ValidationError{Field: "format", Message: "formato inválido (csv, ndjson, vcard)"}
+	}
+}
+
+// ContentType returns the media type of a file of format
+func ContentType(format string) string {
+	switch format {
+	case model.ExportFormatNDJSON:
+		return "application/x-ndjson"
+	case model.ExportFormatVCard:
+		return "text/vcard; charset=utf-8"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// FileName returns the name of an export of format generated at t
+func FileName(format string, t time.Time) string {
+	extension := "csv"
+	switch format {
+	case model.ExportFormatNDJSON:
+		extension = "ndjson"
+	case model.ExportFormatVCard:
+		extension = "vcf"
+	}
+	return fmt.Sprintf("customers-%s.%s", t.UTC().Format("20060102-150405"), extension)
+}
+
+// csvWriter writes a header row with the columns and a row per customer
+type csvWriter struct {
+	csv     *csv.Writer
+	columns []string
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	writer := &csvWriter{csv: csv.NewWriter(w), columns: columns}
+	if err := writer.csv.Write(columns); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	return writer, nil
+}
+
+func (w *csvWriter) Write(customer *model.Customer) error {
+	record := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		if column == model.ExportColumnVehicles {
+			record[i] = csvCell(vehiclesSummary(customer.Vehicles))
+			continue
+		}
+		record[i] = csvCell(formatValue(columnValue(customer, column)))
+	}
+	return w.csv.Write(record)
+}
+
+func (w *csvWriter) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// csvCell neutralizes cells a spreadsheet would run as a formula. A leading sign is kept when
+// the rest is a number or a formatted phone number, which a spreadsheet shows as a value and
+// never evaluates as a reference or a function.
+func csvCell(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '@', '\t', '\r':
+		return "'" + value
+	case '+', '-':
+		if !signedValue(value[1:]) {
+			return "'" + value
+		}
+	}
+	return value
+}
+
+// signedValue reports whether the text after a leading sign has only digits, spaces,
+// parentheses, dashes and decimal points, with at least one digit
+func signedValue(rest string) bool {
+	digits := false
+	for _, r := range rest {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = true
+		case r == ' ', r == '(', r == ')', r == '-', r == '.':
+		default:
+			return false
+		}
+	}
+	return digits
+}
+
+// ndjsonWriter writes a JSON object per line with the columns as keys, in column order
+type ndjsonWriter struct {
+	w       io.Writer
+	columns []string
+}
+
+func (w *ndjsonWriter) Write(customer *model.Customer) error {
+	var line strings.Builder
+	line.WriteByte('{')
+	for i, column := range w.columns {
+		var value interface{}
+		if column == model.ExportColumnVehicles {
+			value = vehiclesJSON(customer.Vehicles)
+		} else {
+			value = columnValue(customer, column)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode column %s: %w", column, err)
+		}
+		if i > 0 {
+			line.WriteByte(',')
+		}
+		line.WriteString(strconv.Quote(column))
+		line.WriteByte(':')
+		line.Write(encoded)
+	}
+	line.WriteString("}\n")
+
+	_, err := io.WriteString(w.w, line.String())
+	return err
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return nil
+}
+
+// columnValue returns the value of a customer or stats column: a string, bool, number or
+// time, or nil when the customer has no value
+func columnValue(customer *model.Customer, column string) interface{} {
+	switch column {
+	case "id":
+		return customer.ID
+	case "first_name":
+		return customer.FirstName
+	case "last_name":
+		return customer.LastName
+	case "email":
+		return optional(customer.Email)
+	case "phone":
+		return optional(customer.Phone)
+	case "customer_type":
+		return customer.CustomerType
+	case "company_name":
+		return optional(customer.CompanyName)
+	case "tax_id":
+		return optional(customer.TaxID)
+	case "address":
+		return optional(customer.Address)
+	case "birthday":
+		if customer.Birthday == nil {
+			return nil
+		}
+		return customer.Birthday.Format("2006-01-02")
+	case "notes":
+		return optional(customer.Notes)
+	case "is_active":
+		return customer.IsActive
+	case "created_at":
+		return customer.CreatedAt.UTC()
+	case "updated_at":
+		return customer.UpdatedAt.UTC()
+	}
+
+	stats := customer.Stats
+	if stats == nil {
+		return nil
+	}
+	switch column {
+	case "total_orders":
+		return stats.TotalOrders
+	case "total_spent":
+		return stats.TotalSpent
+	case "average_order_value":
+		return stats.AverageOrderValue
+	case "last_visit":
+		if stats.LastVisit.IsZero() {
+			return nil
+		}
+		return stats.LastVisit.UTC()
+	case "visits_count":
+		return stats.VisitsCount
+	case "level":
+		return stats.GetCustomerLevel()
+	}
+	return nil
+}
+
+// formatValue returns a column value as text
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// optional returns the value of an optional field, or nil
+func optional(value *string) interface{} {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return *value
+}
+
+// vehicleDescription describes a vehicle in one line, with its VIN when it has one
+func vehicleDescription(vehicle *model.Vehicle) string {
+	description := vehicle.FullDescription()
+	if vehicle.VIN != nil && *vehicle.VIN != "" {
+		description += " - VIN: " + *vehicle.VIN
+	}
+	return description
+}
+
+// vehiclesSummary describes the vehicles of a customer in one cell
+func vehiclesSummary(vehicles []*model.Vehicle) string {
+	descriptions := make([]string, len(vehicles))
+	for i, vehicle := range vehicles {
+		descriptions[i] = vehicleDescription(vehicle)
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// exportedVehicle is a vehicle in an NDJSON export
+type exportedVehicle struct {
+	ID           string  `json:"id"`
+	Make         string  `json:"make"`
+	Model        string  `json:"model"`
+	Year         int     `json:"year"`
+	VIN          *string `json:"vin"`
+	LicensePlate *string `json:"license_plate"`
+	Color        *string `json:"color"`
+	Engine       *string `json:"engine"`
+	IsActive     bool    `json:"is_active"`
+}
+
+// vehiclesJSON returns the vehicles of a customer for an NDJSON export
+func vehiclesJSON(vehicles []*model.Vehicle) []exportedVehicle {
+	exported := make([]exportedVehicle, len(vehicles))
+	for i, vehicle := range vehicles {
+		exported[i] = exportedVehicle{
+			ID:           vehicle.ID,
+			Make:         vehicle.Make,
+			Model:        vehicle.Model,
+			Year:         vehicle.Year,
+			VIN:          vehicle.VIN,
+			LicensePlate: vehicle.LicensePlate,
+			Color:        vehicle.Color,
+			Engine:       vehicle.Engine,
+			IsActive:     vehicle.IsActive,
+		}
+	}
+	return exported
+}
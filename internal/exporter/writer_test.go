@@ -0,0 +1,171 @@
+package exporter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"", ""},
+		{"Ana", "Ana"},
+		{"=SUM(A1:A2)", "'=SUM(A1:A2)"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tTab", "'\tTab"},
+		{"\rReturn", "'\rReturn"},
+		{"+1 (555) 123-4567", "+1 (555) 123-4567"},
+		{"+34-600-111-222", "+34-600-111-222"},
+		{"+52 55 1234 5678", "+52 55 1234 5678"},
+		{"-12.50", "-12.50"},
+		{"+cmd|' /C calc'!A0", "'+cmd|' /C calc'!A0"},
+		{"-A1+B1", "'-A1+B1"},
+		{"+Inf", "'+Inf"},
+		{"-", "'-"},
+		{"+()", "'+()"},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		if got := csvCell(tt.value); got != tt.want {
+			t.Errorf("csvCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// testCustomer returns a customer with every exported field set
+func testCustomer() *model.Customer {
+	email, phone, notes := "ana@example.com", "+34-600-111-222", "Línea 1\nLínea 2; con, separadores"
+	company, address := "Talleres García", "Calle Mayor 1, Madrid"
+	birthday := time.Date(1985, 3, 14, 0, 0, 0, 0, time.UTC)
+	vin, color := "1HGCM82633A004352", "Rojo"
+	updated := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	return &model.Customer{
+		ID:           "6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c10",
+		FirstName:    "Ana",
+		LastName:     "García",
+		Email:        &email,
+		Phone:        &phone,
+		CustomerType: model.CustomerTypeBusiness,
+		CompanyName:  &company,
+		Address:      &address,
+		Birthday:     &birthday,
+		Notes:        &notes,
+		IsActive:     true,
+		CreatedAt:    updated,
+		UpdatedAt:    updated,
+		Stats:        &model.CustomerStats{TotalSpent: 1234.5},
+		Vehicles:     []*model.Vehicle{{ID: "v-1", Make: "Honda", Model: "Accord", Year: 2003, VIN: &vin, Color: &color, IsActive: true}},
+	}
+}
+
+// export writes customers with a writer of format and columns and returns the file
+func export(t *testing.T, format string, columns []string, customers ...*model.Customer) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, model.CustomerExportOptions{Format: format, Columns: columns})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for _, customer := range customers {
+		if err := writer.Write(customer); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	return buf.String()
+}
+
+func TestCSVWriter(t *testing.T) {
+	formula := testCustomer()
+	formula.FirstName = "=HYPERLINK(\"http://example.com\")"
+	formula.Email, formula.Stats, formula.Vehicles = nil, nil, nil
+
+	got := export(t, model.ExportFormatCSV, []string{"first_name", "email", "phone", "notes", "total_spent", "vehicles"}, testCustomer(), formula)
+	want := "first_name,email,phone,notes,total_spent,vehicles\n" +
+		"Ana,ana@example.com,+34-600-111-222,\"Línea 1\nLínea 2; con, separadores\",1234.50,2003 Honda Accord (Rojo) - VIN: 1HGCM82633A004352\n" +
+		"\"'=HYPERLINK(\"\"http://example.com\"\")\",,+34-600-111-222,\"Línea 1\nLínea 2; con, separadores\",,\n"
+	if got != want {
+		t.Errorf("unexpected CSV:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	customer := testCustomer()
+	customer.Phone = nil
+
+	got := export(t, model.ExportFormatNDJSON, []string{"id", "last_name", "phone", "is_active", "birthday", "updated_at", "total_spent", "vehicles"}, customer)
+	want := `{"id":"6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c10","last_name":"García","phone":null,"is_active":true,` +
+		`"birthday":"1985-03-14","updated_at":"2024-05-06T07:08:09Z","total_spent":1234.5,` +
+		`"vehicles":[{"id":"v-1","make":"Honda","model":"Accord","year":2003,"vin":"1HGCM82633A004352","license_plate":null,"color":"Rojo","engine":null,"is_active":true}]}` + "\n"
+	if got != want {
+		t.Errorf("unexpected NDJSON:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestVCardWriter(t *testing.T) {
+	got := export(t, model.ExportFormatVCard,
+		[]string{"id", "first_name", "last_name", "customer_type", "email", "phone", "company_name", "address", "birthday", "notes", "updated_at", "total_spent", "vehicles"},
+		testCustomer())
+	want := strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"FN:Ana García",
+		"N:García;Ana;;;",
+		"UID:urn:uuid:6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c10",
+		"KIND:org",
+		"EMAIL:ana@example.com",
+		"TEL:+34-600-111-222",
+		"ORG:Talleres García",
+		`ADR:;;Calle Mayor 1\, Madrid;;;;`,
+		"BDAY:19850314",
+		`NOTE:Línea 1\nLínea 2\; con\, separadores`,
+		"REV:20240506T070809Z",
+		"X-TOTAL-SPENT:1234.50",
+		"X-VEHICLE:2003 Honda Accord (Rojo) - VIN: 1HGCM82633A004352",
+		"END:VCARD",
+	}, "\r\n") + "\r\n"
+	if got != want {
+		t.Errorf("unexpected vCard:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestVCardFoldsLongLines(t *testing.T) {
+	customer := testCustomer()
+	notes := strings.Repeat("ñ", 60) // 120 octetos
+	customer.Notes = &notes
+
+	got := export(t, model.ExportFormatVCard, []string{"notes"}, customer)
+	for _, line := range strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n") {
+		if len(line) > vcardLineOctets {
+			t.Errorf("line of %d octets exceeds %d: %q", len(line), vcardLineOctets, line)
+		}
+	}
+
+	// Al desplegar las líneas se recupera la nota sin caracteres partidos
+	unfolded := strings.ReplaceAll(got, "\r\n ", "")
+	if !strings.Contains(unfolded, "NOTE:"+notes+"\r\n") {
+		t.Errorf("expected the unfolded note, got %q", unfolded)
+	}
+	// Sin nombre seleccionado, FN es el ID
+	if !strings.Contains(got, "FN:"+customer.ID+"\r\n") {
+		t.Errorf("expected the ID as FN, got %q", got)
+	}
+}
+
+func TestNewWriterInvalidFormat(t *testing.T) {
+	_, err := NewWriter(&bytes.Buffer{}, model.CustomerExportOptions{Format: "xlsx"})
+	var validationErr *model.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "format" {
+		t.Errorf("expected a validation error on format, got %v", err)
+	}
+}
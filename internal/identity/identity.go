@@ -0,0 +1,52 @@
+package identity
+
+import (
+	"context"
+)
+
+// contextKey is the type used for identity values stored in a context
+type contextKey string
+
+// StaffKey is the context key for the authenticated staff member
+const StaffKey contextKey = "staff"
+
+// Staff represents the authenticated staff member performing a request
+type Staff struct {
+	ID       string
+	Name     string
+	TenantID string
+	Roles    []string
+}
+
+// HasRole checks if the staff member has the given role
+func (s *Staff) HasRole(role string) bool {
+	for _, r := range s.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// DisplayName returns the staff name, falling back to the staff ID
+func (s *Staff) DisplayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.ID
+}
+
+// WithStaff adds the authenticated staff member to the context
+func WithStaff(ctx context.Context, staff *Staff) context.Context {
+	return context.WithValue(ctx, StaffKey, staff)
+}
+
+// StaffFromContext retrieves the authenticated staff member from the context
+func StaffFromContext(ctx context.Context) (*Staff, bool) {
+	staff, ok := ctx.Value(StaffKey).(*Staff)
+	return staff, ok && staff != nil
+}
+
+// System is the author of changes made without a staff identity, which only happens when
+// authentication is disabled and the API Gateway does not send x-staff-id
+var System = Staff{ID: "system", Name: "System User"}
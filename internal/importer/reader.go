@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// maxLineBytes limits a single NDJSON line
+const maxLineBytes = 1 << 20
+
+// utf8BOM is written by spreadsheets at the start of UTF-8 CSV exports
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Reader reads customer import rows from a CSV or NDJSON file. Source columns are renamed
+// to import fields through the column mapping; unmapped columns named after an import
+// field are taken as is and any other column is ignored.
+type Reader struct {
+	format  string
+	mapping map[string]string // columna normalizada -> campo
+
+	// CSV
+	csv    *csv.Reader
+	header []string // campo de cada columna; vacío si se ignora
+
+	// NDJSON
+	lines *bufio.Reader
+	line  int
+}
+
+// NewReader creates a reader of format (csv or ndjson). A CSV file must start with a header
+// row; its delimiter is a comma or, as exported by spreadsheets in many locales, a semicolon.
+func NewReader(r io.Reader, format string, columnMapping map[string]string) (*Reader, error) {
+	mapping := make(map[string]string, len(columnMapping))
+	for column, field := range columnMapping {
+		mapping[normalizeColumn(column)] = field
+	}
+
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+
+	reader := &Reader{format: format, mapping: mapping}
+	switch format {
+	case model.ImportFormatCSV:
+		if err := reader.readHeader(buffered); err != nil {
+			return nil, err
+		}
+	case model.ImportFormatNDJSON:
+		reader.lines = buffered
+	default:
+		return nil, &model.ValidationError{Field: "format", Message: "formato inválido (csv, ndjson)"}
+	}
+
+	return reader, nil
+}
+
+// Read returns the next row, or io.EOF after the last one. A malformed row is returned
+// with Err set and reading can continue; other errors stop the file.
+func (r *Reader) Read() (*model.CustomerImportRow, error) {
+	if r.format == model.ImportFormatCSV {
+		return r.readCSV()
+	}
+	return r.readNDJSON()
+}
+
+// readHeader reads the CSV header and resolves the field of each column
+func (r *Reader) readHeader(buffered *bufio.Reader) error {
+	first, err := buffered.Peek(buffered.Size())
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	if end := bytes.IndexByte(first, '\n'); end >= 0 {
+		first = first[:end]
+	}
+
+	r.csv = csv.NewReader(buffered)
+	if bytes.Count(first, []byte{';'}) > bytes.Count(first, []byte{','}) {
+		r.csv.Comma = ';'
+	}
+	r.csv.FieldsPerRecord = -1
+	r.csv.TrimLeadingSpace = true
+
+	columns, err := r.csv.Read()
+	if errors.Is(err, io.EOF) {
+		return &model.ValidationError{Field: "file", Message: "el archivo CSV está vacío"}
+	}
+	if err != nil {
+		return &model.ValidationError{Field: "file", Message: fmt.Sprintf("cabecera CSV inválida: %v", err)}
+	}
+
+	r.header = make([]string, len(columns))
+	mapped := false
+	for i, column := range columns {
+		r.header[i] = r.field(column)
+		mapped = mapped || r.header[i] != ""
+	}
+	if !mapped {
+		return &model.ValidationError{Field: "column_mapping", Message: "ninguna columna del archivo corresponde a un campo de cliente"}
+	}
+	return nil
+}
+
+// readCSV reads the next CSV record
+func (r *Reader) readCSV() (*model.CustomerImportRow, error) {
+	record, err := r.csv.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &model.CustomerImportRow{
+			Line: parseErr.StartLine,
+			Err:  &model.ValidationError{Field: "row", Message: fmt.Sprintf("fila CSV mal formada: %v", parseErr.Err)},
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV row: %w", err)
+	}
+
+	line, _ := r.csv.FieldPos(0)
+	row := &model.CustomerImportRow{Line: line, Values: make(map[string]string, len(record))}
+	for i, value := range record {
+		if i < len(r.header) && r.header[i] != "" {
+			row.Values[r.header[i]] = strings.TrimSpace(value)
+		}
+	}
+	return row, nil
+}
+
+// readNDJSON reads the next non-blank line as a JSON object of scalar values
+func (r *Reader) readNDJSON() (*model.CustomerImportRow, error) {
+	for {
+		data, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		r.line++
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		row := &model.CustomerImportRow{Line: r.line, Values: map[string]string{}}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			row.Err = &model.ValidationError{Field: "row", Message: fmt.Sprintf("JSON inválido: %v", err)}
+			return row, nil
+		}
+
+		for key, value := range object {
+			field := r.field(key)
+			if field == "" || value == nil {
+				continue
+			}
+			switch v := value.(type) {
+			case string:
+				row.Values[field] = strings.TrimSpace(v)
+			case json.Number, bool:
+				row.Values[field] = fmt.Sprint(v)
+			default:
+				row.Err = &model.ValidationError{Field: field, Message: "debe ser un texto, un número o un booleano"}
+				return row, nil
+			}
+		}
+		return row, nil
+	}
+}
+
+// readLine returns the next line without its terminator, or io.EOF after the last one
+func (r *Reader) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := r.lines.ReadLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(line) > 0 {
+				return line, nil
+			}
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("failed to read NDJSON line: %w", err)
+		}
+		line = append(line, chunk...)
+		if len(line) > maxLineBytes {
+			return nil, &model.ValidationError{Field: "file", Message: fmt.Sprintf("la línea %d supera %d bytes", r.line+1, maxLineBytes)}
+		}
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// field returns the import field of a source column, or "" when the column is ignored
+func (r *Reader) field(column string) string {
+	column = normalizeColumn(column)
+	if field, ok := r.mapping[column]; ok {
+		return field
+	}
+	if model.IsValidImportField(column) {
+		return column
+	}
+	return ""
+}
+
+// normalizeColumn compares column names ignoring case and surrounding spaces
+func normalizeColumn(column string) string {
+	return strings.ToLower(strings.TrimSpace(column))
+}
@@ -0,0 +1,153 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// readAll reads every row of input
+func readAll(t *testing.T, input, format string, mapping map[string]string) []*model.CustomerImportRow {
+	t.Helper()
+
+	reader, err := NewReader(strings.NewReader(input), format, mapping)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+
+	var rows []*model.CustomerImportRow
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestReadCSVHeaderMapping(t *testing.T) {
+	input := "\xEF\xBB\xBF Nombre ;last_name;Correo;Ignorada\nAna; García ;ana@example.com;x\n"
+	rows := readAll(t, input, model.ImportFormatCSV, map[string]string{"nombre": "first_name", "CORREO": "email"})
+
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(rows))
+	}
+	row := rows[0]
+	if row.Line != 2 || row.Err != nil {
+		t.Fatalf("expected a valid row at line 2, got line %d: %v", row.Line, row.Err)
+	}
+	want := map[string]string{"first_name": "Ana", "last_name": "García", "email": "ana@example.com"}
+	if len(row.Values) != len(want) {
+		t.Errorf("expected values %v, got %v", want, row.Values)
+	}
+	for field, value := range want {
+		if row.Values[field] != value {
+			t.Errorf("expected %s=%q, got %q", field, value, row.Values[field])
+		}
+	}
+}
+
+func TestReadCSVRows(t *testing.T) {
+	input := "first_name,last_name,email\n" +
+		"Ana,García,ana@example.com\n" +
+		"\n" + // Las líneas en blanco no son filas
+		",,\n" + // Una fila de celdas vacías llega vacía y el servicio la omite
+		"Luis,\"Pérez,ana@example.com\n"
+	rows := readAll(t, input, model.ImportFormatCSV, nil)
+
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+	if rows[0].Line != 2 || rows[0].Values["first_name"] != "Ana" {
+		t.Errorf("unexpected first row %+v", rows[0])
+	}
+	if rows[1].Line != 4 || rows[1].Err != nil || rows[1].Values["first_name"] != "" || rows[1].Values["email"] != "" {
+		t.Errorf("expected an empty row at line 4, got %+v", rows[1])
+	}
+
+	var validationErr *model.ValidationError
+	if rows[2].Line != 5 || !errors.As(rows[2].Err, &validationErr) || validationErr.Field != "row" {
+		t.Errorf("expected a malformed row at line 5, got line %d: %v", rows[2].Line, rows[2].Err)
+	}
+}
+
+func TestNewReaderErrors(t *testing.T) {
+	tests := []struct {
+		name, input, format, field string
+	}{
+		{"empty CSV", "", model.ImportFormatCSV, "file"},
+		{"malformed header", "first_name,\"last_name\n", model.ImportFormatCSV, "file"},
+		{"no known column", "Nombre,Apellidos\nAna,García\n", model.ImportFormatCSV, "column_mapping"},
+		{"unknown format", "", "xlsx", "format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(strings.NewReader(tt.input), tt.format, nil)
+			var validationErr *model.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("expected a validation error on %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	input := `{"Nombre": " Ana ", "last_name": "García", "vehicle_year": 2019, "is_active": true, "otra": "x", "email": null}` + "\n" +
+		"\n" +
+		`{"first_name": "Luis"` + "\n" +
+		`{"first_name": ["Luis"]}` + "\n" +
+		`{}` // Última línea sin salto
+	rows := readAll(t, input, model.ImportFormatNDJSON, map[string]string{"Nombre": "first_name", "is_active": "vehicle_make"})
+
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %d", len(rows))
+	}
+
+	first := rows[0]
+	if first.Line != 1 || first.Err != nil {
+		t.Fatalf("expected a valid row at line 1, got line %d: %v", first.Line, first.Err)
+	}
+	want := map[string]string{"first_name": "Ana", "last_name": "García", "vehicle_year": "2019", "vehicle_make": "true"}
+	if len(first.Values) != len(want) {
+		t.Errorf("expected values %v, got %v", want, first.Values)
+	}
+	for field, value := range want {
+		if first.Values[field] != value {
+			t.Errorf("expected %s=%q, got %q", field, value, first.Values[field])
+		}
+	}
+
+	var validationErr *model.ValidationError
+	if rows[1].Line != 3 || !errors.As(rows[1].Err, &validationErr) || validationErr.Field != "row" {
+		t.Errorf("expected invalid JSON at line 3, got line %d: %v", rows[1].Line, rows[1].Err)
+	}
+	if rows[2].Line != 4 || !errors.As(rows[2].Err, &validationErr) || validationErr.Field != "first_name" {
+		t.Errorf("expected a non-scalar first_name at line 4, got line %d: %v", rows[2].Line, rows[2].Err)
+	}
+	if rows[3].Line != 5 || rows[3].Err != nil || len(rows[3].Values) != 0 {
+		t.Errorf("expected an empty row at line 5, got %+v", rows[3])
+	}
+}
+
+func TestReadNDJSONLineTooLong(t *testing.T) {
+	input := `{"first_name": "Ana"}` + "\n" + `{"notes": "` + strings.Repeat("x", maxLineBytes) + `"}` + "\n"
+	reader, err := NewReader(strings.NewReader(input), model.ImportFormatNDJSON, nil)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	if _, err := reader.Read(); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	_, err = reader.Read()
+	var validationErr *model.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "file" {
+		t.Errorf("expected the long line to stop the file, got %v", err)
+	}
+}
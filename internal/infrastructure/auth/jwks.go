@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// jwk is a single JSON Web Key; only RSA signing keys are used
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// jwkSet is a JSON Web Key Set document
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// LoadJWKSFile reads a JWKS document and returns its RSA signing keys indexed by kid
+func LoadJWKSFile(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+
+	var set jwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS file: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") || (key.Alg != "" && key.Alg != AlgRS256) {
+			continue
+		}
+
+		publicKey, err := key.rsaPublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %q: %w", key.Kid, err)
+		}
+		keys[key.Kid] = publicKey
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS file %s has no RSA signing keys", path)
+	}
+
+	return keys, nil
+}
+
+// rsaPublicKey builds the RSA public key from the modulus and exponent
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("unsupported exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}, nil
+}
@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/encomos/api-encomos/customer-service/internal/config"
+)
+
+// Supported signing algorithms
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+var (
+	// ErrMalformedToken is returned when the token is not a valid compact JWS
+	ErrMalformedToken = errors.New("malformed token")
+	// ErrUnsupportedAlgorithm is returned for algorithms without a configured key
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	// ErrInvalidSignature is returned when the signature does not match
+	ErrInvalidSignature = errors.New("invalid token signature")
+	// ErrTokenExpired is returned when the token is expired or not yet valid
+	ErrTokenExpired = errors.New("token expired or not yet valid")
+	// ErrInvalidClaims is returned when issuer, audience or required claims are wrong
+	ErrInvalidClaims = errors.New("invalid token claims")
+)
+
+// header is the JOSE header of a token
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// Claims are the JWT claims used by the service
+type Claims struct {
+	Subject   string
+	Name      string
+	TenantID  string
+	Roles     []string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+}
+
+// rawClaims mirrors the token payload; aud and roles accept a string or a list
+type rawClaims struct {
+	Subject   string          `json:"sub"`
+	StaffID   string          `json:"staff_id"`
+	Name      string          `json:"name"`
+	TenantID  string          `json:"tenant_id"`
+	Roles     json.RawMessage `json:"roles"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *int64          `json:"exp"`
+	NotBefore *int64          `json:"nbf"`
+}
+
+// Verifier validates JWTs signed with HS256 or RS256
+type Verifier struct {
+	hmacSecret []byte
+	rsaKeys    map[string]*rsa.PublicKey
+	issuer     string
+	audience   string
+	leeway     time.Duration
+	now        func() time.Time
+}
+
+// NewVerifier creates a verifier from the auth configuration, loading the JWKS file if set
+func NewVerifier(cfg *config.AuthConfig) (*Verifier, error) {
+	v := &Verifier{
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   cfg.Leeway,
+		now:      time.Now,
+	}
+
+	if cfg.JWTSecret != "" {
+		v.hmacSecret = []byte(cfg.JWTSecret)
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := LoadJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKeys = keys
+	}
+
+	if v.hmacSecret == nil && len(v.rsaKeys) == 0 {
+		return nil, fmt.Errorf("no JWT verification key configured")
+	}
+
+	return v, nil
+}
+
+// Verify checks the token signature and standard claims and returns its claims
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if err := v.verifySignature(h, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var raw rawClaims
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	claims, err := raw.toClaims()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// verifySignature verifies the signature with the key matching the header algorithm
+func (v *Verifier) verifySignature(h header, signingInput string, signature []byte) error {
+	switch h.Alg {
+	case AlgHS256:
+		if v.hmacSecret == nil {
+			return ErrUnsupportedAlgorithm
+		}
+		mac := hmac.New(sha256.New, v.hmacSecret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	case AlgRS256:
+		if len(v.rsaKeys) == 0 {
+			return ErrUnsupportedAlgorithm
+		}
+		key, err := v.rsaKey(h.Kid)
+		if err != nil {
+			return err
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+}
+
+// rsaKey returns the key for kid; a token without kid is accepted only with a single key
+func (v *Verifier) rsaKey(kid string) (*rsa.PublicKey, error) {
+	if kid != "" {
+		key, ok := v.rsaKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown key id %q", ErrInvalidSignature, kid)
+		}
+		return key, nil
+	}
+
+	if len(v.rsaKeys) == 1 {
+		for _, key := range v.rsaKeys {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: key id is required", ErrInvalidSignature)
+}
+
+// validateClaims checks expiration, issuer, audience and the staff subject
+func (v *Verifier) validateClaims(claims *Claims) error {
+	now := v.now()
+
+	if claims.ExpiresAt.IsZero() || now.After(claims.ExpiresAt.Add(v.leeway)) {
+		return ErrTokenExpired
+	}
+	if !claims.NotBefore.IsZero() && now.Add(v.leeway).Before(claims.NotBefore) {
+		return ErrTokenExpired
+	}
+
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidClaims)
+	}
+
+	if v.audience != "" && !containsString(claims.Audience, v.audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidClaims)
+	}
+
+	if claims.Subject == "" {
+		return fmt.Errorf("%w: staff id is required", ErrInvalidClaims)
+	}
+
+	return nil
+}
+
+// toClaims normalizes the raw payload
+func (r *rawClaims) toClaims() (*Claims, error) {
+	claims := &Claims{
+		Subject:  r.StaffID,
+		Name:     r.Name,
+		TenantID: r.TenantID,
+		Issuer:   r.Issuer,
+	}
+
+	// staff_id tiene prioridad sobre sub cuando ambos están presentes
+	if claims.Subject == "" {
+		claims.Subject = r.Subject
+	}
+
+	var err error
+	if claims.Roles, err = stringOrList(r.Roles); err != nil {
+		return nil, fmt.Errorf("%w: roles", ErrInvalidClaims)
+	}
+	if claims.Audience, err = stringOrList(r.Audience); err != nil {
+		return nil, fmt.Errorf("%w: aud", ErrInvalidClaims)
+	}
+
+	if r.ExpiresAt != nil {
+		claims.ExpiresAt = time.Unix(*r.ExpiresAt, 0)
+	}
+	if r.NotBefore != nil {
+		claims.NotBefore = time.Unix(*r.NotBefore, 0)
+	}
+
+	return claims, nil
+}
+
+// decodeSegment decodes a base64url JSON segment
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// stringOrList decodes a JSON value that may be a single string or a list of strings
+func stringOrList(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}, nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// containsString checks if a list contains a value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
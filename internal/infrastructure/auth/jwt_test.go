@@ -0,0 +1,312 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/encomos/api-encomos/customer-service/internal/config"
+)
+
+const (
+	testSecret   = "test-hmac-secret"
+	testIssuer   = "https://auth.encomos.test"
+	testAudience = "customer-service"
+	testKid      = "key-1"
+)
+
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// testRSAKey is shared by the tests; generating RSA keys is slow
+var testRSAKey = func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+// segment encodes v as a base64url JSON segment
+func segment(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// signHS256 returns a token signed with HMAC-SHA256 and secret
+func signHS256(t *testing.T, h map[string]interface{}, claims map[string]interface{}, secret []byte) string {
+	t.Helper()
+	input := segment(t, h) + "." + segment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signRS256 returns a token signed with RSA-SHA256 and key
+func signRS256(t *testing.T, h map[string]interface{}, claims map[string]interface{}, key *rsa.PrivateKey) string {
+	t.Helper()
+	input := segment(t, h) + "." + segment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// validClaims returns claims accepted by testVerifier
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":       "user-1",
+		"name":      "Ana García",
+		"tenant_id": "6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c10",
+		"roles":     []string{"staff"},
+		"iss":       testIssuer,
+		"aud":       testAudience,
+		"exp":       testNow.Add(time.Hour).Unix(),
+	}
+}
+
+// with returns a copy of claims with the given changes; a nil value removes the claim
+func with(claims map[string]interface{}, changes map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(claims))
+	for k, v := range claims {
+		out[k] = v
+	}
+	for k, v := range changes {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// testVerifier accepts HS256 with testSecret and RS256 with testRSAKey as testKid
+func testVerifier(leeway time.Duration) *Verifier {
+	return &Verifier{
+		hmacSecret: []byte(testSecret),
+		rsaKeys:    map[string]*rsa.PublicKey{testKid: &testRSAKey.PublicKey},
+		issuer:     testIssuer,
+		audience:   testAudience,
+		leeway:     leeway,
+		now:        func() time.Time { return testNow },
+	}
+}
+
+func TestVerify(t *testing.T) {
+	hs := map[string]interface{}{"alg": AlgHS256, "typ": "JWT"}
+	rs := map[string]interface{}{"alg": AlgRS256, "kid": testKid, "typ": "JWT"}
+	secret := []byte(testSecret)
+
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&testRSAKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tamper := func(token string) string {
+		parts := strings.Split(token, ".")
+		parts[1] = segment(t, with(validClaims(), map[string]interface{}{"roles": []string{"admin"}}))
+		return strings.Join(parts, ".")
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		leeway  time.Duration
+		wantErr error
+	}{
+		{"valid HS256", signHS256(t, hs, validClaims(), secret), 0, nil},
+		{"valid RS256", signRS256(t, rs, validClaims(), testRSAKey), 0, nil},
+		{"alg none", segment(t, map[string]interface{}{"alg": "none"}) + "." + segment(t, validClaims()) + ".", 0, ErrUnsupportedAlgorithm},
+		{"HS256 signed with the RSA public key", signHS256(t, hs, validClaims(), publicKeyPEM), 0, ErrInvalidSignature},
+		{"RS256 header with an HMAC signature", signHS256(t, rs, validClaims(), secret), 0, ErrInvalidSignature},
+		{"unsupported algorithm", signHS256(t, map[string]interface{}{"alg": "HS512"}, validClaims(), secret), 0, ErrUnsupportedAlgorithm},
+		{"tampered HS256 payload", tamper(signHS256(t, hs, validClaims(), secret)), 0, ErrInvalidSignature},
+		{"tampered RS256 payload", tamper(signRS256(t, rs, validClaims(), testRSAKey)), 0, ErrInvalidSignature},
+		{"HS256 wrong secret", signHS256(t, hs, validClaims(), []byte("other-secret")), 0, ErrInvalidSignature},
+		{"RS256 other key", signRS256(t, rs, validClaims(), otherKey), 0, ErrInvalidSignature},
+		{"unknown kid", signRS256(t, map[string]interface{}{"alg": AlgRS256, "kid": "key-2"}, validClaims(), testRSAKey), 0, ErrInvalidSignature},
+		{"single key without kid", signRS256(t, map[string]interface{}{"alg": AlgRS256}, validClaims(), testRSAKey), 0, nil},
+		{"malformed", "not-a-token", 0, ErrMalformedToken},
+		{"expired", signHS256(t, hs, with(validClaims(), map[string]interface{}{"exp": testNow.Add(-time.Minute).Unix()}), secret), 0, ErrTokenExpired},
+		{"expired within leeway", signHS256(t, hs, with(validClaims(), map[string]interface{}{"exp": testNow.Add(-time.Minute).Unix()}), secret), 2 * time.Minute, nil},
+		{"expired beyond leeway", signHS256(t, hs, with(validClaims(), map[string]interface{}{"exp": testNow.Add(-5 * time.Minute).Unix()}), secret), 2 * time.Minute, ErrTokenExpired},
+		{"without exp", signHS256(t, hs, with(validClaims(), map[string]interface{}{"exp": nil}), secret), 0, ErrTokenExpired},
+		{"not yet valid", signHS256(t, hs, with(validClaims(), map[string]interface{}{"nbf": testNow.Add(time.Minute).Unix()}), secret), 0, ErrTokenExpired},
+		{"nbf within leeway", signHS256(t, hs, with(validClaims(), map[string]interface{}{"nbf": testNow.Add(time.Minute).Unix()}), secret), 2 * time.Minute, nil},
+		{"wrong issuer", signHS256(t, hs, with(validClaims(), map[string]interface{}{"iss": "https://evil.test"}), secret), 0, ErrInvalidClaims},
+		{"wrong audience", signHS256(t, hs, with(validClaims(), map[string]interface{}{"aud": "billing-service"}), secret), 0, ErrInvalidClaims},
+		{"audience in a list", signHS256(t, hs, with(validClaims(), map[string]interface{}{"aud": []string{"billing-service", testAudience}}), secret), 0, nil},
+		{"without subject", signHS256(t, hs, with(validClaims(), map[string]interface{}{"sub": nil}), secret), 0, ErrInvalidClaims},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := testVerifier(tt.leeway).Verify(tt.token)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("expected a valid token, got %v", err)
+				}
+				if claims.Subject != "user-1" {
+					t.Errorf("unexpected subject %q", claims.Subject)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyAlgorithmWithoutKey(t *testing.T) {
+	// Sin secreto HMAC, un token HS256 firmado con la clave pública RSA no se acepta
+	v := testVerifier(0)
+	v.hmacSecret = nil
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&testRSAKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := signHS256(t, map[string]interface{}{"alg": AlgHS256}, validClaims(), publicKeyDER)
+	if _, err := v.Verify(token); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Fatalf("expected %v, got %v", ErrUnsupportedAlgorithm, err)
+	}
+
+	// Sin claves RSA no se aceptan tokens RS256
+	v = testVerifier(0)
+	v.rsaKeys = nil
+	token = signRS256(t, map[string]interface{}{"alg": AlgRS256, "kid": testKid}, validClaims(), testRSAKey)
+	if _, err := v.Verify(token); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Fatalf("expected %v, got %v", ErrUnsupportedAlgorithm, err)
+	}
+}
+
+func TestVerifyClaims(t *testing.T) {
+	claims := with(validClaims(), map[string]interface{}{"staff_id": "staff-7", "roles": "admin"})
+	token := signHS256(t, map[string]interface{}{"alg": AlgHS256}, claims, []byte(testSecret))
+
+	got, err := testVerifier(0).Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if got.Subject != "staff-7" {
+		t.Errorf("staff_id must take precedence over sub, got %q", got.Subject)
+	}
+	if len(got.Roles) != 1 || got.Roles[0] != "admin" {
+		t.Errorf("expected a single role from a string claim, got %v", got.Roles)
+	}
+	if got.TenantID != "6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c10" || got.Name != "Ana García" {
+		t.Errorf("unexpected claims %+v", got)
+	}
+}
+
+// writeJWKS writes a JWKS document with keys and returns its path
+func writeJWKS(t *testing.T, keys ...map[string]string) string {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// rsaJWK returns the JWK of the public part of key
+func rsaJWK(kid string, key *rsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"alg": AlgRS256,
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func TestLoadJWKSFile(t *testing.T) {
+	encryptionKey := rsaJWK("enc", testRSAKey)
+	encryptionKey["use"] = "enc"
+	path := writeJWKS(t, rsaJWK(testKid, testRSAKey), encryptionKey, map[string]string{"kty": "EC", "kid": "ec-1"})
+
+	keys, err := LoadJWKSFile(path)
+	if err != nil {
+		t.Fatalf("LoadJWKSFile: %v", err)
+	}
+	if len(keys) != 1 || keys[testKid] == nil || keys[testKid].N.Cmp(testRSAKey.N) != 0 {
+		t.Fatalf("expected only the RSA signing key, got %v", keys)
+	}
+
+	// Un verificador creado con el archivo acepta tokens de esa clave
+	v, err := NewVerifier(&config.AuthConfig{JWKSFile: path, Issuer: testIssuer, Audience: testAudience})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	v.now = func() time.Time { return testNow }
+	token := signRS256(t, map[string]interface{}{"alg": AlgRS256, "kid": testKid}, validClaims(), testRSAKey)
+	if _, err := v.Verify(token); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestLoadJWKSFileErrors(t *testing.T) {
+	badExponent := rsaJWK(testKid, testRSAKey)
+	badExponent["e"] = base64.RawURLEncoding.EncodeToString([]byte{1})
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"missing file", filepath.Join(t.TempDir(), "missing.json"), "failed to read"},
+		{"no signing keys", writeJWKS(t, map[string]string{"kty": "EC", "kid": "ec-1"}), "no RSA signing keys"},
+		{"invalid exponent", writeJWKS(t, badExponent), "unsupported exponent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadJWKSFile(tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	malformed := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(malformed, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadJWKSFile(malformed); err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Fatalf("expected a parse error, got %v", err)
+	}
+}
+
+func TestNewVerifierRequiresKey(t *testing.T) {
+	if _, err := NewVerifier(&config.AuthConfig{}); err == nil {
+		t.Fatal("expected an error without verification keys")
+	}
+}
@@ -0,0 +1,235 @@
+package authz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	customerpb "github.com/encomos/api-encomos/customer-service/proto/customer"
+)
+
+// AnyRole allows a method, note type or export column to every caller
+const AnyRole = "*"
+
+// PermissionDeniedReason is the ErrorInfo reason of a PermissionError status
+const PermissionDeniedReason = "ROLE_NOT_ALLOWED"
+
+// PermissionError explains why a call was denied
+type PermissionError struct {
+	Resource      string
+	RequiredRoles []string
+	CallerRoles   []string
+}
+
+func (e *PermissionError) Error() string {
+	callerRoles := "none"
+	if len(e.CallerRoles) > 0 {
+		callerRoles = strings.Join(e.CallerRoles, ", ")
+	}
+	if len(e.RequiredRoles) == 0 {
+		return fmt.Sprintf("%s is not allowed by the authorization policy (caller roles: %s)", e.Resource, callerRoles)
+	}
+	return fmt.Sprintf("%s requires one of the roles [%s] (caller roles: %s)",
+		e.Resource, strings.Join(e.RequiredRoles, ", "), callerRoles)
+}
+
+// GRPCStatus returns a PermissionDenied status with an ErrorInfo naming the denied resource,
+// the required roles (empty when nothing allows it) and the caller roles
+func (e *PermissionError) GRPCStatus() *status.Status {
+	st := status.New(codes.PermissionDenied, e.Error())
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: PermissionDeniedReason,
+		Domain: customerpb.CustomerService_ServiceDesc.ServiceName,
+		Metadata: map[string]string{
+			"resource":       e.Resource,
+			"required_roles": strings.Join(e.RequiredRoles, ","),
+			"caller_roles":   strings.Join(e.CallerRoles, ","),
+		},
+	})
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// Policy maps each CustomerService full method name to the roles allowed to call it,
+// and restricts who can read or write notes of sensitive types and who can export
+// sensitive customer columns
+type Policy struct {
+	methods       map[string][]string
+	noteTypes     map[string][]string
+	exportColumns map[string][]string
+}
+
+// policyFile is the JSON representation of a Policy
+type policyFile struct {
+	Methods       map[string][]string `json:"methods"`
+	NoteTypes     map[string][]string `json:"note_types"`
+	ExportColumns map[string][]string `json:"export_columns"`
+}
+
+// LoadPolicyFile reads a JSON policy file
+func LoadPolicyFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization policy: %w", err)
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy parses a JSON policy. Method names may be written with or without the
+// leading slash; every name must be a CustomerService method.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var file policyFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse authorization policy: %w", err)
+	}
+
+	return NewPolicy(file.Methods, file.NoteTypes, file.ExportColumns)
+}
+
+// NewPolicy builds a policy from method, note type and export column role lists
+func NewPolicy(methods, noteTypes, exportColumns map[string][]string) (*Policy, error) {
+	known := customerServiceMethods()
+
+	policy := &Policy{
+		methods:       make(map[string][]string, len(methods)),
+		noteTypes:     make(map[string][]string, len(noteTypes)),
+		exportColumns: make(map[string][]string, len(exportColumns)),
+	}
+
+	for method, roles := range methods {
+		fullMethod := "/" + strings.TrimPrefix(method, "/")
+		if !known[fullMethod] {
+			return nil, fmt.Errorf("authorization policy references unknown method %s", method)
+		}
+		if len(roles) == 0 {
+			return nil, fmt.Errorf("authorization policy for %s has no roles", method)
+		}
+		policy.methods[fullMethod] = roles
+	}
+
+	for noteType, roles := range noteTypes {
+		if len(roles) == 0 {
+			return nil, fmt.Errorf("authorization policy for note type %s has no roles", noteType)
+		}
+		policy.noteTypes[noteType] = roles
+	}
+
+	for column, roles := range exportColumns {
+		if !model.IsValidExportColumn(column) {
+			return nil, fmt.Errorf("authorization policy references unknown export column %s", column)
+		}
+		if len(roles) == 0 {
+			return nil, fmt.Errorf("authorization policy for export column %s has no roles", column)
+		}
+		policy.exportColumns[column] = roles
+	}
+
+	return policy, nil
+}
+
+// AuthorizeMethod checks that the caller roles may invoke the full method name.
+// Methods missing from the policy are denied.
+func (p *Policy) AuthorizeMethod(fullMethod string, roles []string) error {
+	allowed, ok := p.methods[fullMethod]
+	if !ok {
+		return &PermissionError{Resource: methodName(fullMethod), CallerRoles: roles}
+	}
+	if !hasAnyRole(allowed, roles) {
+		return &PermissionError{Resource: methodName(fullMethod), RequiredRoles: allowed, CallerRoles: roles}
+	}
+	return nil
+}
+
+// AuthorizeNoteType checks that the caller roles may read or write notes of the given type.
+// Note types missing from the policy are unrestricted.
+func (p *Policy) AuthorizeNoteType(noteType string, roles []string) error {
+	allowed, ok := p.noteTypes[noteType]
+	if !ok || hasAnyRole(allowed, roles) {
+		return nil
+	}
+	return &PermissionError{Resource: fmt.Sprintf("note type %s", noteType), RequiredRoles: allowed, CallerRoles: roles}
+}
+
+// CanAccessNoteType reports whether the caller roles may read notes of the given type
+func (p *Policy) CanAccessNoteType(noteType string, roles []string) bool {
+	return p.AuthorizeNoteType(noteType, roles) == nil
+}
+
+// RestrictedNoteTypes returns the note types the caller roles may not read
+func (p *Policy) RestrictedNoteTypes(roles []string) []string {
+	var restricted []string
+	for noteType, allowed := range p.noteTypes {
+		if !hasAnyRole(allowed, roles) {
+			restricted = append(restricted, noteType)
+		}
+	}
+	return restricted
+}
+
+// AuthorizeExportColumn checks that the caller roles may export the given column.
+// Columns missing from the policy are unrestricted.
+func (p *Policy) AuthorizeExportColumn(column string, roles []string) error {
+	allowed, ok := p.exportColumns[column]
+	if !ok || hasAnyRole(allowed, roles) {
+		return nil
+	}
+	return &PermissionError{Resource: fmt.Sprintf("export column %s", column), RequiredRoles: allowed, CallerRoles: roles}
+}
+
+// RestrictedExportColumns returns the export columns the caller roles may not read
+func (p *Policy) RestrictedExportColumns(roles []string) []string {
+	var restricted []string
+	for column, allowed := range p.exportColumns {
+		if !hasAnyRole(allowed, roles) {
+			restricted = append(restricted, column)
+		}
+	}
+	return restricted
+}
+
+// hasAnyRole checks if any caller role is allowed
+func hasAnyRole(allowed, roles []string) bool {
+	for _, a := range allowed {
+		if a == AnyRole {
+			return true
+		}
+		for _, r := range roles {
+			if a == r {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// methodName returns the short RPC name used in error messages
+func methodName(fullMethod string) string {
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[i+1:]
+	}
+	return fullMethod
+}
+
+// customerServiceMethods returns the full method names served by CustomerService
+func customerServiceMethods() map[string]bool {
+	desc := customerpb.CustomerService_ServiceDesc
+	methods := make(map[string]bool, len(desc.Methods)+len(desc.Streams))
+	for _, m := range desc.Methods {
+		methods["/"+desc.ServiceName+"/"+m.MethodName] = true
+	}
+	for _, s := range desc.Streams {
+		methods["/"+desc.ServiceName+"/"+s.StreamName] = true
+	}
+	return methods
+}
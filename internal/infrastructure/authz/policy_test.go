@@ -0,0 +1,207 @@
+package authz
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testPolicy = `{
+  "methods": {
+    "customer.v1.CustomerService/GetCustomer": ["admin", "manager", "staff"],
+    "/customer.v1.CustomerService/DeleteCustomer": ["admin"],
+    "customer.v1.CustomerService/ListCustomers": ["*"]
+  },
+  "note_types": {
+    "warning": ["admin", "manager"]
+  },
+  "export_columns": {
+    "tax_id": ["admin"],
+    "total_spent": ["admin", "manager"]
+  }
+}`
+
+const (
+	getCustomer    = "/customer.v1.CustomerService/GetCustomer"
+	deleteCustomer = "/customer.v1.CustomerService/DeleteCustomer"
+	listCustomers  = "/customer.v1.CustomerService/ListCustomers"
+	updateCustomer = "/customer.v1.CustomerService/UpdateCustomer"
+)
+
+func mustParsePolicy(t *testing.T) *Policy {
+	t.Helper()
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	return policy
+}
+
+func TestAuthorizeMethod(t *testing.T) {
+	policy := mustParsePolicy(t)
+
+	tests := []struct {
+		name    string
+		method  string
+		roles   []string
+		allowed bool
+	}{
+		{"listed role", getCustomer, []string{"staff"}, true},
+		{"one of several roles", deleteCustomer, []string{"staff", "admin"}, true},
+		{"missing role", deleteCustomer, []string{"manager", "staff"}, false},
+		{"no roles", getCustomer, nil, false},
+		{"any role", listCustomers, nil, true},
+		{"method not in policy", updateCustomer, []string{"admin"}, false},
+		{"unknown method", "/customer.v1.CustomerService/DropTenant", []string{"admin"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.AuthorizeMethod(tt.method, tt.roles)
+			if tt.allowed && err != nil {
+				t.Fatalf("expected %s to be allowed for %v, got %v", tt.method, tt.roles, err)
+			}
+			if !tt.allowed {
+				var permissionErr *PermissionError
+				if !errors.As(err, &permissionErr) {
+					t.Fatalf("expected a PermissionError for %s with %v, got %v", tt.method, tt.roles, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthorizeMethodDenyByDefault(t *testing.T) {
+	policy := mustParsePolicy(t)
+
+	// Un método sin entrada no exige roles concretos: no lo permite nadie
+	err := policy.AuthorizeMethod(updateCustomer, []string{"admin"})
+	var permissionErr *PermissionError
+	if !errors.As(err, &permissionErr) {
+		t.Fatalf("expected a PermissionError, got %v", err)
+	}
+	if len(permissionErr.RequiredRoles) != 0 {
+		t.Errorf("expected no required roles, got %v", permissionErr.RequiredRoles)
+	}
+	if !strings.Contains(err.Error(), "UpdateCustomer is not allowed") {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
+
+func TestPermissionErrorStatus(t *testing.T) {
+	policy := mustParsePolicy(t)
+
+	err := policy.AuthorizeMethod(deleteCustomer, []string{"staff", "manager"})
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("expected a gRPC status, got %v", err)
+	}
+	if st.Code() != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %s", st.Code())
+	}
+
+	info := errorInfo(t, st)
+	if info.Reason != PermissionDeniedReason {
+		t.Errorf("expected reason %s, got %s", PermissionDeniedReason, info.Reason)
+	}
+	want := map[string]string{
+		"resource":       "DeleteCustomer",
+		"required_roles": "admin",
+		"caller_roles":   "staff,manager",
+	}
+	for key, value := range want {
+		if info.Metadata[key] != value {
+			t.Errorf("metadata %s: expected %q, got %q", key, value, info.Metadata[key])
+		}
+	}
+}
+
+func TestAuthorizeNoteType(t *testing.T) {
+	policy := mustParsePolicy(t)
+
+	if err := policy.AuthorizeNoteType("warning", []string{"manager"}); err != nil {
+		t.Errorf("manager should write warning notes: %v", err)
+	}
+	if err := policy.AuthorizeNoteType("warning", []string{"staff"}); err == nil {
+		t.Error("staff should not write warning notes")
+	}
+	if err := policy.AuthorizeNoteType("general", nil); err != nil {
+		t.Errorf("note types missing from the policy are unrestricted: %v", err)
+	}
+	if policy.CanAccessNoteType("warning", []string{"staff"}) {
+		t.Error("staff should not read warning notes")
+	}
+
+	if restricted := policy.RestrictedNoteTypes([]string{"staff"}); len(restricted) != 1 || restricted[0] != "warning" {
+		t.Errorf("expected [warning] restricted for staff, got %v", restricted)
+	}
+	if restricted := policy.RestrictedNoteTypes([]string{"admin"}); len(restricted) != 0 {
+		t.Errorf("expected nothing restricted for admin, got %v", restricted)
+	}
+}
+
+func TestAuthorizeExportColumn(t *testing.T) {
+	policy := mustParsePolicy(t)
+
+	if err := policy.AuthorizeExportColumn("tax_id", []string{"admin"}); err != nil {
+		t.Errorf("admin should export tax_id: %v", err)
+	}
+	if err := policy.AuthorizeExportColumn("tax_id", []string{"manager"}); err == nil {
+		t.Error("manager should not export tax_id")
+	}
+	if err := policy.AuthorizeExportColumn("email", []string{"staff"}); err != nil {
+		t.Errorf("columns missing from the policy are unrestricted: %v", err)
+	}
+
+	restricted := policy.RestrictedExportColumns([]string{"staff"})
+	sort.Strings(restricted)
+	if strings.Join(restricted, ",") != "tax_id,total_spent" {
+		t.Errorf("expected tax_id and total_spent restricted for staff, got %v", restricted)
+	}
+	if restricted := policy.RestrictedExportColumns([]string{"manager"}); len(restricted) != 1 || restricted[0] != "tax_id" {
+		t.Errorf("expected [tax_id] restricted for manager, got %v", restricted)
+	}
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   string
+	}{
+		{"malformed JSON", `{"methods": {`, "failed to parse"},
+		{"wrong type", `{"methods": {"customer.v1.CustomerService/GetCustomer": "admin"}}`, "failed to parse"},
+		{"unknown field", `{"roles": {}}`, "failed to parse"},
+		{"unknown method", `{"methods": {"customer.v1.CustomerService/DropTenant": ["admin"]}}`, "unknown method"},
+		{"method without roles", `{"methods": {"customer.v1.CustomerService/GetCustomer": []}}`, "has no roles"},
+		{"note type without roles", `{"note_types": {"warning": []}}`, "has no roles"},
+		{"unknown export column", `{"export_columns": {"password": ["admin"]}}`, "unknown export column"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tt.policy))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// errorInfo returns the ErrorInfo detail of st
+func errorInfo(t *testing.T, st *status.Status) *errdetails.ErrorInfo {
+	t.Helper()
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	t.Fatalf("status %v has no ErrorInfo", st)
+	return nil
+}
@@ -0,0 +1,302 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxBodyBytes limits JSON request bodies (event batches included)
+const maxBodyBytes = 4 << 20
+
+// Límites de los archivos subidos a las rutas de carga (importaciones)
+const (
+	maxUploadBytes   = 64 << 20
+	uploadChunkBytes = 64 << 10
+)
+
+var (
+	unmarshalOptions = protojson.UnmarshalOptions{}
+	marshalOptions   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+)
+
+// BindingError reports a request that could not be translated to a protobuf message
+type BindingError struct {
+	Message string
+}
+
+func (e *BindingError) Error() string {
+	return e.Message
+}
+
+// bindRequest fills msg from the JSON body (when the route takes one), the query string
+// and the path wildcards, in that order; path values win
+func bindRequest(r *http.Request, rt route, msg proto.Message) error {
+	if rt.body {
+		data, err := decodeBody(r, msg)
+		if err != nil {
+			return err
+		}
+		if rt.patch {
+			if err := defaultUpdateMask(msg, data, rt); err != nil {
+				return err
+			}
+		}
+	}
+
+	for key, values := range r.URL.Query() {
+		if err := setField(msg, key, values); err != nil {
+			return err
+		}
+	}
+
+	for wildcard, field := range rt.pathParams {
+		if err := setField(msg, field, []string{r.PathValue(wildcard)}); err != nil {
+			return err
+		}
+	}
+
+	return bindIfMatch(r, msg)
+}
+
+// bindIfMatch uses the If-Match header (the resource version, e.g. "7") as expected_version
+// when the request message has that field and the client did not set it
+func bindIfMatch(r *http.Request, msg proto.Message) error {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return nil
+	}
+
+	m := msg.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName("expected_version")
+	if fd == nil || m.Has(fd) {
+		return nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(header, "W/"), `"`), 10, 64)
+	if err != nil || version <= 0 {
+		return &BindingError{Message: fmt.Sprintf("invalid If-Match header %q: must be the resource version", header)}
+	}
+	m.Set(fd, protoreflect.ValueOfInt64(version))
+	return nil
+}
+
+// decodeBody reads a protojson body into msg and returns the raw body; an empty body
+// leaves msg untouched
+func decodeBody(r *http.Request, msg proto.Message) ([]byte, error) {
+	data, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if err := unmarshalOptions.Unmarshal(data, msg); err != nil {
+		return nil, &BindingError{Message: fmt.Sprintf("invalid JSON body: %v", err)}
+	}
+	return data, nil
+}
+
+// defaultUpdateMask sets update_mask to the top-level fields present in a PATCH body when
+// the client did not send one. Path wildcards (the resource ID) are not part of the mask.
+func defaultUpdateMask(msg proto.Message, data []byte, rt route) error {
+	m := msg.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName("update_mask")
+	if fd == nil || m.Has(fd) || len(data) == 0 {
+		return nil
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(data, &body); err != nil {
+		return &BindingError{Message: fmt.Sprintf("invalid JSON body: %v", err)}
+	}
+
+	skip := map[string]bool{"update_mask": true}
+	for _, field := range rt.pathParams {
+		skip[field] = true
+	}
+
+	fields := m.Descriptor().Fields()
+	paths := make([]string, 0, len(body))
+	for key := range body {
+		field := fields.ByName(protoreflect.Name(key))
+		if field == nil {
+			field = fields.ByJSONName(key)
+		}
+		if field == nil || skip[string(field.Name())] {
+			continue
+		}
+		paths = append(paths, string(field.Name()))
+	}
+	sort.Strings(paths)
+
+	m.Set(fd, protoreflect.ValueOfMessage((&fieldmaskpb.FieldMask{Paths: paths}).ProtoReflect()))
+	return nil
+}
+
+// readBody reads the request body up to maxBodyBytes
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	if err != nil {
+		return nil, &BindingError{Message: fmt.Sprintf("failed to read request body: %v", err)}
+	}
+	if len(data) > maxBodyBytes {
+		return nil, &BindingError{Message: fmt.Sprintf("request body exceeds %d bytes", maxBodyBytes)}
+	}
+	return data, nil
+}
+
+// setField assigns string values to the message field named by its proto or JSON name
+func setField(msg proto.Message, name string, values []string) error {
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+
+	fd := fields.ByName(protoreflect.Name(name))
+	if fd == nil {
+		fd = fields.ByJSONName(name)
+	}
+	if fd == nil {
+		return &BindingError{Message: fmt.Sprintf("unknown parameter %s", name)}
+	}
+
+	if fd.IsList() {
+		list := m.Mutable(fd).List()
+		for _, raw := range values {
+			for _, part := range strings.Split(raw, ",") {
+				if part = strings.TrimSpace(part); part == "" {
+					continue
+				}
+				value, err := parseValue(fd, part)
+				if err != nil {
+					return err
+				}
+				list.Append(value)
+			}
+		}
+		return nil
+	}
+
+	if fd.IsMap() {
+		return setMapField(m, fd, values)
+	}
+
+	if len(values) == 0 {
+		return &BindingError{Message: fmt.Sprintf("parameter %s cannot be set from the URL", name)}
+	}
+
+	value, err := parseValue(fd, values[len(values)-1])
+	if err != nil {
+		return err
+	}
+	m.Set(fd, value)
+	return nil
+}
+
+// setMapField fills a map<string, string> field from "key:value" pairs separated by commas
+func setMapField(m protoreflect.Message, fd protoreflect.FieldDescriptor, values []string) error {
+	if fd.MapKey().Kind() != protoreflect.StringKind || fd.MapValue().Kind() != protoreflect.StringKind {
+		return &BindingError{Message: fmt.Sprintf("parameter %s cannot be set from the URL", fd.Name())}
+	}
+
+	entries := m.Mutable(fd).Map()
+	for _, raw := range values {
+		for _, part := range strings.Split(raw, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(part, ":")
+			if !ok {
+				return &BindingError{Message: fmt.Sprintf("invalid value %q for parameter %s: expected key:value", part, fd.Name())}
+			}
+			entries.Set(protoreflect.ValueOfString(strings.TrimSpace(key)).MapKey(), protoreflect.ValueOfString(strings.TrimSpace(value)))
+		}
+	}
+	return nil
+}
+
+// parseValue converts a URL value to the protobuf field kind
+func parseValue(fd protoreflect.FieldDescriptor, raw string) (protoreflect.Value, error) {
+	invalid := func() (protoreflect.Value, error) {
+		return protoreflect.Value{}, &BindingError{Message: fmt.Sprintf("invalid value %q for parameter %s", raw, fd.Name())}
+	}
+
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(raw), nil
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfBool(v), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfInt32(int32(v)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfInt64(v), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfUint32(uint32(v)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfUint64(v), nil
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(raw, 32)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfFloat32(float32(v)), nil
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfFloat64(v), nil
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(raw)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		v, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return invalid()
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), nil
+	case protoreflect.MessageKind:
+		if fd.Message().FullName() == "google.protobuf.Timestamp" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return invalid()
+			}
+			return protoreflect.ValueOfMessage(timestamppb.New(t).ProtoReflect()), nil
+		}
+	}
+
+	return protoreflect.Value{}, &BindingError{Message: fmt.Sprintf("parameter %s cannot be set from the URL", fd.Name())}
+}
@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	corsAllowedMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsMaxAge         = "600"
+)
+
+// handleCORS sets the CORS headers for allowed origins and answers preflight requests.
+// Returns true when the request has been fully handled.
+func (g *Gateway) handleCORS(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+
+	w.Header().Add("Vary", "Origin")
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+	allowOrigin, ok := g.allowOrigin(origin)
+	if !ok {
+		if preflight {
+			w.WriteHeader(http.StatusForbidden)
+			return true
+		}
+		return false
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+
+	if !preflight {
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
+		return false
+	}
+
+	w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+	w.Header().Set("Access-Control-Allow-Headers", strings.Join(append([]string{"Content-Type", "If-Match"}, forwardedHeaders...), ", "))
+	w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for an origin, if allowed
+func (g *Gateway) allowOrigin(origin string) (string, bool) {
+	for _, allowed := range g.allowedOrigins {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" {
+			return "*", true
+		}
+		if strings.EqualFold(allowed, origin) {
+			return origin, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,715 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+	"github.com/encomos/api-encomos/customer-service/internal/requestid"
+	customerpb "github.com/encomos/api-encomos/customer-service/proto/customer"
+)
+
+// route maps a REST endpoint to a CustomerService RPC
+type route struct {
+	pattern    string            // net/http ServeMux pattern (method and path)
+	rpc        string            // CustomerService method name
+	body       bool              // the JSON body is decoded into the request message
+	patch      bool              // update_mask defaults to the fields present in the body
+	pathParams map[string]string // path wildcard -> request field
+	upload     string            // bytes field receiving the raw body in chunks (bidirectional streams)
+	download   string            // bytes field written as the raw response body (server streams)
+}
+
+// routes is the REST mapping of every CustomerService RPC
+var routes = []route{
+	{pattern: "GET /v1/customers", rpc: "ListCustomers"},
+	{pattern: "POST /v1/customers", rpc: "CreateCustomer", body: true},
+	{pattern: "GET /v1/customers/search", rpc: "SearchCustomers"},
+	{pattern: "GET /v1/customers/duplicates", rpc: "FindDuplicateCustomers"},
+	{pattern: "GET /v1/customers/deleted", rpc: "ListDeletedCustomers"},
+	{pattern: "GET /v1/customers/{id}", rpc: "GetCustomer", pathParams: map[string]string{"id": "id"}},
+	{pattern: "PUT /v1/customers/{id}", rpc: "UpdateCustomer", body: true, pathParams: map[string]string{"id": "id"}},
+	{pattern: "PATCH /v1/customers/{id}", rpc: "UpdateCustomer", body: true, patch: true, pathParams: map[string]string{"id": "id"}},
+	{pattern: "DELETE /v1/customers/{id}", rpc: "DeleteCustomer", pathParams: map[string]string{"id": "id"}},
+	{pattern: "POST /v1/customers/{id}/merge", rpc: "MergeCustomers", body: true, pathParams: map[string]string{"id": "survivor_id"}},
+	{pattern: "POST /v1/customers/{id}/restore", rpc: "RestoreCustomer", body: true, pathParams: map[string]string{"id": "id"}},
+	{pattern: "POST /v1/customers/{id}/anonymize", rpc: "AnonymizeCustomer", body: true, pathParams: map[string]string{"id": "id"}},
+	{pattern: "GET /v1/customers/{id}/data-export", rpc: "ExportCustomerData", download: "data", pathParams: map[string]string{"id": "id"}},
+	{pattern: "GET /v1/customers/{id}/duplicates", rpc: "FindDuplicateCustomers", pathParams: map[string]string{"id": "customer_id"}},
+	{pattern: "GET /v1/customers/{id}/history", rpc: "GetCustomerHistory", pathParams: map[string]string{"id": "customer_id"}},
+	{pattern: "POST /v1/customers/{id}/notes", rpc: "AddCustomerNote", body: true, pathParams: map[string]string{"id": "customer_id"}},
+	{pattern: "GET /v1/customers/{id}/notes", rpc: "ListCustomerNotes", pathParams: map[string]string{"id": "customer_id"}},
+	{pattern: "GET /v1/customers/{id}/vehicles", rpc: "ListVehicles", pathParams: map[string]string{"id": "customer_id"}},
+	{pattern: "POST /v1/customers/{id}/vehicles", rpc: "CreateVehicle", body: true, pathParams: map[string]string{"id": "customer_id"}},
+	{pattern: "GET /v1/vehicles/{id}", rpc: "GetVehicle", pathParams: map[string]string{"id": "id"}},
+	{pattern: "PUT /v1/vehicles/{id}", rpc: "UpdateVehicle", body: true, pathParams: map[string]string{"id": "id"}},
+	{pattern: "PATCH /v1/vehicles/{id}", rpc: "UpdateVehicle", body: true, patch: true, pathParams: map[string]string{"id": "id"}},
+	{pattern: "DELETE /v1/vehicles/{id}", rpc: "DeleteVehicle", pathParams: map[string]string{"id": "id"}},
+	{pattern: "POST /v1/customer-events", rpc: "IngestCustomerEvents", body: true},
+	{pattern: "POST /v1/customers/import", rpc: "ImportCustomers", upload: "chunk"},
+	{pattern: "GET /v1/customers/export", rpc: "ExportCustomers", download: "data"},
+	{pattern: "GET /v1/audit-log", rpc: "GetCustomerAuditLog"},
+	{pattern: "GET /v1/customers/{id}/audit-log", rpc: "GetCustomerAuditLog", pathParams: map[string]string{"id": "customer_id"}},
+}
+
+// forwardedHeaders are copied to the incoming gRPC metadata (lower-cased)
+var forwardedHeaders = []string{
+	"X-Tenant-ID",
+	"X-Tenant-License",
+	"X-Tenant-Locale",
+	"X-Tenant-Timezone",
+	"Authorization",
+	"X-Request-ID",
+}
+
+// staffHeaders carry the staff identity resolved by the API Gateway. Without a token they
+// decide the roles of the caller, so they are only forwarded from trusted proxies.
+var staffHeaders = []string{
+	"X-Staff-ID",
+	"X-Staff-Name",
+	"X-Staff-Roles",
+}
+
+// Gateway translates REST/JSON requests to CustomerService calls. Requests run through
+// the same interceptor chain as gRPC calls (tenant, authentication, authorization, logging).
+type Gateway struct {
+	server            customerpb.CustomerServiceServer
+	unaryInterceptor  grpc.UnaryServerInterceptor
+	streamInterceptor grpc.StreamServerInterceptor
+	allowedOrigins    []string
+	trustedProxies    []*net.IPNet
+	mux               *http.ServeMux
+	logger            *logger.Logger
+}
+
+// NewGateway creates the REST gateway. Every CustomerService RPC must have a route.
+// trustedProxies lists the IPs or CIDRs allowed to send the staff identity headers.
+func NewGateway(
+	server customerpb.CustomerServiceServer,
+	unaryInterceptor grpc.UnaryServerInterceptor,
+	streamInterceptor grpc.StreamServerInterceptor,
+	allowedOrigins []string,
+	trustedProxies []string,
+	logger *logger.Logger,
+) (*Gateway, error) {
+	proxies, err := parseTrustedProxies(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Gateway{
+		server:            server,
+		unaryInterceptor:  unaryInterceptor,
+		streamInterceptor: streamInterceptor,
+		allowedOrigins:    allowedOrigins,
+		trustedProxies:    proxies,
+		mux:               http.NewServeMux(),
+		logger:            logger,
+	}
+
+	desc := customerpb.CustomerService_ServiceDesc
+	mapped := make(map[string]bool, len(routes))
+
+	for _, rt := range routes {
+		handler, err := g.routeHandler(desc, rt)
+		if err != nil {
+			return nil, err
+		}
+		g.mux.Handle(rt.pattern, handler)
+		mapped[rt.rpc] = true
+	}
+
+	for _, m := range desc.Methods {
+		if !mapped[m.MethodName] {
+			return nil, fmt.Errorf("REST gateway has no route for %s", m.MethodName)
+		}
+	}
+	for _, s := range desc.Streams {
+		if !mapped[s.StreamName] {
+			return nil, fmt.Errorf("REST gateway has no route for %s", s.StreamName)
+		}
+	}
+
+	return g, nil
+}
+
+// ServeHTTP applies CORS and dispatches to the RPC routes
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if g.handleCORS(w, r) {
+		return
+	}
+
+	// El ID de la petición se genera aquí si falta, para devolverlo aunque la petición falle
+	requestID := r.Header.Get("X-Request-ID")
+	if !requestid.Valid(requestID) {
+		requestID = requestid.New()
+		r.Header.Set("X-Request-ID", requestID)
+	}
+	w.Header().Set("X-Request-ID", requestID)
+
+	g.mux.ServeHTTP(w, r)
+}
+
+// routeHandler builds the HTTP handler for a route
+func (g *Gateway) routeHandler(desc grpc.ServiceDesc, rt route) (http.Handler, error) {
+	fullMethod := "/" + desc.ServiceName + "/" + rt.rpc
+
+	for i := range desc.Methods {
+		method := desc.Methods[i]
+		if method.MethodName != rt.rpc {
+			continue
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			g.serveUnary(w, r, rt, method)
+		}), nil
+	}
+
+	for i := range desc.Streams {
+		stream := desc.Streams[i]
+		if stream.StreamName != rt.rpc {
+			continue
+		}
+		if rt.upload != "" {
+			if !stream.ClientStreams || !stream.ServerStreams {
+				return nil, fmt.Errorf("REST gateway upload route %s needs a bidirectional stream", rt.pattern)
+			}
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				g.serveUpload(w, r, rt, fullMethod, stream)
+			}), nil
+		}
+		if rt.download != "" {
+			if stream.ClientStreams || !stream.ServerStreams {
+				return nil, fmt.Errorf("REST gateway download route %s needs a server stream", rt.pattern)
+			}
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				g.serveDownload(w, r, rt, fullMethod, stream)
+			}), nil
+		}
+		if !stream.ClientStreams || stream.ServerStreams {
+			return nil, fmt.Errorf("REST gateway only supports client streaming for %s", rt.rpc)
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			g.serveClientStream(w, r, fullMethod, stream)
+		}), nil
+	}
+
+	return nil, fmt.Errorf("REST gateway route %s references unknown method %s", rt.pattern, rt.rpc)
+}
+
+// serveUnary decodes the request, calls the generated method handler through the
+// interceptor chain and writes the JSON response
+func (g *Gateway) serveUnary(w http.ResponseWriter, r *http.Request, rt route, method grpc.MethodDesc) {
+	var bindErr error
+	dec := func(in interface{}) error {
+		msg, ok := in.(proto.Message)
+		if !ok {
+			return status.Errorf(codes.Internal, "unexpected request type %T", in)
+		}
+		if err := bindRequest(r, rt, msg); err != nil {
+			bindErr = err
+			return err
+		}
+		return nil
+	}
+
+	resp, err := method.Handler(g.server, g.incomingContext(r), dec, g.unaryInterceptor)
+	if bindErr != nil {
+		g.writeError(w, status.Error(codes.InvalidArgument, bindErr.Error()))
+		return
+	}
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+
+	g.writeMessage(w, http.StatusOK, resp)
+}
+
+// serveClientStream feeds the "events" array of the JSON body to a client-streaming RPC
+func (g *Gateway) serveClientStream(w http.ResponseWriter, r *http.Request, fullMethod string, desc grpc.StreamDesc) {
+	data, err := readBody(r)
+	if err != nil {
+		g.writeError(w, status.Error(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	var body struct {
+		Events []json.RawMessage `json:"events"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		g.writeError(w, status.Errorf(codes.InvalidArgument, "invalid JSON body: %v", err))
+		return
+	}
+
+	stream := &jsonClientStream{ctx: g.incomingContext(r), requests: body.Events}
+	info := &grpc.StreamServerInfo{
+		FullMethod:     fullMethod,
+		IsClientStream: desc.ClientStreams,
+		IsServerStream: desc.ServerStreams,
+	}
+
+	if g.streamInterceptor != nil {
+		err = g.streamInterceptor(g.server, stream, info, desc.Handler)
+	} else {
+		err = desc.Handler(g.server, stream)
+	}
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	if stream.response == nil {
+		g.writeError(w, status.Error(codes.Internal, "stream closed without a response"))
+		return
+	}
+
+	g.writeMessage(w, http.StatusOK, stream.response)
+}
+
+// serveUpload streams the raw request body to a bidirectional RPC: the first message is
+// bound from the query string and path, and every message carries the next chunk of the
+// body in the route's upload field. The responses are written as NDJSON as they are sent;
+// an error after the first response is written as a last {"error": status} line.
+func (g *Gateway) serveUpload(w http.ResponseWriter, r *http.Request, rt route, fullMethod string, desc grpc.StreamDesc) {
+	stream := &uploadStream{
+		ctx:  g.incomingContext(r),
+		r:    r,
+		rt:   rt,
+		body: http.MaxBytesReader(w, r.Body, maxUploadBytes),
+		w:    w,
+	}
+	info := &grpc.StreamServerInfo{
+		FullMethod:     fullMethod,
+		IsClientStream: desc.ClientStreams,
+		IsServerStream: desc.ServerStreams,
+	}
+
+	var err error
+	if g.streamInterceptor != nil {
+		err = g.streamInterceptor(g.server, stream, info, desc.Handler)
+	} else {
+		err = desc.Handler(g.server, stream)
+	}
+	if stream.bindErr != nil {
+		err = status.Error(codes.InvalidArgument, stream.bindErr.Error())
+	}
+	if err == nil {
+		return
+	}
+
+	if !stream.sent {
+		g.writeError(w, err)
+		return
+	}
+
+	data, marshalErr := marshalOptions.Marshal(status.Convert(err).Proto())
+	if marshalErr != nil {
+		g.logger.WithError(marshalErr).Error("failed to marshal gateway error")
+		return
+	}
+	stream.writeLine(append(append([]byte(`{"error":`), data...), '}'))
+}
+
+// serveDownload calls a server-streaming RPC bound from the query string and path and
+// writes the route's download field of every response as the raw body. The content_type
+// and filename fields of the responses, when set, become the Content-Type and
+// Content-Disposition headers. An error before the first byte is written as a JSON status;
+// after it the connection is aborted so the client does not take a truncated file as complete.
+func (g *Gateway) serveDownload(w http.ResponseWriter, r *http.Request, rt route, fullMethod string, desc grpc.StreamDesc) {
+	stream := &downloadStream{
+		ctx: g.incomingContext(r),
+		r:   r,
+		rt:  rt,
+		w:   w,
+	}
+	info := &grpc.StreamServerInfo{
+		FullMethod:     fullMethod,
+		IsClientStream: desc.ClientStreams,
+		IsServerStream: desc.ServerStreams,
+	}
+
+	var err error
+	if g.streamInterceptor != nil {
+		err = g.streamInterceptor(g.server, stream, info, desc.Handler)
+	} else {
+		err = desc.Handler(g.server, stream)
+	}
+	if stream.bindErr != nil {
+		err = status.Error(codes.InvalidArgument, stream.bindErr.Error())
+	}
+	if err == nil {
+		stream.writeHeader()
+		return
+	}
+
+	if !stream.wroteHeader {
+		g.writeError(w, err)
+		return
+	}
+
+	g.logger.WithFields(map[string]interface{}{
+		"method": fullMethod,
+	}).WithError(err).Error("download aborted after the response started")
+	panic(http.ErrAbortHandler)
+}
+
+// incomingContext exposes the forwarded HTTP headers as incoming gRPC metadata. The staff
+// headers of clients that are not trusted proxies are dropped.
+func (g *Gateway) incomingContext(r *http.Request) context.Context {
+	headers := forwardedHeaders
+	if g.fromTrustedProxy(r) {
+		headers = append(append([]string{}, forwardedHeaders...), staffHeaders...)
+	}
+
+	md := metadata.MD{}
+	for _, header := range headers {
+		if values := r.Header.Values(header); len(values) > 0 {
+			md.Append(strings.ToLower(header), values...)
+		}
+	}
+	return metadata.NewIncomingContext(r.Context(), md)
+}
+
+// fromTrustedProxy checks whether the request comes directly from a trusted proxy
+func (g *Gateway) fromTrustedProxy(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range g.trustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies parses IPs and CIDRs; a bare IP trusts only that address
+func parseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", value)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// writeMessage writes a protobuf message as JSON
+func (g *Gateway) writeMessage(w http.ResponseWriter, code int, resp interface{}) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		g.writeError(w, status.Errorf(codes.Internal, "unexpected response type %T", resp))
+		return
+	}
+
+	data, err := marshalOptions.Marshal(msg)
+	if err != nil {
+		g.logger.WithError(err).Error("failed to marshal gateway response")
+		g.writeError(w, status.Error(codes.Internal, "failed to encode response"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// writeError writes a gRPC status as a google.rpc.Status JSON body
+func (g *Gateway) writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+
+	data, marshalErr := marshalOptions.Marshal(st.Proto())
+	if marshalErr != nil {
+		g.logger.WithError(marshalErr).Error("failed to marshal gateway error")
+		data = []byte(`{"code":` + strconv.Itoa(int(codes.Internal)) + `,"message":"internal server error"}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(HTTPStatusFromCode(st.Code()))
+	w.Write(data)
+}
+
+// HTTPStatusFromCode maps a gRPC status code to the equivalent HTTP status
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// jsonClientStream serves a client-streaming RPC from already decoded JSON messages
+type jsonClientStream struct {
+	ctx      context.Context
+	requests []json.RawMessage
+	next     int
+	response proto.Message
+}
+
+func (s *jsonClientStream) SetHeader(metadata.MD) error  { return nil }
+func (s *jsonClientStream) SendHeader(metadata.MD) error { return nil }
+func (s *jsonClientStream) SetTrailer(metadata.MD)       {}
+func (s *jsonClientStream) Context() context.Context     { return s.ctx }
+
+// SendMsg records the response sent by SendAndClose
+func (s *jsonClientStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type %T", m)
+	}
+	s.response = msg
+	return nil
+}
+
+// RecvMsg decodes the next JSON message, returning io.EOF after the last one
+func (s *jsonClientStream) RecvMsg(m interface{}) error {
+	if s.next >= len(s.requests) {
+		return io.EOF
+	}
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type %T", m)
+	}
+
+	raw := s.requests[s.next]
+	s.next++
+	if err := unmarshalOptions.Unmarshal(raw, msg); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid message %d: %v", s.next, err)
+	}
+	return nil
+}
+
+// uploadStream serves a bidirectional RPC from a raw request body, writing the responses as NDJSON
+type uploadStream struct {
+	ctx     context.Context
+	r       *http.Request
+	rt      route
+	body    io.Reader
+	w       http.ResponseWriter
+	started bool // se envió el primer mensaje
+	sent    bool // se escribió alguna respuesta
+	bindErr error
+}
+
+func (s *uploadStream) SetHeader(metadata.MD) error  { return nil }
+func (s *uploadStream) SendHeader(metadata.MD) error { return nil }
+func (s *uploadStream) SetTrailer(metadata.MD)       {}
+func (s *uploadStream) Context() context.Context     { return s.ctx }
+
+// SendMsg writes a response as one NDJSON line and flushes it to the client
+func (s *uploadStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type %T", m)
+	}
+	data, err := marshalOptions.Marshal(msg)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode response: %v", err)
+	}
+
+	if !s.sent {
+		s.w.Header().Set("Content-Type", "application/x-ndjson")
+		s.w.WriteHeader(http.StatusOK)
+		s.sent = true
+	}
+	return s.writeLine(data)
+}
+
+// writeLine writes data followed by a newline and flushes it
+func (s *uploadStream) writeLine(data []byte) error {
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err := http.NewResponseController(s.w).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// RecvMsg returns the next chunk of the body; the first message also carries the parameters
+// of the URL. It returns io.EOF once the body has been sent.
+func (s *uploadStream) RecvMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type %T", m)
+	}
+
+	chunk := make([]byte, uploadChunkBytes)
+	n, err := io.ReadFull(s.body, chunk)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return status.Errorf(codes.InvalidArgument, "request body exceeds %d bytes", maxUploadBytes)
+		}
+		return status.Errorf(codes.InvalidArgument, "failed to read request body: %v", err)
+	}
+	if n == 0 && s.started {
+		return io.EOF
+	}
+
+	if !s.started {
+		s.started = true
+		if err := bindRequest(s.r, s.rt, msg); err != nil {
+			s.bindErr = err
+			return err
+		}
+	}
+
+	reflected := msg.ProtoReflect()
+	fd := reflected.Descriptor().Fields().ByName(protoreflect.Name(s.rt.upload))
+	if fd == nil || fd.Kind() != protoreflect.BytesKind {
+		return status.Errorf(codes.Internal, "upload field %s is not a bytes field", s.rt.upload)
+	}
+	reflected.Set(fd, protoreflect.ValueOfBytes(chunk[:n]))
+	return nil
+}
+
+// downloadStream serves a server-streaming RPC, writing its responses as a raw body
+type downloadStream struct {
+	ctx         context.Context
+	r           *http.Request
+	rt          route
+	w           http.ResponseWriter
+	received    bool
+	contentType string
+	filename    string
+	wroteHeader bool
+	bindErr     error
+}
+
+func (s *downloadStream) SetHeader(metadata.MD) error  { return nil }
+func (s *downloadStream) SendHeader(metadata.MD) error { return nil }
+func (s *downloadStream) SetTrailer(metadata.MD)       {}
+func (s *downloadStream) Context() context.Context     { return s.ctx }
+
+// SendMsg records the content type and file name of a response and writes its data
+func (s *downloadStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected response type %T", m)
+	}
+
+	reflected := msg.ProtoReflect()
+	fields := reflected.Descriptor().Fields()
+	if fd := fields.ByName("content_type"); fd != nil && fd.Kind() == protoreflect.StringKind && reflected.Get(fd).String() != "" {
+		s.contentType = reflected.Get(fd).String()
+	}
+	if fd := fields.ByName("filename"); fd != nil && fd.Kind() == protoreflect.StringKind && reflected.Get(fd).String() != "" {
+		s.filename = reflected.Get(fd).String()
+	}
+
+	fd := fields.ByName(protoreflect.Name(s.rt.download))
+	if fd == nil || fd.Kind() != protoreflect.BytesKind {
+		return status.Errorf(codes.Internal, "download field %s is not a bytes field", s.rt.download)
+	}
+	data := reflected.Get(fd).Bytes()
+	if len(data) == 0 {
+		return nil
+	}
+
+	// Las cabeceras se escriben con el primer dato, para poder responder un error antes
+	s.writeHeader()
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	if err := http.NewResponseController(s.w).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// writeHeader writes the status and headers of the download once
+func (s *downloadStream) writeHeader() {
+	if s.wroteHeader {
+		return
+	}
+	s.wroteHeader = true
+
+	contentType := s.contentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	s.w.Header().Set("Content-Type", contentType)
+	if s.filename != "" {
+		s.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": s.filename}))
+	}
+	s.w.WriteHeader(http.StatusOK)
+}
+
+// RecvMsg binds the request from the query string and path; there is only one
+func (s *downloadStream) RecvMsg(m interface{}) error {
+	if s.received {
+		return io.EOF
+	}
+	s.received = true
+
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type %T", m)
+	}
+	if err := bindRequest(s.r, s.rt, msg); err != nil {
+		s.bindErr = err
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+	customerpb "github.com/encomos/api-encomos/customer-service/proto/customer"
+)
+
+const testTenantID = "6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c10"
+
+// recordedCall is the RPC reached by a gateway request
+type recordedCall struct {
+	method string
+	req    interface{}
+	md     metadata.MD
+}
+
+// newTestGateway returns a gateway whose interceptors record the call instead of running
+// the CustomerService, so only the HTTP translation is exercised
+func newTestGateway(t *testing.T, allowedOrigins, trustedProxies []string) (*Gateway, *recordedCall) {
+	t.Helper()
+
+	call := &recordedCall{}
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		call.method = info.FullMethod
+		call.req = req
+		call.md, _ = metadata.FromIncomingContext(ctx)
+		return &emptypb.Empty{}, nil
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		call.method = info.FullMethod
+		call.md, _ = metadata.FromIncomingContext(ss.Context())
+		return nil
+	}
+
+	g, err := NewGateway(customerpb.UnimplementedCustomerServiceServer{}, unary, stream, allowedOrigins, trustedProxies, logger.NewWithService("test"))
+	if err != nil {
+		t.Fatalf("NewGateway: %v", err)
+	}
+	return g, call
+}
+
+// serve runs a request through the gateway from remoteAddr
+func serve(g *Gateway, method, target, body, remoteAddr string, headers map[string]string) *httptest.ResponseRecorder {
+	var r *http.Request
+	if body != "" {
+		r = httptest.NewRequest(method, target, strings.NewReader(body))
+	} else {
+		r = httptest.NewRequest(method, target, nil)
+	}
+	if remoteAddr != "" {
+		r.RemoteAddr = remoteAddr
+	}
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, r)
+	return w
+}
+
+func TestGatewayRoutes(t *testing.T) {
+	g, call := newTestGateway(t, nil, nil)
+
+	tests := []struct {
+		method, target, body string
+		rpc                  string
+	}{
+		{"GET", "/v1/customers", "", "ListCustomers"},
+		{"GET", "/v1/customers/search?query=ana", "", "SearchCustomers"},
+		{"GET", "/v1/customers/deleted", "", "ListDeletedCustomers"},
+		{"GET", "/v1/customers/duplicates", "", "FindDuplicateCustomers"},
+		{"GET", "/v1/customers/export", "", "ExportCustomers"},
+		{"POST", "/v1/customers/import", "first_name,last_name\n", "ImportCustomers"},
+		{"GET", "/v1/customers/c-1", "", "GetCustomer"},
+		{"PUT", "/v1/customers/c-1", `{"first_name":"Ana"}`, "UpdateCustomer"},
+		{"DELETE", "/v1/customers/c-1", "", "DeleteCustomer"},
+		{"POST", "/v1/customers/c-1/notes", `{"note":"Llamar"}`, "AddCustomerNote"},
+		{"GET", "/v1/customers/c-1/data-export", "", "ExportCustomerData"},
+		{"GET", "/v1/vehicles/v-1", "", "GetVehicle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			*call = recordedCall{}
+			w := serve(g, tt.method, tt.target, tt.body, "", nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+			}
+			if want := "/customer.v1.CustomerService/" + tt.rpc; call.method != want {
+				t.Errorf("expected %s, got %s", want, call.method)
+			}
+		})
+	}
+}
+
+func TestGatewayBinding(t *testing.T) {
+	g, call := newTestGateway(t, nil, nil)
+
+	w := serve(g, "GET", "/v1/customers/c-1?include_vehicles=true", "", "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	get, ok := call.req.(*customerpb.GetCustomerRequest)
+	if !ok || get.Id != "c-1" || !get.IncludeVehicles {
+		t.Fatalf("unexpected request %v", call.req)
+	}
+
+	w = serve(g, "POST", "/v1/customers/c-1/notes", `{"note":"Llamar","type":"general"}`, "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	note, ok := call.req.(*customerpb.AddCustomerNoteRequest)
+	if !ok || note.CustomerId != "c-1" || note.Note != "Llamar" {
+		t.Fatalf("unexpected request %v", call.req)
+	}
+
+	w = serve(g, "GET", "/v1/customers/c-1?unknown=1", "", "", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown parameter, got %d", w.Code)
+	}
+}
+
+func TestGatewayPatchUpdateMask(t *testing.T) {
+	g, call := newTestGateway(t, nil, nil)
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"proto names", `{"first_name":"Ana","email":"ana@example.com"}`, []string{"email", "first_name"}},
+		{"JSON names", `{"lastName":"García","isActive":false}`, []string{"is_active", "last_name"}},
+		{"path field left out", `{"id":"other","phone":"600111222"}`, []string{"phone"}},
+		{"explicit mask", `{"first_name":"Ana","email":"ana@example.com","update_mask":"firstName"}`, []string{"first_name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(g, "PATCH", "/v1/customers/c-1", tt.body, "", map[string]string{"If-Match": `"7"`})
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+			}
+			req, ok := call.req.(*customerpb.UpdateCustomerRequest)
+			if !ok {
+				t.Fatalf("unexpected request %T", call.req)
+			}
+			if req.Id != "c-1" || req.ExpectedVersion != 7 {
+				t.Errorf("expected id c-1 at version 7, got %s at %d", req.Id, req.ExpectedVersion)
+			}
+			paths := req.GetUpdateMask().GetPaths()
+			if strings.Join(paths, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected update_mask %v, got %v", tt.want, paths)
+			}
+		})
+	}
+
+	// PUT no deriva la máscara del cuerpo
+	serve(g, "PUT", "/v1/customers/c-1", `{"first_name":"Ana"}`, "", nil)
+	if req := call.req.(*customerpb.UpdateCustomerRequest); req.UpdateMask != nil {
+		t.Errorf("PUT must not set update_mask, got %v", req.UpdateMask)
+	}
+}
+
+func TestGatewayCORS(t *testing.T) {
+	preflight := func(origin string) map[string]string {
+		return map[string]string{"Origin": origin, "Access-Control-Request-Method": "PATCH"}
+	}
+
+	tests := []struct {
+		name       string
+		allowed    []string
+		method     string
+		headers    map[string]string
+		wantCode   int
+		wantOrigin string
+	}{
+		{"allowed preflight", []string{"https://app.encomos.test"}, "OPTIONS", preflight("https://app.encomos.test"), http.StatusNoContent, "https://app.encomos.test"},
+		{"denied preflight", []string{"https://app.encomos.test"}, "OPTIONS", preflight("https://evil.test"), http.StatusForbidden, ""},
+		{"wildcard preflight", []string{"*"}, "OPTIONS", preflight("https://evil.test"), http.StatusNoContent, "*"},
+		{"allowed request", []string{"https://app.encomos.test"}, "GET", map[string]string{"Origin": "https://app.encomos.test"}, http.StatusOK, "https://app.encomos.test"},
+		{"denied request", []string{"https://app.encomos.test"}, "GET", map[string]string{"Origin": "https://evil.test"}, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, call := newTestGateway(t, tt.allowed, nil)
+			w := serve(g, tt.method, "/v1/customers/c-1", "", "", tt.headers)
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d", tt.wantCode, w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("expected Access-Control-Allow-Origin %q, got %q", tt.wantOrigin, got)
+			}
+			if tt.method == "OPTIONS" && call.method != "" {
+				t.Errorf("a preflight must not reach %s", call.method)
+			}
+			if tt.wantCode == http.StatusNoContent {
+				allowHeaders := w.Header().Get("Access-Control-Allow-Headers")
+				if !strings.Contains(allowHeaders, "Authorization") || strings.Contains(allowHeaders, "X-Staff-Roles") {
+					t.Errorf("unexpected Access-Control-Allow-Headers %q", allowHeaders)
+				}
+			}
+		})
+	}
+}
+
+func TestGatewayForwardsHeaders(t *testing.T) {
+	g, call := newTestGateway(t, nil, []string{"10.0.0.0/8", "192.0.2.10"})
+
+	headers := map[string]string{
+		"X-Tenant-ID":   testTenantID,
+		"Authorization": "Bearer token",
+		"X-Staff-ID":    "s-1",
+		"X-Staff-Roles": "admin",
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		wantStaff  bool
+	}{
+		{"trusted network", "10.1.2.3:4567", true},
+		{"trusted address", "192.0.2.10:4567", true},
+		{"untrusted client", "192.0.2.11:4567", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, target := range []string{"/v1/customers/c-1", "/v1/customers/export"} {
+				w := serve(g, "GET", target, "", tt.remoteAddr, headers)
+				if w.Code != http.StatusOK {
+					t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+				}
+				if got := call.md.Get("x-tenant-id"); len(got) != 1 || got[0] != testTenantID {
+					t.Errorf("%s: expected x-tenant-id in metadata, got %v", target, got)
+				}
+				if got := call.md.Get("authorization"); len(got) != 1 || got[0] != "Bearer token" {
+					t.Errorf("%s: expected authorization in metadata, got %v", target, got)
+				}
+				if got := call.md.Get("x-request-id"); len(got) != 1 || got[0] == "" {
+					t.Errorf("%s: expected a generated x-request-id, got %v", target, got)
+				}
+
+				staffID, roles := call.md.Get("x-staff-id"), call.md.Get("x-staff-roles")
+				if tt.wantStaff && (len(staffID) != 1 || len(roles) != 1 || roles[0] != "admin") {
+					t.Errorf("%s: expected the staff headers of a trusted proxy, got %v %v", target, staffID, roles)
+				}
+				if !tt.wantStaff && (len(staffID) != 0 || len(roles) != 0) {
+					t.Errorf("%s: staff headers of an untrusted client must be dropped, got %v %v", target, staffID, roles)
+				}
+			}
+		})
+	}
+}
+
+func TestNewGatewayRejectsInvalidProxy(t *testing.T) {
+	_, err := NewGateway(customerpb.UnimplementedCustomerServiceServer{}, nil, nil, nil, []string{"not-an-ip"}, logger.NewWithService("test"))
+	if err == nil {
+		t.Fatal("expected an error for an invalid trusted proxy")
+	}
+}
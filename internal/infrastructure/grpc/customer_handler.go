@@ -1,17 +1,30 @@
 package grpc
 
 import (
+	"bufio"
 	"context"
-	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
 	"github.com/encomos/api-encomos/customer-service/internal/domain/service"
+	"github.com/encomos/api-encomos/customer-service/internal/exporter"
+	"github.com/encomos/api-encomos/customer-service/internal/identity"
+	"github.com/encomos/api-encomos/customer-service/internal/importer"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/authz"
+	"github.com/encomos/api-encomos/customer-service/internal/pagetoken"
+	"github.com/encomos/api-encomos/customer-service/internal/tenancy"
 	customerpb "github.com/encomos/api-encomos/customer-service/proto/customer"
 )
 
@@ -20,28 +33,22 @@ type CustomerHandler struct {
 	customerpb.UnimplementedCustomerServiceServer
 	customerService *service.CustomerService
 	vehicleService  *service.VehicleService
+	policy          *authz.Policy // nil when authorization is disabled
+	pageTokens      *pagetoken.Codec
 }
 
 // NewCustomerHandler creates a new customer handler
-func NewCustomerHandler(customerService *service.CustomerService, vehicleService *service.VehicleService) *CustomerHandler {
+func NewCustomerHandler(customerService *service.CustomerService, vehicleService *service.VehicleService, policy *authz.Policy, pageTokens *pagetoken.Codec) *CustomerHandler {
 	return &CustomerHandler{
 		customerService: customerService,
 		vehicleService:  vehicleService,
+		policy:          policy,
+		pageTokens:      pageTokens,
 	}
 }
 
 // ListCustomers lists customers with filtering and pagination
 func (h *CustomerHandler) ListCustomers(ctx context.Context, req *customerpb.ListCustomersRequest) (*customerpb.ListCustomersResponse, error) {
-	// DEBUG: Verificar tenant_id en contexto
-	tenantIDValue := ctx.Value("tenant_id")
-	println("DEBUG HANDLER: tenant_id from context:", tenantIDValue)
-	if tenantIDValue != nil {
-		println("DEBUG HANDLER: tenant_id type:", fmt.Sprintf("%T", tenantIDValue))
-		println("DEBUG HANDLER: tenant_id value:", tenantIDValue.(string))
-	} else {
-		println("DEBUG HANDLER: tenant_id is NIL in context!")
-	}
-
 	// Validar entrada
 	if req.Page < 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "page must be non-negative")
@@ -62,12 +69,42 @@ func (h *CustomerHandler) ListCustomers(ctx context.Context, req *customerpb.Lis
 		Limit:        int(req.Limit),
 		SortBy:       req.SortBy,
 		SortOrder:    req.SortOrder,
+		Level:        req.Level,
+		TotalMode:    req.TotalMode,
+	}
+	var lastVisitFrom, lastVisitTo string
+	if req.MinTotalSpent != 0 {
+		filter.MinTotalSpent = &req.MinTotalSpent
+	}
+	if req.LastVisitFrom != nil {
+		from := req.LastVisitFrom.AsTime()
+		filter.LastVisitFrom = &from
+		lastVisitFrom = from.Format(time.RFC3339Nano)
+	}
+	if req.LastVisitTo != nil {
+		to := req.LastVisitTo.AsTime()
+		filter.LastVisitTo = &to
+		lastVisitTo = to.Format(time.RFC3339Nano)
 	}
 
+	// El token solo vale para el mismo listado (filtros y orden)
+	scope := pagetoken.Scope("ListCustomers", req.Search, req.CustomerType, strconv.FormatBool(req.ActiveOnly), req.SortBy, req.SortOrder,
+		strconv.FormatFloat(req.MinTotalSpent, 'g', -1, 64), req.Level, lastVisitFrom, lastVisitTo)
+	cursor, err := decodePageToken(ctx, h.pageTokens, req.PageToken, scope)
+	if err != nil {
+		return nil, err
+	}
+	filter.Cursor = cursor
+
 	// Ejecutar búsqueda
-	customers, total, err := h.customerService.ListCustomers(ctx, filter)
+	customers, page, err := h.customerService.ListCustomers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	nextPageToken, err := encodePageToken(ctx, h.pageTokens, page.Next, scope)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to list customers: %v", err)
+		return nil, err
 	}
 
 	// Convertir a protobuf
@@ -77,14 +114,16 @@ func (h *CustomerHandler) ListCustomers(ctx context.Context, req *customerpb.Lis
 	}
 
 	// Calcular páginas totales
-	totalPages := int32((total + int(req.Limit) - 1) / int(req.Limit))
+	totalPages := int32((page.Total + int(req.Limit) - 1) / int(req.Limit))
 
 	return &customerpb.ListCustomersResponse{
-		Customers:  pbCustomers,
-		Total:      int32(total),
-		Page:       req.Page,
-		Limit:      req.Limit,
-		TotalPages: totalPages,
+		Customers:       pbCustomers,
+		Total:           int32(page.Total),
+		Page:            req.Page,
+		Limit:           req.Limit,
+		TotalPages:      totalPages,
+		NextPageToken:   nextPageToken,
+		TotalIsEstimate: page.TotalIsEstimate,
 	}, nil
 }
 
@@ -94,12 +133,14 @@ func (h *CustomerHandler) GetCustomer(ctx context.Context, req *customerpb.GetCu
 		return nil, status.Errorf(codes.InvalidArgument, "customer ID is required")
 	}
 
-	customer, err := h.customerService.GetCustomer(ctx, req.Id, req.IncludeVehicles, req.IncludeNotes)
+	customer, err := h.customerService.GetCustomer(ctx, req.Id, req.IncludeVehicles, req.IncludeNotes, req.IncludeStats)
 	if err != nil {
-		if isNotFoundError(err) {
-			return nil, status.Errorf(codes.NotFound, "customer not found")
-		}
-		return nil, status.Errorf(codes.Internal, "failed to get customer: %v", err)
+		return nil, err
+	}
+
+	// Ocultar notas de tipos restringidos para el rol del solicitante
+	if customer.CustomerNotes != nil {
+		customer.CustomerNotes = h.visibleNotes(ctx, customer.CustomerNotes)
 	}
 
 	return &customerpb.GetCustomerResponse{
@@ -121,18 +162,14 @@ func (h *CustomerHandler) CreateCustomer(ctx context.Context, req *customerpb.Cr
 	}
 
 	// Extraer tenant ID del contexto (puesto por TenantInterceptor)
-	tenantIDValue := ctx.Value("tenant_id")
-	if tenantIDValue == nil {
-		return nil, status.Errorf(codes.Internal, "tenant ID not found in context")
-	}
-	tenantID, ok := tenantIDValue.(string)
+	tenant, ok := tenancy.FromContext(ctx)
 	if !ok {
-		return nil, status.Errorf(codes.Internal, "invalid tenant ID type in context")
+		return nil, status.Errorf(codes.Internal, "tenant not found in context")
 	}
 
 	// Convertir de protobuf a modelo
 	create := model.CustomerCreate{
-		TenantID:     tenantID,
+		TenantID:     tenant.ID,
 		FirstName:    req.FirstName,
 		LastName:     req.LastName,
 		Email:        stringPtrFromProto(req.Email),
@@ -154,20 +191,20 @@ func (h *CustomerHandler) CreateCustomer(ctx context.Context, req *customerpb.Cr
 		create.Preferences = req.Preferences.AsMap()
 	}
 
+	// Vehículos creados en la misma transacción que el cliente
+	for _, vehicleReq := range req.Vehicles {
+		create.Vehicles = append(create.Vehicles, vehicleCreateFromProto(vehicleReq))
+	}
+
 	// Crear cliente
-	customer, err := h.customerService.CreateCustomer(ctx, create)
+	customer, duplicates, err := h.customerService.CreateCustomer(ctx, create)
 	if err != nil {
-		if isValidationError(err) {
-			return nil, status.Errorf(codes.InvalidArgument, "validation error: %v", err)
-		}
-		if isDuplicateError(err) {
-			return nil, status.Errorf(codes.AlreadyExists, "customer already exists: %v", err)
-		}
-		return nil, status.Errorf(codes.Internal, "failed to create customer: %v", err)
+		return nil, err
 	}
 
 	return &customerpb.CreateCustomerResponse{
-		Customer: h.customerToProto(customer),
+		Customer:           h.customerToProto(customer),
+		PossibleDuplicates: h.duplicateCandidatesToProto(duplicates),
 	}, nil
 }
 
@@ -177,91 +214,206 @@ func (h *CustomerHandler) UpdateCustomer(ctx context.Context, req *customerpb.Up
 		return nil, status.Errorf(codes.InvalidArgument, "customer ID is required")
 	}
 
-	// Convertir de protobuf a modelo
-	update := model.CustomerUpdate{
-		ID: req.Id,
+	// Convertir de protobuf a modelo; con update_mask solo se aplican las rutas indicadas
+	var update model.CustomerUpdate
+	if req.UpdateMask != nil {
+		var err error
+		if update, err = customerUpdateFromMask(req); err != nil {
+			return nil, err
+		}
+	} else {
+		update = customerUpdateFromRequest(req)
 	}
+	update.ExpectedVersion = req.ExpectedVersion
 
-	if req.FirstName != "" {
-		update.FirstName = &req.FirstName
-	}
-	if req.LastName != "" {
-		update.LastName = &req.LastName
+	// Actualizar cliente
+	customer, err := h.customerService.UpdateCustomer(ctx, update)
+	if err != nil {
+		return nil, err
 	}
-	if req.Email != "" {
-		update.Email = &req.Email
+
+	return &customerpb.UpdateCustomerResponse{
+		Customer: h.customerToProto(customer),
+	}, nil
+}
+
+// DeleteCustomer moves a customer to the trash, or previews what that affects with dry_run
+func (h *CustomerHandler) DeleteCustomer(ctx context.Context, req *customerpb.DeleteCustomerRequest) (*customerpb.DeleteCustomerResponse, error) {
+	if req.Id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "customer ID is required")
 	}
-	if req.Phone != "" {
-		update.Phone = &req.Phone
+
+	impact, err := h.customerService.DeleteCustomer(ctx, req.Id, req.ExpectedVersion, req.DryRun)
+	if err != nil {
+		return nil, err
 	}
-	if req.CustomerType != "" {
-		update.CustomerType = &req.CustomerType
+
+	return &customerpb.DeleteCustomerResponse{
+		Success: !req.DryRun,
+		Impact: &customerpb.DeleteImpact{
+			Vehicles:       impact.Vehicles,
+			Notes:          impact.Notes,
+			HistoryEntries: impact.HistoryEntries,
+			HasStats:       impact.HasStats,
+			PurgeAt:        timestamppb.New(impact.PurgeAt),
+		},
+	}, nil
+}
+
+// RestoreCustomer takes a customer out of the trash
+func (h *CustomerHandler) RestoreCustomer(ctx context.Context, req *customerpb.RestoreCustomerRequest) (*customerpb.RestoreCustomerResponse, error) {
+	if req.Id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "customer ID is required")
 	}
-	if req.CompanyName != "" {
-		update.CompanyName = &req.CompanyName
+
+	customer, err := h.customerService.RestoreCustomer(ctx, req.Id, req.ExpectedVersion)
+	if err != nil {
+		return nil, err
 	}
-	if req.TaxId != "" {
-		update.TaxID = &req.TaxId
+
+	return &customerpb.RestoreCustomerResponse{
+		Customer: h.customerToProto(customer),
+	}, nil
+}
+
+// ListDeletedCustomers lists the customers in the trash
+func (h *CustomerHandler) ListDeletedCustomers(ctx context.Context, req *customerpb.ListDeletedCustomersRequest) (*customerpb.ListDeletedCustomersResponse, error) {
+	filter := model.CustomerFilter{
+		Search:    req.Search,
+		Limit:     int(req.Limit),
+		TotalMode: model.TotalModeNone,
 	}
-	if req.Address != "" {
-		update.Address = &req.Address
+
+	scope := pagetoken.Scope("ListDeletedCustomers", req.Search)
+	cursor, err := decodePageToken(ctx, h.pageTokens, req.PageToken, scope)
+	if err != nil {
+		return nil, err
 	}
-	if req.Notes != "" {
-		update.Notes = &req.Notes
+	filter.Cursor = cursor
+
+	customers, page, err := h.customerService.ListDeletedCustomers(ctx, filter)
+	if err != nil {
+		return nil, err
 	}
 
-	update.IsActive = &req.IsActive
+	nextPageToken, err := encodePageToken(ctx, h.pageTokens, page.Next, scope)
+	if err != nil {
+		return nil, err
+	}
 
-	if req.Birthday != nil {
-		birthday := req.Birthday.AsTime()
-		update.Birthday = &birthday
+	pbCustomers := make([]*customerpb.Customer, len(customers))
+	for i, customer := range customers {
+		pbCustomers[i] = h.customerToProto(customer)
 	}
 
-	if req.Preferences != nil {
-		prefs := req.Preferences.AsMap()
-		update.Preferences = prefs
+	return &customerpb.ListDeletedCustomersResponse{
+		Customers:     pbCustomers,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// AnonymizeCustomer irreversibly erases the personal data of a customer
+func (h *CustomerHandler) AnonymizeCustomer(ctx context.Context, req *customerpb.AnonymizeCustomerRequest) (*customerpb.AnonymizeCustomerResponse, error) {
+	if req.Id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "customer ID is required")
 	}
 
-	// Actualizar cliente
-	customer, err := h.customerService.UpdateCustomer(ctx, update)
+	result, err := h.customerService.AnonymizeCustomer(ctx, req.Id, req.ExpectedVersion)
 	if err != nil {
-		if isNotFoundError(err) {
-			return nil, status.Errorf(codes.NotFound, "customer not found")
-		}
-		if isValidationError(err) {
-			return nil, status.Errorf(codes.InvalidArgument, "validation error: %v", err)
-		}
-		if isDuplicateError(err) {
-			return nil, status.Errorf(codes.AlreadyExists, "customer already exists: %v", err)
-		}
-		return nil, status.Errorf(codes.Internal, "failed to update customer: %v", err)
+		return nil, err
 	}
 
-	return &customerpb.UpdateCustomerResponse{
-		Customer: h.customerToProto(customer),
+	return &customerpb.AnonymizeCustomerResponse{
+		Customer:                 h.customerToProto(result.Customer),
+		AnonymizedNotes:          result.Notes,
+		AnonymizedHistoryEntries: result.HistoryEntries,
+		ClearedVehicleNotes:      result.VehicleNotes,
+		RedactedAuditEntries:     result.AuditEntries,
 	}, nil
 }
 
-// DeleteCustomer deletes a customer
-func (h *CustomerHandler) DeleteCustomer(ctx context.Context, req *customerpb.DeleteCustomerRequest) (*customerpb.DeleteCustomerResponse, error) {
+// ExportCustomerData streams the JSON bundle with the personal data of a customer
+func (h *CustomerHandler) ExportCustomerData(req *customerpb.ExportCustomerDataRequest, stream grpc.ServerStreamingServer[customerpb.ExportCustomerDataResponse]) error {
 	if req.Id == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "customer ID is required")
+		return status.Errorf(codes.InvalidArgument, "customer ID is required")
 	}
 
-	err := h.customerService.DeleteCustomer(ctx, req.Id)
+	// Las notas de tipos restringidos para el rol del solicitante no se exportan
+	export, err := h.customerService.ExportCustomerData(stream.Context(), req.Id, h.restrictedNoteTypes(stream.Context()))
 	if err != nil {
-		if isNotFoundError(err) {
-			return nil, status.Errorf(codes.NotFound, "customer not found")
+		return err
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode customer data")
+	}
+
+	// En trozos de exportChunkBytes; el primero lleva el tipo y el nombre del fichero
+	for offset := 0; offset == 0 || offset < len(data); offset += exportChunkBytes {
+		resp := &customerpb.ExportCustomerDataResponse{Data: data[offset:min(offset+exportChunkBytes, len(data))]}
+		if offset == 0 {
+			resp.ContentType = "application/json"
+			resp.Filename = fmt.Sprintf("customer-%s-data.json", export.Customer.ID)
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
 		}
-		return nil, status.Errorf(codes.Internal, "failed to delete customer: %v", err)
 	}
+	return nil
+}
 
-	return &customerpb.DeleteCustomerResponse{
-		Success: true,
+// MergeCustomers consolidates duplicate profiles into a surviving customer
+func (h *CustomerHandler) MergeCustomers(ctx context.Context, req *customerpb.MergeCustomersRequest) (*customerpb.MergeCustomersResponse, error) {
+	merge := model.CustomerMerge{
+		SurvivorID:      req.SurvivorId,
+		DuplicateIDs:    req.DuplicateIds,
+		FieldChoices:    req.FieldChoices,
+		ExpectedVersion: req.ExpectedVersion,
+		DryRun:          req.DryRun,
+	}
+
+	result, err := h.customerService.MergeCustomers(ctx, merge)
+	if err != nil {
+		return nil, err
+	}
+
+	return &customerpb.MergeCustomersResponse{
+		Customer:      h.customerToProto(result.Customer),
+		MergedIds:     result.MergedIDs,
+		VehiclesMoved: int32(result.VehiclesMoved),
+		NotesMoved:    int32(result.NotesMoved),
+		DryRun:        result.DryRun,
 	}, nil
 }
 
-// SearchCustomers performs advanced search on customers
+// FindDuplicateCustomers returns the customers that may duplicate a customer or the given data
+func (h *CustomerHandler) FindDuplicateCustomers(ctx context.Context, req *customerpb.FindDuplicateCustomersRequest) (*customerpb.FindDuplicateCustomersResponse, error) {
+	query := model.DuplicateQuery{
+		CustomerID: req.CustomerId,
+		Probe: model.DuplicateProbe{
+			FirstName:   req.FirstName,
+			LastName:    req.LastName,
+			CompanyName: req.CompanyName,
+			Email:       req.Email,
+			Phone:       req.Phone,
+			TaxID:       req.TaxId,
+		},
+		MinScore: req.MinScore,
+		Limit:    int(req.Limit),
+	}
+
+	candidates, err := h.customerService.FindDuplicateCustomers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &customerpb.FindDuplicateCustomersResponse{
+		Candidates: h.duplicateCandidatesToProto(candidates),
+	}, nil
+}
+
+// SearchCustomers performs a ranked search on customers
 func (h *CustomerHandler) SearchCustomers(ctx context.Context, req *customerpb.SearchCustomersRequest) (*customerpb.SearchCustomersResponse, error) {
 	if req.Query == "" {
 		return &customerpb.SearchCustomersResponse{
@@ -272,41 +424,54 @@ func (h *CustomerHandler) SearchCustomers(ctx context.Context, req *customerpb.S
 
 	limit := int(req.Limit)
 	if limit <= 0 {
-		limit = 20
+		limit = model.DefaultSearchLimit
 	}
-	if limit > 50 {
-		limit = 50
+	if limit > model.MaxSearchLimit {
+		limit = model.MaxSearchLimit
 	}
 
-	// Construir filtro de búsqueda
+	// Campos de búsqueda separados por coma; vacío = todos
 	var searchFields []string
-	if req.SearchFields != "" {
-		// Parsear campos de búsqueda separados por coma
-		// Por simplicidad, usaremos todos los campos por defecto
-		searchFields = []string{"name", "email", "phone", "tax_id"}
+	for _, field := range strings.Split(req.SearchFields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			searchFields = append(searchFields, field)
+		}
 	}
 
 	filter := model.CustomerSearchFilter{
 		Query:        req.Query,
 		SearchFields: searchFields,
+		Page:         int(req.Page),
 		Limit:        limit,
 	}
 
 	// Ejecutar búsqueda
-	customers, err := h.customerService.SearchCustomers(ctx, filter)
+	results, total, err := h.customerService.SearchCustomers(ctx, filter)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to search customers: %v", err)
+		return nil, err
 	}
 
 	// Convertir a protobuf
-	pbCustomers := make([]*customerpb.Customer, len(customers))
-	for i, customer := range customers {
-		pbCustomers[i] = h.customerToProto(customer)
+	pbCustomers := make([]*customerpb.Customer, len(results))
+	pbResults := make([]*customerpb.SearchResult, len(results))
+	for i, result := range results {
+		pbCustomers[i] = h.customerToProto(result.Customer)
+		pbResults[i] = &customerpb.SearchResult{
+			CustomerId: result.Customer.ID,
+			Score:      result.Score,
+		}
+		for _, highlight := range result.Highlights {
+			pbResults[i].Highlights = append(pbResults[i].Highlights, &customerpb.SearchHighlight{
+				Field: highlight.Field,
+				Value: highlight.Value,
+			})
+		}
 	}
 
 	return &customerpb.SearchCustomersResponse{
 		Customers: pbCustomers,
-		Total:     int32(len(customers)),
+		Total:     int32(total),
+		Results:   pbResults,
 	}, nil
 }
 
@@ -319,11 +484,21 @@ func (h *CustomerHandler) AddCustomerNote(ctx context.Context, req *customerpb.A
 		return nil, status.Errorf(codes.InvalidArgument, "note content is required")
 	}
 
-	// Por ahora, usar valores dummy para staff info - esto se obtendrá del token JWT en producción
+	// El autor de la nota es el staff autenticado (AuthInterceptor) o el de los metadatos
+	// del API Gateway (IdentityInterceptor); sin autenticación puede no haber ninguno
+	author := identity.System
+	var roles []string
+	if staff, ok := identity.StaffFromContext(ctx); ok {
+		roles = staff.Roles
+		if staff.ID != "" {
+			author = *staff
+		}
+	}
+
 	create := model.CustomerNoteCreate{
 		CustomerID: req.CustomerId,
-		StaffID:    "1",           // TODO: Obtener del contexto de autenticación
-		StaffName:  "System User", // TODO: Obtener del contexto de autenticación
+		StaffID:    author.ID,
+		StaffName:  author.DisplayName(),
 		Note:       req.Note,
 		Type:       req.Type,
 	}
@@ -332,15 +507,15 @@ func (h *CustomerHandler) AddCustomerNote(ctx context.Context, req *customerpb.A
 		create.Type = "general"
 	}
 
+	if h.policy != nil {
+		if err := h.policy.AuthorizeNoteType(create.Type, roles); err != nil {
+			return nil, err
+		}
+	}
+
 	note, err := h.customerService.AddCustomerNote(ctx, create)
 	if err != nil {
-		if isNotFoundError(err) {
-			return nil, status.Errorf(codes.NotFound, "customer not found")
-		}
-		if isValidationError(err) {
-			return nil, status.Errorf(codes.InvalidArgument, "validation error: %v", err)
-		}
-		return nil, status.Errorf(codes.Internal, "failed to add customer note: %v", err)
+		return nil, err
 	}
 
 	return &customerpb.AddCustomerNoteResponse{
@@ -348,19 +523,440 @@ func (h *CustomerHandler) AddCustomerNote(ctx context.Context, req *customerpb.A
 	}, nil
 }
 
-// GetCustomerHistory retrieves customer history (placeholder implementation)
+// GetCustomerHistory retrieves the customer timeline
 func (h *CustomerHandler) GetCustomerHistory(ctx context.Context, req *customerpb.GetCustomerHistoryRequest) (*customerpb.GetCustomerHistoryResponse, error) {
 	if req.CustomerId == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "customer ID is required")
 	}
+	if req.Page < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "page must be non-negative")
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20 // Default limit
+	}
+	if req.Limit > 100 {
+		req.Limit = 100 // Max limit
+	}
+
+	filter := model.CustomerHistoryFilter{
+		CustomerID:       req.CustomerId,
+		Type:             req.Type,
+		Page:             int(req.Page),
+		Limit:            int(req.Limit),
+		ExcludeNoteTypes: h.restrictedNoteTypes(ctx),
+	}
+	if req.DateFrom != nil {
+		dateFrom := req.DateFrom.AsTime()
+		filter.DateFrom = &dateFrom
+	}
+	if req.DateTo != nil {
+		dateTo := req.DateTo.AsTime()
+		filter.DateTo = &dateTo
+	}
+
+	items, total, err := h.customerService.GetCustomerHistory(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pbItems := make([]*customerpb.CustomerHistoryItem, len(items))
+	for i, item := range items {
+		pbItem, err := h.customerHistoryItemToProto(item)
+		if err != nil {
+			return nil, err
+		}
+		pbItems[i] = pbItem
+	}
 
-	// TODO: Implementar lógica real de historial cuando tengamos integración con sales/appointments
 	return &customerpb.GetCustomerHistoryResponse{
-		Items: []*customerpb.CustomerHistoryItem{},
-		Total: 0,
+		Items: pbItems,
+		Total: int32(total),
+	}, nil
+}
+
+// ListCustomerNotes lists the notes of a customer, newest first, with page tokens. Notes
+// whose type is restricted for the caller are left out.
+func (h *CustomerHandler) ListCustomerNotes(ctx context.Context, req *customerpb.ListCustomerNotesRequest) (*customerpb.ListCustomerNotesResponse, error) {
+	if req.CustomerId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "customer ID is required")
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20 // Default limit
+	}
+	if req.Limit > 100 {
+		req.Limit = 100 // Max limit
+	}
+
+	// Pedir un tipo restringido es un error; sin tipo, los restringidos se omiten
+	if req.Type != "" && h.policy != nil {
+		if err := h.policy.AuthorizeNoteType(req.Type, callerRoles(ctx)); err != nil {
+			return nil, err
+		}
+	}
+
+	filter := model.CustomerNoteFilter{
+		CustomerID:   req.CustomerId,
+		Type:         req.Type,
+		Limit:        int(req.Limit),
+		ExcludeTypes: h.restrictedNoteTypes(ctx),
+		TotalMode:    req.TotalMode,
+	}
+	var dateFrom, dateTo string
+	if req.DateFrom != nil {
+		from := req.DateFrom.AsTime()
+		filter.DateFrom = &from
+		dateFrom = from.Format(time.RFC3339Nano)
+	}
+	if req.DateTo != nil {
+		to := req.DateTo.AsTime()
+		filter.DateTo = &to
+		dateTo = to.Format(time.RFC3339Nano)
+	}
+
+	scope := pagetoken.Scope("ListCustomerNotes", req.CustomerId, req.Type, dateFrom, dateTo)
+	cursor, err := decodePageToken(ctx, h.pageTokens, req.PageToken, scope)
+	if err != nil {
+		return nil, err
+	}
+	filter.Cursor = cursor
+
+	notes, page, err := h.customerService.ListCustomerNotes(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	nextPageToken, err := encodePageToken(ctx, h.pageTokens, page.Next, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	pbNotes := make([]*customerpb.CustomerNote, len(notes))
+	for i, note := range notes {
+		pbNotes[i] = h.customerNoteToProto(note)
+	}
+
+	return &customerpb.ListCustomerNotesResponse{
+		Notes:           pbNotes,
+		Total:           int32(page.Total),
+		NextPageToken:   nextPageToken,
+		TotalIsEstimate: page.TotalIsEstimate,
 	}, nil
 }
 
+// GetCustomerAuditLog lists the audit entries of customers and vehicles
+func (h *CustomerHandler) GetCustomerAuditLog(ctx context.Context, req *customerpb.GetCustomerAuditLogRequest) (*customerpb.GetCustomerAuditLogResponse, error) {
+	if req.Page < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "page must be non-negative")
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20 // Default limit
+	}
+	if req.Limit > 100 {
+		req.Limit = 100 // Max limit
+	}
+
+	filter := model.AuditFilter{
+		CustomerID: req.CustomerId,
+		ActorID:    req.ActorId,
+		Action:     req.Action,
+		Page:       int(req.Page),
+		Limit:      int(req.Limit),
+	}
+	if req.DateFrom != nil {
+		dateFrom := req.DateFrom.AsTime()
+		filter.DateFrom = &dateFrom
+	}
+	if req.DateTo != nil {
+		dateTo := req.DateTo.AsTime()
+		filter.DateTo = &dateTo
+	}
+
+	entries, total, err := h.customerService.GetCustomerAuditLog(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pbEntries := make([]*customerpb.AuditLogEntry, len(entries))
+	for i, entry := range entries {
+		pbEntry, err := h.auditEntryToProto(entry)
+		if err != nil {
+			return nil, err
+		}
+		pbEntries[i] = pbEntry
+	}
+
+	return &customerpb.GetCustomerAuditLogResponse{
+		Entries: pbEntries,
+		Total:   int32(total),
+	}, nil
+}
+
+// callerRoles returns the roles of the staff performing the request
+func callerRoles(ctx context.Context) []string {
+	if staff, ok := identity.StaffFromContext(ctx); ok {
+		return staff.Roles
+	}
+	return nil
+}
+
+// restrictedNoteTypes returns the note types the caller may not read
+func (h *CustomerHandler) restrictedNoteTypes(ctx context.Context) []string {
+	if h.policy == nil {
+		return nil
+	}
+	return h.policy.RestrictedNoteTypes(callerRoles(ctx))
+}
+
+// visibleNotes drops the notes whose type is restricted for the caller
+func (h *CustomerHandler) visibleNotes(ctx context.Context, notes []*model.CustomerNote) []*model.CustomerNote {
+	if h.policy == nil {
+		return notes
+	}
+
+	roles := callerRoles(ctx)
+	visible := make([]*model.CustomerNote, 0, len(notes))
+	for _, note := range notes {
+		if h.policy.CanAccessNoteType(note.Type, roles) {
+			visible = append(visible, note)
+		}
+	}
+	return visible
+}
+
+// maxIngestEvents limits the events accepted in a single IngestCustomerEvents stream
+const maxIngestEvents = 1000
+
+// IngestCustomerEvents receives a stream of activity events from other services and
+// applies them in a single transaction once the client closes the stream
+func (h *CustomerHandler) IngestCustomerEvents(stream grpc.ClientStreamingServer[customerpb.CustomerEvent, customerpb.IngestCustomerEventsResponse]) error {
+	var events []*model.CustomerEvent
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(events) >= maxIngestEvents {
+			return status.Errorf(codes.InvalidArgument, "a stream cannot contain more than %d events", maxIngestEvents)
+		}
+
+		event := &model.CustomerEvent{
+			EventID:     req.EventId,
+			CustomerID:  req.CustomerId,
+			Type:        req.Type,
+			Source:      req.Source,
+			Title:       req.Title,
+			Description: req.Description,
+			Amount:      req.Amount,
+			Status:      req.Status,
+		}
+		if req.Data != nil {
+			event.Data = req.Data.AsMap()
+		}
+		if req.OccurredAt != nil {
+			event.OccurredAt = req.OccurredAt.AsTime()
+		}
+
+		events = append(events, event)
+	}
+
+	result, err := h.customerService.IngestCustomerEvents(stream.Context(), events)
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&customerpb.IngestCustomerEventsResponse{
+		Received:   int32(result.Received),
+		Ingested:   int32(result.Ingested),
+		Duplicates: int32(result.Duplicates),
+	})
+}
+
+// ImportCustomers imports the CSV or NDJSON file streamed by the client and streams back
+// the results of each batch once it is applied, followed by a summary
+func (h *CustomerHandler) ImportCustomers(stream grpc.BidiStreamingServer[customerpb.ImportCustomersRequest, customerpb.ImportCustomersResponse]) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Errorf(codes.InvalidArgument, "the first message must carry the import options")
+	}
+	if err != nil {
+		return err
+	}
+
+	options := model.CustomerImportOptions{
+		Format:        first.Format,
+		ColumnMapping: first.ColumnMapping,
+		UpsertKey:     first.UpsertKey,
+		DryRun:        first.DryRun,
+		BatchSize:     int(first.BatchSize),
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	rows, err := importer.NewReader(&importChunkReader{stream: stream, pending: first.Chunk}, options.Format, options.ColumnMapping)
+	if err != nil {
+		return err
+	}
+
+	summary, err := h.customerService.ImportCustomers(stream.Context(), options, rows, func(results []*model.CustomerImportResult) error {
+		return stream.Send(&customerpb.ImportCustomersResponse{Results: importResultsToProto(results)})
+	})
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&customerpb.ImportCustomersResponse{
+		Summary: &customerpb.ImportSummary{
+			Rows:    int32(summary.Rows),
+			Created: int32(summary.Created),
+			Updated: int32(summary.Updated),
+			Skipped: int32(summary.Skipped),
+			Errors:  int32(summary.Errors),
+			DryRun:  options.DryRun,
+		},
+	})
+}
+
+// importChunkReader reads the file chunks of an ImportCustomers stream as a single stream of bytes
+type importChunkReader struct {
+	stream  grpc.BidiStreamingServer[customerpb.ImportCustomersRequest, customerpb.ImportCustomersResponse]
+	pending []byte
+}
+
+// Read returns the bytes of the current chunk, receiving the next one when it is consumed
+func (r *importChunkReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.pending = req.Chunk
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// exportChunkBytes is the size of the file chunks sent by ExportCustomers
+const exportChunkBytes = 64 << 10
+
+// ExportCustomers streams the customers matching the ListCustomers filters as a CSV,
+// NDJSON or vCard file. Columns the caller may not read are left out of the default
+// selection; asking for one explicitly is denied.
+func (h *CustomerHandler) ExportCustomers(req *customerpb.ExportCustomersRequest, stream grpc.ServerStreamingServer[customerpb.ExportCustomersResponse]) error {
+	ctx := stream.Context()
+
+	options := model.CustomerExportOptions{
+		Format:          req.Format,
+		Columns:         req.Columns,
+		IncludeVehicles: req.IncludeVehicles,
+		IncludeStats:    req.IncludeStats,
+	}
+	if h.policy != nil {
+		roles := callerRoles(ctx)
+		for _, column := range req.Columns {
+			if err := h.policy.AuthorizeExportColumn(strings.ToLower(strings.TrimSpace(column)), roles); err != nil {
+				return err
+			}
+		}
+		options.ExcludedColumns = h.policy.RestrictedExportColumns(roles)
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	filter := model.CustomerFilter{
+		Search:       req.Search,
+		CustomerType: req.CustomerType,
+		ActiveOnly:   req.ActiveOnly,
+		SortBy:       req.SortBy,
+		SortOrder:    req.SortOrder,
+		Level:        req.Level,
+	}
+	if req.MinTotalSpent != 0 {
+		filter.MinTotalSpent = &req.MinTotalSpent
+	}
+	if req.LastVisitFrom != nil {
+		from := req.LastVisitFrom.AsTime()
+		filter.LastVisitFrom = &from
+	}
+	if req.LastVisitTo != nil {
+		to := req.LastVisitTo.AsTime()
+		filter.LastVisitTo = &to
+	}
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	// La cabecera se envía antes de leer, así que los filtros se validan aquí
+	if err := stream.Send(&customerpb.ExportCustomersResponse{
+		ContentType: exporter.ContentType(options.Format),
+		Filename:    exporter.FileName(options.Format, time.Now()),
+	}); err != nil {
+		return err
+	}
+
+	// Los datos se envían en trozos de exportChunkBytes a medida que se escriben
+	chunks := bufio.NewWriterSize(&exportChunkWriter{stream: stream}, exportChunkBytes)
+	writer, err := exporter.NewWriter(chunks, options)
+	if err != nil {
+		return err
+	}
+
+	err = h.customerService.ExportCustomers(ctx, filter, options, func(customers []*model.Customer) error {
+		for _, customer := range customers {
+			if err := writer.Write(customer); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return chunks.Flush()
+}
+
+// exportChunkWriter sends every write as a chunk of an ExportCustomers stream
+type exportChunkWriter struct {
+	stream grpc.ServerStreamingServer[customerpb.ExportCustomersResponse]
+}
+
+func (w *exportChunkWriter) Write(p []byte) (int, error) {
+	// Un mensaje enviado no se puede modificar y bufio reutiliza p
+	data := append([]byte(nil), p...)
+	if err := w.stream.Send(&customerpb.ExportCustomersResponse{Data: data}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// importResultsToProto converts the results of an import batch to protobuf
+func importResultsToProto(results []*model.CustomerImportResult) []*customerpb.ImportRowResult {
+	pbResults := make([]*customerpb.ImportRowResult, len(results))
+	for i, result := range results {
+		pbResults[i] = &customerpb.ImportRowResult{
+			Line:       int32(result.Line),
+			Status:     result.Status,
+			CustomerId: result.CustomerID,
+			VehicleId:  result.VehicleID,
+			Field:      result.Field,
+			Message:    result.Message,
+		}
+	}
+	return pbResults
+}
+
 // customerToProto converts a domain Customer to protobuf
 func (h *CustomerHandler) customerToProto(customer *model.Customer) *customerpb.Customer {
 	pb := &customerpb.Customer{
@@ -372,6 +968,7 @@ func (h *CustomerHandler) customerToProto(customer *model.Customer) *customerpb.
 		IsActive:     customer.IsActive,
 		CreatedAt:    timestamppb.New(customer.CreatedAt),
 		UpdatedAt:    timestamppb.New(customer.UpdatedAt),
+		Version:      customer.Version,
 	}
 
 	if customer.Email != nil {
@@ -395,10 +992,17 @@ func (h *CustomerHandler) customerToProto(customer *model.Customer) *customerpb.
 	if customer.Birthday != nil {
 		pb.Birthday = timestamppb.New(*customer.Birthday)
 	}
+	if customer.DeletedAt != nil {
+		pb.DeletedAt = timestamppb.New(*customer.DeletedAt)
+		pb.PurgeAt = timestamppb.New(h.customerService.PurgeAt(*customer.DeletedAt))
+	}
+	if customer.AnonymizedAt != nil {
+		pb.AnonymizedAt = timestamppb.New(*customer.AnonymizedAt)
+	}
 
 	// Convert preferences
-	if customer.Preferences != nil && len(customer.Preferences) > 0 {
-		// TODO: Convert map to protobuf Struct
+	if len(customer.Preferences) > 0 {
+		pb.Preferences = structFromMap(customer.Preferences)
 	}
 
 	// Convert vehicles if present
@@ -417,6 +1021,114 @@ func (h *CustomerHandler) customerToProto(customer *model.Customer) *customerpb.
 		}
 	}
 
+	// Convert stats if present
+	if customer.Stats != nil {
+		pb.Stats = h.customerStatsToProto(customer.Stats)
+	}
+
+	return pb
+}
+
+// customerHistoryItemToProto converts a domain CustomerHistoryItem to protobuf
+func (h *CustomerHandler) customerHistoryItemToProto(item *model.CustomerHistoryItem) (*customerpb.CustomerHistoryItem, error) {
+	pb := &customerpb.CustomerHistoryItem{
+		Id:          item.ID,
+		Type:        item.Type,
+		Title:       item.Title,
+		Description: item.Description,
+		Amount:      item.Amount,
+		Status:      item.Status,
+		CreatedAt:   timestamppb.New(item.CreatedAt),
+	}
+
+	if len(item.Data) > 0 {
+		data, err := structpb.NewStruct(item.Data)
+		if err != nil {
+			return nil, err
+		}
+		pb.Data = data
+	}
+
+	return pb, nil
+}
+
+// auditEntryToProto converts a domain AuditEntry to protobuf, with the changes sorted by field
+func (h *CustomerHandler) auditEntryToProto(entry *model.AuditEntry) (*customerpb.AuditLogEntry, error) {
+	pb := &customerpb.AuditLogEntry{
+		Id:           entry.ID,
+		CustomerId:   entry.CustomerID,
+		ResourceType: entry.ResourceType,
+		ResourceId:   entry.ResourceID,
+		Action:       entry.Action,
+		ActorId:      entry.ActorID,
+		ActorName:    entry.ActorName,
+		RequestId:    entry.RequestID,
+		CreatedAt:    timestamppb.New(entry.CreatedAt),
+	}
+
+	fields := make([]string, 0, len(entry.Changes))
+	for field := range entry.Changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		change := entry.Changes[field]
+		before, err := structpb.NewValue(change.Before)
+		if err != nil {
+			return nil, err
+		}
+		after, err := structpb.NewValue(change.After)
+		if err != nil {
+			return nil, err
+		}
+		pb.Changes = append(pb.Changes, &customerpb.AuditFieldChange{
+			Field:  field,
+			Before: before,
+			After:  after,
+		})
+	}
+
+	return pb, nil
+}
+
+// duplicateCandidatesToProto converts domain DuplicateCandidates to protobuf
+func (h *CustomerHandler) duplicateCandidatesToProto(candidates []*model.DuplicateCandidate) []*customerpb.DuplicateCandidate {
+	pbCandidates := make([]*customerpb.DuplicateCandidate, len(candidates))
+	for i, candidate := range candidates {
+		reasons := make([]*customerpb.DuplicateMatchReason, len(candidate.Reasons))
+		for j, reason := range candidate.Reasons {
+			reasons[j] = &customerpb.DuplicateMatchReason{
+				Reason:      reason.Reason,
+				Similarity:  reason.Similarity,
+				Description: reason.Description,
+			}
+		}
+		pbCandidates[i] = &customerpb.DuplicateCandidate{
+			Customer: h.customerToProto(candidate.Customer),
+			Score:    candidate.Score,
+			Reasons:  reasons,
+		}
+	}
+	return pbCandidates
+}
+
+// customerStatsToProto converts domain CustomerStats to protobuf
+func (h *CustomerHandler) customerStatsToProto(stats *model.CustomerStats) *customerpb.CustomerStats {
+	pb := &customerpb.CustomerStats{
+		TotalOrders:       stats.TotalOrders,
+		TotalSpent:        stats.TotalSpent,
+		AverageOrderValue: stats.AverageOrderValue,
+		VisitsCount:       stats.VisitsCount,
+		FavoriteCategory:  stats.FavoriteCategory,
+		FavoriteProducts:  stats.FavoriteProducts,
+		Level:             stats.GetCustomerLevel(),
+	}
+
+	if !stats.LastVisit.IsZero() {
+		pb.LastVisit = timestamppb.New(stats.LastVisit)
+	}
+
 	return pb
 }
 
@@ -431,6 +1143,7 @@ func (h *CustomerHandler) vehicleToProto(vehicle *model.Vehicle) *customerpb.Veh
 		IsActive:   vehicle.IsActive,
 		CreatedAt:  timestamppb.New(vehicle.CreatedAt),
 		UpdatedAt:  timestamppb.New(vehicle.UpdatedAt),
+		Version:    vehicle.Version,
 	}
 
 	if vehicle.VIN != nil {
@@ -449,7 +1162,9 @@ func (h *CustomerHandler) vehicleToProto(vehicle *model.Vehicle) *customerpb.Veh
 		pb.Notes = *vehicle.Notes
 	}
 
-	// TODO: Convert metadata to protobuf Struct
+	if len(vehicle.Metadata) > 0 {
+		pb.Metadata = structFromMap(vehicle.Metadata)
+	}
 
 	return pb
 }
@@ -467,51 +1182,73 @@ func (h *CustomerHandler) customerNoteToProto(note *model.CustomerNote) *custome
 	}
 }
 
-// Helper functions
+// customerUpdateFromRequest applies the non-empty fields of a request without update_mask
+func customerUpdateFromRequest(req *customerpb.UpdateCustomerRequest) model.CustomerUpdate {
+	update := model.CustomerUpdate{
+		ID: req.Id,
+	}
 
-func stringPtrFromProto(s string) *string {
-	if s == "" {
-		return nil
+	if req.FirstName != "" {
+		update.FirstName = &req.FirstName
+	}
+	if req.LastName != "" {
+		update.LastName = &req.LastName
+	}
+	if req.Email != "" {
+		update.Email = &req.Email
+	}
+	if req.Phone != "" {
+		update.Phone = &req.Phone
+	}
+	if req.CustomerType != "" {
+		update.CustomerType = &req.CustomerType
+	}
+	if req.CompanyName != "" {
+		update.CompanyName = &req.CompanyName
+	}
+	if req.TaxId != "" {
+		update.TaxID = &req.TaxId
+	}
+	if req.Address != "" {
+		update.Address = &req.Address
+	}
+	if req.Notes != "" {
+		update.Notes = &req.Notes
 	}
-	return &s
-}
 
-func isNotFoundError(err error) bool {
-	return err == sql.ErrNoRows ||
-		(err != nil && (containsString(err.Error(), "not found") ||
-			containsString(err.Error(), "does not exist")))
-}
+	update.IsActive = &req.IsActive
 
-func isValidationError(err error) bool {
-	if err == nil {
-		return false
+	if req.Birthday != nil {
+		birthday := req.Birthday.AsTime()
+		update.Birthday = &birthday
+	}
+
+	if req.Preferences != nil {
+		prefs := req.Preferences.AsMap()
+		update.Preferences = prefs
 	}
-	_, ok := err.(*model.ValidationError)
-	return ok || containsString(err.Error(), "validation error")
-}
 
-func isDuplicateError(err error) bool {
-	return err != nil && (containsString(err.Error(), "already exists") ||
-		containsString(err.Error(), "duplicate") ||
-		containsString(err.Error(), "unique constraint"))
+	return update
 }
 
-func containsString(s, substr string) bool {
-	return len(s) >= len(substr) &&
-		(s == substr ||
-			(len(s) > len(substr) &&
-				(s[:len(substr)] == substr ||
-					s[len(s)-len(substr):] == substr ||
-					findSubstring(s, substr))))
+// Helper functions
+
+func stringPtrFromProto(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
 }
 
-func findSubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
+// structFromMap converts a JSON map (preferences, metadata) to a protobuf Struct. The maps
+// are decoded from JSONB or from a Struct, so they only hold JSON values; a value that
+// cannot be converted anyway returns nil rather than failing the whole response.
+func structFromMap(m map[string]interface{}) *structpb.Struct {
+	pb, err := structpb.NewStruct(m)
+	if err != nil {
+		return nil
 	}
-	return false
+	return pb
 }
 
 // RegisterService registers the customer service with the gRPC server
@@ -0,0 +1,204 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/encomos/api-encomos/customer-service/internal/config"
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/identity"
+	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+	customerpb "github.com/encomos/api-encomos/customer-service/proto/customer"
+)
+
+// fakeNoteRepo lists notes, leaving out the excluded types as the PostgreSQL repository does
+type fakeNoteRepo struct {
+	repository.CustomerNoteRepository
+	notes []*model.CustomerNote
+}
+
+func (r *fakeNoteRepo) List(ctx context.Context, filter model.CustomerNoteFilter) ([]*model.CustomerNote, model.PageInfo, error) {
+	excluded := make(map[string]bool, len(filter.ExcludeTypes))
+	for _, noteType := range filter.ExcludeTypes {
+		excluded[noteType] = true
+	}
+
+	var notes []*model.CustomerNote
+	for _, note := range r.notes {
+		if note.CustomerID == filter.CustomerID && !excluded[note.Type] {
+			notes = append(notes, note)
+		}
+	}
+	return notes, model.PageInfo{}, nil
+}
+
+func (r *fakeNoteRepo) Create(ctx context.Context, note *model.CustomerNote) error {
+	r.notes = append(r.notes, note)
+	return nil
+}
+
+// fakeVehicleRepo has no vehicles
+type fakeVehicleRepo struct {
+	repository.VehicleRepository
+}
+
+func (r *fakeVehicleRepo) ListByCustomers(ctx context.Context, customerIDs []string) ([]*model.Vehicle, error) {
+	return nil, nil
+}
+
+// fakeStatsRepo has no stats
+type fakeStatsRepo struct {
+	repository.CustomerStatsRepository
+}
+
+func (r *fakeStatsRepo) Exists(ctx context.Context, customerID string) (bool, error) {
+	return false, nil
+}
+
+// fakeHistoryRepo accepts the timeline items
+type fakeHistoryRepo struct {
+	repository.CustomerHistoryRepository
+}
+
+func (r *fakeHistoryRepo) Create(ctx context.Context, item *model.CustomerHistoryItem) error {
+	return nil
+}
+
+// fakeAuditRepo keeps the recorded entries
+type fakeAuditRepo struct {
+	repository.AuditLogRepository
+	entries []*model.AuditEntry
+}
+
+func (r *fakeAuditRepo) Create(ctx context.Context, entry *model.AuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// writeTestPolicy writes an authorization policy file and returns its path
+func writeTestPolicy(t *testing.T, policy string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "authz.json")
+	if err := os.WriteFile(path, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExportCustomerDataHidesRestrictedNotes(t *testing.T) {
+	customer := &model.Customer{ID: "c-1", TenantID: testTenantID, FirstName: "Ana", LastName: "García", CustomerType: model.CustomerTypeIndividual}
+	audit := &fakeAuditRepo{}
+	repos := testRepos{
+		customers: &fakeCustomerRepo{customers: map[string]*model.Customer{customer.ID: customer}},
+		vehicles:  &fakeVehicleRepo{},
+		notes: &fakeNoteRepo{notes: []*model.CustomerNote{
+			{ID: "n-1", CustomerID: customer.ID, Type: model.NoteTypeGeneral, Note: "Prefiere citas por la mañana"},
+			{ID: "n-2", CustomerID: customer.ID, Type: model.NoteTypeWarning, Note: "Impagos anteriores"},
+		}},
+		stats: &fakeStatsRepo{},
+		audit: audit,
+	}
+
+	appConfig := &config.Config{Authz: config.AuthzConfig{Enabled: true, PolicyFile: writeTestPolicy(t, `{
+		"methods": {"customer.v1.CustomerService/ExportCustomerData": ["admin", "staff"]},
+		"note_types": {"warning": ["admin"]}
+	}`)}}
+	client := newTestClient(t, appConfig, repos)
+
+	tests := []struct {
+		roles string
+		want  []string
+	}{
+		{"staff", []string{"n-1"}},
+		{"admin", []string{"n-1", "n-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.roles, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(tenantContext(), "x-staff-id", "s-1", "x-staff-roles", tt.roles)
+			export := receiveCustomerData(t, client, ctx, customer.ID)
+
+			var ids []string
+			for _, note := range export.Notes {
+				ids = append(ids, note.ID)
+			}
+			if len(ids) != len(tt.want) || ids[0] != tt.want[0] || ids[len(ids)-1] != tt.want[len(tt.want)-1] {
+				t.Errorf("expected notes %v, got %v", tt.want, ids)
+			}
+
+			// La auditoría cuenta las notas entregadas
+			entry := audit.entries[len(audit.entries)-1]
+			if entry.Action != model.AuditActionCustomerDataExported || entry.Changes["notes"].After != len(tt.want) {
+				t.Errorf("expected an export audit entry with %d notes, got %s %v", len(tt.want), entry.Action, entry.Changes["notes"])
+			}
+		})
+	}
+}
+
+// receiveCustomerData reads the chunks of ExportCustomerData and decodes the bundle
+func receiveCustomerData(t *testing.T, client customerpb.CustomerServiceClient, ctx context.Context, id string) *model.CustomerDataExport {
+	t.Helper()
+
+	stream, err := client.ExportCustomerData(ctx, &customerpb.ExportCustomerDataRequest{Id: id})
+	if err != nil {
+		t.Fatalf("ExportCustomerData: %v", err)
+	}
+
+	var data []byte
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("ExportCustomerData: %v", err)
+		}
+		data = append(data, resp.Data...)
+	}
+
+	export := &model.CustomerDataExport{}
+	if err := json.Unmarshal(data, export); err != nil {
+		t.Fatalf("failed to decode the bundle: %v", err)
+	}
+	return export
+}
+
+func TestAddCustomerNoteAuthor(t *testing.T) {
+	customer := &model.Customer{ID: "c-1", TenantID: testTenantID, FirstName: "Ana", LastName: "García", CustomerType: model.CustomerTypeIndividual}
+	repos := testRepos{
+		customers: &fakeCustomerRepo{customers: map[string]*model.Customer{customer.ID: customer}},
+		notes:     &fakeNoteRepo{},
+		history:   &fakeHistoryRepo{},
+		audit:     &fakeAuditRepo{},
+	}
+
+	// Sin autenticación el autor es el staff de los metadatos o, si no hay, el del sistema
+	client := newTestClient(t, &config.Config{}, repos)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantID   string
+		wantName string
+	}{
+		{"metadata identity", metadata.AppendToOutgoingContext(tenantContext(), "x-staff-id", "s-1", "x-staff-name", "Luis"), "s-1", "Luis"},
+		{"without identity", tenantContext(), identity.System.ID, identity.System.Name},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.AddCustomerNote(tt.ctx, &customerpb.AddCustomerNoteRequest{CustomerId: customer.ID, Note: "Llamar antes de la revisión"})
+			if err != nil {
+				t.Fatalf("AddCustomerNote: %v", err)
+			}
+			if resp.Note.StaffId != tt.wantID || resp.Note.StaffName != tt.wantName {
+				t.Errorf("expected author %s (%s), got %s (%s)", tt.wantID, tt.wantName, resp.Note.StaffId, resp.Note.StaffName)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package grpc
+
+import (
+	customerpb "github.com/encomos/api-encomos/customer-service/proto/customer"
+)
+
+// CustomerServiceServer composes the customer and vehicle handlers into the single
+// customerpb.CustomerServiceServer registered on the gRPC server.
+//
+// CustomerHandler embeds UnimplementedCustomerServiceServer, so its vehicle methods are
+// shadowed by the shallower VehicleHandler methods promoted through this struct.
+type CustomerServiceServer struct {
+	*CustomerHandler
+	*VehicleHandler
+}
+
+// Compile-time check that every CustomerService RPC is served
+var _ customerpb.CustomerServiceServer = (*CustomerServiceServer)(nil)
+
+// NewCustomerServiceServer creates the composed CustomerService implementation
+func NewCustomerServiceServer(customerHandler *CustomerHandler, vehicleHandler *VehicleHandler) *CustomerServiceServer {
+	return &CustomerServiceServer{
+		CustomerHandler: customerHandler,
+		VehicleHandler:  vehicleHandler,
+	}
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/pagetoken"
+	"github.com/encomos/api-encomos/customer-service/internal/tenancy"
+)
+
+// decodePageToken returns the cursor of a page token issued to the tenant of the request
+// for the same listing scope, or nil when no token was sent
+func decodePageToken(ctx context.Context, codec *pagetoken.Codec, token, scope string) (*model.PageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	tenantID, err := tenancy.IDFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "tenant is required")
+	}
+
+	key, err := codec.Decode(token, tenantID, scope)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page_token: it is corrupted or was issued for another listing")
+	}
+
+	return &model.PageCursor{Key: key}, nil
+}
+
+// encodePageToken returns the token of the following page, or an empty string on the last page
+func encodePageToken(ctx context.Context, codec *pagetoken.Codec, next *model.PageCursor, scope string) (string, error) {
+	if next == nil {
+		return "", nil
+	}
+
+	tenantID, err := tenancy.IDFromContext(ctx)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "tenant is required")
+	}
+
+	token, err := codec.Encode(tenantID, scope, next.Key)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to encode page token")
+	}
+
+	return token, nil
+}
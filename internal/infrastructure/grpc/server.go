@@ -13,24 +13,53 @@ import (
 
 	"github.com/encomos/api-encomos/customer-service/internal/config"
 	"github.com/encomos/api-encomos/customer-service/internal/domain/service"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/auth"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/authz"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/gateway"
 	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/metrics"
 	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/middleware"
+	"github.com/encomos/api-encomos/customer-service/internal/pagetoken"
 	customerpb "github.com/encomos/api-encomos/customer-service/proto/customer"
 )
 
 // Server represents the gRPC server
 type Server struct {
-	server   *grpc.Server
-	listener net.Listener
-	config   *config.GRPCConfig
-	logger   *logger.Logger
+	server            *grpc.Server
+	listener          net.Listener
+	config            *config.GRPCConfig
+	policy            *authz.Policy
+	pageTokens        *pagetoken.Codec
+	unaryInterceptor  grpc.UnaryServerInterceptor
+	streamInterceptor grpc.StreamServerInterceptor
+	customerServer    customerpb.CustomerServiceServer
+	logger            *logger.Logger
 }
 
-// NewServer creates a new gRPC server
-func NewServer(cfg *config.GRPCConfig) (*Server, error) {
+// NewServer creates a new gRPC server. grpcMetrics may be nil to disable RPC metrics.
+func NewServer(appConfig *config.Config, grpcMetrics *metrics.GRPCMetrics) (*Server, error) {
+	cfg := &appConfig.GRPC
+
 	// Create logger
 	logger := logger.NewWithService("customer-service")
 
+	unaryInterceptor, streamInterceptor, policy, err := newInterceptorChains(appConfig, grpcMetrics, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Page tokens must be signed with the same secret by every replica; production requires
+	// it (validateConfig), elsewhere a random secret is only fit for a single process
+	tokenSecret := []byte(appConfig.Pagination.TokenSecret)
+	if len(tokenSecret) == 0 {
+		tokenSecret, err = pagetoken.NewRandomSecret()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate page token secret: %w", err)
+		}
+		logger.WithFields(map[string]interface{}{"pagination": "random_secret", "environment": appConfig.Server.Environment}).
+			Warn("PAGINATION_TOKEN_SECRET is not set: page tokens are only valid in this process, so behind more than one replica or after a restart next_page_token fails with INVALID_ARGUMENT; set the same secret on every replica")
+	}
+
 	// Create listener
 	address := fmt.Sprintf(":%d", cfg.Port)
 	listener, err := net.Listen("tcp", address)
@@ -40,17 +69,8 @@ func NewServer(cfg *config.GRPCConfig) (*Server, error) {
 
 	// Create gRPC server with middleware
 	serverOptions := []grpc.ServerOption{
-		grpc.ChainUnaryInterceptor(
-			middleware.TenantInterceptor(logger),
-			middleware.LoggingInterceptor(logger),
-			middleware.RecoveryInterceptor(logger),
-			// TODO: Add authentication interceptor when auth service is ready
-		),
-		grpc.ChainStreamInterceptor(
-			middleware.StreamTenantInterceptor(logger),
-			middleware.StreamLoggingInterceptor(logger),
-			middleware.StreamRecoveryInterceptor(logger),
-		),
+		grpc.UnaryInterceptor(unaryInterceptor),
+		grpc.StreamInterceptor(streamInterceptor),
 	}
 
 	// Add TLS if configured
@@ -62,10 +82,14 @@ func NewServer(cfg *config.GRPCConfig) (*Server, error) {
 	server := grpc.NewServer(serverOptions...)
 
 	return &Server{
-		server:   server,
-		listener: listener,
-		config:   cfg,
-		logger:   logger,
+		server:            server,
+		listener:          listener,
+		config:            cfg,
+		policy:            policy,
+		pageTokens:        pagetoken.NewCodec(tokenSecret),
+		unaryInterceptor:  unaryInterceptor,
+		streamInterceptor: streamInterceptor,
+		logger:            logger,
 	}, nil
 }
 
@@ -75,10 +99,12 @@ func (s *Server) RegisterServices(
 	vehicleService *service.VehicleService,
 ) {
 	// Create handlers
-	customerHandler := NewCustomerHandler(customerService, vehicleService)
+	customerHandler := NewCustomerHandler(customerService, vehicleService, s.policy, s.pageTokens)
+	vehicleHandler := NewVehicleHandler(vehicleService, s.pageTokens)
 
-	// Register services
-	customerpb.RegisterCustomerServiceServer(s.server, customerHandler)
+	// Register services (customer and vehicle RPCs share the CustomerService definition)
+	s.customerServer = NewCustomerServiceServer(customerHandler, vehicleHandler)
+	customerpb.RegisterCustomerServiceServer(s.server, s.customerServer)
 
 	// Register health service
 	healthServer := health.NewServer()
@@ -94,6 +120,15 @@ func (s *Server) RegisterServices(
 	s.logger.WithFields(map[string]interface{}{"status": "registered"}).Info("All gRPC services registered successfully")
 }
 
+// NewGateway creates the REST/JSON gateway for the registered CustomerService.
+// Gateway requests run through the same interceptor chain as gRPC calls.
+func (s *Server) NewGateway(allowedOrigins, trustedProxies []string) (*gateway.Gateway, error) {
+	if s.customerServer == nil {
+		return nil, fmt.Errorf("services must be registered before creating the gateway")
+	}
+	return gateway.NewGateway(s.customerServer, s.unaryInterceptor, s.streamInterceptor, allowedOrigins, trustedProxies, s.logger)
+}
+
 // Start starts the gRPC server
 func (s *Server) Start() error {
 	s.logger.WithFields(map[string]interface{}{"address": s.listener.Addr().String()}).Info("Starting gRPC server")
@@ -202,3 +237,94 @@ func (s *Server) SetServingStatus(serviceName string, status grpc_health_v1.Heal
 		"status":  status.String(),
 	}).Info("Service status updated")
 }
+
+// newInterceptorChains builds the unary and stream interceptor chains shared by the gRPC
+// server and the REST gateway. The policy is nil when authorization is disabled.
+func newInterceptorChains(appConfig *config.Config, grpcMetrics *metrics.GRPCMetrics, logger *logger.Logger) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor, *authz.Policy, error) {
+	// Recovery is the outermost interceptor so a panic in any other interceptor, and not only
+	// in the handler, ends as Internal instead of killing the process
+	unaryInterceptors := []grpc.UnaryServerInterceptor{middleware.RecoveryInterceptor(logger)}
+	streamInterceptors := []grpc.StreamServerInterceptor{middleware.StreamRecoveryInterceptor(logger)}
+
+	// Metrics run before the tenant and auth interceptors so rejected requests are counted
+	if grpcMetrics != nil {
+		unaryInterceptors = append(unaryInterceptors, middleware.MetricsInterceptor(grpcMetrics))
+		streamInterceptors = append(streamInterceptors, middleware.StreamMetricsInterceptor(grpcMetrics))
+	}
+
+	// The request ID is resolved before any rejection so every log line and audit entry carries it
+	unaryInterceptors = append(unaryInterceptors, middleware.RequestIDInterceptor())
+	streamInterceptors = append(streamInterceptors, middleware.StreamRequestIDInterceptor())
+
+	unaryInterceptors = append(unaryInterceptors, middleware.TenantInterceptor(logger))
+	streamInterceptors = append(streamInterceptors, middleware.StreamTenantInterceptor(logger))
+
+	// Authentication runs after the tenant interceptor to compare the token tenant
+	if appConfig.Auth.Enabled {
+		verifier, err := auth.NewVerifier(&appConfig.Auth)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create JWT verifier: %w", err)
+		}
+		unaryInterceptors = append(unaryInterceptors, middleware.AuthInterceptor(verifier, logger))
+		streamInterceptors = append(streamInterceptors, middleware.StreamAuthInterceptor(verifier, logger))
+	} else {
+		logger.WithFields(map[string]interface{}{"auth": "disabled"}).Warn("Authentication is disabled, staff identity will not be available")
+	}
+
+	// Authorization uses the token identity or the gateway-supplied staff metadata
+	var policy *authz.Policy
+	if appConfig.Authz.Enabled {
+		var err error
+		policy, err = authz.LoadPolicyFile(appConfig.Authz.PolicyFile)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load authorization policy: %w", err)
+		}
+		unaryInterceptors = append(unaryInterceptors, middleware.AuthorizationInterceptor(policy, logger))
+		streamInterceptors = append(streamInterceptors, middleware.StreamAuthorizationInterceptor(policy, logger))
+	} else {
+		logger.WithFields(map[string]interface{}{"authz": "disabled"}).Warn("Authorization is disabled, every method is allowed")
+		// Sin autorización, la identidad del gateway se sigue usando para la auditoría
+		unaryInterceptors = append(unaryInterceptors, middleware.IdentityInterceptor())
+		streamInterceptors = append(streamInterceptors, middleware.StreamIdentityInterceptor())
+	}
+
+	// Domain errors are translated last, so every interceptor above sees the final status
+	unaryInterceptors = append(unaryInterceptors,
+		middleware.LoggingInterceptor(logger),
+		middleware.ErrorInterceptor(logger),
+	)
+	streamInterceptors = append(streamInterceptors,
+		middleware.StreamLoggingInterceptor(logger),
+		middleware.StreamErrorInterceptor(logger),
+	)
+
+	return chainUnaryInterceptors(unaryInterceptors), chainStreamInterceptors(streamInterceptors), policy, nil
+}
+
+// chainUnaryInterceptors composes interceptors so the first one is the outermost
+func chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// chainStreamInterceptors composes stream interceptors so the first one is the outermost
+func chainStreamInterceptors(interceptors []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(srv interface{}, stream grpc.ServerStream) error {
+				return interceptor(srv, stream, info, inner)
+			}
+		}
+		return next(srv, stream)
+	}
+}
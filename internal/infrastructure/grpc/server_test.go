@@ -0,0 +1,239 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/encomos/api-encomos/customer-service/internal/config"
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/domain/service"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+	"github.com/encomos/api-encomos/customer-service/internal/pagetoken"
+	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+	customerpb "github.com/encomos/api-encomos/customer-service/proto/customer"
+)
+
+const testTenantID = "6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c10"
+
+// fakeCustomerRepo answers the lookups used by the tests; any other method panics through
+// the embedded nil interface, which the recovery interceptor turns into Internal
+type fakeCustomerRepo struct {
+	repository.CustomerRepository
+	customers   map[string]*model.Customer
+	takenEmails map[string]bool
+	panicOnGet  bool
+}
+
+func (r *fakeCustomerRepo) GetByID(ctx context.Context, id string) (*model.Customer, error) {
+	if r.panicOnGet {
+		panic("fake repository failure")
+	}
+	if customer, ok := r.customers[id]; ok {
+		return customer, nil
+	}
+	return nil, model.NewNotFoundError("customer", id)
+}
+
+func (r *fakeCustomerRepo) ResolveMergedID(ctx context.Context, mergedID string) (string, error) {
+	return "", model.NewNotFoundError("customer_merge", mergedID)
+}
+
+func (r *fakeCustomerRepo) ExistsByEmail(ctx context.Context, email string, excludeID *string) (bool, error) {
+	return r.takenEmails[email], nil
+}
+
+func (r *fakeCustomerRepo) ExistsByTaxID(ctx context.Context, taxID string, excludeID *string) (bool, error) {
+	return false, nil
+}
+
+// fakeTransactor runs fn without a transaction
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// testRepos are the repositories of a test server; nil ones are not used by the test
+type testRepos struct {
+	customers *fakeCustomerRepo
+	vehicles  repository.VehicleRepository
+	notes     repository.CustomerNoteRepository
+	stats     repository.CustomerStatsRepository
+	history   repository.CustomerHistoryRepository
+	audit     repository.AuditLogRepository
+}
+
+// newTestClient serves the CustomerService over bufconn with the interceptor chains that
+// NewServer builds for appConfig and the given repositories
+func newTestClient(t *testing.T, appConfig *config.Config, repos testRepos) customerpb.CustomerServiceClient {
+	t.Helper()
+
+	log := logger.NewWithService("test")
+	unary, stream, policy, err := newInterceptorChains(appConfig, nil, log)
+	if err != nil {
+		t.Fatalf("newInterceptorChains: %v", err)
+	}
+
+	customerService := service.NewCustomerService(repos.customers, repos.vehicles, repos.notes, repos.stats, repos.history, nil, repos.audit, fakeTransactor{}, model.DuplicatePolicy{}, time.Hour)
+	vehicleService := service.NewVehicleService(repos.vehicles, repos.customers, repos.audit, fakeTransactor{})
+	pageTokens := pagetoken.NewCodec([]byte("test-secret"))
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream))
+	customerpb.RegisterCustomerServiceServer(server, NewCustomerServiceServer(
+		NewCustomerHandler(customerService, vehicleService, policy, pageTokens),
+		NewVehicleHandler(vehicleService, pageTokens),
+	))
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return customerpb.NewCustomerServiceClient(conn)
+}
+
+// tenantContext returns an outgoing context with the tenant metadata of the API Gateway
+func tenantContext() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", testTenantID)
+}
+
+func TestServerNotFound(t *testing.T) {
+	client := newTestClient(t, &config.Config{}, testRepos{customers: &fakeCustomerRepo{}})
+
+	_, err := client.GetCustomer(tenantContext(), &customerpb.GetCustomerRequest{Id: "missing"})
+	st := status.Convert(err)
+	if st.Code() != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
+	info := statusDetail[*errdetails.ResourceInfo](t, st)
+	if info.ResourceType != "customer" || info.ResourceName != "missing" {
+		t.Errorf("unexpected ResourceInfo %+v", info)
+	}
+}
+
+func TestServerCustomerPreferences(t *testing.T) {
+	customer := &model.Customer{
+		ID:           "c-1",
+		TenantID:     testTenantID,
+		FirstName:    "Ana",
+		LastName:     "García",
+		CustomerType: model.CustomerTypeIndividual,
+		Preferences:  model.CustomerPreferences{"contact": "email", "reminders": true},
+	}
+	client := newTestClient(t, &config.Config{}, testRepos{customers: &fakeCustomerRepo{customers: map[string]*model.Customer{customer.ID: customer}}})
+
+	resp, err := client.GetCustomer(tenantContext(), &customerpb.GetCustomerRequest{Id: customer.ID})
+	if err != nil {
+		t.Fatalf("GetCustomer: %v", err)
+	}
+	prefs := resp.Customer.Preferences.AsMap()
+	if prefs["contact"] != "email" || prefs["reminders"] != true {
+		t.Errorf("unexpected preferences %v", prefs)
+	}
+}
+
+func TestServerValidation(t *testing.T) {
+	client := newTestClient(t, &config.Config{}, testRepos{customers: &fakeCustomerRepo{}})
+
+	_, err := client.CreateCustomer(tenantContext(), &customerpb.CreateCustomerRequest{
+		FirstName:    "Ana",
+		LastName:     "García",
+		CustomerType: "reseller",
+		Email:        "not-an-email",
+	})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+
+	badRequest := statusDetail[*errdetails.BadRequest](t, st)
+	fields := make(map[string]bool)
+	for _, violation := range badRequest.FieldViolations {
+		fields[violation.Field] = true
+	}
+	if len(fields) != 2 || !fields["customer_type"] || !fields["email"] {
+		t.Errorf("expected violations for customer_type and email, got %v", badRequest.FieldViolations)
+	}
+}
+
+func TestServerConflict(t *testing.T) {
+	client := newTestClient(t, &config.Config{}, testRepos{customers: &fakeCustomerRepo{takenEmails: map[string]bool{"ana@example.com": true}}})
+
+	_, err := client.CreateCustomer(tenantContext(), &customerpb.CreateCustomerRequest{
+		FirstName:    "Ana",
+		LastName:     "García",
+		CustomerType: model.CustomerTypeIndividual,
+		Email:        "ana@example.com",
+	})
+	st := status.Convert(err)
+	if st.Code() != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists, got %v", err)
+	}
+
+	info := statusDetail[*errdetails.ResourceInfo](t, st)
+	if info.ResourceType != "customer" || info.ResourceName != "ana@example.com" {
+		t.Errorf("unexpected ResourceInfo %+v", info)
+	}
+	badRequest := statusDetail[*errdetails.BadRequest](t, st)
+	if len(badRequest.FieldViolations) != 1 || badRequest.FieldViolations[0].Field != "email" {
+		t.Errorf("expected a violation for email, got %v", badRequest.FieldViolations)
+	}
+}
+
+func TestServerMissingTenant(t *testing.T) {
+	client := newTestClient(t, &config.Config{}, testRepos{customers: &fakeCustomerRepo{}})
+
+	_, err := client.GetCustomer(context.Background(), &customerpb.GetCustomerRequest{Id: "c-1"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected the tenant interceptor to reject the call with InvalidArgument, got %v", err)
+	}
+}
+
+func TestServerRecoversPanics(t *testing.T) {
+	client := newTestClient(t, &config.Config{}, testRepos{customers: &fakeCustomerRepo{panicOnGet: true}})
+
+	_, err := client.GetCustomer(tenantContext(), &customerpb.GetCustomerRequest{Id: "c-1"})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+
+	// El servidor sigue atendiendo después del panic
+	_, err = client.CreateCustomer(tenantContext(), &customerpb.CreateCustomerRequest{FirstName: "Ana", LastName: "García", CustomerType: "reseller"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument after the panic, got %v", err)
+	}
+}
+
+// statusDetail returns the first detail of type T in st
+func statusDetail[T any](t *testing.T, st *status.Status) T {
+	t.Helper()
+	for _, detail := range st.Details() {
+		if d, ok := detail.(T); ok {
+			return d
+		}
+	}
+	var zero T
+	t.Fatalf("status %v has no %T detail", st.Proto(), zero)
+	return zero
+}
@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	customerpb "github.com/encomos/api-encomos/customer-service/proto/customer"
+)
+
+// customerUpdatePaths are the UpdateCustomerRequest fields accepted in update_mask
+var customerUpdatePaths = []string{
+	"first_name", "last_name", "email", "phone", "customer_type", "company_name",
+	"tax_id", "address", "birthday", "notes", "preferences", "is_active",
+}
+
+// vehicleUpdatePaths are the UpdateVehicleRequest fields accepted in update_mask
+var vehicleUpdatePaths = []string{
+	"make", "model", "year", "vin", "license_plate", "color", "engine", "notes",
+	"is_active", "metadata",
+}
+
+// maskPaths returns the paths of mask as a set. Paths outside allowed (including
+// nested paths and identifiers such as id) are reported as update_mask violations.
+func maskPaths(mask *fieldmaskpb.FieldMask, allowed []string) (map[string]bool, error) {
+	known := make(map[string]bool, len(allowed))
+	for _, path := range allowed {
+		known[path] = true
+	}
+
+	var errs model.ValidationErrors
+	paths := make(map[string]bool, len(mask.GetPaths()))
+	for _, path := range mask.GetPaths() {
+		if !known[path] {
+			errs.Add("update_mask", fmt.Sprintf("ruta desconocida o no actualizable: %q", path))
+			continue
+		}
+		paths[path] = true
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, &model.ValidationError{Field: "update_mask", Message: "debe indicar al menos un campo"}
+	}
+
+	return paths, nil
+}
+
+// customerUpdateFromMask applies only the masked fields; a masked field left empty is cleared
+func customerUpdateFromMask(req *customerpb.UpdateCustomerRequest) (model.CustomerUpdate, error) {
+	update := model.CustomerUpdate{ID: req.Id}
+
+	paths, err := maskPaths(req.UpdateMask, customerUpdatePaths)
+	if err != nil {
+		return update, err
+	}
+
+	// Cadenas vacías se conservan: el modelo las interpreta como NULL (o falla la validación si el campo es requerido)
+	stringFields := map[string]struct {
+		value  string
+		target **string
+	}{
+		"first_name":    {req.FirstName, &update.FirstName},
+		"last_name":     {req.LastName, &update.LastName},
+		"email":         {req.Email, &update.Email},
+		"phone":         {req.Phone, &update.Phone},
+		"customer_type": {req.CustomerType, &update.CustomerType},
+		"company_name":  {req.CompanyName, &update.CompanyName},
+		"tax_id":        {req.TaxId, &update.TaxID},
+		"address":       {req.Address, &update.Address},
+		"notes":         {req.Notes, &update.Notes},
+	}
+	for path, field := range stringFields {
+		if paths[path] {
+			value := field.value
+			*field.target = &value
+		}
+	}
+
+	if paths["birthday"] {
+		if req.Birthday != nil {
+			birthday := req.Birthday.AsTime()
+			update.Birthday = &birthday
+		} else {
+			update.ClearBirthday = true
+		}
+	}
+	if paths["preferences"] {
+		update.Preferences = model.CustomerPreferences{}
+		if req.Preferences != nil {
+			update.Preferences = req.Preferences.AsMap()
+		}
+	}
+	if paths["is_active"] {
+		isActive := req.IsActive
+		update.IsActive = &isActive
+	}
+
+	return update, nil
+}
+
+// vehicleUpdateFromMask applies only the masked fields; a masked field left empty is cleared
+func vehicleUpdateFromMask(req *customerpb.UpdateVehicleRequest) (model.VehicleUpdate, error) {
+	update := model.VehicleUpdate{ID: req.Id}
+
+	paths, err := maskPaths(req.UpdateMask, vehicleUpdatePaths)
+	if err != nil {
+		return update, err
+	}
+
+	stringFields := map[string]struct {
+		value  string
+		target **string
+	}{
+		"make":          {req.Make, &update.Make},
+		"model":         {req.Model, &update.Model},
+		"vin":           {req.Vin, &update.VIN},
+		"license_plate": {req.LicensePlate, &update.LicensePlate},
+		"color":         {req.Color, &update.Color},
+		"engine":        {req.Engine, &update.Engine},
+		"notes":         {req.Notes, &update.Notes},
+	}
+	for path, field := range stringFields {
+		if paths[path] {
+			value := field.value
+			*field.target = &value
+		}
+	}
+
+	if paths["year"] {
+		year := int(req.Year)
+		update.Year = &year
+	}
+	if paths["is_active"] {
+		isActive := req.IsActive
+		update.IsActive = &isActive
+	}
+	if paths["metadata"] {
+		update.Metadata = model.VehicleMetadata{}
+		if req.Metadata != nil {
+			update.Metadata = req.Metadata.AsMap()
+		}
+	}
+
+	return update, nil
+}
@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"strconv"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -9,18 +10,21 @@ import (
 
 	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
 	"github.com/encomos/api-encomos/customer-service/internal/domain/service"
+	"github.com/encomos/api-encomos/customer-service/internal/pagetoken"
 	customerpb "github.com/encomos/api-encomos/customer-service/proto/customer"
 )
 
 // VehicleHandler handles vehicle-related gRPC requests
 type VehicleHandler struct {
 	vehicleService *service.VehicleService
+	pageTokens     *pagetoken.Codec
 }
 
 // NewVehicleHandler creates a new vehicle handler
-func NewVehicleHandler(vehicleService *service.VehicleService) *VehicleHandler {
+func NewVehicleHandler(vehicleService *service.VehicleService, pageTokens *pagetoken.Codec) *VehicleHandler {
 	return &VehicleHandler{
 		vehicleService: vehicleService,
+		pageTokens:     pageTokens,
 	}
 }
 
@@ -44,12 +48,26 @@ func (h *VehicleHandler) ListVehicles(ctx context.Context, req *customerpb.ListV
 		ActiveOnly: req.ActiveOnly,
 		Page:       int(req.Page),
 		Limit:      int(req.Limit),
+		TotalMode:  req.TotalMode,
 	}
 
+	// El token solo vale para el mismo listado (filtros)
+	scope := pagetoken.Scope("ListVehicles", req.CustomerId, req.Search, strconv.FormatBool(req.ActiveOnly))
+	cursor, err := decodePageToken(ctx, h.pageTokens, req.PageToken, scope)
+	if err != nil {
+		return nil, err
+	}
+	filter.Cursor = cursor
+
 	// Ejecutar búsqueda
-	vehicles, total, err := h.vehicleService.ListVehicles(ctx, filter)
+	vehicles, page, err := h.vehicleService.ListVehicles(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	nextPageToken, err := encodePageToken(ctx, h.pageTokens, page.Next, scope)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to list vehicles: %v", err)
+		return nil, err
 	}
 
 	// Convertir a protobuf
@@ -59,8 +77,10 @@ func (h *VehicleHandler) ListVehicles(ctx context.Context, req *customerpb.ListV
 	}
 
 	return &customerpb.ListVehiclesResponse{
-		Vehicles: pbVehicles,
-		Total:    int32(total),
+		Vehicles:        pbVehicles,
+		Total:           int32(page.Total),
+		NextPageToken:   nextPageToken,
+		TotalIsEstimate: page.TotalIsEstimate,
 	}, nil
 }
 
@@ -72,10 +92,7 @@ func (h *VehicleHandler) GetVehicle(ctx context.Context, req *customerpb.GetVehi
 
 	vehicle, err := h.vehicleService.GetVehicle(ctx, req.Id)
 	if err != nil {
-		if isNotFoundError(err) {
-			return nil, status.Errorf(codes.NotFound, "vehicle not found")
-		}
-		return nil, status.Errorf(codes.Internal, "failed to get vehicle: %v", err)
+		return nil, err
 	}
 
 	return &customerpb.GetVehicleResponse{
@@ -100,36 +117,12 @@ func (h *VehicleHandler) CreateVehicle(ctx context.Context, req *customerpb.Crea
 	}
 
 	// Convertir de protobuf a modelo
-	create := model.VehicleCreate{
-		CustomerID:   req.CustomerId,
-		Make:         req.Make,
-		Model:        req.Model,
-		Year:         int(req.Year),
-		VIN:          stringPtrFromProto(req.Vin),
-		LicensePlate: stringPtrFromProto(req.LicensePlate),
-		Color:        stringPtrFromProto(req.Color),
-		Engine:       stringPtrFromProto(req.Engine),
-		Notes:        stringPtrFromProto(req.Notes),
-		Metadata:     make(model.VehicleMetadata),
-	}
-
-	if req.Metadata != nil {
-		create.Metadata = req.Metadata.AsMap()
-	}
+	create := vehicleCreateFromProto(req)
 
 	// Crear vehículo
 	vehicle, err := h.vehicleService.CreateVehicle(ctx, create)
 	if err != nil {
-		if isValidationError(err) {
-			return nil, status.Errorf(codes.InvalidArgument, "validation error: %v", err)
-		}
-		if isDuplicateError(err) {
-			return nil, status.Errorf(codes.AlreadyExists, "vehicle already exists: %v", err)
-		}
-		if isNotFoundError(err) {
-			return nil, status.Errorf(codes.NotFound, "customer not found")
-		}
-		return nil, status.Errorf(codes.Internal, "failed to create vehicle: %v", err)
+		return nil, err
 	}
 
 	return &customerpb.CreateVehicleResponse{
@@ -143,57 +136,22 @@ func (h *VehicleHandler) UpdateVehicle(ctx context.Context, req *customerpb.Upda
 		return nil, status.Errorf(codes.InvalidArgument, "vehicle ID is required")
 	}
 
-	// Convertir de protobuf a modelo
-	update := model.VehicleUpdate{
-		ID: req.Id,
-	}
-
-	if req.Make != "" {
-		update.Make = &req.Make
-	}
-	if req.Model != "" {
-		update.Model = &req.Model
-	}
-	if req.Year > 0 {
-		year := int(req.Year)
-		update.Year = &year
-	}
-	if req.Vin != "" {
-		update.VIN = &req.Vin
-	}
-	if req.LicensePlate != "" {
-		update.LicensePlate = &req.LicensePlate
-	}
-	if req.Color != "" {
-		update.Color = &req.Color
-	}
-	if req.Engine != "" {
-		update.Engine = &req.Engine
-	}
-	if req.Notes != "" {
-		update.Notes = &req.Notes
-	}
-
-	update.IsActive = &req.IsActive
-
-	if req.Metadata != nil {
-		metadata := req.Metadata.AsMap()
-		update.Metadata = metadata
+	// Convertir de protobuf a modelo; con update_mask solo se aplican las rutas indicadas
+	var update model.VehicleUpdate
+	if req.UpdateMask != nil {
+		var err error
+		if update, err = vehicleUpdateFromMask(req); err != nil {
+			return nil, err
+		}
+	} else {
+		update = vehicleUpdateFromRequest(req)
 	}
+	update.ExpectedVersion = req.ExpectedVersion
 
 	// Actualizar vehículo
 	vehicle, err := h.vehicleService.UpdateVehicle(ctx, update)
 	if err != nil {
-		if isNotFoundError(err) {
-			return nil, status.Errorf(codes.NotFound, "vehicle not found")
-		}
-		if isValidationError(err) {
-			return nil, status.Errorf(codes.InvalidArgument, "validation error: %v", err)
-		}
-		if isDuplicateError(err) {
-			return nil, status.Errorf(codes.AlreadyExists, "vehicle already exists: %v", err)
-		}
-		return nil, status.Errorf(codes.Internal, "failed to update vehicle: %v", err)
+		return nil, err
 	}
 
 	return &customerpb.UpdateVehicleResponse{
@@ -207,12 +165,9 @@ func (h *VehicleHandler) DeleteVehicle(ctx context.Context, req *customerpb.Dele
 		return nil, status.Errorf(codes.InvalidArgument, "vehicle ID is required")
 	}
 
-	err := h.vehicleService.DeleteVehicle(ctx, req.Id)
+	err := h.vehicleService.DeleteVehicle(ctx, req.Id, req.ExpectedVersion)
 	if err != nil {
-		if isNotFoundError(err) {
-			return nil, status.Errorf(codes.NotFound, "vehicle not found")
-		}
-		return nil, status.Errorf(codes.Internal, "failed to delete vehicle: %v", err)
+		return nil, err
 	}
 
 	return &customerpb.DeleteVehicleResponse{
@@ -220,6 +175,28 @@ func (h *VehicleHandler) DeleteVehicle(ctx context.Context, req *customerpb.Dele
 	}, nil
 }
 
+// vehicleCreateFromProto converts a CreateVehicleRequest to the domain model
+func vehicleCreateFromProto(req *customerpb.CreateVehicleRequest) model.VehicleCreate {
+	create := model.VehicleCreate{
+		CustomerID:   req.CustomerId,
+		Make:         req.Make,
+		Model:        req.Model,
+		Year:         int(req.Year),
+		VIN:          stringPtrFromProto(req.Vin),
+		LicensePlate: stringPtrFromProto(req.LicensePlate),
+		Color:        stringPtrFromProto(req.Color),
+		Engine:       stringPtrFromProto(req.Engine),
+		Notes:        stringPtrFromProto(req.Notes),
+		Metadata:     make(model.VehicleMetadata),
+	}
+
+	if req.Metadata != nil {
+		create.Metadata = req.Metadata.AsMap()
+	}
+
+	return create
+}
+
 // vehicleToProto converts a domain Vehicle to protobuf
 func (h *VehicleHandler) vehicleToProto(vehicle *model.Vehicle) *customerpb.Vehicle {
 	pb := &customerpb.Vehicle{
@@ -231,6 +208,7 @@ func (h *VehicleHandler) vehicleToProto(vehicle *model.Vehicle) *customerpb.Vehi
 		IsActive:   vehicle.IsActive,
 		CreatedAt:  timestamppb.New(vehicle.CreatedAt),
 		UpdatedAt:  timestamppb.New(vehicle.UpdatedAt),
+		Version:    vehicle.Version,
 	}
 
 	if vehicle.VIN != nil {
@@ -249,7 +227,51 @@ func (h *VehicleHandler) vehicleToProto(vehicle *model.Vehicle) *customerpb.Vehi
 		pb.Notes = *vehicle.Notes
 	}
 
-	// TODO: Convert metadata to protobuf Struct when needed
+	if len(vehicle.Metadata) > 0 {
+		pb.Metadata = structFromMap(vehicle.Metadata)
+	}
 
 	return pb
 }
+
+// vehicleUpdateFromRequest applies the non-empty fields of a request without update_mask
+func vehicleUpdateFromRequest(req *customerpb.UpdateVehicleRequest) model.VehicleUpdate {
+	update := model.VehicleUpdate{
+		ID: req.Id,
+	}
+
+	if req.Make != "" {
+		update.Make = &req.Make
+	}
+	if req.Model != "" {
+		update.Model = &req.Model
+	}
+	if req.Year > 0 {
+		year := int(req.Year)
+		update.Year = &year
+	}
+	if req.Vin != "" {
+		update.VIN = &req.Vin
+	}
+	if req.LicensePlate != "" {
+		update.LicensePlate = &req.LicensePlate
+	}
+	if req.Color != "" {
+		update.Color = &req.Color
+	}
+	if req.Engine != "" {
+		update.Engine = &req.Engine
+	}
+	if req.Notes != "" {
+		update.Notes = &req.Notes
+	}
+
+	update.IsActive = &req.IsActive
+
+	if req.Metadata != nil {
+		metadata := req.Metadata.AsMap()
+		update.Metadata = metadata
+	}
+
+	return update
+}
@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/persistence/postgres"
+)
+
+// TenantCountsTTL bounds how often the per-tenant counts are queried
+const TenantCountsTTL = 30 * time.Second
+
+// RegisterDBStats exposes the sql.DBStats connection pool statistics
+func RegisterDBStats(registry *Registry, stats func() sql.DBStats) {
+	gauge := func(name, help string, value func(s sql.DBStats) float64) {
+		NewGaugeFunc(registry, name, help, nil, func(ctx context.Context) ([]GaugeSample, error) {
+			return []GaugeSample{{Value: value(stats())}}, nil
+		})
+	}
+	counter := func(name, help string, value func(s sql.DBStats) float64) {
+		NewCounterFunc(registry, name, help, nil, func(ctx context.Context) ([]GaugeSample, error) {
+			return []GaugeSample{{Value: value(stats())}}, nil
+		})
+	}
+
+	gauge("customer_service_db_max_open_connections", "Maximum number of open connections to the database.",
+		func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })
+	gauge("customer_service_db_open_connections", "Number of established connections, both in use and idle.",
+		func(s sql.DBStats) float64 { return float64(s.OpenConnections) })
+	gauge("customer_service_db_in_use_connections", "Number of connections currently in use.",
+		func(s sql.DBStats) float64 { return float64(s.InUse) })
+	gauge("customer_service_db_idle_connections", "Number of idle connections.",
+		func(s sql.DBStats) float64 { return float64(s.Idle) })
+	counter("customer_service_db_wait_count_total", "Total number of connections waited for.",
+		func(s sql.DBStats) float64 { return float64(s.WaitCount) })
+	counter("customer_service_db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.",
+		func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })
+	counter("customer_service_db_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) })
+	counter("customer_service_db_max_idle_time_closed_total", "Total number of connections closed due to SetConnMaxIdleTime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) })
+	counter("customer_service_db_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) })
+}
+
+// TenantCountsReader reads the per-tenant totals
+type TenantCountsReader interface {
+	CountsByTenant(ctx context.Context) ([]postgres.TenantCounts, error)
+}
+
+// RegisterTenantCounts exposes per-tenant customer and vehicle counts. The counts are
+// cached for ttl so frequent scrapes do not scan the tables every time.
+func RegisterTenantCounts(registry *Registry, reader TenantCountsReader, ttl time.Duration) {
+	cache := &tenantCountsCache{reader: reader, ttl: ttl}
+
+	gauge := func(name, help string, value func(c postgres.TenantCounts) int64) {
+		NewGaugeFunc(registry, name, help, []string{"tenant_id"}, func(ctx context.Context) ([]GaugeSample, error) {
+			counts, err := cache.get(ctx)
+			if err != nil {
+				return nil, err
+			}
+			samples := make([]GaugeSample, 0, len(counts))
+			for _, c := range counts {
+				samples = append(samples, GaugeSample{LabelValues: []string{c.TenantID}, Value: float64(value(c))})
+			}
+			return samples, nil
+		})
+	}
+
+	gauge("customer_service_customers", "Number of customers per tenant.",
+		func(c postgres.TenantCounts) int64 { return c.Customers })
+	gauge("customer_service_active_customers", "Number of active customers per tenant.",
+		func(c postgres.TenantCounts) int64 { return c.ActiveCustomers })
+	gauge("customer_service_vehicles", "Number of vehicles per tenant.",
+		func(c postgres.TenantCounts) int64 { return c.Vehicles })
+}
+
+// tenantCountsCache shares one query between the tenant gauges of a scrape
+type tenantCountsCache struct {
+	reader TenantCountsReader
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	counts    []postgres.TenantCounts
+	fetchedAt time.Time
+}
+
+// get returns the cached counts, refreshing them once the ttl has expired
+func (c *tenantCountsCache) get(ctx context.Context) ([]postgres.TenantCounts, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.fetchedAt.IsZero() && time.Since(c.fetchedAt) < c.ttl {
+		return c.counts, nil
+	}
+
+	counts, err := c.reader.CountsByTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.counts = counts
+	c.fetchedAt = time.Now()
+	return counts, nil
+}
+
+// RegisterProcessMetrics exposes the service version and uptime
+func RegisterProcessMetrics(registry *Registry, version string, startedAt time.Time) {
+	NewGaugeFunc(registry, "customer_service_build_info", "Build information of the customer service.",
+		[]string{"version"}, func(ctx context.Context) ([]GaugeSample, error) {
+			return []GaugeSample{{LabelValues: []string{version}, Value: 1}}, nil
+		})
+	NewGaugeFunc(registry, "customer_service_start_time_seconds", "Start time of the process since unix epoch in seconds.",
+		nil, func(ctx context.Context) ([]GaugeSample, error) {
+			return []GaugeSample{{Value: float64(startedAt.Unix())}}, nil
+		})
+	NewGaugeFunc(registry, "customer_service_uptime_seconds", "Time since the process started in seconds.",
+		nil, func(ctx context.Context) ([]GaugeSample, error) {
+			return []GaugeSample{{Value: time.Since(startedAt).Seconds()}}, nil
+		})
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// RPC types used in the grpc_type label
+const (
+	RPCTypeUnary        = "unary"
+	RPCTypeClientStream = "client_stream"
+	RPCTypeServerStream = "server_stream"
+	RPCTypeBidiStream   = "bidi_stream"
+)
+
+// GRPCMetrics holds the per-method gRPC server metrics
+type GRPCMetrics struct {
+	started  *CounterVec
+	handled  *CounterVec
+	duration *HistogramVec
+}
+
+// NewGRPCMetrics creates and registers the gRPC server metrics
+func NewGRPCMetrics(registry *Registry) *GRPCMetrics {
+	return &GRPCMetrics{
+		started: NewCounterVec(registry, "grpc_server_started_total",
+			"Total number of RPCs started on the server.",
+			"grpc_type", "grpc_service", "grpc_method"),
+		handled: NewCounterVec(registry, "grpc_server_handled_total",
+			"Total number of RPCs completed on the server, regardless of success or failure.",
+			"grpc_type", "grpc_service", "grpc_method", "grpc_code"),
+		duration: NewHistogramVec(registry, "grpc_server_handling_seconds",
+			"Histogram of response latency (seconds) of RPCs handled by the server.",
+			DefaultBuckets,
+			"grpc_type", "grpc_service", "grpc_method"),
+	}
+}
+
+// Started records the start of an RPC
+func (m *GRPCMetrics) Started(rpcType, fullMethod string) {
+	service, method := splitMethodName(fullMethod)
+	m.started.Inc(rpcType, service, method)
+}
+
+// Handled records the completion of an RPC with its status code and latency
+func (m *GRPCMetrics) Handled(rpcType, fullMethod string, code codes.Code, elapsed time.Duration) {
+	service, method := splitMethodName(fullMethod)
+	m.handled.Inc(rpcType, service, method, code.String())
+	m.duration.Observe(elapsed.Seconds(), rpcType, service, method)
+}
+
+// splitMethodName splits /package.Service/Method into service and method
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
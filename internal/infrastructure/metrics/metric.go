@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultBuckets are latency buckets in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// CounterVec is a monotonically increasing counter partitioned by labels
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec creates and registers a counter
+func NewCounterVec(registry *Registry, name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		series:     make(map[string]*counterSeries),
+	}
+	registry.Register(c, name)
+	return c
+}
+
+// Inc increments the counter for the label values by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increments the counter for the label values; negative values are ignored
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	checkLabels(c.name, c.labelNames, labelValues)
+
+	key := seriesKey(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{labelValues: append([]string(nil), labelValues...)}
+		c.series[key] = s
+	}
+	s.value += v
+}
+
+// Collect writes the counter family
+func (c *CounterVec) Collect(ctx context.Context, w *Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w.Header(c.name, c.help, TypeCounter)
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		w.Sample(c.name, c.labelNames, s.labelValues, s.value)
+	}
+	return nil
+}
+
+// HistogramVec samples observations into cumulative buckets partitioned by labels
+type HistogramVec struct {
+	name       string
+	help       string
+	buckets    []float64
+	labelNames []string
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // per bucket, not cumulative
+	count       uint64
+	sum         float64
+}
+
+// NewHistogramVec creates and registers a histogram with the given upper bounds
+func NewHistogramVec(registry *Registry, name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	h := &HistogramVec{
+		name:       name,
+		help:       help,
+		buckets:    sorted,
+		labelNames: labelNames,
+		series:     make(map[string]*histogramSeries),
+	}
+	registry.Register(h, name)
+	return h
+}
+
+// Observe records a value for the label values
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	checkLabels(h.name, h.labelNames, labelValues)
+
+	key := seriesKey(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = s
+	}
+
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+// Collect writes the histogram family
+func (h *HistogramVec) Collect(ctx context.Context, w *Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	bucketLabels := append(append([]string(nil), h.labelNames...), "le")
+
+	w.Header(h.name, h.help, TypeHistogram)
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			w.Sample(h.name+"_bucket", bucketLabels, append(append([]string(nil), s.labelValues...), formatFloat(upper)), float64(cumulative))
+		}
+		w.Sample(h.name+"_bucket", bucketLabels, append(append([]string(nil), s.labelValues...), "+Inf"), float64(s.count))
+		w.Sample(h.name+"_sum", h.labelNames, s.labelValues, s.sum)
+		w.Sample(h.name+"_count", h.labelNames, s.labelValues, float64(s.count))
+	}
+	return nil
+}
+
+// GaugeSample is a gauge value computed at scrape time
+type GaugeSample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// GaugeFunc is a gauge family whose samples are computed at scrape time
+type GaugeFunc struct {
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+	collect    func(ctx context.Context) ([]GaugeSample, error)
+}
+
+// NewGaugeFunc creates and registers a gauge computed by fn on every scrape
+func NewGaugeFunc(registry *Registry, name, help string, labelNames []string, fn func(ctx context.Context) ([]GaugeSample, error)) *GaugeFunc {
+	g := &GaugeFunc{name: name, help: help, metricType: TypeGauge, labelNames: labelNames, collect: fn}
+	registry.Register(g, name)
+	return g
+}
+
+// NewCounterFunc creates and registers a counter read from an external source on every
+// scrape (e.g. cumulative sql.DBStats fields)
+func NewCounterFunc(registry *Registry, name, help string, labelNames []string, fn func(ctx context.Context) ([]GaugeSample, error)) *GaugeFunc {
+	g := &GaugeFunc{name: name, help: help, metricType: TypeCounter, labelNames: labelNames, collect: fn}
+	registry.Register(g, name)
+	return g
+}
+
+// Collect writes the gauge family
+func (g *GaugeFunc) Collect(ctx context.Context, w *Writer) error {
+	samples, err := g.collect(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to collect %s: %w", g.name, err)
+	}
+
+	// Las muestras vienen de datos externos: un error deja fuera la familia sin tumbar el scrape
+	for _, s := range samples {
+		if err := labelsError(g.name, g.labelNames, s.LabelValues); err != nil {
+			return err
+		}
+	}
+
+	w.Header(g.name, g.help, g.metricType)
+	for _, s := range samples {
+		w.Sample(g.name, g.labelNames, s.LabelValues, s.Value)
+	}
+	return nil
+}
+
+// checkLabels panics on a label cardinality mismatch in Inc, Add or Observe, which is a
+// programming error in the caller (as WithLabelValues in the Prometheus client)
+func checkLabels(name string, labelNames, labelValues []string) {
+	if err := labelsError(name, labelNames, labelValues); err != nil {
+		panic(err.Error())
+	}
+}
+
+// labelsError reports a label cardinality mismatch
+func labelsError(name string, labelNames, labelValues []string) error {
+	if len(labelNames) != len(labelValues) {
+		return fmt.Errorf("metric %s expects %d label values, got %d", name, len(labelNames), len(labelValues))
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+)
+
+// Metric types of the Prometheus text exposition format
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// ContentType is the Prometheus text exposition format content type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Collector writes one or more metric families at scrape time
+type Collector interface {
+	Collect(ctx context.Context, w *Writer) error
+}
+
+// Registry holds the collectors exposed on /metrics
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+	names      map[string]bool
+	logger     *logger.Logger
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry(logger *logger.Logger) *Registry {
+	return &Registry{
+		names:  make(map[string]bool),
+		logger: logger,
+	}
+}
+
+// Register adds a collector; the family names must be unique in the registry. Collectors
+// are registered at startup, so a duplicate name panics (as MustRegister in the Prometheus
+// client) instead of exposing two families with the same name.
+func (r *Registry) Register(c Collector, names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range names {
+		if r.names[name] {
+			panic(fmt.Sprintf("metric %s is already registered", name))
+		}
+		r.names[name] = true
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+// Handler serves the metrics in the Prometheus text format. A failing collector is
+// logged and skipped so the remaining metrics are still scraped.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.RLock()
+		collectors := append([]Collector(nil), r.collectors...)
+		r.mu.RUnlock()
+
+		w.Header().Set("Content-Type", ContentType)
+		w.WriteHeader(http.StatusOK)
+
+		out := &Writer{w: bufio.NewWriter(w)}
+		for _, c := range collectors {
+			if err := c.Collect(req.Context(), out); err != nil {
+				r.logger.WithError(err).Error("failed to collect metrics")
+			}
+		}
+		out.w.Flush()
+	})
+}
+
+// Writer writes metric families in the Prometheus text format
+type Writer struct {
+	w *bufio.Writer
+}
+
+// Header writes the HELP and TYPE lines of a family
+func (w *Writer) Header(name, help, metricType string) {
+	fmt.Fprintf(w.w, "# HELP %s %s\n", name, escapeHelp(help))
+	fmt.Fprintf(w.w, "# TYPE %s %s\n", name, metricType)
+}
+
+// Sample writes a single sample line
+func (w *Writer) Sample(name string, labelNames, labelValues []string, value float64) {
+	w.w.WriteString(name)
+	if len(labelNames) > 0 {
+		w.w.WriteByte('{')
+		for i, labelName := range labelNames {
+			if i > 0 {
+				w.w.WriteByte(',')
+			}
+			w.w.WriteString(labelName)
+			w.w.WriteString(`="`)
+			w.w.WriteString(escapeLabelValue(labelValues[i]))
+			w.w.WriteByte('"')
+		}
+		w.w.WriteByte('}')
+	}
+	w.w.WriteByte(' ')
+	w.w.WriteString(formatFloat(value))
+	w.w.WriteByte('\n')
+}
+
+// escapeHelp escapes backslashes and line feeds in HELP text
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+// escapeLabelValue escapes backslashes, quotes and line feeds in label values
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// formatFloat formats a sample value
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// seriesKey joins label values into a map key
+func seriesKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+// sortedKeys returns the map keys in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+)
+
+// scrape returns the body served by the registry handler
+func scrape(t *testing.T, registry *Registry) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("unexpected Content-Type %q", got)
+	}
+	body, err := io.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+// assertGolden compares the exposition line by line so a mismatch shows the first difference
+func assertGolden(t *testing.T, got, want string) {
+	t.Helper()
+	gotLines, wantLines := strings.Split(got, "\n"), strings.Split(want, "\n")
+	for i := 0; i < len(gotLines) || i < len(wantLines); i++ {
+		var g, w string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g != w {
+			t.Fatalf("line %d:\n got: %q\nwant: %q\n\nfull output:\n%s", i+1, g, w, got)
+		}
+	}
+}
+
+func TestCounterExposition(t *testing.T) {
+	registry := NewRegistry(logger.NewWithService("test"))
+	counter := NewCounterVec(registry, "test_requests_total", "Requests with a \\ backslash\nand a line feed.", "method", "code")
+
+	counter.Inc("GetCustomer", "OK")
+	counter.Add(2, "GetCustomer", "OK")
+	counter.Inc("CreateCustomer", "InvalidArgument")
+	counter.Add(-5, "CreateCustomer", "InvalidArgument") // Se ignora: un contador no baja
+	counter.Inc(`Say "hi"`, "a\\b\nc")
+
+	// Las series se ordenan por sus valores; las etiquetas siguen el orden declarado
+	assertGolden(t, scrape(t, registry), `# HELP test_requests_total Requests with a \\ backslash\nand a line feed.
+# TYPE test_requests_total counter
+test_requests_total{method="CreateCustomer",code="InvalidArgument"} 1
+test_requests_total{method="GetCustomer",code="OK"} 3
+test_requests_total{method="Say \"hi\"",code="a\\b\nc"} 1
+`)
+}
+
+func TestHistogramExposition(t *testing.T) {
+	registry := NewRegistry(logger.NewWithService("test"))
+	histogram := NewHistogramVec(registry, "test_duration_seconds", "Request latency.", []float64{1, 0.1, 0.5}, "method")
+
+	histogram.Observe(0.05, "GetCustomer")
+	histogram.Observe(0.1, "GetCustomer") // El límite es inclusivo (le)
+	histogram.Observe(0.7, "GetCustomer")
+	histogram.Observe(3, "GetCustomer") // Solo en +Inf
+
+	assertGolden(t, scrape(t, registry), `# HELP test_duration_seconds Request latency.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{method="GetCustomer",le="0.1"} 2
+test_duration_seconds_bucket{method="GetCustomer",le="0.5"} 2
+test_duration_seconds_bucket{method="GetCustomer",le="1"} 3
+test_duration_seconds_bucket{method="GetCustomer",le="+Inf"} 4
+test_duration_seconds_sum{method="GetCustomer"} 3.85
+test_duration_seconds_count{method="GetCustomer"} 4
+`)
+}
+
+func TestHistogramWithoutLabels(t *testing.T) {
+	registry := NewRegistry(logger.NewWithService("test"))
+	histogram := NewHistogramVec(registry, "test_size_bytes", "Sizes.", []float64{10})
+	histogram.Observe(4)
+
+	assertGolden(t, scrape(t, registry), `# HELP test_size_bytes Sizes.
+# TYPE test_size_bytes histogram
+test_size_bytes_bucket{le="10"} 1
+test_size_bytes_bucket{le="+Inf"} 1
+test_size_bytes_sum 4
+test_size_bytes_count 1
+`)
+}
+
+func TestGaugeFuncExposition(t *testing.T) {
+	registry := NewRegistry(logger.NewWithService("test"))
+	NewGaugeFunc(registry, "test_up", "Whether the service is up.", nil, func(ctx context.Context) ([]GaugeSample, error) {
+		return []GaugeSample{{Value: 1}}, nil
+	})
+	NewGaugeFunc(registry, "test_failing", "Always fails.", []string{"tenant_id"}, func(ctx context.Context) ([]GaugeSample, error) {
+		return nil, errors.New("database unavailable")
+	})
+	NewGaugeFunc(registry, "test_bad_labels", "Returns samples without labels.", []string{"tenant_id"}, func(ctx context.Context) ([]GaugeSample, error) {
+		return []GaugeSample{{LabelValues: []string{"t-1"}, Value: 1}, {Value: 2}}, nil
+	})
+	NewCounterFunc(registry, "test_waits_total", "Special values.", []string{"kind"}, func(ctx context.Context) ([]GaugeSample, error) {
+		return []GaugeSample{
+			{LabelValues: []string{"inf"}, Value: math.Inf(1)},
+			{LabelValues: []string{"nan"}, Value: math.NaN()},
+			{LabelValues: []string{"large"}, Value: 1e21},
+		}, nil
+	})
+
+	// Las familias que fallan se omiten enteras y el resto se sigue exponiendo
+	assertGolden(t, scrape(t, registry), `# HELP test_up Whether the service is up.
+# TYPE test_up gauge
+test_up 1
+# HELP test_waits_total Special values.
+# TYPE test_waits_total counter
+test_waits_total{kind="inf"} +Inf
+test_waits_total{kind="nan"} NaN
+test_waits_total{kind="large"} 1e+21
+`)
+}
+
+func TestRegistryPanics(t *testing.T) {
+	expectPanic := func(t *testing.T, want string, fn func()) {
+		t.Helper()
+		defer func() {
+			r := recover()
+			if r == nil || !strings.Contains(r.(string), want) {
+				t.Fatalf("expected a panic containing %q, got %v", want, r)
+			}
+		}()
+		fn()
+	}
+
+	t.Run("duplicate registration", func(t *testing.T) {
+		registry := NewRegistry(logger.NewWithService("test"))
+		NewCounterVec(registry, "test_total", "Help.")
+		expectPanic(t, "already registered", func() {
+			NewHistogramVec(registry, "test_total", "Help.", DefaultBuckets)
+		})
+	})
+
+	t.Run("label cardinality", func(t *testing.T) {
+		registry := NewRegistry(logger.NewWithService("test"))
+		counter := NewCounterVec(registry, "test_total", "Help.", "method")
+		expectPanic(t, "expects 1 label values, got 2", func() {
+			counter.Inc("GetCustomer", "extra")
+		})
+	})
+}
+
+func TestSplitMethodName(t *testing.T) {
+	service, method := splitMethodName("/customer.v1.CustomerService/GetCustomer")
+	if service != "customer.v1.CustomerService" || method != "GetCustomer" {
+		t.Errorf("unexpected split %s %s", service, method)
+	}
+	if service, _ := splitMethodName("Malformed"); service != "unknown" {
+		t.Errorf("expected unknown service, got %s", service)
+	}
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/encomos/api-encomos/customer-service/internal/identity"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/auth"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+	"github.com/encomos/api-encomos/customer-service/internal/tenancy"
+)
+
+// publicMethodPrefixes are the methods that do not require a token
+var publicMethodPrefixes = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+// AuthInterceptor verifies the bearer JWT and adds the staff identity to context.
+// It must run after TenantInterceptor so the token tenant can be checked against x-tenant-id.
+func AuthInterceptor(verifier *auth.Verifier, logger *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if isPublicMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, verifier, logger, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor verifies the bearer JWT for stream requests
+func StreamAuthInterceptor(verifier *auth.Verifier, logger *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isPublicMethod(info.FullMethod) {
+			return handler(srv, stream)
+		}
+
+		ctx, err := authenticate(stream.Context(), verifier, logger, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &wrappedServerStream{
+			ServerStream: stream,
+			ctx:          ctx,
+		})
+	}
+}
+
+// authenticate verifies the token from metadata and returns the context with the staff identity
+func authenticate(ctx context.Context, verifier *auth.Verifier, logger *logger.Logger, method string) (context.Context, error) {
+	token, err := bearerToken(ctx)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"method": method,
+		}).Warn("missing bearer token")
+		return nil, err
+	}
+
+	claims, err := verifier.Verify(token)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"method": method,
+		}).WithError(err).Warn("invalid bearer token")
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+
+	// El tenant del token debe coincidir con el x-tenant-id de la petición
+	tenantID, _ := tenancy.IDFromContext(ctx)
+	if claims.TenantID == "" || !strings.EqualFold(claims.TenantID, tenantID) {
+		logger.WithFields(map[string]interface{}{
+			"method":       method,
+			"staff_id":     claims.Subject,
+			"tenant_id":    tenantID,
+			"token_tenant": claims.TenantID,
+		}).Warn("token tenant does not match x-tenant-id")
+		return nil, status.Errorf(codes.PermissionDenied, "token is not valid for this tenant")
+	}
+
+	return identity.WithStaff(ctx, &identity.Staff{
+		ID:       claims.Subject,
+		Name:     claims.Name,
+		TenantID: claims.TenantID,
+		Roles:    claims.Roles,
+	}), nil
+}
+
+// bearerToken extracts the token from the authorization metadata
+func bearerToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Errorf(codes.Unauthenticated, "authorization token is required")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", status.Errorf(codes.Unauthenticated, "authorization token is required")
+	}
+
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || strings.TrimSpace(token) == "" {
+		return "", status.Errorf(codes.Unauthenticated, "authorization must use the Bearer scheme")
+	}
+
+	return strings.TrimSpace(token), nil
+}
+
+// isPublicMethod checks if the method is exempt from authentication
+func isPublicMethod(fullMethod string) bool {
+	for _, prefix := range publicMethodPrefixes {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/encomos/api-encomos/customer-service/internal/config"
+	"github.com/encomos/api-encomos/customer-service/internal/identity"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/auth"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+	"github.com/encomos/api-encomos/customer-service/internal/tenancy"
+)
+
+const (
+	testAuthSecret = "test-hmac-secret"
+	testTenantID   = "6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c10"
+	otherTenantID  = "0b7d4f1e-2c3a-4e5f-8a9b-1c2d3e4f5a6b"
+)
+
+// testToken returns an HS256 token signed with testAuthSecret
+func testToken(t *testing.T, claims map[string]interface{}) string {
+	t.Helper()
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	input := encode(map[string]string{"alg": auth.AlgHS256, "typ": "JWT"}) + "." + encode(claims)
+	mac := hmac.New(sha256.New, []byte(testAuthSecret))
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// staffClaims returns valid claims for the staff member id of tenantID
+func staffClaims(id, tenantID string) map[string]interface{} {
+	return map[string]interface{}{
+		"sub":       id,
+		"tenant_id": tenantID,
+		"roles":     []string{"staff"},
+		"exp":       time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// authContext returns an incoming context of testTenantID with the authorization metadata
+func authContext(t *testing.T, authorization string) context.Context {
+	t.Helper()
+	tenant, err := tenancy.New(testTenantID, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := metadata.Pairs("x-tenant-id", testTenantID)
+	if authorization != "" {
+		md.Set("authorization", authorization)
+	}
+	return tenancy.WithTenant(metadata.NewIncomingContext(context.Background(), md), tenant)
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	verifier, err := auth.NewVerifier(&config.AuthConfig{Enabled: true, JWTSecret: testAuthSecret})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	interceptor := AuthInterceptor(verifier, logger.NewWithService("test"))
+
+	precedence := staffClaims("user-1", testTenantID)
+	precedence["staff_id"] = "staff-7"
+	expired := staffClaims("staff-1", testTenantID)
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		wantCode      codes.Code
+		wantStaff     string
+	}{
+		{"valid token", testGetCustomer, "Bearer " + testToken(t, staffClaims("staff-1", testTenantID)), codes.OK, "staff-1"},
+		{"staff_id takes precedence over sub", testGetCustomer, "Bearer " + testToken(t, precedence), codes.OK, "staff-7"},
+		{"missing token", testGetCustomer, "", codes.Unauthenticated, ""},
+		{"other scheme", testGetCustomer, "Basic dXNlcjpwYXNz", codes.Unauthenticated, ""},
+		{"invalid token", testGetCustomer, "Bearer not-a-token", codes.Unauthenticated, ""},
+		{"expired token", testGetCustomer, "Bearer " + testToken(t, expired), codes.Unauthenticated, ""},
+		{"token of another tenant", testGetCustomer, "Bearer " + testToken(t, staffClaims("staff-1", otherTenantID)), codes.PermissionDenied, ""},
+		{"token without tenant", testGetCustomer, "Bearer " + testToken(t, staffClaims("staff-1", "")), codes.PermissionDenied, ""},
+		{"public method without token", "/grpc.health.v1.Health/Check", "", codes.OK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var staff *identity.Staff
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				staff, _ = identity.StaffFromContext(ctx)
+				return "ok", nil
+			}
+
+			_, err := interceptor(authContext(t, tt.authorization), nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("expected %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantStaff == "" {
+				return
+			}
+			if staff == nil || staff.ID != tt.wantStaff || staff.TenantID != testTenantID {
+				t.Errorf("expected staff %q of the tenant in context, got %+v", tt.wantStaff, staff)
+			}
+		})
+	}
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	verifier, err := auth.NewVerifier(&config.AuthConfig{Enabled: true, JWTSecret: testAuthSecret})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	interceptor := StreamAuthInterceptor(verifier, logger.NewWithService("test"))
+
+	var staff *identity.Staff
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		staff, _ = identity.StaffFromContext(stream.Context())
+		return nil
+	}
+
+	ctx := authContext(t, "Bearer "+testToken(t, staffClaims("staff-1", testTenantID)))
+	if err := interceptor(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: testExport}, handler); err != nil {
+		t.Fatalf("expected the stream to be authenticated, got %v", err)
+	}
+	if staff == nil || staff.ID != "staff-1" {
+		t.Errorf("expected the staff identity in the stream context, got %+v", staff)
+	}
+
+	ctx = authContext(t, "Bearer "+testToken(t, staffClaims("staff-1", otherTenantID)))
+	err = interceptor(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: testExport}, handler)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+}
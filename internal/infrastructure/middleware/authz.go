@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/encomos/api-encomos/customer-service/internal/identity"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/authz"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+)
+
+// AuthorizationInterceptor enforces the role policy for each method.
+// Roles come from the token identity when present; otherwise from the
+// gateway-supplied x-staff-id, x-staff-name and x-staff-roles metadata.
+func AuthorizationInterceptor(policy *authz.Policy, logger *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if isPublicMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authorize(ctx, policy, logger, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthorizationInterceptor enforces the role policy for stream requests
+func StreamAuthorizationInterceptor(policy *authz.Policy, logger *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isPublicMethod(info.FullMethod) {
+			return handler(srv, stream)
+		}
+
+		ctx, err := authorize(stream.Context(), policy, logger, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &wrappedServerStream{
+			ServerStream: stream,
+			ctx:          ctx,
+		})
+	}
+}
+
+// IdentityInterceptor adds the gateway-supplied staff identity to the context when no
+// token identity is present. It replaces AuthorizationInterceptor when authorization is
+// disabled, so audit entries still record who performed a change.
+func IdentityInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withMetadataStaff(ctx), req)
+	}
+}
+
+// StreamIdentityInterceptor adds the gateway-supplied staff identity to stream requests
+func StreamIdentityInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &wrappedServerStream{
+			ServerStream: stream,
+			ctx:          withMetadataStaff(stream.Context()),
+		})
+	}
+}
+
+// withMetadataStaff adds the staff from metadata unless the context already has one
+func withMetadataStaff(ctx context.Context) context.Context {
+	if _, ok := identity.StaffFromContext(ctx); ok {
+		return ctx
+	}
+	if staff := staffFromMetadata(ctx); staff != nil {
+		return identity.WithStaff(ctx, staff)
+	}
+	return ctx
+}
+
+// authorize resolves the caller identity and checks it against the policy. A denial is an
+// *authz.PermissionError, which gRPC sends as PermissionDenied with an ErrorInfo.
+func authorize(ctx context.Context, policy *authz.Policy, logger *logger.Logger, method string) (context.Context, error) {
+	ctx = withMetadataStaff(ctx)
+	staff, _ := identity.StaffFromContext(ctx)
+
+	var roles []string
+	if staff != nil {
+		roles = staff.Roles
+	}
+
+	if err := policy.AuthorizeMethod(method, roles); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"method": method,
+			"roles":  roles,
+		}).Warn("permission denied")
+		return nil, err
+	}
+
+	return ctx, nil
+}
+
+// staffFromMetadata builds the staff identity forwarded by the API Gateway
+func staffFromMetadata(ctx context.Context) *identity.Staff {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	staff := &identity.Staff{
+		ID:    firstMetadataValue(md, "x-staff-id"),
+		Name:  firstMetadataValue(md, "x-staff-name"),
+		Roles: parseRoles(md.Get("x-staff-roles")),
+	}
+	if staff.ID == "" && len(staff.Roles) == 0 {
+		return nil
+	}
+
+	return staff
+}
+
+// parseRoles splits comma separated role lists
+func parseRoles(values []string) []string {
+	var roles []string
+	for _, value := range values {
+		for _, role := range strings.Split(value, ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
+
+// firstMetadataValue returns the first value of a metadata key
+func firstMetadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
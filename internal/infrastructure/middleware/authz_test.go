@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/encomos/api-encomos/customer-service/internal/identity"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/authz"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+)
+
+const (
+	testGetCustomer    = "/customer.v1.CustomerService/GetCustomer"
+	testDeleteCustomer = "/customer.v1.CustomerService/DeleteCustomer"
+	testExport         = "/customer.v1.CustomerService/ExportCustomers"
+)
+
+func testAuthzPolicy(t *testing.T) *authz.Policy {
+	t.Helper()
+	policy, err := authz.NewPolicy(map[string][]string{
+		testGetCustomer:    {"admin", "staff"},
+		testDeleteCustomer: {"admin"},
+		testExport:         {"admin"},
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+	return policy
+}
+
+// staffContext returns an incoming context with the API Gateway staff metadata
+func staffContext(id, roles string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-staff-id", id, "x-staff-roles", roles))
+}
+
+func TestAuthorizationInterceptorDenies(t *testing.T) {
+	interceptor := AuthorizationInterceptor(testAuthzPolicy(t), logger.NewWithService("test"))
+
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return "ok", nil
+	}
+
+	_, err := interceptor(staffContext("s-1", "staff, manager"), nil, &grpc.UnaryServerInfo{FullMethod: testDeleteCustomer}, handler)
+	if called {
+		t.Fatal("the handler must not run when the call is denied")
+	}
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+
+	var info *errdetails.ErrorInfo
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.ErrorInfo); ok {
+			info = d
+		}
+	}
+	if info == nil {
+		t.Fatalf("expected an ErrorInfo detail in %v", st.Details())
+	}
+	if info.Reason != authz.PermissionDeniedReason {
+		t.Errorf("expected reason %s, got %s", authz.PermissionDeniedReason, info.Reason)
+	}
+	if info.Metadata["resource"] != "DeleteCustomer" || info.Metadata["required_roles"] != "admin" || info.Metadata["caller_roles"] != "staff,manager" {
+		t.Errorf("unexpected metadata %v", info.Metadata)
+	}
+}
+
+func TestAuthorizationInterceptorAllows(t *testing.T) {
+	interceptor := AuthorizationInterceptor(testAuthzPolicy(t), logger.NewWithService("test"))
+
+	var staff *identity.Staff
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		staff, _ = identity.StaffFromContext(ctx)
+		return "ok", nil
+	}
+
+	if _, err := interceptor(staffContext("s-1", "staff"), nil, &grpc.UnaryServerInfo{FullMethod: testGetCustomer}, handler); err != nil {
+		t.Fatalf("expected the call to be allowed, got %v", err)
+	}
+	if staff == nil || staff.ID != "s-1" {
+		t.Fatalf("expected the gateway staff in the handler context, got %+v", staff)
+	}
+}
+
+func TestAuthorizationInterceptorPrefersTokenIdentity(t *testing.T) {
+	interceptor := AuthorizationInterceptor(testAuthzPolicy(t), logger.NewWithService("test"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	// Los roles del token prevalecen sobre los de la metadata del gateway
+	ctx := identity.WithStaff(staffContext("s-1", "admin"), &identity.Staff{ID: "s-2", Roles: []string{"staff"}})
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testDeleteCustomer}, handler)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied with the token roles, got %v", err)
+	}
+}
+
+func TestAuthorizationInterceptorSkipsPublicMethods(t *testing.T) {
+	interceptor := AuthorizationInterceptor(testAuthzPolicy(t), logger.NewWithService("test"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler); err != nil {
+		t.Fatalf("health checks need no roles, got %v", err)
+	}
+}
+
+// testServerStream is a grpc.ServerStream carrying only a context
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context { return s.ctx }
+
+func TestStreamAuthorizationInterceptor(t *testing.T) {
+	interceptor := StreamAuthorizationInterceptor(testAuthzPolicy(t), logger.NewWithService("test"))
+	info := &grpc.StreamServerInfo{FullMethod: testExport, IsServerStream: true}
+
+	called := false
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		called = true
+		return nil
+	}
+
+	err := interceptor(nil, &testServerStream{ctx: staffContext("s-1", "staff")}, info, handler)
+	if status.Code(err) != codes.PermissionDenied || called {
+		t.Fatalf("expected PermissionDenied without running the handler, got %v", err)
+	}
+
+	if err := interceptor(nil, &testServerStream{ctx: staffContext("s-1", "admin")}, info, handler); err != nil || !called {
+		t.Fatalf("expected the stream to be allowed, got %v", err)
+	}
+}
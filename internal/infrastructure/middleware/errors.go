@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorInterceptor translates domain errors returned by the handlers into gRPC statuses
+func ErrorInterceptor(logger *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, statusError(logger, info.FullMethod, err)
+		}
+		return resp, nil
+	}
+}
+
+// StreamErrorInterceptor translates domain errors returned by stream handlers into gRPC statuses
+func StreamErrorInterceptor(logger *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, stream); err != nil {
+			return statusError(logger, info.FullMethod, err)
+		}
+		return nil
+	}
+}
+
+// statusError converts err with StatusFromError and logs the cause of internal errors,
+// whose message is not sent to the client
+func statusError(logger *logger.Logger, method string, err error) error {
+	st := StatusFromError(err)
+	if st.Code() == codes.Internal {
+		if _, ok := status.FromError(err); !ok {
+			logger.WithFields(map[string]interface{}{
+				"method": method,
+			}).WithError(err).Error("Internal error in gRPC handler")
+		}
+	}
+	return st.Err()
+}
+
+// StatusFromError maps an error to a gRPC status:
+//   - errors that already carry a status are returned unchanged
+//   - model.ValidationError, model.ValidationErrors and VehicleValidationReport → InvalidArgument with BadRequest field violations
+//   - model.NotFoundError → NotFound with ResourceInfo
+//   - model.ConflictError → AlreadyExists with ResourceInfo and the conflicting field as a BadRequest violation
+//   - model.DuplicateCustomerError → AlreadyExists with a ResourceInfo per likely duplicate
+//   - model.VersionConflictError → FailedPrecondition (stale expected_version) or Aborted (concurrent writes) with PreconditionFailure
+//   - context cancellation and deadlines → Canceled and DeadlineExceeded
+//   - anything else → Internal with a generic message
+func StatusFromError(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	var report *model.VehicleValidationReport
+	if errors.As(err, &report) {
+		code := codes.InvalidArgument
+		if report.OnlyDuplicates() {
+			code = codes.AlreadyExists
+		}
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(report.Issues))
+		for _, issue := range report.Issues {
+			violations = append(violations, fieldViolation(issue.FieldPath(), issue.Message))
+		}
+		return withDetails(status.New(code, report.Error()), &errdetails.BadRequest{FieldViolations: violations})
+	}
+
+	var validationErrs model.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(validationErrs))
+		for _, validationErr := range validationErrs {
+			violations = append(violations, fieldViolation(validationErr.Field, validationErr.Message))
+		}
+		return withDetails(status.New(codes.InvalidArgument, validationErrs.Error()), &errdetails.BadRequest{FieldViolations: violations})
+	}
+
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		return withDetails(status.New(codes.InvalidArgument, validationErr.Error()), &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{fieldViolation(validationErr.Field, validationErr.Message)},
+		})
+	}
+
+	var notFoundErr *model.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return withDetails(status.New(codes.NotFound, notFoundErr.Error()), &errdetails.ResourceInfo{
+			ResourceType: notFoundErr.Resource,
+			ResourceName: notFoundErr.Value,
+			Description:  notFoundErr.Error(),
+		})
+	}
+
+	var conflictErr *model.ConflictError
+	if errors.As(err, &conflictErr) {
+		return withDetails(status.New(codes.AlreadyExists, conflictErr.Error()),
+			&errdetails.ResourceInfo{
+				ResourceType: conflictErr.Resource,
+				ResourceName: conflictErr.Value,
+				Description:  conflictErr.Error(),
+			},
+			&errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{fieldViolation(conflictErr.Field, conflictErr.Message)},
+			},
+		)
+	}
+
+	var duplicateErr *model.DuplicateCustomerError
+	if errors.As(err, &duplicateErr) {
+		details := make([]protoadapt.MessageV1, 0, len(duplicateErr.Candidates))
+		for _, candidate := range duplicateErr.Candidates {
+			reasons := make([]string, len(candidate.Reasons))
+			for i, reason := range candidate.Reasons {
+				reasons[i] = reason.Description
+			}
+			details = append(details, &errdetails.ResourceInfo{
+				ResourceType: "customer",
+				ResourceName: candidate.Customer.ID,
+				Description:  fmt.Sprintf("posible duplicado (puntuación %.2f): %s", candidate.Score, strings.Join(reasons, ", ")),
+			})
+		}
+		return withDetails(status.New(codes.AlreadyExists, duplicateErr.Error()), details...)
+	}
+
+	var versionErr *model.VersionConflictError
+	if errors.As(err, &versionErr) {
+		code := codes.FailedPrecondition
+		description := "la versión enviada no es la actual; vuelva a leer el recurso"
+		if versionErr.Concurrent {
+			code = codes.Aborted
+			description = "el recurso se modificó simultáneamente; reintente la operación"
+		}
+		return withDetails(status.New(code, versionErr.Error()),
+			&errdetails.PreconditionFailure{
+				Violations: []*errdetails.PreconditionFailure_Violation{{
+					Type:        "VERSION",
+					Subject:     versionErr.Resource + "/" + versionErr.ID,
+					Description: description,
+				}},
+			},
+			&errdetails.ResourceInfo{
+				ResourceType: versionErr.Resource,
+				ResourceName: versionErr.ID,
+				Description:  versionErr.Error(),
+			},
+		)
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, "deadline exceeded")
+	}
+
+	return status.New(codes.Internal, "internal server error")
+}
+
+// fieldViolation builds a BadRequest field violation
+func fieldViolation(field, description string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{Field: field, Description: description}
+}
+
+// withDetails attaches details to st; if they cannot be encoded the bare status is kept
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) *status.Status {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return detailed
+}
@@ -2,12 +2,12 @@ package middleware
 
 import (
 	"context"
-	"fmt"
 	"runtime/debug"
 	"time"
 
 	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/logger"
-	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/persistence/postgres"
+	"github.com/encomos/api-encomos/customer-service/internal/requestid"
+	"github.com/encomos/api-encomos/customer-service/internal/tenancy"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -20,18 +20,24 @@ func LoggingInterceptor(logger *logger.Logger) grpc.UnaryServerInterceptor {
 		start := time.Now()
 
 		logger.WithFields(map[string]interface{}{
-			"method": info.FullMethod,
-			"type":   "unary",
+			"method":     info.FullMethod,
+			"type":       "unary",
+			"request_id": requestid.FromContext(ctx),
 		}).Info("gRPC request started")
 
 		resp, err := handler(ctx, req)
 
 		duration := time.Since(start)
-		logEntry := logger.WithFields(map[string]interface{}{
-			"method":   info.FullMethod,
-			"duration": duration.String(),
-			"type":     "unary",
-		})
+		fields := map[string]interface{}{
+			"method":     info.FullMethod,
+			"duration":   duration.String(),
+			"type":       "unary",
+			"request_id": requestid.FromContext(ctx),
+		}
+		if tenant, ok := tenancy.FromContext(ctx); ok {
+			fields["tenant_id"] = tenant.ID
+		}
+		logEntry := logger.WithFields(fields)
 
 		if err != nil {
 			logEntry.WithError(err).Error("gRPC request failed")
@@ -68,18 +74,24 @@ func StreamLoggingInterceptor(logger *logger.Logger) grpc.StreamServerIntercepto
 		start := time.Now()
 
 		logger.WithFields(map[string]interface{}{
-			"method": info.FullMethod,
-			"type":   "stream",
+			"method":     info.FullMethod,
+			"type":       "stream",
+			"request_id": requestid.FromContext(stream.Context()),
 		}).Info("gRPC stream started")
 
 		err := handler(srv, stream)
 
 		duration := time.Since(start)
-		logEntry := logger.WithFields(map[string]interface{}{
-			"method":   info.FullMethod,
-			"duration": duration.String(),
-			"type":     "stream",
-		})
+		fields := map[string]interface{}{
+			"method":     info.FullMethod,
+			"duration":   duration.String(),
+			"type":       "stream",
+			"request_id": requestid.FromContext(stream.Context()),
+		}
+		if tenant, ok := tenancy.FromContext(stream.Context()); ok {
+			fields["tenant_id"] = tenant.ID
+		}
+		logEntry := logger.WithFields(fields)
 
 		if err != nil {
 			logEntry.WithError(err).Error("gRPC stream failed")
@@ -110,113 +122,89 @@ func StreamRecoveryInterceptor(logger *logger.Logger) grpc.StreamServerIntercept
 	}
 }
 
-// TenantInterceptor extracts tenant_id from metadata and adds it to context
+// TenantInterceptor resolves the request tenant from metadata and adds it to the context
 func TenantInterceptor(logger *logger.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// Extract metadata from context
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			logger.WithFields(map[string]interface{}{
-				"method": info.FullMethod,
-			}).Error("no metadata found in request")
-			return nil, status.Errorf(codes.InvalidArgument, "tenant_id is required")
-		}
-
-		// DEBUG: Log all metadata
-		logger.WithFields(map[string]interface{}{
-			"method":   info.FullMethod,
-			"metadata": md,
-		}).Info("DEBUG: All incoming metadata")
-
-		// Get tenant_id from metadata (sent by API Gateway)
-		tenantIDValues := md.Get("x-tenant-id")
-		if len(tenantIDValues) == 0 {
-			logger.WithFields(map[string]interface{}{
-				"method":   info.FullMethod,
-				"metadata": md,
-			}).Error("x-tenant-id not found in metadata")
-			return nil, status.Errorf(codes.InvalidArgument, "tenant_id is required")
+		// Health checks and reflection are not tenant-scoped
+		if isPublicMethod(info.FullMethod) {
+			return handler(ctx, req)
 		}
 
-		tenantID := tenantIDValues[0]
-		if tenantID == "" {
-			logger.WithFields(map[string]interface{}{
-				"method": info.FullMethod,
-			}).Error("x-tenant-id is empty")
-			return nil, status.Errorf(codes.InvalidArgument, "tenant_id is required")
+		tenant, err := tenantFromMetadata(ctx, logger, info.FullMethod)
+		if err != nil {
+			return nil, err
 		}
 
-		// Add tenant_id to context using the correct postgres helper function
-		ctx = postgres.WithTenantID(ctx, tenantID)
-
-		logger.WithFields(map[string]interface{}{
-			"method":    info.FullMethod,
-			"tenant_id": tenantID,
-		}).Info("DEBUG TENANT INTERCEPTOR: tenant_id extracted and added to context")
-
-		// Verify it was added correctly
-		verifyValue, verifyOK := postgres.GetTenantID(ctx)
-		logger.WithFields(map[string]interface{}{
-			"method":       info.FullMethod,
-			"verify_value": verifyValue,
-			"verify_ok":    verifyOK,
-			"verify_type":  fmt.Sprintf("%T", verifyValue),
-		}).Info("DEBUG TENANT INTERCEPTOR: Verifying context value")
-
-		return handler(ctx, req)
+		return handler(tenancy.WithTenant(ctx, tenant), req)
 	}
 }
 
-// StreamTenantInterceptor extracts tenant_id from metadata for stream requests
+// StreamTenantInterceptor resolves the request tenant for stream requests
 func StreamTenantInterceptor(logger *logger.Logger) grpc.StreamServerInterceptor {
 	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		ctx := stream.Context()
-
-		// Extract metadata from context
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			logger.WithFields(map[string]interface{}{
-				"method": info.FullMethod,
-			}).Error("no metadata found in stream request")
-			return status.Errorf(codes.InvalidArgument, "tenant_id is required")
+		// Health checks and reflection are not tenant-scoped
+		if isPublicMethod(info.FullMethod) {
+			return handler(srv, stream)
 		}
 
-
-		// Get tenant_id from metadata
-		tenantIDValues := md.Get("x-tenant-id")
-		if len(tenantIDValues) == 0 {
-			logger.WithFields(map[string]interface{}{
-				"method": info.FullMethod,
-			}).Error("x-tenant-id not found in metadata")
-			return status.Errorf(codes.InvalidArgument, "tenant_id is required")
-		}
-
-		tenantID := tenantIDValues[0]
-		if tenantID == "" {
-			logger.WithFields(map[string]interface{}{
-				"method": info.FullMethod,
-			}).Error("x-tenant-id is empty")
-			return status.Errorf(codes.InvalidArgument, "tenant_id is required")
+		tenant, err := tenantFromMetadata(stream.Context(), logger, info.FullMethod)
+		if err != nil {
+			return err
 		}
 
-		// Add tenant_id to context using the correct postgres helper function
-		ctx = postgres.WithTenantID(ctx, tenantID)
-
-		logger.WithFields(map[string]interface{}{
-			"method":    info.FullMethod,
-			"tenant_id": tenantID,
-		}).Debug("tenant_id extracted from metadata for stream")
-
 		// Wrap the stream with the new context
 		wrapped := &wrappedServerStream{
 			ServerStream: stream,
-			ctx:          ctx,
+			ctx:          tenancy.WithTenant(stream.Context(), tenant),
 		}
 
 		return handler(srv, wrapped)
 	}
 }
 
+// tenantFromMetadata builds the tenant from the metadata sent by the API Gateway:
+// x-tenant-id (required UUID), x-tenant-license, x-tenant-locale and x-tenant-timezone
+func tenantFromMetadata(ctx context.Context, logger *logger.Logger, method string) (*tenancy.Tenant, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		logger.WithFields(map[string]interface{}{
+			"method": method,
+		}).Error("no metadata found in request")
+		return nil, status.Errorf(codes.InvalidArgument, "tenant_id is required")
+	}
+
+	tenantID := firstMetadataValue(md, "x-tenant-id")
+	if tenantID == "" {
+		logger.WithFields(map[string]interface{}{
+			"method": method,
+		}).Error("x-tenant-id not found in metadata")
+		return nil, status.Errorf(codes.InvalidArgument, "tenant_id is required")
+	}
+
+	if err := tenancy.ValidateID(tenantID); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"method": method,
+		}).Error("x-tenant-id is not a valid UUID")
+		return nil, status.Errorf(codes.InvalidArgument, "tenant_id must be a valid UUID")
+	}
+
+	tenant, err := tenancy.New(
+		tenantID,
+		firstMetadataValue(md, "x-tenant-license"),
+		firstMetadataValue(md, "x-tenant-locale"),
+		firstMetadataValue(md, "x-tenant-timezone"),
+	)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"method":    method,
+			"tenant_id": tenantID,
+		}).WithError(err).Error("invalid tenant metadata")
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	return tenant, nil
+}
+
 // wrappedServerStream wraps a grpc.ServerStream with a custom context
 type wrappedServerStream struct {
 	grpc.ServerStream
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/encomos/api-encomos/customer-service/internal/infrastructure/metrics"
+)
+
+// MetricsInterceptor records request counts, status codes and latency per method.
+// It runs first in the chain so rejected requests (tenant, auth) are counted too.
+func MetricsInterceptor(m *metrics.GRPCMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		m.Started(metrics.RPCTypeUnary, info.FullMethod)
+
+		resp, err := handler(ctx, req)
+
+		m.Handled(metrics.RPCTypeUnary, info.FullMethod, status.Code(err), time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamMetricsInterceptor records request counts, status codes and latency for streams
+func StreamMetricsInterceptor(m *metrics.GRPCMetrics) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		rpcType := streamRPCType(info)
+		start := time.Now()
+		m.Started(rpcType, info.FullMethod)
+
+		err := handler(srv, stream)
+
+		m.Handled(rpcType, info.FullMethod, status.Code(err), time.Since(start))
+		return err
+	}
+}
+
+// streamRPCType returns the grpc_type label of a stream
+func streamRPCType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return metrics.RPCTypeBidiStream
+	case info.IsClientStream:
+		return metrics.RPCTypeClientStream
+	default:
+		return metrics.RPCTypeServerStream
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/encomos/api-encomos/customer-service/internal/requestid"
+)
+
+// RequestIDInterceptor adds the x-request-id sent by the caller to the context, generating
+// one when it is missing or invalid, and returns it in the response headers
+func RequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id := requestIDFromMetadata(ctx)
+		// Las llamadas del gateway REST no tienen transporte gRPC; el gateway devuelve el encabezado
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.Header, id))
+
+		return handler(requestid.WithRequestID(ctx, id), req)
+	}
+}
+
+// StreamRequestIDInterceptor adds the request ID to the context of stream requests
+func StreamRequestIDInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := requestIDFromMetadata(stream.Context())
+		_ = stream.SetHeader(metadata.Pairs(requestid.Header, id))
+
+		return handler(srv, &wrappedServerStream{
+			ServerStream: stream,
+			ctx:          requestid.WithRequestID(stream.Context(), id),
+		})
+	}
+}
+
+// requestIDFromMetadata returns the caller's request ID or a new one
+func requestIDFromMetadata(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if id := firstMetadataValue(md, requestid.Header); requestid.Valid(id) {
+			return id
+		}
+	}
+	return requestid.New()
+}
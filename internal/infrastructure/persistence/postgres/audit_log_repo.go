@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+	"github.com/lib/pq"
+)
+
+type auditLogRepository struct {
+	db *DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *DB) repository.AuditLogRepository {
+	return &auditLogRepository{
+		db: db,
+	}
+}
+
+// Create records an audit entry. Called with the context of Transactor.WithinTransaction,
+// the entry is committed or discarded together with the change it describes.
+func (r *auditLogRepository) Create(ctx context.Context, entry *model.AuditEntry) error {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit changes: %w", err)
+	}
+
+	query := `
+		INSERT INTO customer_audit_log (
+			tenant_id, customer_id, resource_type, resource_id, action,
+			actor_id, actor_name, request_id, changes
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		) RETURNING id, created_at`
+
+	err = r.db.QueryRowWithTenant(ctx, tenantID, query,
+		tenantID,
+		entry.CustomerID,
+		entry.ResourceType,
+		entry.ResourceID,
+		entry.Action,
+		nullIfEmpty(entry.ActorID),
+		nullIfEmpty(entry.ActorName),
+		nullIfEmpty(entry.RequestID),
+		changes,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+
+	entry.TenantID = tenantID
+	return nil
+}
+
+// List retrieves audit entries, newest first
+func (r *auditLogRepository) List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, int, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Build WHERE clause
+	whereConditions := []string{"al.tenant_id = $1"}
+	args := []interface{}{tenantID}
+	argCount := 1
+
+	if filter.CustomerID != "" {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("al.customer_id = $%d", argCount))
+		args = append(args, filter.CustomerID)
+	}
+
+	if filter.ActorID != "" {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("al.actor_id = $%d", argCount))
+		args = append(args, filter.ActorID)
+	}
+
+	if filter.Action != "" {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("al.action = $%d", argCount))
+		args = append(args, filter.Action)
+	}
+
+	if filter.DateFrom != nil {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("al.created_at >= $%d", argCount))
+		args = append(args, *filter.DateFrom)
+	}
+
+	if filter.DateTo != nil {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("al.created_at <= $%d", argCount))
+		args = append(args, *filter.DateTo)
+	}
+
+	whereClause := "WHERE " + strings.Join(whereConditions, " AND ")
+
+	// Count total records
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM customer_audit_log al %s", whereClause)
+
+	var total int
+	err = r.db.QueryRowWithTenant(ctx, tenantID, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	// Build pagination
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50 // Default limit
+	}
+	offset := 0
+	if filter.Page > 0 {
+		offset = (filter.Page - 1) * limit
+	}
+
+	// Main query
+	query := fmt.Sprintf(`
+		SELECT al.id, al.tenant_id, al.customer_id, al.resource_type, al.resource_id,
+			   al.action, al.actor_id, al.actor_name, al.request_id, al.changes, al.created_at
+		FROM customer_audit_log al
+		%s
+		ORDER BY al.created_at DESC, al.id DESC
+		LIMIT %d OFFSET %d`, whereClause, limit, offset)
+
+	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*model.AuditEntry
+	for rows.Next() {
+		entry := &model.AuditEntry{}
+		var actorID, actorName, requestID sql.NullString
+		var changes []byte
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.TenantID,
+			&entry.CustomerID,
+			&entry.ResourceType,
+			&entry.ResourceID,
+			&entry.Action,
+			&actorID,
+			&actorName,
+			&requestID,
+			&changes,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+
+		entry.ActorID = actorID.String
+		entry.ActorName = actorName.String
+		entry.RequestID = requestID.String
+
+		if len(changes) > 0 {
+			if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+				return nil, 0, fmt.Errorf("failed to unmarshal audit changes: %w", err)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate over audit entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// RedactCustomer replaces with text the non-null before and after values of fields in the
+// entries of a customer and of the duplicates merged into it, keeping the entries, and
+// returns how many entries changed
+func (r *auditLogRepository) RedactCustomer(ctx context.Context, customerID string, fields []string, text string) (int64, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Cada cambio es {"before": ..., "after": ...}; los null se conservan. Las redirecciones
+	// de customer_merges apuntan siempre al superviviente final, así que basta un nivel.
+	query := `
+		UPDATE customer_audit_log SET changes = (
+			SELECT jsonb_object_agg(field, CASE WHEN field = ANY($3) THEN (
+				SELECT jsonb_object_agg(side, CASE WHEN value = 'null'::jsonb THEN value ELSE to_jsonb($4::text) END)
+				FROM jsonb_each(change) AS sides(side, value)
+			) ELSE change END)
+			FROM jsonb_each(changes) AS fields(field, change)
+		)
+		WHERE tenant_id = $1
+		  AND (customer_id = $2 OR customer_id IN (
+			  SELECT merged_id FROM customer_merges WHERE tenant_id = $1 AND survivor_id = $2
+		  ))
+		  AND changes ?| $3`
+
+	result, err := r.db.ExecWithTenant(ctx, tenantID, query, tenantID, customerID, pq.Array(fields), text)
+	if err != nil {
+		return 0, fmt.Errorf("failed to redact audit entries: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+)
+
+type customerEventRepository struct {
+	db *DB
+}
+
+// NewCustomerEventRepository creates a new customer event repository
+func NewCustomerEventRepository(db *DB) repository.CustomerEventRepository {
+	return &customerEventRepository{
+		db: db,
+	}
+}
+
+// Ingest records a batch of events in a single tenant transaction. Each event is
+// registered in customer_events first; events whose ID was already processed are
+// counted as duplicates and skipped, the rest are appended to customer_history and
+// applied to customer_stats.
+func (r *customerEventRepository) Ingest(ctx context.Context, events []*model.CustomerEvent) (*model.CustomerEventIngestResult, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.CustomerEventIngestResult{Received: len(events)}
+
+	err = r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		knownCustomers := make(map[string]bool)
+
+		for _, event := range events {
+			// Verificar que el cliente pertenece al tenant
+			if !knownCustomers[event.CustomerID] {
+				var exists bool
+				err := tx.QueryRowContext(ctx,
+					"SELECT EXISTS (SELECT 1 FROM customers WHERE id = $1 AND tenant_id = $2)",
+					event.CustomerID, tenantID).Scan(&exists)
+				if err != nil {
+					return fmt.Errorf("failed to check customer existence: %w", err)
+				}
+				if !exists {
+					return model.NewNotFoundError("customer", event.CustomerID)
+				}
+				knownCustomers[event.CustomerID] = true
+			}
+
+			ingested, err := r.ingestEvent(ctx, tx, tenantID, event)
+			if err != nil {
+				return fmt.Errorf("failed to ingest event %s: %w", event.EventID, err)
+			}
+
+			if ingested {
+				result.Ingested++
+				result.IngestedEvents = append(result.IngestedEvents, event)
+			} else {
+				result.Duplicates++
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ingestEvent applies a single event inside the ingestion transaction and reports
+// whether it was new
+func (r *customerEventRepository) ingestEvent(ctx context.Context, tx *sql.Tx, tenantID string, event *model.CustomerEvent) (bool, error) {
+	// Registrar el evento; si ya existe es un reintento y no se vuelve a aplicar
+	var eventID string
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO customer_events (tenant_id, event_id, customer_id, type, source, occurred_at, received_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (tenant_id, event_id) DO NOTHING
+		RETURNING event_id`,
+		tenantID,
+		event.EventID,
+		event.CustomerID,
+		event.Type,
+		nullIfEmpty(event.Source),
+		event.OccurredAt,
+	).Scan(&eventID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to register event: %w", err)
+	}
+
+	// Agregar al historial
+	item := event.ToHistoryItem()
+	args, err := customerHistoryInsertArgs(tenantID, item)
+	if err != nil {
+		return false, err
+	}
+	if err := tx.QueryRowContext(ctx, customerHistoryInsertQuery, args...).Scan(&item.ID, &item.CreatedAt); err != nil {
+		return false, fmt.Errorf("failed to append event to history: %w", err)
+	}
+
+	// Actualizar estadísticas de forma incremental
+	if err := r.applyToStats(ctx, tx, tenantID, event); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// applyToStats locks the customer's stats row, creating it if needed, and applies the event
+func (r *customerEventRepository) applyToStats(ctx context.Context, tx *sql.Tx, tenantID string, event *model.CustomerEvent) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO customer_stats (customer_id, tenant_id, calculated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (customer_id) DO NOTHING`, event.CustomerID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to initialize customer stats: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM customer_stats cs
+		WHERE cs.customer_id = $1 AND cs.tenant_id = $2
+		FOR UPDATE`, customerStatsColumns)
+
+	stats, err := scanCustomerStats(tx.QueryRowContext(ctx, query, event.CustomerID, tenantID))
+	if err != nil {
+		return fmt.Errorf("failed to lock customer stats: %w", err)
+	}
+
+	event.ApplyToStats(stats)
+
+	if err := stats.Validate(); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, customerStatsUpdateQuery, customerStatsUpdateArgs(tenantID, stats)...)
+	if err != nil {
+		return fmt.Errorf("failed to update customer stats: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+)
+
+// customerHistoryInsertQuery inserts a timeline item; shared with the event ingestion transaction
+const customerHistoryInsertQuery = `
+	INSERT INTO customer_history (
+		tenant_id, customer_id, type, title, description,
+		amount, status, data, reference_id, created_at
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+	) RETURNING id, created_at`
+
+type customerHistoryRepository struct {
+	db *DB
+}
+
+// NewCustomerHistoryRepository creates a new customer history repository
+func NewCustomerHistoryRepository(db *DB) repository.CustomerHistoryRepository {
+	return &customerHistoryRepository{
+		db: db,
+	}
+}
+
+// Create records a new item in the customer timeline
+func (r *customerHistoryRepository) Create(ctx context.Context, item *model.CustomerHistoryItem) error {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	args, err := customerHistoryInsertArgs(tenantID, item)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRowWithTenant(ctx, tenantID, customerHistoryInsertQuery, args...).Scan(&item.ID, &item.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create customer history item: %w", err)
+	}
+
+	item.TenantID = tenantID
+	return nil
+}
+
+// List retrieves the timeline of a customer, newest first
+func (r *customerHistoryRepository) List(ctx context.Context, filter model.CustomerHistoryFilter) ([]*model.CustomerHistoryItem, int, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Build WHERE clause
+	whereConditions := []string{"ch.tenant_id = $1", "ch.customer_id = $2"}
+	args := []interface{}{tenantID, filter.CustomerID}
+	argCount := 2
+
+	if filter.Type != "" {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("ch.type = $%d", argCount))
+		args = append(args, filter.Type)
+	}
+
+	if filter.DateFrom != nil {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("ch.created_at >= $%d", argCount))
+		args = append(args, *filter.DateFrom)
+	}
+
+	if filter.DateTo != nil {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("ch.created_at <= $%d", argCount))
+		args = append(args, *filter.DateTo)
+	}
+
+	if len(filter.ExcludeNoteTypes) > 0 {
+		argCount++
+		whereConditions = append(whereConditions,
+			fmt.Sprintf("NOT (ch.type = 'note' AND COALESCE(ch.data->>'note_type', '') = ANY($%d))", argCount))
+		args = append(args, pq.Array(filter.ExcludeNoteTypes))
+	}
+
+	whereClause := "WHERE " + strings.Join(whereConditions, " AND ")
+
+	// Count total records
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM customer_history ch %s", whereClause)
+
+	var total int
+	err = r.db.QueryRowWithTenant(ctx, tenantID, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count customer history: %w", err)
+	}
+
+	// Build pagination
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50 // Default limit
+	}
+	offset := 0
+	if filter.Page > 0 {
+		offset = (filter.Page - 1) * limit
+	}
+
+	// Main query
+	query := fmt.Sprintf(`
+		SELECT ch.id, ch.tenant_id, ch.customer_id, ch.type, ch.title,
+			   ch.description, ch.amount, ch.status, ch.data,
+			   ch.reference_id, ch.created_at
+		FROM customer_history ch
+		%s
+		ORDER BY ch.created_at DESC, ch.id DESC
+		LIMIT %d OFFSET %d`, whereClause, limit, offset)
+
+	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list customer history: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*model.CustomerHistoryItem
+	for rows.Next() {
+		item := &model.CustomerHistoryItem{}
+		var description, status, referenceID sql.NullString
+		var data []byte
+
+		err := rows.Scan(
+			&item.ID,
+			&item.TenantID,
+			&item.CustomerID,
+			&item.Type,
+			&item.Title,
+			&description,
+			&item.Amount,
+			&status,
+			&data,
+			&referenceID,
+			&item.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan customer history item: %w", err)
+		}
+
+		item.Description = description.String
+		item.Status = status.String
+		item.ReferenceID = referenceID.String
+
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &item.Data); err != nil {
+				return nil, 0, fmt.Errorf("failed to unmarshal history data: %w", err)
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate over customer history: %w", err)
+	}
+
+	return items, total, nil
+}
+
+// CountByCustomer counts the timeline items of a customer
+func (r *customerHistoryRepository) CountByCustomer(ctx context.Context, customerID string) (int64, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = r.db.QueryRowWithTenant(ctx, tenantID,
+		"SELECT COUNT(*) FROM customer_history WHERE tenant_id = $1 AND customer_id = $2",
+		tenantID, customerID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count customer history: %w", err)
+	}
+
+	return count, nil
+}
+
+// customerHistoryInsertArgs builds the arguments of customerHistoryInsertQuery
+func customerHistoryInsertArgs(tenantID string, item *model.CustomerHistoryItem) ([]interface{}, error) {
+	if err := item.Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(item.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal history data: %w", err)
+	}
+
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now()
+	}
+
+	return []interface{}{
+		tenantID,
+		item.CustomerID,
+		item.Type,
+		item.Title,
+		nullIfEmpty(item.Description),
+		item.Amount,
+		nullIfEmpty(item.Status),
+		data,
+		nullIfEmpty(item.ReferenceID),
+		item.CreatedAt,
+	}, nil
+}
+
+// nullIfEmpty stores an empty string as NULL
+func nullIfEmpty(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// ReplaceDescriptions replaces the description of the entries of a customer of the given
+// types and returns how many entries changed
+func (r *customerHistoryRepository) ReplaceDescriptions(ctx context.Context, customerID string, types []string, text string) (int64, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		UPDATE customer_history SET description = $3
+		WHERE tenant_id = $1 AND customer_id = $2 AND type = ANY($4)
+			AND description IS DISTINCT FROM $3`
+
+	result, err := r.db.ExecWithTenant(ctx, tenantID, query, tenantID, customerID, text, pq.Array(types))
+	if err != nil {
+		return 0, fmt.Errorf("failed to replace customer history descriptions: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
 	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+	"github.com/lib/pq"
 )
 
 type customerNoteRepository struct {
@@ -63,7 +64,7 @@ func (r *customerNoteRepository) GetByID(ctx context.Context, id string) (*model
 		SELECT cn.id, cn.customer_id, cn.staff_id, cn.staff_name,
 			   cn.note, cn.type, cn.created_at
 		FROM customer_notes cn
-		INNER JOIN customers c ON cn.customer_id = c.id
+		INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE cn.id = $1`
 
 	note := &model.CustomerNote{}
@@ -80,7 +81,7 @@ func (r *customerNoteRepository) GetByID(ctx context.Context, id string) (*model
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("customer note with ID %s not found", id)
+			return nil, model.NewNotFoundError("customer_note", id)
 		}
 		return nil, fmt.Errorf("failed to get customer note: %w", err)
 	}
@@ -111,17 +112,19 @@ func (r *customerNoteRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("customer note with ID %s not found", id)
+		return model.NewNotFoundError("customer_note", id)
 	}
 
 	return nil
 }
 
 // List retrieves customer notes with filtering and pagination
-func (r *customerNoteRepository) List(ctx context.Context, filter model.CustomerNoteFilter) ([]*model.CustomerNote, int, error) {
+func (r *customerNoteRepository) List(ctx context.Context, filter model.CustomerNoteFilter) ([]*model.CustomerNote, model.PageInfo, error) {
+	var page model.PageInfo
+
 	tenantID, err := GetTenantIDFromContext(ctx)
 	if err != nil {
-		return nil, 0, err
+		return nil, page, err
 	}
 
 	// Build WHERE clause
@@ -154,55 +157,67 @@ func (r *customerNoteRepository) List(ctx context.Context, filter model.Customer
 		args = append(args, *filter.DateTo)
 	}
 
+	if len(filter.ExcludeTypes) > 0 {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("NOT (cn.type = ANY($%d))", argCount))
+		args = append(args, pq.Array(filter.ExcludeTypes))
+	}
+
 	whereClause := ""
 	if len(whereConditions) > 0 {
 		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
 	}
 
-	// Count total records
-	countQuery := fmt.Sprintf(`
-		SELECT COUNT(*) 
-		FROM customer_notes cn 
-		INNER JOIN customers c ON cn.customer_id = c.id 
-		%s`, whereClause)
-
-	var total int
-	err = r.db.QueryRowWithTenant(ctx, tenantID, countQuery, args...).Scan(&total)
+	// Count total records (sin la condición del cursor)
+	from := "FROM customer_notes cn INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL " + whereClause
+	page.Total, page.TotalIsEstimate, err = r.db.countRows(ctx, tenantID, filter.TotalMode, from, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count customer notes: %w", err)
+		return nil, page, fmt.Errorf("failed to count customer notes: %w", err)
 	}
 
-	// Build pagination
+	// Continuar tras la última fila de la página anterior
+	if filter.Cursor != nil {
+		after, afterArgs, err := customerNoteListOrder.after(filter.Cursor, argCount+1)
+		if err != nil {
+			return nil, page, err
+		}
+		args = append(args, afterArgs...)
+		if whereClause == "" {
+			whereClause = "WHERE " + after
+		} else {
+			whereClause += " AND " + after
+		}
+	}
+
+	// Build pagination; una fila de más indica que hay otra página
 	limit := filter.Limit
 	if limit <= 0 {
 		limit = 50 // Default limit
 	}
-	offset := 0
-	if filter.Page > 0 {
-		offset = (filter.Page - 1) * limit
-	}
+	offset := pageOffset(filter.Cursor, filter.Page, limit)
 
 	// Main query
 	query := fmt.Sprintf(`
 		SELECT cn.id, cn.customer_id, cn.staff_id, cn.staff_name, 
-			   cn.note, cn.type, cn.created_at
+			   cn.note, cn.type, cn.created_at, %s
 		FROM customer_notes cn
-		INNER JOIN customers c ON cn.customer_id = c.id
-		%s
-		ORDER BY cn.created_at DESC
-		LIMIT %d OFFSET %d`, whereClause, limit, offset)
+		INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL
+		%s %s
+		LIMIT %d OFFSET %d`, customerNoteListOrder.keyColumns(), whereClause, customerNoteListOrder.orderBy(), limit+1, offset)
 
 	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list customer notes: %w", err)
+		return nil, page, fmt.Errorf("failed to list customer notes: %w", err)
 	}
 	defer rows.Close()
 
 	var notes []*model.CustomerNote
+	var keys [][]string
 	for rows.Next() {
 		note := &model.CustomerNote{}
+		key, keyDest := customerNoteListOrder.keyDest()
 
-		err := rows.Scan(
+		err := scanWithExtra(rows, keyDest...).Scan(
 			&note.ID,
 			&note.CustomerID,
 			&note.StaffID,
@@ -212,17 +227,30 @@ func (r *customerNoteRepository) List(ctx context.Context, filter model.Customer
 			&note.CreatedAt,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan customer note: %w", err)
+			return nil, page, fmt.Errorf("failed to scan customer note: %w", err)
 		}
 
 		notes = append(notes, note)
+		keys = append(keys, key)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("failed to iterate over customer notes: %w", err)
+		return nil, page, fmt.Errorf("failed to iterate over customer notes: %w", err)
 	}
 
-	return notes, total, nil
+	page.Next = nextCursor(keys, limit)
+	if page.Next != nil {
+		notes = notes[:limit]
+	}
+
+	return notes, page, nil
+}
+
+// customerNoteListOrder is the order of note listings: newest first, ending in the id so
+// pages can continue after the last key
+var customerNoteListOrder = keysetOrder{
+	{expr: "cn.created_at", cast: "timestamptz", desc: true},
+	{expr: "cn.id", cast: "uuid", desc: true},
 }
 
 // ListByCustomer retrieves all notes for a customer
@@ -258,7 +286,7 @@ func (r *customerNoteRepository) ListByStaff(ctx context.Context, staffID string
 	err = r.db.QueryRowWithTenant(ctx, tenantID, `
 		SELECT COUNT(*) 
 		FROM customer_notes cn 
-		INNER JOIN customers c ON cn.customer_id = c.id
+		INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE cn.staff_id = $1`, staffID).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count notes by staff: %w", err)
@@ -273,7 +301,7 @@ func (r *customerNoteRepository) ListByStaff(ctx context.Context, staffID string
 		SELECT cn.id, cn.customer_id, cn.staff_id, cn.staff_name, 
 			   cn.note, cn.type, cn.created_at
 		FROM customer_notes cn
-		INNER JOIN customers c ON cn.customer_id = c.id
+		INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE cn.staff_id = $1
 		ORDER BY cn.created_at DESC
 		LIMIT $2 OFFSET $3`
@@ -314,7 +342,8 @@ func (r *customerNoteRepository) ListByType(ctx context.Context, noteType string
 		Page:  page,
 		Limit: limit,
 	}
-	return r.List(ctx, filter)
+	notes, pageInfo, err := r.List(ctx, filter)
+	return notes, pageInfo.Total, err
 }
 
 // ListRecent retrieves recent notes across all customers
@@ -328,7 +357,7 @@ func (r *customerNoteRepository) ListRecent(ctx context.Context, limit int) ([]*
 		SELECT cn.id, cn.customer_id, cn.staff_id, cn.staff_name, 
 			   cn.note, cn.type, cn.created_at
 		FROM customer_notes cn
-		INNER JOIN customers c ON cn.customer_id = c.id
+		INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL
 		ORDER BY cn.created_at DESC
 		LIMIT $1`
 
@@ -394,7 +423,7 @@ func (r *customerNoteRepository) Count(ctx context.Context) (int64, error) {
 	err = r.db.QueryRowWithTenant(ctx, tenantID, `
 		SELECT COUNT(*) 
 		FROM customer_notes cn 
-		INNER JOIN customers c ON cn.customer_id = c.id`).Scan(&count)
+		INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL`).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count customer notes: %w", err)
 	}
@@ -413,7 +442,7 @@ func (r *customerNoteRepository) CountByCustomer(ctx context.Context, customerID
 	err = r.db.QueryRowWithTenant(ctx, tenantID, `
 		SELECT COUNT(*)
 		FROM customer_notes cn
-		INNER JOIN customers c ON cn.customer_id = c.id
+		INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE cn.customer_id = $1`, customerID).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count notes by customer: %w", err)
@@ -433,7 +462,7 @@ func (r *customerNoteRepository) CountByType(ctx context.Context, noteType strin
 	err = r.db.QueryRowWithTenant(ctx, tenantID, `
 		SELECT COUNT(*) 
 		FROM customer_notes cn 
-		INNER JOIN customers c ON cn.customer_id = c.id
+		INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE cn.type = $1`, noteType).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count notes by type: %w", err)
@@ -453,7 +482,7 @@ func (r *customerNoteRepository) CountByStaff(ctx context.Context, staffID strin
 	err = r.db.QueryRowWithTenant(ctx, tenantID, `
 		SELECT COUNT(*)
 		FROM customer_notes cn
-		INNER JOIN customers c ON cn.customer_id = c.id
+		INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE cn.staff_id = $1`, staffID).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count notes by staff: %w", err)
@@ -472,7 +501,7 @@ func (r *customerNoteRepository) GetNoteTypesCount(ctx context.Context, customer
 	query := `
 		SELECT cn.type, COUNT(*) as count
 		FROM customer_notes cn
-		INNER JOIN customers c ON cn.customer_id = c.id
+		INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE cn.customer_id = $1
 		GROUP BY cn.type
 		ORDER BY count DESC`
@@ -510,7 +539,7 @@ func (r *customerNoteRepository) GetMostActiveStaff(ctx context.Context, limit i
 		SELECT cn.staff_id, cn.staff_name, COUNT(*) as note_count,
 			   MAX(cn.created_at) as last_note_created
 		FROM customer_notes cn
-		INNER JOIN customers c ON cn.customer_id = c.id
+		INNER JOIN customers c ON cn.customer_id = c.id AND c.deleted_at IS NULL
 		GROUP BY cn.staff_id, cn.staff_name
 		ORDER BY note_count DESC, last_note_created DESC
 		LIMIT $1`
@@ -543,3 +572,26 @@ func (r *customerNoteRepository) GetMostActiveStaff(ctx context.Context, limit i
 
 	return result, nil
 }
+
+// ReplaceTextByCustomer replaces the text of every note of a customer and returns how many
+// notes changed
+func (r *customerNoteRepository) ReplaceTextByCustomer(ctx context.Context, customerID string, text string) (int64, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `UPDATE customer_notes SET note = $2 WHERE customer_id = $1 AND note <> $2`
+
+	result, err := r.db.ExecWithTenant(ctx, tenantID, query, customerID, text)
+	if err != nil {
+		return 0, fmt.Errorf("failed to replace customer notes text: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -5,6 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
 	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
@@ -28,38 +31,13 @@ func (r *customerRepository) Create(ctx context.Context, customer *model.Custome
 		return err
 	}
 
-	query := `
-		INSERT INTO customers (
-			tenant_id, first_name, last_name, email, phone, 
-			customer_type, company_name, tax_id, address, birthday, 
-			notes, preferences, is_active, created_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
-		) RETURNING id, created_at, updated_at`
-
-	err = r.db.QueryRowWithTenant(ctx, tenantID, query,
-		tenantID,
-		customer.FirstName,
-		customer.LastName,
-		NullString(customer.Email),
-		NullString(customer.Phone),
-		customer.CustomerType,
-		NullString(customer.CompanyName),
-		NullString(customer.TaxID),
-		NullString(customer.Address),
-		NullTime(customer.Birthday),
-		NullString(customer.Notes),
-		customer.Preferences,
-		customer.IsActive,
-		customer.CreatedAt,
-		customer.UpdatedAt,
-	).Scan(&customer.ID, &customer.CreatedAt, &customer.UpdatedAt)
-
+	err = r.db.QueryRowWithTenant(ctx, tenantID, customerInsertQuery, customerInsertArgs(tenantID, customer)...).
+		Scan(&customer.ID, &customer.CreatedAt, &customer.UpdatedAt, &customer.Version)
 	if err != nil {
-		return fmt.Errorf("failed to create customer: %w", err)
+		return fmt.Errorf("failed to create customer: %w", conflictFromUniqueViolation(err))
 	}
 
-	customer.TenantID = fmt.Sprintf("%d", tenantID)
+	customer.TenantID = tenantID
 	return nil
 }
 
@@ -70,18 +48,370 @@ func (r *customerRepository) GetByID(ctx context.Context, id string) (*model.Cus
 		return nil, err
 	}
 
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL`
+
+	customer, err := scanCustomer(r.db.QueryRowWithTenant(ctx, tenantID, query, id, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("customer", id)
+		}
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	return customer, nil
+}
+
+// Update updates a customer if its version is still customer.Version and bumps the version.
+// Returns a VersionConflictError if the row changed since it was read. A set AnonymizedAt is
+// saved; once saved it is never cleared.
+func (r *customerRepository) Update(ctx context.Context, customer *model.Customer) error {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		SELECT id, tenant_id, first_name, last_name, email, phone,
-			   customer_type, company_name, tax_id, address, birthday,
-			   notes, preferences, is_active, created_at, updated_at
-		FROM customers
-		WHERE id = $1`
+		UPDATE customers SET
+			first_name = $2, last_name = $3, email = $4, phone = $5,
+			customer_type = $6, company_name = $7, tax_id = $8, address = $9,
+			birthday = $10, notes = $11, preferences = $12, is_active = $13,
+			updated_at = $14, anonymized_at = COALESCE(anonymized_at, $16), version = version + 1
+		WHERE id = $1 AND tenant_id = $17 AND version = $15 AND deleted_at IS NULL
+		RETURNING version`
+
+	return r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		var version int64
+		err := tx.QueryRowContext(ctx, query,
+			customer.ID,
+			customer.FirstName,
+			customer.LastName,
+			NullString(customer.Email),
+			NullString(customer.Phone),
+			customer.CustomerType,
+			NullString(customer.CompanyName),
+			NullString(customer.TaxID),
+			NullString(customer.Address),
+			NullTime(customer.Birthday),
+			NullString(customer.Notes),
+			customer.Preferences,
+			customer.IsActive,
+			customer.UpdatedAt,
+			customer.Version,
+			NullTime(customer.AnonymizedAt),
+			tenantID,
+		).Scan(&version)
+
+		if err == sql.ErrNoRows {
+			return customerVersionConflict(ctx, tx, tenantID, customer.ID, customer.Version, false)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update customer: %w", conflictFromUniqueViolation(err))
+		}
+
+		customer.Version = version
+		return nil
+	})
+}
+
+// Delete deletes a customer if its version is still version
+func (r *customerRepository) Delete(ctx context.Context, id string, version int64) error {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM customers WHERE id = $1 AND tenant_id = $2 AND version = $3`
+
+	return r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id, tenantID, version)
+		if err != nil {
+			return fmt.Errorf("failed to delete customer: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return versionConflict(ctx, tx, "customers", "customer", id, version)
+		}
+
+		return nil
+	})
+}
+
+// SoftDelete moves a customer to the trash if its version is still version and bumps the
+// version. Its vehicles, notes and history stay until the customer is restored or purged.
+func (r *customerRepository) SoftDelete(ctx context.Context, id string, version int64, deletedAt time.Time) (int64, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		UPDATE customers SET deleted_at = $3, updated_at = $3, version = version + 1
+		WHERE id = $1 AND tenant_id = $4 AND version = $2 AND deleted_at IS NULL
+		RETURNING version`
+
+	var newVersion int64
+	err = r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, query, id, version, deletedAt, tenantID).Scan(&newVersion)
+		if err == sql.ErrNoRows {
+			return customerVersionConflict(ctx, tx, tenantID, id, version, false)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete customer: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return newVersion, nil
+}
+
+// Restore takes a customer out of the trash if its version is still version and bumps the
+// version. Returns a ConflictError if an active customer took its email or tax ID meanwhile.
+func (r *customerRepository) Restore(ctx context.Context, id string, version int64) (*model.Customer, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE customers SET deleted_at = NULL, updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND tenant_id = $3 AND version = $2 AND deleted_at IS NOT NULL
+		RETURNING ` + customerColumns
+
+	var customer *model.Customer
+	err = r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		var err error
+		customer, err = scanCustomer(tx.QueryRowContext(ctx, query, id, version, tenantID))
+		if err == sql.ErrNoRows {
+			return customerVersionConflict(ctx, tx, tenantID, id, version, true)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore customer: %w", conflictFromUniqueViolation(err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// customerVersionConflict is versionConflict for customers: a customer on the other side of
+// the trash (in it when deleted is false, out of it when true) or of another tenant is not found
+func customerVersionConflict(ctx context.Context, tx *sql.Tx, tenantID, id string, expected int64, deleted bool) error {
+	var current int64
+	var inTrash bool
+	err := tx.QueryRowContext(ctx,
+		"SELECT version, deleted_at IS NOT NULL FROM customers WHERE id = $1 AND tenant_id = $2",
+		id, tenantID).Scan(&current, &inTrash)
+	if err == sql.ErrNoRows || (err == nil && inTrash != deleted) {
+		return model.NewNotFoundError("customer", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read customer version: %w", err)
+	}
+
+	return &model.VersionConflictError{
+		Resource:        "customer",
+		ID:              id,
+		ExpectedVersion: expected,
+		CurrentVersion:  current,
+	}
+}
+
+// GetDeletedByID retrieves a customer in the trash by ID
+func (r *customerRepository) GetDeletedByID(ctx context.Context, id string) (*model.Customer, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL`
+
+	customer, err := scanCustomer(r.db.QueryRowWithTenant(ctx, tenantID, query, id, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("customer", id)
+		}
+		return nil, fmt.Errorf("failed to get deleted customer: %w", err)
+	}
+
+	return customer, nil
+}
+
+// PurgeDeleted permanently deletes up to limit customers of the tenant of ctx that went to
+// the trash before before, with their vehicles, notes, stats and history (ON DELETE CASCADE
+// through the (id, tenant_id) foreign keys), and returns their IDs
+func (r *customerRepository) PurgeDeleted(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Los más antiguos primero; SKIP LOCKED evita esperar a un restore en curso
+	query := `
+		DELETE FROM customers
+		WHERE tenant_id = $3 AND id IN (
+			SELECT id FROM customers
+			WHERE tenant_id = $3 AND deleted_at < $1
+			ORDER BY deleted_at, id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`
+
+	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, before, limit, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge deleted customers: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan purged customer: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over purged customers: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Merge moves the vehicles, notes, history and ingested events of the duplicates to the
+// survivor, redirects the duplicate IDs (and the IDs previously merged into them) to the
+// survivor and deletes the duplicates, whose stats go with them. Each duplicate is deleted
+// only at the version it was read; the survivor profile and stats are saved by the caller.
+func (r *customerRepository) Merge(ctx context.Context, survivorID string, duplicates []*model.Customer) error {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
 
+	duplicateIDs := make([]string, len(duplicates))
+	for i, duplicate := range duplicates {
+		duplicateIDs[i] = duplicate.ID
+	}
+
+	// Las tablas dependientes apuntan al superviviente antes de borrar los duplicados (ON DELETE CASCADE)
+	repoints := []struct{ query, what string }{
+		{`UPDATE vehicles SET customer_id = $1, version = version + 1, updated_at = NOW() WHERE customer_id = ANY($2)`, "vehicles"},
+		{`UPDATE customer_notes SET customer_id = $1 WHERE customer_id = ANY($2)`, "notes"},
+		{`UPDATE customer_history SET customer_id = $1 WHERE customer_id = ANY($2)`, "history"},
+		{`UPDATE customer_events SET customer_id = $1 WHERE customer_id = ANY($2)`, "events"},
+		{`UPDATE customer_merges SET survivor_id = $1 WHERE survivor_id = ANY($2)`, "previous merges"},
+	}
+
+	return r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		for _, repoint := range repoints {
+			if _, err := tx.ExecContext(ctx, repoint.query, survivorID, pq.Array(duplicateIDs)); err != nil {
+				return fmt.Errorf("failed to move %s to the surviving customer: %w", repoint.what, err)
+			}
+		}
+
+		for _, duplicate := range duplicates {
+			result, err := tx.ExecContext(ctx,
+				`DELETE FROM customers WHERE id = $1 AND tenant_id = $2 AND version = $3`,
+				duplicate.ID, tenantID, duplicate.Version)
+			if err != nil {
+				return fmt.Errorf("failed to delete merged customer: %w", err)
+			}
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", err)
+			}
+			if rowsAffected == 0 {
+				return versionConflict(ctx, tx, "customers", "customer", duplicate.ID, duplicate.Version)
+			}
+
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO customer_merges (tenant_id, merged_id, survivor_id) VALUES ($1, $2, $3)`,
+				tenantID, duplicate.ID, survivorID)
+			if err != nil {
+				return fmt.Errorf("failed to record customer merge: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ResolveMergedID returns the customer a merged customer ID was merged into
+func (r *customerRepository) ResolveMergedID(ctx context.Context, mergedID string) (string, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var survivorID string
+	err = r.db.QueryRowWithTenant(ctx, tenantID,
+		"SELECT survivor_id FROM customer_merges WHERE tenant_id = $1 AND merged_id = $2",
+		tenantID, mergedID).Scan(&survivorID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", model.NewNotFoundError("customer", mergedID)
+		}
+		return "", fmt.Errorf("failed to resolve merged customer: %w", err)
+	}
+
+	return survivorID, nil
+}
+
+// CreateWithVehicles creates a customer and its vehicles in a single tenant transaction.
+// Nothing is persisted if any vehicle conflicts with an existing VIN or license plate.
+func (r *customerRepository) CreateWithVehicles(ctx context.Context, customer *model.Customer, vehicles []*model.Vehicle) error {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, customerInsertQuery, customerInsertArgs(tenantID, customer)...).
+			Scan(&customer.ID, &customer.CreatedAt, &customer.UpdatedAt, &customer.Version)
+		if err != nil {
+			return fmt.Errorf("failed to create customer: %w", conflictFromUniqueViolation(err))
+		}
+
+		for _, vehicle := range vehicles {
+			vehicle.CustomerID = customer.ID
+		}
+
+		return insertVehiclesTx(ctx, tx, tenantID, vehicles)
+	})
+	if err != nil {
+		return err
+	}
+
+	customer.TenantID = tenantID
+	customer.Vehicles = vehicles
+	return nil
+}
+
+// customerColumns are the columns read by scanCustomer
+const customerColumns = `
+	id, tenant_id, first_name, last_name, email, phone,
+	customer_type, company_name, tax_id, address, birthday,
+	notes, preferences, is_active, created_at, updated_at, version, deleted_at, anonymized_at`
+
+// scanCustomer scans a row selected with customerColumns
+func scanCustomer(row rowScanner) (*model.Customer, error) {
 	customer := &model.Customer{}
 	var email, phone, companyName, taxID, address, notes sql.NullString
-	var birthday sql.NullTime
+	var birthday, deletedAt, anonymizedAt sql.NullTime
 
-	err = r.db.QueryRowWithTenant(ctx, tenantID, query, id).Scan(
+	err := row.Scan(
 		&customer.ID,
 		&customer.TenantID,
 		&customer.FirstName,
@@ -98,16 +428,14 @@ func (r *customerRepository) GetByID(ctx context.Context, id string) (*model.Cus
 		&customer.IsActive,
 		&customer.CreatedAt,
 		&customer.UpdatedAt,
+		&customer.Version,
+		&deletedAt,
+		&anonymizedAt,
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("customer with ID %s not found", id)
-		}
-		return nil, fmt.Errorf("failed to get customer: %w", err)
+		return nil, err
 	}
 
-	// Convert nullable fields
 	customer.Email = StringFromNull(email)
 	customer.Phone = StringFromNull(phone)
 	customer.CompanyName = StringFromNull(companyName)
@@ -115,27 +443,26 @@ func (r *customerRepository) GetByID(ctx context.Context, id string) (*model.Cus
 	customer.Address = StringFromNull(address)
 	customer.Notes = StringFromNull(notes)
 	customer.Birthday = TimeFromNull(birthday)
+	customer.DeletedAt = TimeFromNull(deletedAt)
+	customer.AnonymizedAt = TimeFromNull(anonymizedAt)
 
 	return customer, nil
 }
 
-// Update updates a customer
-func (r *customerRepository) Update(ctx context.Context, customer *model.Customer) error {
-	tenantID, err := GetTenantIDFromContext(ctx)
-	if err != nil {
-		return err
-	}
-
-	query := `
-		UPDATE customers SET
-			first_name = $2, last_name = $3, email = $4, phone = $5,
-			customer_type = $6, company_name = $7, tax_id = $8, address = $9,
-			birthday = $10, notes = $11, preferences = $12, is_active = $13,
-			updated_at = $14
-		WHERE id = $1`
-
-	result, err := r.db.ExecWithTenant(ctx, tenantID, query,
-		customer.ID,
+// customerInsertQuery inserts a customer and returns its generated fields
+const customerInsertQuery = `
+	INSERT INTO customers (
+		tenant_id, first_name, last_name, email, phone,
+		customer_type, company_name, tax_id, address, birthday,
+		notes, preferences, is_active, created_at, updated_at
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+	) RETURNING id, created_at, updated_at, version`
+
+// customerInsertArgs returns the customerInsertQuery arguments
+func customerInsertArgs(tenantID string, customer *model.Customer) []interface{} {
+	return []interface{}{
+		tenantID,
 		customer.FirstName,
 		customer.LastName,
 		NullString(customer.Email),
@@ -148,305 +475,393 @@ func (r *customerRepository) Update(ctx context.Context, customer *model.Custome
 		NullString(customer.Notes),
 		customer.Preferences,
 		customer.IsActive,
+		customer.CreatedAt,
 		customer.UpdatedAt,
-	)
+	}
+}
 
+// List retrieves customers with filtering and pagination
+func (r *customerRepository) List(ctx context.Context, filter model.CustomerFilter) ([]*model.Customer, model.PageInfo, error) {
+	var page model.PageInfo
+
+	tenantID, err := GetTenantIDFromContext(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to update customer: %w", err)
+		return nil, page, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	// Build WHERE clause; el tenant se filtra explícitamente además de por RLS
+	whereConditions := []string{"c.tenant_id = $1"}
+	args := []interface{}{tenantID}
+	argCount := 1
+
+	// Búsqueda como en Search; sin orden explícito los resultados se ordenan por relevancia
+	relevance := ""
+	if terms := model.SearchTerms(filter.Search); len(terms) > 0 {
+		var match string
+		match, relevance = customerSearchExpressions(nil, argCount+1, argCount+2)
+		argCount += 2
+		whereConditions = append(whereConditions, match)
+		args = append(args, searchTSQuery(terms), strings.Join(terms, " "))
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("customer with ID %s not found", customer.ID)
+	// La papelera solo lista clientes eliminados y el resto de listados los oculta
+	if filter.Deleted {
+		whereConditions = append(whereConditions, "c.deleted_at IS NOT NULL")
+	} else {
+		whereConditions = append(whereConditions, "c.deleted_at IS NULL")
 	}
 
-	return nil
-}
+	if filter.CustomerType != "" {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("c.customer_type = $%d", argCount))
+		args = append(args, filter.CustomerType)
+	}
 
-// Delete deletes a customer
-func (r *customerRepository) Delete(ctx context.Context, id string) error {
-	tenantID, err := GetTenantIDFromContext(ctx)
-	if err != nil {
-		return err
+	if filter.ActiveOnly {
+		whereConditions = append(whereConditions, "c.is_active = true")
 	}
 
-	query := `DELETE FROM customers WHERE id = $1`
+	// Los filtros de estadísticas descartan las filas sin customer_stats, así que el
+	// planificador convierte el LEFT JOIN en un JOIN y puede usar sus índices
+	if filter.MinTotalSpent != nil {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("cs.total_spent >= $%d", argCount))
+		args = append(args, *filter.MinTotalSpent)
+	}
 
-	result, err := r.db.ExecWithTenant(ctx, tenantID, query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete customer: %w", err)
+	if filter.Level != "" {
+		condition, err := customerLevelCondition(filter.Level)
+		if err != nil {
+			return nil, page, err
+		}
+		whereConditions = append(whereConditions, condition)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if filter.LastVisitFrom != nil {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("cs.last_visit >= $%d", argCount))
+		args = append(args, *filter.LastVisitFrom)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("customer with ID %s not found", id)
+	if filter.LastVisitTo != nil {
+		argCount++
+		whereConditions = append(whereConditions, fmt.Sprintf("cs.last_visit <= $%d", argCount))
+		args = append(args, *filter.LastVisitTo)
 	}
 
-	return nil
-}
+	whereClause := "WHERE " + strings.Join(whereConditions, " AND ")
 
-// List retrieves customers with filtering and pagination
-func (r *customerRepository) List(ctx context.Context, filter model.CustomerFilter) ([]*model.Customer, int, error) {
-	// DEBUG: Verificar tenant_id en contexto ANTES de GetTenantIDFromContext
-	tenantIDValue := ctx.Value("tenant_id")
-	println("DEBUG REPO: tenant_id from context:", tenantIDValue)
-	if tenantIDValue != nil {
-		println("DEBUG REPO: tenant_id type:", fmt.Sprintf("%T", tenantIDValue))
-	} else {
-		println("DEBUG REPO: tenant_id is NIL in context!")
+	// Count total records (sin la condición del cursor)
+	page.Total, page.TotalIsEstimate, err = r.db.countRows(ctx, tenantID, filter.TotalMode, customerListFrom+" "+whereClause, args...)
+	if err != nil {
+		return nil, page, fmt.Errorf("failed to count customers: %w", err)
 	}
 
-	tenantID, err := GetTenantIDFromContext(ctx)
+	// Continuar tras la última fila de la página anterior
+	order := customerListOrder(filter, relevance)
+	if filter.Cursor != nil {
+		after, afterArgs, err := order.after(filter.Cursor, argCount+1)
+		if err != nil {
+			return nil, page, err
+		}
+		argCount += len(afterArgs)
+		args = append(args, afterArgs...)
+		whereClause += " AND " + after
+	}
+
+	// Build pagination; una fila de más indica que hay otra página
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50 // Default limit
+	}
+	offset := pageOffset(filter.Cursor, filter.Page, limit)
+
+	// Main query, con las estadísticas de cada cliente
+	query := fmt.Sprintf(`
+		SELECT %s, %s, %s
+		%s
+		%s %s
+		LIMIT %d OFFSET %d`, qualifiedColumns("c", customerColumns), joinedCustomerStatsColumns, order.keyColumns(),
+		customerListFrom, whereClause, order.orderBy(), limit+1, offset)
+
+	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, args...)
 	if err != nil {
-		println("DEBUG REPO: GetTenantIDFromContext ERROR:", err.Error())
-		return nil, 0, err
+		return nil, page, fmt.Errorf("failed to list customers: %w", err)
 	}
-	println("DEBUG REPO: tenantID extracted successfully:", tenantID)
+	defer rows.Close()
 
-	// Build WHERE clause
-	var whereConditions []string
-	var args []interface{}
-	argCount := 0
+	var customers []*model.Customer
+	var keys [][]string
+	for rows.Next() {
+		var stats joinedCustomerStats
+		key, keyDest := order.keyDest()
+		customer, err := scanCustomer(scanWithExtra(rows, append(stats.dest(), keyDest...)...))
+		if err != nil {
+			return nil, page, fmt.Errorf("failed to scan customer: %w", err)
+		}
+		customer.Stats = stats.stats(customer.TenantID)
+		customers = append(customers, customer)
+		keys = append(keys, key)
+	}
 
-	if filter.Search != "" {
-		argCount++
-		whereConditions = append(whereConditions, fmt.Sprintf(
-			"(first_name ILIKE $%d OR last_name ILIKE $%d OR email ILIKE $%d OR company_name ILIKE $%d)",
-			argCount, argCount, argCount, argCount))
-		args = append(args, "%"+filter.Search+"%")
+	if err = rows.Err(); err != nil {
+		return nil, page, fmt.Errorf("failed to iterate over customers: %w", err)
 	}
 
-	if filter.CustomerType != "" {
-		argCount++
-		whereConditions = append(whereConditions, fmt.Sprintf("customer_type = $%d", argCount))
-		args = append(args, filter.CustomerType)
+	page.Next = nextCursor(keys, limit)
+	if page.Next != nil {
+		customers = customers[:limit]
 	}
 
-	if filter.ActiveOnly {
-		whereConditions = append(whereConditions, "is_active = true")
+	return customers, page, nil
+}
+
+// customerListFrom joins the stats of each customer to the listing (NULL without stats)
+const customerListFrom = "FROM customers c LEFT JOIN customer_stats cs ON cs.customer_id = c.id"
+
+// customerListOrder returns the order of a customer listing: the trash by deletion date, by
+// relevance when searching without an explicit sort, by the requested sort, or newest first. Every order ends in the
+// id, so it is total and pages can continue after the last key.
+func customerListOrder(filter model.CustomerFilter, relevance string) keysetOrder {
+	id := func(desc bool) keysetColumn {
+		return keysetColumn{expr: "c.id", cast: "uuid", desc: desc}
+	}
+	createdAt := func(desc bool) keysetColumn {
+		return keysetColumn{expr: "c.created_at", cast: "timestamptz", desc: desc}
 	}
 
-	whereClause := ""
-	if len(whereConditions) > 0 {
-		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	if filter.Deleted && filter.SortBy == "" {
+		// La papelera lista primero lo eliminado más recientemente
+		return keysetOrder{{expr: "c.deleted_at", cast: "timestamptz", desc: true}, id(true)}
 	}
 
-	// Build ORDER BY clause
-	orderBy := "ORDER BY created_at DESC"
-	if filter.SortBy != "" {
-		direction := "ASC"
-		if filter.SortOrder == "desc" {
-			direction = "DESC"
-		}
+	if relevance != "" && (filter.SortBy == "" || filter.SortBy == "relevance") {
+		// El cursor guarda la relevancia como real, el tipo con el que se compara
+		return keysetOrder{{expr: "(" + relevance + ")::real", cast: "real", desc: true}, createdAt(true), id(true)}
+	}
 
-		switch filter.SortBy {
-		case "name":
-			orderBy = fmt.Sprintf("ORDER BY first_name %s, last_name %s", direction, direction)
-		case "created_at":
-			orderBy = fmt.Sprintf("ORDER BY created_at %s", direction)
-		case "company_name":
-			orderBy = fmt.Sprintf("ORDER BY company_name %s", direction)
+	desc := filter.SortOrder == "desc"
+	switch filter.SortBy {
+	case "name":
+		return keysetOrder{
+			{expr: "c.first_name", cast: "text", desc: desc},
+			{expr: "c.last_name", cast: "text", desc: desc},
+			id(desc),
 		}
+	case "created_at":
+		return keysetOrder{createdAt(desc), id(desc)}
+	case "company_name":
+		return keysetOrder{{expr: "coalesce(c.company_name, '')", cast: "text", desc: desc}, id(desc)}
+	case "last_visit":
+		// Sin visitas (o sin estadísticas) cuenta como la visita más antigua
+		return keysetOrder{{expr: "coalesce(cs.last_visit, '-infinity')", cast: "timestamptz", desc: desc}, id(desc)}
+	case "total_spent":
+		return keysetOrder{{expr: "coalesce(cs.total_spent, 0)", cast: "numeric", desc: desc}, id(desc)}
 	}
 
-	// Count total records
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM customers %s", whereClause)
-	println("DEBUG REPO: countQuery =", countQuery)
-	println("DEBUG REPO: args =", fmt.Sprintf("%v", args))
-	var total int
-	err = r.db.QueryRowWithTenant(ctx, tenantID, countQuery, args...).Scan(&total)
+	return keysetOrder{createdAt(true), id(true)}
+}
+
+// Search performs a ranked search on customers: every term matches as a word prefix
+// (search_vector) or the whole query matches a word with typos (pg_trgm word similarity over
+// search_text). It returns the page of results, best first, and the total number of matches.
+func (r *customerRepository) Search(ctx context.Context, filter model.CustomerSearchFilter) ([]*model.CustomerSearchResult, int, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
 	if err != nil {
-		println("DEBUG REPO: QueryRowWithTenant ERROR:", err.Error())
-		return nil, 0, fmt.Errorf("failed to count customers: %w", err)
+		return nil, 0, err
+	}
+
+	terms := model.SearchTerms(filter.Query)
+	if len(terms) == 0 {
+		return []*model.CustomerSearchResult{}, 0, nil
+	}
+
+	match, score := customerSearchExpressions(filter.SearchFields, 1, 2)
+	args := []interface{}{searchTSQuery(terms), strings.Join(terms, " ")}
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM customers WHERE %s AND is_active = true AND deleted_at IS NULL", match)
+	if err := r.db.QueryRowWithTenant(ctx, tenantID, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
 	}
 
-	// Build pagination
 	limit := filter.Limit
 	if limit <= 0 {
-		limit = 50 // Default limit
+		limit = model.DefaultSearchLimit
 	}
 	offset := 0
 	if filter.Page > 0 {
 		offset = (filter.Page - 1) * limit
 	}
 
-	// Main query
 	query := fmt.Sprintf(`
-		SELECT id, tenant_id, first_name, last_name, email, phone,
-			   customer_type, company_name, tax_id, address, birthday,
-			   notes, preferences, is_active, created_at, updated_at
-		FROM customers 
-		%s %s
-		LIMIT %d OFFSET %d`, whereClause, orderBy, limit, offset)
+		SELECT %s, %s AS score
+		FROM customers
+		WHERE %s AND is_active = true AND deleted_at IS NULL
+		ORDER BY score DESC, last_name, first_name, id
+		LIMIT %d OFFSET %d`, customerColumns, score, match, limit, offset)
 
 	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list customers: %w", err)
+		return nil, 0, fmt.Errorf("failed to search customers: %w", err)
 	}
 	defer rows.Close()
 
-	var customers []*model.Customer
+	results := []*model.CustomerSearchResult{}
 	for rows.Next() {
-		customer := &model.Customer{}
-		var email, phone, companyName, taxID, address, notes sql.NullString
-		var birthday sql.NullTime
-
-		err := rows.Scan(
-			&customer.ID,
-			&customer.TenantID,
-			&customer.FirstName,
-			&customer.LastName,
-			&email,
-			&phone,
-			&customer.CustomerType,
-			&companyName,
-			&taxID,
-			&address,
-			&birthday,
-			&notes,
-			&customer.Preferences,
-			&customer.IsActive,
-			&customer.CreatedAt,
-			&customer.UpdatedAt,
-		)
+		result := &model.CustomerSearchResult{}
+		result.Customer, err = scanCustomer(scanWithExtra(rows, &result.Score))
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan customer: %w", err)
 		}
-
-		// Convert nullable fields
-		customer.Email = StringFromNull(email)
-		customer.Phone = StringFromNull(phone)
-		customer.CompanyName = StringFromNull(companyName)
-		customer.TaxID = StringFromNull(taxID)
-		customer.Address = StringFromNull(address)
-		customer.Notes = StringFromNull(notes)
-		customer.Birthday = TimeFromNull(birthday)
-
-		customers = append(customers, customer)
+		results = append(results, result)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("failed to iterate over customers: %w", err)
+		return nil, 0, fmt.Errorf("failed to iterate over search results: %w", err)
 	}
 
-	return customers, total, nil
+	return results, total, nil
 }
 
-// Search performs advanced search on customers
-func (r *customerRepository) Search(ctx context.Context, filter model.CustomerSearchFilter) ([]*model.Customer, error) {
-	tenantID, err := GetTenantIDFromContext(ctx)
-	if err != nil {
-		return nil, err
-	}
+// customerSearchFields are the SQL expressions of each searchable field. search_vector and
+// search_text (migration 0008) are built from all of them; a search restricted to some
+// fields builds them on the fly from the same expressions.
+var customerSearchFields = map[string]struct {
+	vector string // tsvector ponderado
+	text   string // texto para trigramas
+}{
+	model.SearchFieldName: {
+		vector: "setweight(to_tsvector('simple', coalesce(app_normalize_name(first_name || ' ' || last_name), '')), 'A')",
+		text:   "first_name || ' ' || last_name",
+	},
+	model.SearchFieldCompanyName: {
+		vector: "setweight(to_tsvector('simple', coalesce(app_normalize_name(company_name), '')), 'A')",
+		text:   "coalesce(company_name, '')",
+	},
+	model.SearchFieldEmail: {
+		vector: "setweight(to_tsvector('simple', coalesce(app_normalize_name(email), '')), 'B')",
+		text:   "coalesce(email, '')",
+	},
+	model.SearchFieldTaxID: {
+		vector: "setweight(to_tsvector('simple', coalesce(app_normalize_tax_id(tax_id), '') || ' ' || coalesce(app_normalize_name(tax_id), '')), 'B')",
+		text:   "coalesce(tax_id, '')",
+	},
+	model.SearchFieldPhone: {
+		vector: "setweight(to_tsvector('simple', regexp_replace(coalesce(phone, ''), '[^0-9]', '', 'g')), 'C')",
+		text:   "coalesce(phone, '')",
+	},
+}
 
-	if filter.Query == "" {
-		return []*model.Customer{}, nil
+// customerSearchExpressions returns the match condition and the relevance (0-1) of a search
+// over fields (all when empty), whose tsquery and normalized query are the arguments
+// $tsQueryArg and $termArg
+func customerSearchExpressions(fields []string, tsQueryArg, termArg int) (match, score string) {
+	vector, text := "search_vector", "search_text"
+	if len(fields) > 0 && len(fields) < len(customerSearchFields) {
+		vectors := make([]string, 0, len(fields))
+		texts := make([]string, 0, len(fields))
+		for _, field := range fields {
+			if expr, ok := customerSearchFields[field]; ok {
+				vectors = append(vectors, expr.vector)
+				texts = append(texts, expr.text)
+			}
+		}
+		vector = "(" + strings.Join(vectors, " || ") + ")"
+		text = "app_normalize_name(" + strings.Join(texts, " || ' ' || ") + ")"
 	}
 
-	// Build search conditions based on search fields
-	var searchConditions []string
-	searchFields := filter.SearchFields
-	if len(searchFields) == 0 {
-		// Default search fields
-		searchFields = []string{"name", "email", "phone", "tax_id"}
-	}
+	tsQuery := fmt.Sprintf("to_tsquery('simple', $%d)", tsQueryArg)
+	match = fmt.Sprintf("(%s @@ %s OR $%d <%% %s)", vector, tsQuery, termArg, text)
+	score = fmt.Sprintf("least(1, ts_rank_cd(%s, %s, 32) + word_similarity($%d, %s))", vector, tsQuery, termArg, text)
+	return match, score
+}
 
-	for _, field := range searchFields {
-		switch field {
-		case "name":
-			searchConditions = append(searchConditions,
-				"(first_name ILIKE $1 OR last_name ILIKE $1 OR (first_name || ' ' || last_name) ILIKE $1)")
-		case "email":
-			searchConditions = append(searchConditions, "email ILIKE $1")
-		case "phone":
-			searchConditions = append(searchConditions, "phone ILIKE $1")
-		case "tax_id":
-			searchConditions = append(searchConditions, "tax_id ILIKE $1")
-		case "company_name":
-			searchConditions = append(searchConditions, "company_name ILIKE $1")
-		}
+// searchTSQuery builds a tsquery where every term matches as a word prefix. The terms come
+// from model.SearchTerms (letters and digits only), so they need no escaping.
+func searchTSQuery(terms []string) string {
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = term + ":*"
 	}
+	return strings.Join(prefixes, " & ")
+}
 
-	if len(searchConditions) == 0 {
-		return []*model.Customer{}, nil
+// qualifiedColumns prefixes every column of a select list with a table alias
+func qualifiedColumns(alias, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, column := range parts {
+		parts[i] = alias + "." + strings.TrimSpace(column)
 	}
+	return strings.Join(parts, ", ")
+}
 
-	limit := filter.Limit
-	if limit <= 0 {
-		limit = 20 // Default limit for search
+// scanWithExtra returns a scanner that reads the row into the destinations of the caller
+// followed by extra columns selected after them
+func scanWithExtra(row rowScanner, extra ...interface{}) rowScanner {
+	return scanFunc(func(dest ...interface{}) error {
+		return row.Scan(append(dest, extra...)...)
+	})
+}
+
+// scanFunc adapts a function to rowScanner
+type scanFunc func(dest ...interface{}) error
+
+func (f scanFunc) Scan(dest ...interface{}) error {
+	return f(dest...)
+}
+
+// FindDuplicateCandidates preselects the customers that may duplicate the probe: same
+// normalized tax ID, email local part or phone, or a similar name (pg_trgm). The scoring is
+// done by the caller with model.RankDuplicates.
+func (r *customerRepository) FindDuplicateCandidates(ctx context.Context, probe model.DuplicateProbe, limit int) ([]*model.Customer, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	query := fmt.Sprintf(`
-		SELECT id, tenant_id, first_name, last_name, email, phone,
-			   customer_type, company_name, tax_id, address, birthday,
-			   notes, preferences, is_active, created_at, updated_at
-		FROM customers 
-		WHERE (%s) AND is_active = true
-		ORDER BY 
-			CASE 
-				WHEN first_name ILIKE $1 OR last_name ILIKE $1 THEN 1
-				WHEN email = $2 THEN 2
-				WHEN phone = $2 THEN 3
-				ELSE 4
-			END,
-			first_name, last_name
-		LIMIT %d`, strings.Join(searchConditions, " OR "), limit)
-
-	searchTerm := "%" + filter.Query + "%"
-	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, searchTerm, filter.Query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search customers: %w", err)
+	// Los valores vacíos desactivan su condición; se normalizan igual que las funciones app_*
+	query := `
+		SELECT ` + customerColumns + `
+		FROM customers
+		WHERE id::text <> $1 AND deleted_at IS NULL
+		  AND (
+			($2 <> '' AND app_normalize_tax_id(tax_id) = $2)
+			OR ($3 <> '' AND app_email_local_part(email) = $3)
+			OR ($4 <> '' AND app_phone_key(phone) = $4)
+			OR ($5 <> '' AND app_normalize_name(first_name || ' ' || last_name) % $5)
+			OR ($6 <> '' AND app_normalize_name(company_name) % $6)
+		  )
+		ORDER BY similarity(app_normalize_name(first_name || ' ' || last_name), $5) DESC, created_at
+		LIMIT $7`
+
+	rows, err := r.db.QueryWithTenant(ctx, tenantID, query,
+		probe.ExcludeID,
+		model.NormalizeTaxID(probe.TaxID),
+		model.EmailLocalPart(probe.Email),
+		model.PhoneKey(probe.Phone),
+		probe.FullName(),
+		model.NormalizeName(probe.CompanyName),
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate candidates: %w", err)
 	}
 	defer rows.Close()
 
 	var customers []*model.Customer
 	for rows.Next() {
-		customer := &model.Customer{}
-		var email, phone, companyName, taxID, address, notes sql.NullString
-		var birthday sql.NullTime
-
-		err := rows.Scan(
-			&customer.ID,
-			&customer.TenantID,
-			&customer.FirstName,
-			&customer.LastName,
-			&email,
-			&phone,
-			&customer.CustomerType,
-			&companyName,
-			&taxID,
-			&address,
-			&birthday,
-			&notes,
-			&customer.Preferences,
-			&customer.IsActive,
-			&customer.CreatedAt,
-			&customer.UpdatedAt,
-		)
+		customer, err := scanCustomer(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan customer: %w", err)
 		}
-
-		// Convert nullable fields
-		customer.Email = StringFromNull(email)
-		customer.Phone = StringFromNull(phone)
-		customer.CompanyName = StringFromNull(companyName)
-		customer.TaxID = StringFromNull(taxID)
-		customer.Address = StringFromNull(address)
-		customer.Notes = StringFromNull(notes)
-		customer.Birthday = TimeFromNull(birthday)
-
 		customers = append(customers, customer)
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over duplicate candidates: %w", err)
+	}
+
 	return customers, nil
 }
 
@@ -457,52 +872,16 @@ func (r *customerRepository) GetByEmail(ctx context.Context, email string) (*mod
 		return nil, err
 	}
 
-	query := `
-		SELECT id, tenant_id, first_name, last_name, email, phone,
-			   customer_type, company_name, tax_id, address, birthday,
-			   notes, preferences, is_active, created_at, updated_at
-		FROM customers 
-		WHERE email = $1`
-
-	customer := &model.Customer{}
-	var emailNull, phone, companyName, taxID, address, notes sql.NullString
-	var birthday sql.NullTime
-
-	err = r.db.QueryRowWithTenant(ctx, tenantID, query, email).Scan(
-		&customer.ID,
-		&customer.TenantID,
-		&customer.FirstName,
-		&customer.LastName,
-		&emailNull,
-		&phone,
-		&customer.CustomerType,
-		&companyName,
-		&taxID,
-		&address,
-		&birthday,
-		&notes,
-		&customer.Preferences,
-		&customer.IsActive,
-		&customer.CreatedAt,
-		&customer.UpdatedAt,
-	)
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE email = $1 AND tenant_id = $2 AND deleted_at IS NULL`
 
+	customer, err := scanCustomer(r.db.QueryRowWithTenant(ctx, tenantID, query, email, tenantID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("customer with email %s not found", email)
+			return nil, &model.NotFoundError{Resource: "customer", Key: "email", Value: email}
 		}
 		return nil, fmt.Errorf("failed to get customer by email: %w", err)
 	}
 
-	// Convert nullable fields
-	customer.Email = StringFromNull(emailNull)
-	customer.Phone = StringFromNull(phone)
-	customer.CompanyName = StringFromNull(companyName)
-	customer.TaxID = StringFromNull(taxID)
-	customer.Address = StringFromNull(address)
-	customer.Notes = StringFromNull(notes)
-	customer.Birthday = TimeFromNull(birthday)
-
 	return customer, nil
 }
 
@@ -513,52 +892,16 @@ func (r *customerRepository) GetByTaxID(ctx context.Context, taxID string) (*mod
 		return nil, err
 	}
 
-	query := `
-		SELECT id, tenant_id, first_name, last_name, email, phone,
-			   customer_type, company_name, tax_id, address, birthday,
-			   notes, preferences, is_active, created_at, updated_at
-		FROM customers 
-		WHERE tax_id = $1`
-
-	customer := &model.Customer{}
-	var email, phone, companyName, taxIDNull, address, notes sql.NullString
-	var birthday sql.NullTime
-
-	err = r.db.QueryRowWithTenant(ctx, tenantID, query, taxID).Scan(
-		&customer.ID,
-		&customer.TenantID,
-		&customer.FirstName,
-		&customer.LastName,
-		&email,
-		&phone,
-		&customer.CustomerType,
-		&companyName,
-		&taxIDNull,
-		&address,
-		&birthday,
-		&notes,
-		&customer.Preferences,
-		&customer.IsActive,
-		&customer.CreatedAt,
-		&customer.UpdatedAt,
-	)
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE tax_id = $1 AND tenant_id = $2 AND deleted_at IS NULL`
 
+	customer, err := scanCustomer(r.db.QueryRowWithTenant(ctx, tenantID, query, taxID, tenantID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("customer with tax ID %s not found", taxID)
+			return nil, &model.NotFoundError{Resource: "customer", Key: "tax_id", Value: taxID}
 		}
 		return nil, fmt.Errorf("failed to get customer by tax ID: %w", err)
 	}
 
-	// Convert nullable fields
-	customer.Email = StringFromNull(email)
-	customer.Phone = StringFromNull(phone)
-	customer.CompanyName = StringFromNull(companyName)
-	customer.TaxID = StringFromNull(taxIDNull)
-	customer.Address = StringFromNull(address)
-	customer.Notes = StringFromNull(notes)
-	customer.Birthday = TimeFromNull(birthday)
-
 	return customer, nil
 }
 
@@ -569,7 +912,8 @@ func (r *customerRepository) ListByType(ctx context.Context, customerType string
 		Page:         page,
 		Limit:        limit,
 	}
-	return r.List(ctx, filter)
+	customers, pageInfo, err := r.List(ctx, filter)
+	return customers, pageInfo.Total, err
 }
 
 // ListActive retrieves active customers with pagination
@@ -579,7 +923,8 @@ func (r *customerRepository) ListActive(ctx context.Context, page, limit int) ([
 		Page:       page,
 		Limit:      limit,
 	}
-	return r.List(ctx, filter)
+	customers, pageInfo, err := r.List(ctx, filter)
+	return customers, pageInfo.Total, err
 }
 
 // ListInactive retrieves inactive customers with pagination
@@ -592,7 +937,7 @@ func (r *customerRepository) ListInactive(ctx context.Context, page, limit int)
 	// Count total inactive customers
 	var total int
 	err = r.db.QueryRowWithTenant(ctx, tenantID,
-		"SELECT COUNT(*) FROM customers WHERE is_active = false").Scan(&total)
+		"SELECT COUNT(*) FROM customers WHERE is_active = false AND deleted_at IS NULL").Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count inactive customers: %w", err)
 	}
@@ -602,16 +947,13 @@ func (r *customerRepository) ListInactive(ctx context.Context, page, limit int)
 		offset = (page - 1) * limit
 	}
 
-	query := `
-		SELECT id, tenant_id, first_name, last_name, email, phone,
-			   customer_type, company_name, tax_id, address, birthday,
-			   notes, preferences, is_active, created_at, updated_at
-		FROM customers 
-		WHERE is_active = false
+	query := `SELECT ` + customerColumns + `
+		FROM customers
+		WHERE is_active = false AND tenant_id = $3 AND deleted_at IS NULL
 		ORDER BY updated_at DESC
 		LIMIT $1 OFFSET $2`
 
-	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, limit, offset)
+	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, limit, offset, tenantID)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to list inactive customers: %w", err)
 	}
@@ -619,43 +961,15 @@ func (r *customerRepository) ListInactive(ctx context.Context, page, limit int)
 
 	var customers []*model.Customer
 	for rows.Next() {
-		customer := &model.Customer{}
-		var email, phone, companyName, taxID, address, notes sql.NullString
-		var birthday sql.NullTime
-
-		err := rows.Scan(
-			&customer.ID,
-			&customer.TenantID,
-			&customer.FirstName,
-			&customer.LastName,
-			&email,
-			&phone,
-			&customer.CustomerType,
-			&companyName,
-			&taxID,
-			&address,
-			&birthday,
-			&notes,
-			&customer.Preferences,
-			&customer.IsActive,
-			&customer.CreatedAt,
-			&customer.UpdatedAt,
-		)
+		customer, err := scanCustomer(rows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan customer: %w", err)
 		}
-
-		// Convert nullable fields
-		customer.Email = StringFromNull(email)
-		customer.Phone = StringFromNull(phone)
-		customer.CompanyName = StringFromNull(companyName)
-		customer.TaxID = StringFromNull(taxID)
-		customer.Address = StringFromNull(address)
-		customer.Notes = StringFromNull(notes)
-		customer.Birthday = TimeFromNull(birthday)
-
 		customers = append(customers, customer)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate over inactive customers: %w", err)
+	}
 
 	return customers, total, nil
 }
@@ -669,7 +983,7 @@ func (r *customerRepository) Count(ctx context.Context) (int64, error) {
 
 	var count int64
 	err = r.db.QueryRowWithTenant(ctx, tenantID,
-		"SELECT COUNT(*) FROM customers").Scan(&count)
+		"SELECT COUNT(*) FROM customers WHERE deleted_at IS NULL").Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count customers: %w", err)
 	}
@@ -686,7 +1000,7 @@ func (r *customerRepository) CountByType(ctx context.Context, customerType strin
 
 	var count int64
 	err = r.db.QueryRowWithTenant(ctx, tenantID,
-		"SELECT COUNT(*) FROM customers WHERE customer_type = $1", customerType).Scan(&count)
+		"SELECT COUNT(*) FROM customers WHERE customer_type = $1 AND deleted_at IS NULL", customerType).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count customers by type: %w", err)
 	}
@@ -703,7 +1017,7 @@ func (r *customerRepository) CountActive(ctx context.Context) (int64, error) {
 
 	var count int64
 	err = r.db.QueryRowWithTenant(ctx, tenantID,
-		"SELECT COUNT(*) FROM customers WHERE is_active = true").Scan(&count)
+		"SELECT COUNT(*) FROM customers WHERE is_active = true AND deleted_at IS NULL").Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count active customers: %w", err)
 	}
@@ -718,7 +1032,7 @@ func (r *customerRepository) ExistsByEmail(ctx context.Context, email string, ex
 		return false, err
 	}
 
-	query := "SELECT COUNT(*) FROM customers WHERE email = $1"
+	query := "SELECT COUNT(*) FROM customers WHERE email = $1 AND deleted_at IS NULL"
 	args := []interface{}{email}
 
 	if excludeID != nil {
@@ -742,7 +1056,7 @@ func (r *customerRepository) ExistsByTaxID(ctx context.Context, taxID string, ex
 		return false, err
 	}
 
-	query := "SELECT COUNT(*) FROM customers WHERE tax_id = $1"
+	query := "SELECT COUNT(*) FROM customers WHERE tax_id = $1 AND deleted_at IS NULL"
 	args := []interface{}{taxID}
 
 	if excludeID != nil {
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/tenancy"
+)
+
+// tenantContext returns a context carrying the typed tenant built by TenantInterceptor
+func tenantContext(t *testing.T, tenantID string) context.Context {
+	t.Helper()
+	tenant, err := tenancy.New(tenantID, "autoparts", "es-MX", "America/Mexico_City")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tenancy.WithTenant(context.Background(), tenant)
+}
+
+// TestTypedTenantReachesRepositories checks that the repositories scope every call to the
+// tenant of the typed tenancy.Tenant in the context
+func TestTypedTenantReachesRepositories(t *testing.T) {
+	db := openTestDB(t)
+	tenantA, tenantB := newTestTenantID(t), newTestTenantID(t)
+	seedCustomers(t, db, tenantA, 2)
+	seedCustomers(t, db, tenantB, 3)
+
+	customers := NewCustomerRepository(db)
+	ctxA, ctxB := tenantContext(t, tenantA), tenantContext(t, tenantB)
+
+	for _, tt := range []struct {
+		ctx  context.Context
+		want int64
+	}{{ctxA, 2}, {ctxB, 3}} {
+		count, err := customers.Count(tt.ctx)
+		if err != nil {
+			t.Fatalf("Count: %v", err)
+		}
+		if count != tt.want {
+			t.Errorf("expected %d customers, got %d", tt.want, count)
+		}
+	}
+
+	customer := model.NewCustomer(model.CustomerCreate{FirstName: "Ana", LastName: "García", CustomerType: model.CustomerTypeIndividual})
+	if err := customers.Create(ctxB, customer); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if customer.TenantID != tenantB {
+		t.Errorf("expected the customer of tenant %s, got %s", tenantB, customer.TenantID)
+	}
+
+	if _, err := customers.GetByID(ctxB, customer.ID); err != nil {
+		t.Errorf("GetByID of the own tenant: %v", err)
+	}
+	if _, err := customers.GetByID(ctxA, customer.ID); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("expected NotFound from another tenant, got %v", err)
+	}
+
+	if _, err := customers.Count(context.Background()); !errors.Is(err, tenancy.ErrNoTenant) {
+		t.Errorf("expected ErrNoTenant without a tenant, got %v", err)
+	}
+}
+
+// TestCustomerWritesOfAnotherTenant checks that the versioned writes of a customer from
+// another tenant report NotFound and leave the row as it was
+func TestCustomerWritesOfAnotherTenant(t *testing.T) {
+	db := openTestDB(t)
+	tenantA, tenantB := newTestTenantID(t), newTestTenantID(t)
+	seedCustomers(t, db, tenantA, 0)
+	seedCustomers(t, db, tenantB, 0)
+
+	customers := NewCustomerRepository(db)
+	ctxA, ctxB := tenantContext(t, tenantA), tenantContext(t, tenantB)
+
+	customer := model.NewCustomer(model.CustomerCreate{FirstName: "Ana", LastName: "García", CustomerType: model.CustomerTypeIndividual})
+	if err := customers.Create(ctxB, customer); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	version := customer.Version
+
+	writes := map[string]func() error{
+		"Update": func() error {
+			changed := *customer
+			changed.FirstName = "Otra"
+			return customers.Update(ctxA, &changed)
+		},
+		"SoftDelete": func() error {
+			_, err := customers.SoftDelete(ctxA, customer.ID, version, time.Now())
+			return err
+		},
+		"Restore": func() error {
+			_, err := customers.Restore(ctxA, customer.ID, version)
+			return err
+		},
+		"Delete": func() error { return customers.Delete(ctxA, customer.ID, version) },
+	}
+	for name, write := range writes {
+		if err := write(); !errors.Is(err, model.ErrNotFound) {
+			t.Errorf("%s: expected NotFound from another tenant, got %v", name, err)
+		}
+	}
+
+	listed, _, err := customers.List(ctxA, model.CustomerFilter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(listed) != 0 {
+		t.Errorf("expected no customers of tenant %s, got %d", tenantA, len(listed))
+	}
+
+	stored, err := customers.GetByID(ctxB, customer.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if stored.Version != version || stored.FirstName != "Ana" {
+		t.Errorf("expected the customer unchanged at version %d, got %s at version %d", version, stored.FirstName, stored.Version)
+	}
+}
+
+// TestPurgeDeletedOnlyPurgesTheTenant checks that the purge of a tenant leaves the customers
+// in the trash of other tenants
+func TestPurgeDeletedOnlyPurgesTheTenant(t *testing.T) {
+	db := openTestDB(t)
+	tenantA, tenantB := newTestTenantID(t), newTestTenantID(t)
+	seedCustomers(t, db, tenantA, 0)
+	seedCustomers(t, db, tenantB, 0)
+
+	customers := NewCustomerRepository(db)
+	ctxA, ctxB := tenantContext(t, tenantA), tenantContext(t, tenantB)
+	deletedAt := time.Now().Add(-time.Hour)
+
+	trash := func(ctx context.Context) string {
+		customer := model.NewCustomer(model.CustomerCreate{FirstName: "Ana", LastName: "García", CustomerType: model.CustomerTypeIndividual})
+		if err := customers.Create(ctx, customer); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if _, err := customers.SoftDelete(ctx, customer.ID, customer.Version, deletedAt); err != nil {
+			t.Fatalf("SoftDelete: %v", err)
+		}
+		return customer.ID
+	}
+	idA, idB := trash(ctxA), trash(ctxB)
+
+	purged, err := customers.PurgeDeleted(ctxA, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("PurgeDeleted: %v", err)
+	}
+	if len(purged) != 1 || purged[0] != idA {
+		t.Errorf("expected only %s purged, got %v", idA, purged)
+	}
+
+	if _, err := customers.GetDeletedByID(ctxB, idB); err != nil {
+		t.Errorf("expected the customer of the other tenant still in the trash: %v", err)
+	}
+}
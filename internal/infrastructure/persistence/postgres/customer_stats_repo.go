@@ -0,0 +1,587 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+)
+
+// statsOutdatedAfter matches model.CustomerStats.IsStatsOutdated
+const statsOutdatedAfter = "24 hours"
+
+// customerStatsColumns lists the customer_stats columns in scan order
+const customerStatsColumns = `
+	cs.customer_id, cs.tenant_id, cs.total_orders, cs.total_spent,
+	cs.average_order_value, cs.last_visit, cs.visits_count,
+	cs.favorite_category, cs.favorite_products, cs.calculated_at`
+
+// customerStatsUpdateQuery overwrites the stats row of a customer; shared with event ingestion
+const customerStatsUpdateQuery = `
+	UPDATE customer_stats SET
+		total_orders = $3, total_spent = $4, average_order_value = $5,
+		last_visit = $6, visits_count = $7, favorite_category = $8,
+		favorite_products = $9, calculated_at = $10
+	WHERE customer_id = $1 AND tenant_id = $2`
+
+type customerStatsRepository struct {
+	db *DB
+}
+
+// NewCustomerStatsRepository creates a new customer stats repository
+func NewCustomerStatsRepository(db *DB) repository.CustomerStatsRepository {
+	return &customerStatsRepository{
+		db: db,
+	}
+}
+
+// Create creates the stats row for a customer
+func (r *customerStatsRepository) Create(ctx context.Context, stats *model.CustomerStats) error {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := stats.Validate(); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO customer_stats (
+			customer_id, tenant_id, total_orders, total_spent,
+			average_order_value, last_visit, visits_count,
+			favorite_category, favorite_products, calculated_at
+		)
+		SELECT c.id, c.tenant_id, $3, $4, $5, $6, $7, $8, $9, $10
+		FROM customers c
+		WHERE c.id = $1 AND c.tenant_id = $2
+		RETURNING calculated_at`
+
+	err = r.db.QueryRowWithTenant(ctx, tenantID, query,
+		stats.CustomerID,
+		tenantID,
+		stats.TotalOrders,
+		stats.TotalSpent,
+		stats.AverageOrderValue,
+		nullLastVisit(stats.LastVisit),
+		stats.VisitsCount,
+		stats.FavoriteCategory,
+		pq.Array(stats.FavoriteProducts),
+		stats.CalculatedAt,
+	).Scan(&stats.CalculatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.NewNotFoundError("customer", stats.CustomerID)
+		}
+		return fmt.Errorf("failed to create customer stats: %w", err)
+	}
+
+	stats.TenantID = tenantID
+	return nil
+}
+
+// GetByCustomerID retrieves the stats of a customer
+func (r *customerStatsRepository) GetByCustomerID(ctx context.Context, customerID string) (*model.CustomerStats, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM customer_stats cs
+		WHERE cs.customer_id = $1 AND cs.tenant_id = $2`, customerStatsColumns)
+
+	stats, err := scanCustomerStats(r.db.QueryRowWithTenant(ctx, tenantID, query, customerID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &model.NotFoundError{Resource: "customer_stats", Key: "customer_id", Value: customerID}
+		}
+		return nil, fmt.Errorf("failed to get customer stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Update updates the stats of a customer
+func (r *customerStatsRepository) Update(ctx context.Context, stats *model.CustomerStats) error {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := stats.Validate(); err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecWithTenant(ctx, tenantID, customerStatsUpdateQuery, customerStatsUpdateArgs(tenantID, stats)...)
+	if err != nil {
+		return fmt.Errorf("failed to update customer stats: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &model.NotFoundError{Resource: "customer_stats", Key: "customer_id", Value: stats.CustomerID}
+	}
+
+	return nil
+}
+
+// Delete deletes the stats of a customer
+func (r *customerStatsRepository) Delete(ctx context.Context, customerID string) error {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecWithTenant(ctx, tenantID,
+		"DELETE FROM customer_stats WHERE customer_id = $1 AND tenant_id = $2", customerID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete customer stats: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &model.NotFoundError{Resource: "customer_stats", Key: "customer_id", Value: customerID}
+	}
+
+	return nil
+}
+
+// CalculateAndSave recalculates the derived values of a customer's stats and persists them,
+// creating an empty stats row the first time the customer is seen
+func (r *customerStatsRepository) CalculateAndSave(ctx context.Context, customerID string) (*model.CustomerStats, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO customer_stats AS cs (customer_id, tenant_id, calculated_at)
+		SELECT c.id, c.tenant_id, NOW()
+		FROM customers c
+		WHERE c.id = $1 AND c.tenant_id = $2
+		ON CONFLICT (customer_id) DO UPDATE SET
+			average_order_value = CASE
+				WHEN cs.total_orders > 0 THEN cs.total_spent / cs.total_orders
+				ELSE 0
+			END,
+			calculated_at = NOW()
+		RETURNING %s`, customerStatsColumns)
+
+	stats, err := scanCustomerStats(r.db.QueryRowWithTenant(ctx, tenantID, query, customerID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewNotFoundError("customer", customerID)
+		}
+		return nil, fmt.Errorf("failed to calculate customer stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// RecalculateAll recalculates the stats of every customer of the tenant
+func (r *customerStatsRepository) RecalculateAll(ctx context.Context) error {
+	return r.recalculate(ctx, false)
+}
+
+// RecalculateOutdated recalculates the stats that have not been refreshed in the last 24 hours
+func (r *customerStatsRepository) RecalculateOutdated(ctx context.Context) error {
+	return r.recalculate(ctx, true)
+}
+
+// recalculate refreshes derived values and creates missing stats rows in a single transaction
+func (r *customerStatsRepository) recalculate(ctx context.Context, outdatedOnly bool) error {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	updateQuery := `
+		UPDATE customer_stats SET
+			average_order_value = CASE
+				WHEN total_orders > 0 THEN total_spent / total_orders
+				ELSE 0
+			END,
+			calculated_at = NOW()
+		WHERE tenant_id = $1`
+	if outdatedOnly {
+		updateQuery += fmt.Sprintf(" AND calculated_at < NOW() - INTERVAL '%s'", statsOutdatedAfter)
+	}
+
+	return r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, updateQuery, tenantID); err != nil {
+			return fmt.Errorf("failed to recalculate customer stats: %w", err)
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO customer_stats (customer_id, tenant_id, calculated_at)
+			SELECT c.id, c.tenant_id, NOW()
+			FROM customers c
+			WHERE c.tenant_id = $1
+			  AND NOT EXISTS (SELECT 1 FROM customer_stats cs WHERE cs.customer_id = c.id)`, tenantID)
+		if err != nil {
+			return fmt.Errorf("failed to create missing customer stats: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ListTopCustomersBySpent lists the customers with the highest total spent
+func (r *customerStatsRepository) ListTopCustomersBySpent(ctx context.Context, limit int) ([]*model.CustomerStats, error) {
+	return r.list(ctx, "", "cs.total_spent DESC", limit)
+}
+
+// ListTopCustomersByOrders lists the customers with the most orders
+func (r *customerStatsRepository) ListTopCustomersByOrders(ctx context.Context, limit int) ([]*model.CustomerStats, error) {
+	return r.list(ctx, "", "cs.total_orders DESC", limit)
+}
+
+// ListTopCustomersByFrequency lists the customers with the most visits
+func (r *customerStatsRepository) ListTopCustomersByFrequency(ctx context.Context, limit int) ([]*model.CustomerStats, error) {
+	return r.list(ctx, "", "cs.visits_count DESC, cs.last_visit DESC NULLS LAST", limit)
+}
+
+// ListByLevel lists the customers of a loyalty level (see model.CustomerStats.GetCustomerLevel)
+func (r *customerStatsRepository) ListByLevel(ctx context.Context, level string) ([]*model.CustomerStats, error) {
+	condition, err := customerLevelCondition(level)
+	if err != nil {
+		return nil, err
+	}
+	return r.list(ctx, condition, "cs.total_spent DESC", 0)
+}
+
+// ListVIPCustomers lists the customers of the VIP level
+func (r *customerStatsRepository) ListVIPCustomers(ctx context.Context) ([]*model.CustomerStats, error) {
+	return r.ListByLevel(ctx, model.CustomerLevelVIP)
+}
+
+// ListInactiveCustomers lists the customers without visits in the last daysSince days
+func (r *customerStatsRepository) ListInactiveCustomers(ctx context.Context, daysSince int) ([]*model.CustomerStats, error) {
+	condition := fmt.Sprintf("(cs.last_visit IS NULL OR cs.last_visit < NOW() - INTERVAL '%d days')", daysSince)
+	return r.list(ctx, condition, "cs.last_visit ASC NULLS FIRST", 0)
+}
+
+// ListFrequentCustomers lists the customers with 10 or more orders
+func (r *customerStatsRepository) ListFrequentCustomers(ctx context.Context) ([]*model.CustomerStats, error) {
+	return r.list(ctx, "cs.total_orders >= 10", "cs.total_orders DESC", 0)
+}
+
+// GetTotalStats returns aggregated stats for the tenant
+func (r *customerStatsRepository) GetTotalStats(ctx context.Context) (map[string]interface{}, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vipCondition, _ := customerLevelCondition(model.CustomerLevelVIP)
+	query := fmt.Sprintf(`
+		SELECT COUNT(*),
+			   COALESCE(SUM(cs.total_orders), 0),
+			   COALESCE(SUM(cs.total_spent), 0),
+			   COALESCE(SUM(cs.total_spent) / NULLIF(SUM(cs.total_orders), 0), 0),
+			   COUNT(*) FILTER (WHERE %s),
+			   COUNT(*) FILTER (WHERE cs.total_orders >= 10),
+			   MAX(cs.last_visit)
+		FROM customer_stats cs
+		WHERE cs.tenant_id = $1`, vipCondition)
+
+	var customers, totalOrders, vipCustomers, frequentCustomers int64
+	var totalRevenue, averageOrderValue float64
+	var lastVisit sql.NullTime
+
+	err = r.db.QueryRowWithTenant(ctx, tenantID, query, tenantID).Scan(
+		&customers,
+		&totalOrders,
+		&totalRevenue,
+		&averageOrderValue,
+		&vipCustomers,
+		&frequentCustomers,
+		&lastVisit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total stats: %w", err)
+	}
+
+	return map[string]interface{}{
+		"customers_with_stats": customers,
+		"total_orders":         totalOrders,
+		"total_revenue":        totalRevenue,
+		"average_order_value":  averageOrderValue,
+		"vip_customers":        vipCustomers,
+		"frequent_customers":   frequentCustomers,
+		"last_visit":           TimeFromNull(lastVisit),
+		"calculated_at":        time.Now(),
+	}, nil
+}
+
+// GetAverageOrderValue returns the average order value across the tenant
+func (r *customerStatsRepository) GetAverageOrderValue(ctx context.Context) (float64, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var value float64
+	err = r.db.QueryRowWithTenant(ctx, tenantID, `
+		SELECT COALESCE(SUM(total_spent) / NULLIF(SUM(total_orders), 0), 0)
+		FROM customer_stats
+		WHERE tenant_id = $1`, tenantID).Scan(&value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get average order value: %w", err)
+	}
+
+	return value, nil
+}
+
+// GetTotalRevenue returns the total spent by all customers of the tenant
+func (r *customerStatsRepository) GetTotalRevenue(ctx context.Context) (float64, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var revenue float64
+	err = r.db.QueryRowWithTenant(ctx, tenantID,
+		"SELECT COALESCE(SUM(total_spent), 0) FROM customer_stats WHERE tenant_id = $1", tenantID).Scan(&revenue)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get total revenue: %w", err)
+	}
+
+	return revenue, nil
+}
+
+// Exists checks if a customer has stats
+func (r *customerStatsRepository) Exists(ctx context.Context, customerID string) (bool, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = r.db.QueryRowWithTenant(ctx, tenantID,
+		"SELECT EXISTS (SELECT 1 FROM customer_stats WHERE customer_id = $1 AND tenant_id = $2)",
+		customerID, tenantID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check customer stats existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetOutdatedStats lists the stats that have not been refreshed in the last 24 hours
+func (r *customerStatsRepository) GetOutdatedStats(ctx context.Context) ([]*model.CustomerStats, error) {
+	condition := fmt.Sprintf("cs.calculated_at < NOW() - INTERVAL '%s'", statsOutdatedAfter)
+	return r.list(ctx, condition, "cs.calculated_at ASC", 0)
+}
+
+// Count counts the customers with stats
+func (r *customerStatsRepository) Count(ctx context.Context) (int64, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = r.db.QueryRowWithTenant(ctx, tenantID,
+		"SELECT COUNT(*) FROM customer_stats WHERE tenant_id = $1", tenantID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count customer stats: %w", err)
+	}
+
+	return count, nil
+}
+
+// list runs a tenant-scoped stats query with an optional extra condition, order and limit
+func (r *customerStatsRepository) list(ctx context.Context, condition, orderBy string, limit int) ([]*model.CustomerStats, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Los clientes en la papelera no aparecen en los rankings
+	whereClause := "WHERE cs.tenant_id = $1 AND NOT EXISTS (SELECT 1 FROM customers c WHERE c.id = cs.customer_id AND c.deleted_at IS NOT NULL)"
+	if condition != "" {
+		whereClause += " AND " + condition
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM customer_stats cs
+		%s
+		ORDER BY %s, cs.customer_id`, customerStatsColumns, whereClause, orderBy)
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer stats: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.CustomerStats
+	for rows.Next() {
+		stats, err := scanCustomerStats(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan customer stats: %w", err)
+		}
+		result = append(result, stats)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over customer stats: %w", err)
+	}
+
+	return result, nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanCustomerStats scans a row selected with customerStatsColumns
+func scanCustomerStats(row rowScanner) (*model.CustomerStats, error) {
+	stats := &model.CustomerStats{}
+	var lastVisit sql.NullTime
+	var favoriteCategory sql.NullString
+
+	err := row.Scan(
+		&stats.CustomerID,
+		&stats.TenantID,
+		&stats.TotalOrders,
+		&stats.TotalSpent,
+		&stats.AverageOrderValue,
+		&lastVisit,
+		&stats.VisitsCount,
+		&favoriteCategory,
+		pq.Array(&stats.FavoriteProducts),
+		&stats.CalculatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastVisit.Valid {
+		stats.LastVisit = lastVisit.Time
+	}
+	stats.FavoriteCategory = favoriteCategory.String
+
+	return stats, nil
+}
+
+// joinedCustomerStatsColumns selects the stats of a customer_stats cs LEFT JOIN; every
+// column is NULL when the customer has no stats
+const joinedCustomerStatsColumns = `
+	cs.customer_id, cs.total_orders, cs.total_spent, cs.average_order_value,
+	cs.last_visit, cs.visits_count, cs.favorite_category, cs.favorite_products, cs.calculated_at`
+
+// joinedCustomerStats scans joinedCustomerStatsColumns
+type joinedCustomerStats struct {
+	customerID        sql.NullString
+	totalOrders       sql.NullInt32
+	totalSpent        sql.NullFloat64
+	averageOrderValue sql.NullFloat64
+	lastVisit         sql.NullTime
+	visitsCount       sql.NullInt32
+	favoriteCategory  sql.NullString
+	favoriteProducts  pq.StringArray
+	calculatedAt      sql.NullTime
+}
+
+// dest returns the scan destinations in the order of joinedCustomerStatsColumns
+func (j *joinedCustomerStats) dest() []interface{} {
+	return []interface{}{
+		&j.customerID,
+		&j.totalOrders,
+		&j.totalSpent,
+		&j.averageOrderValue,
+		&j.lastVisit,
+		&j.visitsCount,
+		&j.favoriteCategory,
+		&j.favoriteProducts,
+		&j.calculatedAt,
+	}
+}
+
+// stats returns the scanned stats, or nil when the customer has none
+func (j *joinedCustomerStats) stats(tenantID string) *model.CustomerStats {
+	if !j.customerID.Valid {
+		return nil
+	}
+
+	stats := &model.CustomerStats{
+		CustomerID:        j.customerID.String,
+		TenantID:          tenantID,
+		TotalOrders:       j.totalOrders.Int32,
+		TotalSpent:        j.totalSpent.Float64,
+		AverageOrderValue: j.averageOrderValue.Float64,
+		VisitsCount:       j.visitsCount.Int32,
+		FavoriteCategory:  j.favoriteCategory.String,
+		FavoriteProducts:  j.favoriteProducts,
+		CalculatedAt:      j.calculatedAt.Time,
+	}
+	if j.lastVisit.Valid {
+		stats.LastVisit = j.lastVisit.Time
+	}
+	return stats
+}
+
+// customerStatsUpdateArgs builds the arguments of customerStatsUpdateQuery
+func customerStatsUpdateArgs(tenantID string, stats *model.CustomerStats) []interface{} {
+	return []interface{}{
+		stats.CustomerID,
+		tenantID,
+		stats.TotalOrders,
+		stats.TotalSpent,
+		stats.AverageOrderValue,
+		nullLastVisit(stats.LastVisit),
+		stats.VisitsCount,
+		stats.FavoriteCategory,
+		pq.Array(stats.FavoriteProducts),
+		stats.CalculatedAt,
+	}
+}
+
+// nullLastVisit stores a zero last visit as NULL
+func nullLastVisit(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+// customerLevelCondition translates a customer level into the thresholds used by
+// model.CustomerStats.GetCustomerLevel
+func customerLevelCondition(level string) (string, error) {
+	switch level {
+	case model.CustomerLevelVIP:
+		return "cs.total_spent >= 5000", nil
+	case model.CustomerLevelPremium:
+		return "(cs.total_spent >= 2000 AND cs.total_spent < 5000)", nil
+	case model.CustomerLevelGold:
+		return "(cs.total_spent >= 500 AND cs.total_spent < 2000)", nil
+	case model.CustomerLevelSilver:
+		return "(cs.total_spent < 500 AND cs.total_orders >= 5)", nil
+	case model.CustomerLevelBronze:
+		return "(cs.total_spent < 500 AND cs.total_orders < 5)", nil
+	default:
+		return "", &model.ValidationError{Field: "level", Message: "nivel de cliente inválido"}
+	}
+}
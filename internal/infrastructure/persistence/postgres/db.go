@@ -3,18 +3,29 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/encomos/api-encomos/customer-service/internal/config"
+	"github.com/encomos/api-encomos/customer-service/internal/tenancy"
 	_ "github.com/lib/pq"
 )
 
 // DB wraps the database connection
 type DB struct {
 	*sql.DB
+
+	// system connects with the BYPASSRLS role of the system jobs; nil when not configured
+	system *sql.DB
 }
 
+// ErrNoSystemRole is returned by SystemTransaction when no system role is configured
+var ErrNoSystemRole = errors.New("database system role is not configured")
+
+// systemMaxOpenConns caps the system pool; only background jobs use it
+const systemMaxOpenConns = 2
+
 // NewDB creates a new database connection
 func NewDB(cfg *config.DatabaseConfig) (*DB, error) {
 	// Build connection string
@@ -37,14 +48,60 @@ func NewDB(cfg *config.DatabaseConfig) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &DB{DB: db}, nil
+	if cfg.SystemUser == "" {
+		return &DB{DB: db}, nil
+	}
+
+	system, err := openSystemDB(cfg)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &DB{DB: db, system: system}, nil
+}
+
+// openSystemDB connects with the system role and checks that it bypasses RLS, so the
+// system jobs never read a partial view of the tenants
+func openSystemDB(cfg *config.DatabaseConfig) (*sql.DB, error) {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.SystemUser, cfg.SystemPassword, cfg.Name, cfg.SSLMode)
+
+	system, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open system database connection: %w", err)
+	}
+	system.SetMaxOpenConns(systemMaxOpenConns)
+	system.SetMaxIdleConns(1)
+	system.SetConnMaxLifetime(cfg.MaxLifetime)
+
+	var bypassesRLS bool
+	err = system.QueryRow("SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&bypassesRLS)
+	if err != nil {
+		system.Close()
+		return nil, fmt.Errorf("failed to check system role: %w", err)
+	}
+	if !bypassesRLS {
+		system.Close()
+		return nil, fmt.Errorf("system role %s must have BYPASSRLS", cfg.SystemUser)
+	}
+
+	return system, nil
 }
 
-// Close closes the database connection
+// Close closes the database connections
 func (db *DB) Close() error {
+	if db.system != nil {
+		db.system.Close()
+	}
 	return db.DB.Close()
 }
 
+// HasSystemRole reports whether SystemTransaction can run
+func (db *DB) HasSystemRole() bool {
+	return db.system != nil
+}
+
 // Healthcheck verifies the database connection is healthy
 func (db *DB) Healthcheck(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -53,44 +110,22 @@ func (db *DB) Healthcheck(ctx context.Context) error {
 	return db.PingContext(ctx)
 }
 
-// Context keys for tenant information
-type contextKey string
-
-const (
-	TenantIDKey contextKey = "tenant_id"
-)
-
-// WithTenantID adds tenant ID (UUID string) to context
-func WithTenantID(ctx context.Context, tenantID string) context.Context {
-	return context.WithValue(ctx, TenantIDKey, tenantID)
-}
+// setTenantQuery scopes the RLS tenant to the current transaction only (is_local = true),
+// so the setting never leaks to other requests sharing the pooled connection
+const setTenantQuery = "SELECT set_config('app.current_tenant_id', $1, true)"
 
-// GetTenantID extracts tenant ID from context
-func GetTenantID(ctx context.Context) (string, bool) {
-	tenantID, ok := ctx.Value(TenantIDKey).(string)
-	return tenantID, ok
-}
-
-// SetTenantID sets the tenant ID in the database session for RLS
-func (db *DB) SetTenantID(ctx context.Context, tenantID string) error {
-	// PostgreSQL SET command doesn't accept placeholders, must use string formatting
-	// Safe because tenant_id is validated as UUID format
-	query := fmt.Sprintf("SET app.current_tenant_id = '%s'", tenantID)
-	_, err := db.ExecContext(ctx, query)
-	return err
-}
-
-// BeginTx starts a new transaction with tenant ID set
+// BeginTxWithTenant starts a new transaction with the tenant ID set for RLS
 func (db *DB) BeginTxWithTenant(ctx context.Context, tenantID string) (*sql.Tx, error) {
+	if err := tenancy.ValidateID(tenantID); err != nil {
+		return nil, err
+	}
+
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Set tenant ID for RLS
-	// PostgreSQL SET command doesn't accept placeholders
-	query := fmt.Sprintf("SET app.current_tenant_id = '%s'", tenantID)
-	if _, err := tx.ExecContext(ctx, query); err != nil {
+	if _, err := tx.ExecContext(ctx, setTenantQuery, tenantID); err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to set tenant ID: %w", err)
 	}
@@ -98,50 +133,139 @@ func (db *DB) BeginTxWithTenant(ctx context.Context, tenantID string) (*sql.Tx,
 	return tx, nil
 }
 
-// ExecWithTenant executes a query with tenant ID set
+// boundTx is the transaction opened by Transactor.WithinTransaction, carried in the context
+type boundTx struct {
+	tx       *sql.Tx
+	tenantID string
+}
+
+// txContextKey is the context key for boundTx
+type txContextKey struct{}
+
+// withBoundTx makes tx the transaction used by the helpers of DB for ctx
+func withBoundTx(ctx context.Context, tx *sql.Tx, tenantID string) context.Context {
+	return context.WithValue(ctx, txContextKey{}, &boundTx{tx: tx, tenantID: tenantID})
+}
+
+// txFromContext returns the transaction bound to ctx, or nil. A bound transaction of another
+// tenant is an error: statements must never run with a different RLS tenant than requested.
+func txFromContext(ctx context.Context, tenantID string) (*sql.Tx, error) {
+	bound, ok := ctx.Value(txContextKey{}).(*boundTx)
+	if !ok {
+		return nil, nil
+	}
+	if bound.tenantID != tenantID {
+		return nil, fmt.Errorf("transaction bound to tenant %s cannot run statements for tenant %s", bound.tenantID, tenantID)
+	}
+	return bound.tx, nil
+}
+
+// ExecWithTenant executes a statement in its own tenant-scoped transaction
 func (db *DB) ExecWithTenant(ctx context.Context, tenantID string, query string, args ...interface{}) (sql.Result, error) {
-	// Set tenant ID
-	if err := db.SetTenantID(ctx, tenantID); err != nil {
+	var result sql.Result
+	err := db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		var err error
+		result, err = tx.ExecContext(ctx, query, args...)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return db.ExecContext(ctx, query, args...)
+	return result, nil
+}
+
+// TenantRows are query results read inside a tenant-scoped transaction.
+// Close must be called to finish the transaction.
+type TenantRows struct {
+	*sql.Rows
+	tx *sql.Tx // nil cuando la consulta usa la transacción del contexto, que termina quien la abrió
 }
 
-// QueryWithTenant executes a query with tenant ID set
-func (db *DB) QueryWithTenant(ctx context.Context, tenantID string, query string, args ...interface{}) (*sql.Rows, error) {
-	// Set tenant ID
-	if err := db.SetTenantID(ctx, tenantID); err != nil {
+// Close closes the rows and commits the transaction, rolling back if iteration failed
+func (r *TenantRows) Close() error {
+	closeErr := r.Rows.Close()
+	if r.tx == nil {
+		return closeErr
+	}
+	if closeErr != nil || r.Rows.Err() != nil {
+		r.tx.Rollback()
+		return closeErr
+	}
+	return r.tx.Commit()
+}
+
+// QueryWithTenant executes a query in a tenant-scoped transaction that stays open until rows.Close
+func (db *DB) QueryWithTenant(ctx context.Context, tenantID string, query string, args ...interface{}) (*TenantRows, error) {
+	if tx, err := txFromContext(ctx, tenantID); err != nil || tx != nil {
+		if err != nil {
+			return nil, err
+		}
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		return &TenantRows{Rows: rows}, nil
+	}
+
+	tx, err := db.BeginTxWithTenant(ctx, tenantID)
+	if err != nil {
 		return nil, err
 	}
 
-	return db.QueryContext(ctx, query, args...)
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return &TenantRows{Rows: rows, tx: tx}, nil
 }
 
-// QueryRowWithTenant executes a query that returns a single row with tenant ID set
-func (db *DB) QueryRowWithTenant(ctx context.Context, tenantID string, query string, args ...interface{}) *sql.Row {
-	// Set tenant ID - ignore error as we can't return it from QueryRow
-	db.SetTenantID(ctx, tenantID)
+// TenantRow is a single-row query that runs inside a tenant-scoped transaction when scanned
+type TenantRow struct {
+	db       *DB
+	ctx      context.Context
+	tenantID string
+	query    string
+	args     []interface{}
+}
 
-	return db.QueryRowContext(ctx, query, args...)
+// Scan runs the query and copies the columns into dest. Like sql.Row, it returns
+// sql.ErrNoRows unwrapped when the query selects no rows.
+func (r *TenantRow) Scan(dest ...interface{}) error {
+	return r.db.TransactionWithTenant(r.ctx, r.tenantID, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(r.ctx, r.query, r.args...).Scan(dest...)
+	})
 }
 
-// GetTenantIDFromContext extracts tenant ID from context
-func GetTenantIDFromContext(ctx context.Context) (string, error) {
-	tenantID, ok := GetTenantID(ctx)
-	if !ok {
-		return "", fmt.Errorf("tenant ID not found in context")
+// QueryRowWithTenant prepares a single-row query; it is executed by TenantRow.Scan
+func (db *DB) QueryRowWithTenant(ctx context.Context, tenantID string, query string, args ...interface{}) *TenantRow {
+	return &TenantRow{
+		db:       db,
+		ctx:      ctx,
+		tenantID: tenantID,
+		query:    query,
+		args:     args,
 	}
-	return tenantID, nil
 }
 
-// WithTenantContext creates a context with tenant ID
-func WithTenantContext(ctx context.Context, tenantID string) context.Context {
-	return WithTenantID(ctx, tenantID)
+// GetTenantIDFromContext extracts tenant ID from context
+func GetTenantIDFromContext(ctx context.Context) (string, error) {
+	return tenancy.IDFromContext(ctx)
 }
 
-// Transaction helper function that sets tenant ID and runs a function in a transaction
+// TransactionWithTenant runs fn in a transaction with the tenant ID set for RLS. Inside
+// Transactor.WithinTransaction fn joins the bound transaction, which commits or rolls back
+// as a whole when WithinTransaction returns.
 func (db *DB) TransactionWithTenant(ctx context.Context, tenantID string, fn func(*sql.Tx) error) error {
+	if tx, err := txFromContext(ctx, tenantID); err != nil || tx != nil {
+		if err != nil {
+			return err
+		}
+		return fn(tx)
+	}
+
 	tx, err := db.BeginTxWithTenant(ctx, tenantID)
 	if err != nil {
 		return err
@@ -155,6 +279,27 @@ func (db *DB) TransactionWithTenant(ctx context.Context, tenantID string, fn fun
 	return tx.Commit()
 }
 
+// SystemTransaction runs fn in a read-only transaction of the system role, which bypasses
+// the tenant RLS policies. Reserved for system jobs such as metrics; request handling must
+// use TransactionWithTenant. Returns ErrNoSystemRole when the role is not configured.
+func (db *DB) SystemTransaction(ctx context.Context, fn func(*sql.Tx) error) error {
+	if db.system == nil {
+		return ErrNoSystemRole
+	}
+
+	tx, err := db.system.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // ScanRowsToMap scans SQL rows into a map slice (utility function)
 func ScanRowsToMap(rows *sql.Rows) ([]map[string]interface{}, error) {
 	columns, err := rows.Columns()
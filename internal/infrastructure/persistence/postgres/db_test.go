@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/encomos/api-encomos/customer-service/internal/tenancy"
+)
+
+// testDatabaseURL names the variable with the PostgreSQL URL of the integration tests. The
+// role must not be a superuser nor have BYPASSRLS, which skip the RLS policies altogether.
+const testDatabaseURL = "TEST_DATABASE_URL"
+
+// openTestDB connects to TEST_DATABASE_URL and applies the migrations, or skips the test
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	url := os.Getenv(testDatabaseURL)
+	if url == "" {
+		t.Skipf("%s is not set", testDatabaseURL)
+	}
+
+	sqlDB, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(8) // Menos conexiones que goroutines: se reutilizan entre tenants
+	t.Cleanup(func() { sqlDB.Close() })
+	db := &DB{DB: sqlDB}
+
+	var bypassesRLS bool
+	if err := db.QueryRow("SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&bypassesRLS); err != nil {
+		t.Fatalf("failed to read the database role: %v", err)
+	}
+	if bypassesRLS {
+		t.Skipf("the %s role bypasses RLS; use the application role", testDatabaseURL)
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	return db
+}
+
+// newTestTenantID returns a random tenant UUID
+func newTestTenantID(t *testing.T) string {
+	t.Helper()
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// seedCustomers inserts count customers for tenantID and removes them when the test ends
+func seedCustomers(t *testing.T, db *DB, tenantID string, count int) {
+	t.Helper()
+	ctx := context.Background()
+
+	err := db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		for i := 0; i < count; i++ {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO customers (tenant_id, first_name, last_name, customer_type) VALUES ($1, $2, 'Tenant', 'individual')`,
+				tenantID, fmt.Sprintf("Cliente %d", i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to seed customers of tenant %s: %v", tenantID, err)
+	}
+
+	t.Cleanup(func() {
+		if _, err := db.ExecWithTenant(context.Background(), tenantID, "DELETE FROM customers WHERE tenant_id = $1", tenantID); err != nil {
+			t.Errorf("failed to remove customers of tenant %s: %v", tenantID, err)
+		}
+	})
+}
+
+// TestTenantIsolationUnderConcurrency runs many goroutines per tenant over a pool smaller
+// than the number of goroutines, so connections are reused across tenants. The queries
+// filter by nothing: only RLS and the tenant set by each transaction keep the rows apart.
+func TestTenantIsolationUnderConcurrency(t *testing.T) {
+	db := openTestDB(t)
+
+	const perTenant = 5
+	tenants := []string{newTestTenantID(t), newTestTenantID(t), newTestTenantID(t)}
+	for _, tenantID := range tenants {
+		seedCustomers(t, db, tenantID, perTenant)
+	}
+
+	const workers = 24
+	const iterations = 40
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			ctx := context.Background()
+			for i := 0; i < iterations; i++ {
+				tenantID := tenants[(w+i)%len(tenants)]
+
+				var err error
+				if i%2 == 0 {
+					err = checkRowsWithQuery(ctx, db, tenantID, perTenant)
+				} else {
+					err = checkRowsWithTransaction(ctx, db, tenantID, perTenant)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// checkRowsWithQuery reads every visible customer with QueryWithTenant
+func checkRowsWithQuery(ctx context.Context, db *DB, tenantID string, want int) error {
+	rows, err := db.QueryWithTenant(ctx, tenantID, "SELECT tenant_id FROM customers")
+	if err != nil {
+		return fmt.Errorf("tenant %s: query failed: %w", tenantID, err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var rowTenant string
+		if err := rows.Scan(&rowTenant); err != nil {
+			return fmt.Errorf("tenant %s: scan failed: %w", tenantID, err)
+		}
+		if rowTenant != tenantID {
+			return fmt.Errorf("tenant %s read a row of tenant %s", tenantID, rowTenant)
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("tenant %s: iteration failed: %w", tenantID, err)
+	}
+	if count != want {
+		return fmt.Errorf("tenant %s: expected %d customers, got %d", tenantID, want, count)
+	}
+	return nil
+}
+
+// checkRowsWithTransaction counts the visible customers of each tenant inside TransactionWithTenant
+func checkRowsWithTransaction(ctx context.Context, db *DB, tenantID string, want int) error {
+	return db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		var own, others int
+		err := tx.QueryRowContext(ctx,
+			"SELECT COUNT(*) FILTER (WHERE tenant_id = $1), COUNT(*) FILTER (WHERE tenant_id <> $1) FROM customers",
+			tenantID).Scan(&own, &others)
+		if err != nil {
+			return fmt.Errorf("tenant %s: count failed: %w", tenantID, err)
+		}
+		if others != 0 {
+			return fmt.Errorf("tenant %s read %d rows of other tenants", tenantID, others)
+		}
+		if own != want {
+			return fmt.Errorf("tenant %s: expected %d customers, got %d", tenantID, want, own)
+		}
+		return nil
+	})
+}
+
+// TestQueryWithoutTenantSeesNothing checks that a connection of the pool without a tenant
+// set (a statement outside the helpers) reads no customer
+func TestQueryWithoutTenantSeesNothing(t *testing.T) {
+	db := openTestDB(t)
+	seedCustomers(t, db, newTestTenantID(t), 3)
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM customers").Scan(&count); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("a query without tenant read %d customers", count)
+	}
+}
+
+// TestSessionCannotBypassRLS checks that the policies ignore app.bypass_rls, which any
+// session can set, so only a role with BYPASSRLS reads across tenants
+func TestSessionCannotBypassRLS(t *testing.T) {
+	db := openTestDB(t)
+	seedCustomers(t, db, newTestTenantID(t), 3)
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT set_config('app.bypass_rls', 'on', true)"); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM customers").Scan(&count); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("app.bypass_rls read %d customers", count)
+	}
+}
+
+func TestSystemTransactionRequiresRole(t *testing.T) {
+	db := &DB{}
+	err := db.SystemTransaction(context.Background(), func(tx *sql.Tx) error {
+		t.Fatal("fn must not run without the system role")
+		return nil
+	})
+	if !errors.Is(err, ErrNoSystemRole) {
+		t.Fatalf("expected ErrNoSystemRole, got %v", err)
+	}
+}
+
+// TestTenantRequired checks that the helpers reject a missing or invalid tenant before
+// touching the database
+func TestTenantRequired(t *testing.T) {
+	db := &DB{} // Sin conexión: cualquier acceso a la base de datos fallaría con un panic
+	ctx := context.Background()
+
+	if _, err := GetTenantIDFromContext(ctx); err == nil {
+		t.Error("expected an error for a context without tenant")
+	}
+
+	for _, tenantID := range []string{"", "not-a-uuid", "1 OR 1=1"} {
+		if _, err := db.BeginTxWithTenant(ctx, tenantID); err == nil {
+			t.Errorf("BeginTxWithTenant accepted tenant %q", tenantID)
+		}
+		if err := db.TransactionWithTenant(ctx, tenantID, func(*sql.Tx) error { return nil }); err == nil {
+			t.Errorf("TransactionWithTenant accepted tenant %q", tenantID)
+		}
+		if _, err := db.QueryWithTenant(ctx, tenantID, "SELECT 1"); err == nil {
+			t.Errorf("QueryWithTenant accepted tenant %q", tenantID)
+		}
+		if _, err := db.ExecWithTenant(ctx, tenantID, "SELECT 1"); err == nil {
+			t.Errorf("ExecWithTenant accepted tenant %q", tenantID)
+		}
+	}
+}
+
+// TestBoundTransactionOfAnotherTenant checks that a statement for one tenant never joins
+// the transaction bound to the context for another tenant
+func TestBoundTransactionOfAnotherTenant(t *testing.T) {
+	db := &DB{}
+	tenantA, tenantB := newTestTenantID(t), newTestTenantID(t)
+	ctx := withBoundTx(context.Background(), &sql.Tx{}, tenantA)
+
+	if err := db.TransactionWithTenant(ctx, tenantB, func(*sql.Tx) error { return nil }); err == nil {
+		t.Error("TransactionWithTenant joined the transaction of another tenant")
+	}
+	if _, err := db.QueryWithTenant(ctx, tenantB, "SELECT 1"); err == nil {
+		t.Error("QueryWithTenant joined the transaction of another tenant")
+	}
+
+	tenant, err := tenancy.New(tenantB, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenantID, err := GetTenantIDFromContext(tenancy.WithTenant(ctx, tenant))
+	if err != nil || tenantID != tenantB {
+		t.Errorf("expected tenant %s from the context, got %q (%v)", tenantB, tenantID, err)
+	}
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/lib/pq"
+)
+
+// uniqueViolationCode is the SQLSTATE for unique_violation
+const uniqueViolationCode = "23505"
+
+// uniqueConstraints maps the unique indexes of the schema to the resource and field they protect
+var uniqueConstraints = map[string]struct{ resource, field string }{
+	"customers_tenant_email_key":        {"customer", "email"},
+	"customers_tenant_tax_id_key":       {"customer", "tax_id"},
+	"vehicles_tenant_vin_key":           {"vehicle", "vin"},
+	"vehicles_tenant_license_plate_key": {"vehicle", "license_plate"},
+}
+
+// conflictFromUniqueViolation converts a unique violation on a known index into a
+// model.ConflictError. It covers the race between the existence checks of the
+// service and the insert; any other error is returned unchanged.
+func conflictFromUniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolationCode {
+		return err
+	}
+	constraint, ok := uniqueConstraints[pqErr.Constraint]
+	if !ok {
+		return err
+	}
+	return model.NewConflictError(constraint.resource, constraint.field, "", "el valor ya está registrado")
+}
+
+// versionConflict explains why a versioned write on table matched no row: either the row
+// does not exist for the tenant (NotFoundError) or its version is no longer expected
+// (VersionConflictError). table must be a trusted identifier, never user input.
+func versionConflict(ctx context.Context, tx *sql.Tx, table, resource, id string, expected int64) error {
+	var current int64
+	err := tx.QueryRowContext(ctx, "SELECT version FROM "+table+" WHERE id = $1", id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return model.NewNotFoundError(resource, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s version: %w", resource, err)
+	}
+
+	return &model.VersionConflictError{
+		Resource:        resource,
+		ID:              id,
+		ExpectedVersion: expected,
+		CurrentVersion:  current,
+	}
+}
@@ -0,0 +1,334 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID serializes migration runs across replicas (pg_advisory_xact_lock key)
+const migrationLockID = 7261539018
+
+const createMigrationsTableQuery = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    integer     PRIMARY KEY,
+		name       text        NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`
+
+// Migration is a versioned schema change embedded in the binary
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus describes whether a migration has been applied
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator applies the embedded migrations
+type Migrator struct {
+	db         *DB
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the embedded migrations
+func NewMigrator(db *DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// loadMigrations reads NNNN_name.up.sql / NNNN_name.down.sql pairs sorted by version
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("invalid migration file name %s", fileName)
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionPart, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %s", fileName)
+		}
+		version, err := strconv.Atoi(versionPart)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", fileName)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join("migrations", fileName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", fileName, err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies every pending migration, each one in its own transaction.
+// Returns the migrations that were applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	for _, migration := range m.migrations {
+		ok, err := m.apply(ctx, migration)
+		if err != nil {
+			return applied, err
+		}
+		if ok {
+			applied = append(applied, migration)
+		}
+	}
+	return applied, nil
+}
+
+// Down reverts the last steps applied migrations, most recent first.
+// Returns the migrations that were reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("steps must be greater than zero")
+	}
+
+	var reverted []Migration
+	for i := 0; i < steps; i++ {
+		migration, ok, err := m.revertLatest(ctx)
+		if err != nil {
+			return reverted, err
+		}
+		if !ok {
+			break
+		}
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+// Status lists every embedded migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// CheckSchema returns an error if any embedded migration has not been applied
+func (m *Migrator) CheckSchema(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, fmt.Sprintf("%04d_%s", status.Version, status.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database schema is out of date, pending migrations: %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// apply runs a single migration unless it is already recorded
+func (m *Migrator) apply(ctx context.Context, migration Migration) (bool, error) {
+	applied := false
+	err := m.withLock(ctx, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", migration.Version,
+		).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check migration %d: %w", migration.Version, err)
+		}
+		if exists {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return fmt.Errorf("failed to apply migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name,
+		); err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+		}
+
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+// revertLatest reverts the most recently applied migration
+func (m *Migrator) revertLatest(ctx context.Context) (Migration, bool, error) {
+	var reverted Migration
+	found := false
+	err := m.withLock(ctx, func(tx *sql.Tx) error {
+		var version int
+		err := tx.QueryRowContext(ctx,
+			"SELECT version FROM schema_migrations ORDER BY version DESC LIMIT 1",
+		).Scan(&version)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read latest migration: %w", err)
+		}
+
+		migration, ok := m.find(version)
+		if !ok {
+			return fmt.Errorf("applied migration %d is not embedded in this binary", version)
+		}
+
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return fmt.Errorf("failed to revert migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", version); err != nil {
+			return fmt.Errorf("failed to unrecord migration %d: %w", version, err)
+		}
+
+		reverted = migration
+		found = true
+		return nil
+	})
+	return reverted, found, err
+}
+
+// withLock runs fn in a transaction holding the migration advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration transaction: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, createMigrationsTableQuery); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration transaction: %w", err)
+	}
+	return nil
+}
+
+// appliedVersions returns the applied migration versions with their timestamps
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int]time.Time, error) {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx,
+		"SELECT to_regclass('schema_migrations') IS NOT NULL",
+	).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check schema_migrations table: %w", err)
+	}
+
+	applied := make(map[int]time.Time)
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate applied migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// find returns the embedded migration with the given version
+func (m *Migrator) find(version int) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS customer_notes;
+DROP TABLE IF EXISTS vehicles;
+DROP TABLE IF EXISTS customers;
+DROP FUNCTION IF EXISTS app_current_tenant_id();
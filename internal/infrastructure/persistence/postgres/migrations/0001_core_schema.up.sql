@@ -0,0 +1,77 @@
+-- Esquema base del customer-service: clientes, vehículos y notas.
+-- vehicles y customer_notes guardan tenant_id (por defecto el tenant de la transacción)
+-- y una FK compuesta que garantiza que coincide con el del cliente.
+
+CREATE OR REPLACE FUNCTION app_current_tenant_id() RETURNS uuid
+LANGUAGE sql STABLE AS $$
+    SELECT NULLIF(current_setting('app.current_tenant_id', true), '')::uuid
+$$;
+
+CREATE TABLE customers (
+    id            uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id     uuid        NOT NULL,
+    first_name    text        NOT NULL,
+    last_name     text        NOT NULL,
+    email         text,
+    phone         text,
+    customer_type text        NOT NULL CHECK (customer_type IN ('individual', 'business')),
+    company_name  text,
+    tax_id        text,
+    address       text,
+    birthday      date,
+    notes         text,
+    preferences   jsonb       NOT NULL DEFAULT '{}'::jsonb,
+    is_active     boolean     NOT NULL DEFAULT true,
+    created_at    timestamptz NOT NULL DEFAULT now(),
+    updated_at    timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT customers_id_tenant_key UNIQUE (id, tenant_id)
+);
+
+CREATE UNIQUE INDEX customers_tenant_email_key ON customers (tenant_id, email) WHERE email IS NOT NULL;
+CREATE UNIQUE INDEX customers_tenant_tax_id_key ON customers (tenant_id, tax_id) WHERE tax_id IS NOT NULL;
+CREATE INDEX customers_tenant_created_at_idx ON customers (tenant_id, created_at DESC);
+CREATE INDEX customers_tenant_type_idx ON customers (tenant_id, customer_type);
+CREATE INDEX customers_tenant_last_name_idx ON customers (tenant_id, last_name, first_name);
+
+CREATE TABLE vehicles (
+    id            uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id     uuid        NOT NULL DEFAULT app_current_tenant_id(),
+    customer_id   uuid        NOT NULL,
+    make          text        NOT NULL,
+    model         text        NOT NULL,
+    year          integer     NOT NULL,
+    vin           text,
+    license_plate text,
+    color         text,
+    engine        text,
+    notes         text,
+    is_active     boolean     NOT NULL DEFAULT true,
+    metadata      jsonb       NOT NULL DEFAULT '{}'::jsonb,
+    created_at    timestamptz NOT NULL DEFAULT now(),
+    updated_at    timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT vehicles_customer_fkey FOREIGN KEY (customer_id, tenant_id)
+        REFERENCES customers (id, tenant_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX vehicles_tenant_vin_key ON vehicles (tenant_id, vin) WHERE vin IS NOT NULL;
+CREATE UNIQUE INDEX vehicles_tenant_license_plate_key ON vehicles (tenant_id, license_plate) WHERE license_plate IS NOT NULL;
+CREATE INDEX vehicles_customer_idx ON vehicles (customer_id);
+CREATE INDEX vehicles_tenant_make_model_idx ON vehicles (tenant_id, make, model, year);
+
+CREATE TABLE customer_notes (
+    id          uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id   uuid        NOT NULL DEFAULT app_current_tenant_id(),
+    customer_id uuid        NOT NULL,
+    staff_id    text        NOT NULL,
+    staff_name  text        NOT NULL,
+    note        text        NOT NULL CHECK (char_length(note) <= 2000),
+    type        text        NOT NULL DEFAULT 'general'
+        CHECK (type IN ('general', 'service', 'complaint', 'compliment', 'reminder', 'warning')),
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT customer_notes_customer_fkey FOREIGN KEY (customer_id, tenant_id)
+        REFERENCES customers (id, tenant_id) ON DELETE CASCADE
+);
+
+CREATE INDEX customer_notes_customer_created_at_idx ON customer_notes (customer_id, created_at DESC);
+CREATE INDEX customer_notes_tenant_staff_idx ON customer_notes (tenant_id, staff_id);
+CREATE INDEX customer_notes_tenant_type_idx ON customer_notes (tenant_id, type);
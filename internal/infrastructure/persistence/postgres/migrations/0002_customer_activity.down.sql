@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS customer_events;
+DROP TABLE IF EXISTS customer_history;
+DROP TABLE IF EXISTS customer_stats;
@@ -0,0 +1,51 @@
+-- Estadísticas, historial unificado y registro de eventos ingeridos por cliente
+
+CREATE TABLE customer_stats (
+    customer_id         uuid          PRIMARY KEY,
+    tenant_id           uuid          NOT NULL,
+    total_orders        integer       NOT NULL DEFAULT 0 CHECK (total_orders >= 0),
+    total_spent         numeric(14,2) NOT NULL DEFAULT 0 CHECK (total_spent >= 0),
+    average_order_value numeric(14,2) NOT NULL DEFAULT 0,
+    last_visit          timestamptz,
+    visits_count        integer       NOT NULL DEFAULT 0 CHECK (visits_count >= 0),
+    favorite_category   text,
+    favorite_products   text[]        NOT NULL DEFAULT '{}',
+    calculated_at       timestamptz   NOT NULL DEFAULT now(),
+    CONSTRAINT customer_stats_customer_fkey FOREIGN KEY (customer_id, tenant_id)
+        REFERENCES customers (id, tenant_id) ON DELETE CASCADE
+);
+
+CREATE INDEX customer_stats_tenant_total_spent_idx ON customer_stats (tenant_id, total_spent DESC);
+CREATE INDEX customer_stats_tenant_calculated_at_idx ON customer_stats (tenant_id, calculated_at);
+
+CREATE TABLE customer_history (
+    id           uuid          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id    uuid          NOT NULL,
+    customer_id  uuid          NOT NULL,
+    type         text          NOT NULL CHECK (type IN ('order', 'appointment', 'note', 'payment')),
+    title        text          NOT NULL,
+    description  text,
+    amount       numeric(14,2) NOT NULL DEFAULT 0,
+    status       text,
+    data         jsonb         NOT NULL DEFAULT '{}'::jsonb,
+    reference_id text,
+    created_at   timestamptz   NOT NULL DEFAULT now(),
+    CONSTRAINT customer_history_customer_fkey FOREIGN KEY (customer_id, tenant_id)
+        REFERENCES customers (id, tenant_id) ON DELETE CASCADE
+);
+
+CREATE INDEX customer_history_timeline_idx ON customer_history (tenant_id, customer_id, created_at DESC, id DESC);
+CREATE INDEX customer_history_type_idx ON customer_history (tenant_id, customer_id, type, created_at DESC);
+
+CREATE TABLE customer_events (
+    tenant_id   uuid        NOT NULL,
+    event_id    text        NOT NULL,
+    customer_id uuid        NOT NULL,
+    type        text        NOT NULL,
+    source      text,
+    occurred_at timestamptz NOT NULL,
+    received_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (tenant_id, event_id),
+    CONSTRAINT customer_events_customer_fkey FOREIGN KEY (customer_id, tenant_id)
+        REFERENCES customers (id, tenant_id) ON DELETE CASCADE
+);
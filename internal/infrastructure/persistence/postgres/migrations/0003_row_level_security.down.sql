@@ -0,0 +1,25 @@
+DROP POLICY IF EXISTS tenant_isolation ON customer_events;
+ALTER TABLE customer_events NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE customer_events DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON customer_history;
+ALTER TABLE customer_history NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE customer_history DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON customer_stats;
+ALTER TABLE customer_stats NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE customer_stats DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON customer_notes;
+ALTER TABLE customer_notes NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE customer_notes DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON vehicles;
+ALTER TABLE vehicles NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE vehicles DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON customers;
+ALTER TABLE customers NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE customers DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS app_bypass_rls();
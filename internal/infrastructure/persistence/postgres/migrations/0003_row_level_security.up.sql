@@ -0,0 +1,45 @@
+-- Row-Level Security por tenant. FORCE aplica las políticas también al dueño de las tablas
+-- (el usuario de la aplicación). Los procesos de sistema (métricas globales, purgas) pueden
+-- activar app.bypass_rls = 'on' dentro de su transacción; no es un límite de seguridad frente
+-- a quien ya tiene acceso SQL, solo evita fugas accidentales entre tenants.
+
+CREATE OR REPLACE FUNCTION app_bypass_rls() RETURNS boolean
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(current_setting('app.bypass_rls', true), '') = 'on'
+$$;
+
+ALTER TABLE customers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customers FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON customers
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER TABLE vehicles ENABLE ROW LEVEL SECURITY;
+ALTER TABLE vehicles FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON vehicles
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER TABLE customer_notes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customer_notes FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON customer_notes
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER TABLE customer_stats ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customer_stats FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON customer_stats
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER TABLE customer_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customer_history FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON customer_history
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER TABLE customer_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customer_events FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON customer_events
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
@@ -0,0 +1,5 @@
+-- Versión de fila para control de concurrencia optimista. Cada UPDATE la incrementa
+-- en uno; los repositorios solo escriben si la versión leída sigue vigente.
+
+ALTER TABLE customers ADD COLUMN version bigint NOT NULL DEFAULT 1 CHECK (version > 0);
+ALTER TABLE vehicles  ADD COLUMN version bigint NOT NULL DEFAULT 1 CHECK (version > 0);
@@ -0,0 +1,27 @@
+-- Registro de auditoría de los cambios sobre clientes y vehículos. Sin FK a customers:
+-- las entradas deben sobrevivir al borrado del cliente que describen. Solo se inserta;
+-- la aplicación nunca actualiza ni borra entradas.
+
+CREATE TABLE customer_audit_log (
+    id            uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id     uuid        NOT NULL DEFAULT app_current_tenant_id(),
+    customer_id   uuid        NOT NULL,
+    resource_type text        NOT NULL CHECK (resource_type IN ('customer', 'vehicle')),
+    resource_id   uuid        NOT NULL,
+    action        text        NOT NULL,
+    actor_id      text,
+    actor_name    text,
+    request_id    text,
+    changes       jsonb       NOT NULL DEFAULT '{}'::jsonb,
+    created_at    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX customer_audit_log_customer_idx ON customer_audit_log (tenant_id, customer_id, created_at DESC, id DESC);
+CREATE INDEX customer_audit_log_actor_idx ON customer_audit_log (tenant_id, actor_id, created_at DESC);
+CREATE INDEX customer_audit_log_created_at_idx ON customer_audit_log (tenant_id, created_at DESC);
+
+ALTER TABLE customer_audit_log ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customer_audit_log FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON customer_audit_log
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
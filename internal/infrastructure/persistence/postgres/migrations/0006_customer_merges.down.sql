@@ -0,0 +1,10 @@
+-- customer_history tiene RLS forzado: el dueño de la tabla (quien migra) lo deja sin forzar
+-- solo mientras borra las fusiones de todos los tenants, dentro de la transacción de la migración
+ALTER TABLE customer_history NO FORCE ROW LEVEL SECURITY;
+DELETE FROM customer_history WHERE type = 'merge';
+ALTER TABLE customer_history FORCE ROW LEVEL SECURITY;
+ALTER TABLE customer_history DROP CONSTRAINT customer_history_type_check;
+ALTER TABLE customer_history ADD CONSTRAINT customer_history_type_check
+    CHECK (type IN ('order', 'appointment', 'note', 'payment'));
+
+DROP TABLE IF EXISTS customer_merges;
@@ -0,0 +1,26 @@
+-- Fusión de clientes duplicados. Los duplicados se eliminan tras mover sus datos al
+-- superviviente; customer_merges conserva la redirección de sus IDs. Si el superviviente se
+-- fusiona o elimina después, las redirecciones se reasignan o desaparecen con él.
+
+CREATE TABLE customer_merges (
+    tenant_id   uuid        NOT NULL DEFAULT app_current_tenant_id(),
+    merged_id   uuid        NOT NULL,
+    survivor_id uuid        NOT NULL,
+    merged_at   timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (tenant_id, merged_id),
+    CONSTRAINT customer_merges_survivor_fkey FOREIGN KEY (survivor_id, tenant_id)
+        REFERENCES customers (id, tenant_id) ON DELETE CASCADE
+);
+
+CREATE INDEX customer_merges_survivor_idx ON customer_merges (tenant_id, survivor_id);
+
+ALTER TABLE customer_merges ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customer_merges FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON customer_merges
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+-- Las fusiones se registran en el historial del superviviente
+ALTER TABLE customer_history DROP CONSTRAINT customer_history_type_check;
+ALTER TABLE customer_history ADD CONSTRAINT customer_history_type_check
+    CHECK (type IN ('order', 'appointment', 'note', 'payment', 'merge'));
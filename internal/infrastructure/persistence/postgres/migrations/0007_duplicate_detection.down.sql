@@ -0,0 +1,13 @@
+-- Las extensiones pg_trgm y unaccent se conservan: pueden usarlas otros esquemas
+
+DROP INDEX IF EXISTS customers_tenant_tax_id_normalized_idx;
+DROP INDEX IF EXISTS customers_tenant_email_local_part_idx;
+DROP INDEX IF EXISTS customers_tenant_phone_key_idx;
+DROP INDEX IF EXISTS customers_company_name_trgm_idx;
+DROP INDEX IF EXISTS customers_name_trgm_idx;
+
+DROP FUNCTION IF EXISTS app_normalize_tax_id(text);
+DROP FUNCTION IF EXISTS app_email_local_part(text);
+DROP FUNCTION IF EXISTS app_phone_key(text);
+DROP FUNCTION IF EXISTS app_normalize_name(text);
+DROP FUNCTION IF EXISTS app_unaccent(text);
@@ -0,0 +1,45 @@
+-- Detección de clientes duplicados. Las funciones app_* normalizan nombres, teléfonos, emails e
+-- identificadores fiscales igual que model (customer_duplicate.go), de modo que la preselección
+-- de candidatos en SQL y la puntuación en el servicio comparan los mismos valores.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() no es IMMUTABLE; con el diccionario explícito se puede usar en índices
+CREATE OR REPLACE FUNCTION app_unaccent(text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT AS $$
+    SELECT unaccent('unaccent'::regdictionary, $1)
+$$;
+
+-- Minúsculas sin acentos, con los separadores reducidos a un espacio
+CREATE OR REPLACE FUNCTION app_normalize_name(text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT AS $$
+    SELECT NULLIF(btrim(regexp_replace(lower(app_unaccent($1)), '[^[:alnum:]]+', ' ', 'g')), '')
+$$;
+
+-- Últimos 9 dígitos: ignora prefijos de país y formato; menos de 7 dígitos no se comparan
+CREATE OR REPLACE FUNCTION app_phone_key(text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT AS $$
+    SELECT CASE WHEN length(d) < 7 THEN NULL ELSE right(d, 9) END
+    FROM (SELECT regexp_replace($1, '[^0-9]', '', 'g') AS d) digits
+$$;
+
+-- Parte local del email en minúsculas, sin la etiqueta "+..."
+CREATE OR REPLACE FUNCTION app_email_local_part(text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT AS $$
+    SELECT NULLIF(regexp_replace(split_part(lower(btrim($1)), '@', 1), '\+.*$', ''), '')
+$$;
+
+-- Solo letras y dígitos, en mayúsculas
+CREATE OR REPLACE FUNCTION app_normalize_tax_id(text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT AS $$
+    SELECT NULLIF(upper(regexp_replace($1, '[^[:alnum:]]', '', 'g')), '')
+$$;
+
+CREATE INDEX customers_name_trgm_idx ON customers
+    USING gin (app_normalize_name(first_name || ' ' || last_name) gin_trgm_ops);
+CREATE INDEX customers_company_name_trgm_idx ON customers
+    USING gin (app_normalize_name(company_name) gin_trgm_ops);
+CREATE INDEX customers_tenant_phone_key_idx ON customers (tenant_id, app_phone_key(phone));
+CREATE INDEX customers_tenant_email_local_part_idx ON customers (tenant_id, app_email_local_part(email));
+CREATE INDEX customers_tenant_tax_id_normalized_idx ON customers (tenant_id, app_normalize_tax_id(tax_id));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS customers_search_text_trgm_idx;
+DROP INDEX IF EXISTS customers_search_vector_idx;
+
+ALTER TABLE customers DROP COLUMN IF EXISTS search_text;
+ALTER TABLE customers DROP COLUMN IF EXISTS search_vector;
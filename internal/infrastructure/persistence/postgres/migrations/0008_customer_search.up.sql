@@ -0,0 +1,22 @@
+-- Búsqueda de clientes: search_vector (texto completo, por prefijos) y search_text (trigramas,
+-- tolera erratas) se generan sin acentos a partir de los campos buscables. Las expresiones de
+-- cada campo deben coincidir con customerSearchFields (customer_repo.go), que las usa cuando la
+-- búsqueda se limita a algunos campos.
+
+ALTER TABLE customers ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(app_normalize_name(first_name || ' ' || last_name), '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(app_normalize_name(company_name), '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(app_normalize_name(email), '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(app_normalize_tax_id(tax_id), '') || ' ' || coalesce(app_normalize_name(tax_id), '')), 'B') ||
+    setweight(to_tsvector('simple', regexp_replace(coalesce(phone, ''), '[^0-9]', '', 'g')), 'C')
+) STORED;
+
+ALTER TABLE customers ADD COLUMN search_text text GENERATED ALWAYS AS (
+    app_normalize_name(
+        first_name || ' ' || last_name || ' ' || coalesce(company_name, '') || ' ' ||
+        coalesce(email, '') || ' ' || coalesce(tax_id, '') || ' ' || coalesce(phone, '')
+    )
+) STORED;
+
+CREATE INDEX customers_search_vector_idx ON customers USING gin (search_vector);
+CREATE INDEX customers_search_text_trgm_idx ON customers USING gin (search_text gin_trgm_ops);
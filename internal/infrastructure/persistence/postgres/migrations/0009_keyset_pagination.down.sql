@@ -0,0 +1,9 @@
+CREATE INDEX IF NOT EXISTS customer_notes_customer_created_at_idx ON customer_notes (customer_id, created_at DESC);
+DROP INDEX IF EXISTS customer_notes_customer_created_at_id_idx;
+
+DROP INDEX IF EXISTS vehicles_tenant_year_make_model_id_idx;
+
+CREATE INDEX IF NOT EXISTS customers_tenant_created_at_idx ON customers (tenant_id, created_at DESC);
+DROP INDEX IF EXISTS customers_tenant_company_name_id_idx;
+DROP INDEX IF EXISTS customers_tenant_name_id_idx;
+DROP INDEX IF EXISTS customers_tenant_created_at_id_idx;
@@ -0,0 +1,13 @@
+-- Paginación por cursor (keyset): cada orden de los listados termina en el id para ser total,
+-- y un índice con las mismas columnas permite continuar tras la última fila sin OFFSET.
+-- Las claves deben coincidir con las de customer_repo.go, vehicle_repo.go y customer_note_repo.go.
+
+CREATE INDEX customers_tenant_created_at_id_idx ON customers (tenant_id, created_at, id);
+CREATE INDEX customers_tenant_name_id_idx ON customers (tenant_id, first_name, last_name, id);
+CREATE INDEX customers_tenant_company_name_id_idx ON customers (tenant_id, coalesce(company_name, ''), id);
+DROP INDEX IF EXISTS customers_tenant_created_at_idx;
+
+CREATE INDEX vehicles_tenant_year_make_model_id_idx ON vehicles (tenant_id, year DESC, make, model, id);
+
+CREATE INDEX customer_notes_customer_created_at_id_idx ON customer_notes (customer_id, created_at DESC, id DESC);
+DROP INDEX IF EXISTS customer_notes_customer_created_at_idx;
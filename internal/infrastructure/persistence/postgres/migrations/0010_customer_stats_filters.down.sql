@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS customer_stats_tenant_level_idx;
+DROP INDEX IF EXISTS customer_stats_tenant_last_visit_idx;
@@ -0,0 +1,5 @@
+-- Filtros y orden de ListCustomers por estadísticas: total gastado (ya indexado en 0002),
+-- nivel (umbrales de total_spent y total_orders) y rango de última visita.
+
+CREATE INDEX customer_stats_tenant_last_visit_idx ON customer_stats (tenant_id, last_visit, customer_id);
+CREATE INDEX customer_stats_tenant_level_idx ON customer_stats (tenant_id, total_spent, total_orders);
@@ -0,0 +1,25 @@
+-- Sin papelera, eliminar era definitivo: quitar deleted_at devolvería a los listados a los
+-- clientes en la papelera, y borrarlos perdería datos que aún se pueden restaurar. La
+-- migración se niega a revertir mientras queden clientes en la papelera; hay que restaurarlos
+-- o purgarlos antes. customers tiene RLS forzado: el dueño de la tabla (quien migra) lo deja
+-- sin forzar solo mientras cuenta los de todos los tenants, dentro de la transacción de la migración
+ALTER TABLE customers NO FORCE ROW LEVEL SECURITY;
+DO $$
+DECLARE
+    trashed bigint;
+BEGIN
+    SELECT count(*) INTO trashed FROM customers WHERE deleted_at IS NOT NULL;
+    IF trashed > 0 THEN
+        RAISE EXCEPTION 'cannot revert 0011_customer_trash: % customers are in the trash; restore or purge them first', trashed;
+    END IF;
+END
+$$;
+ALTER TABLE customers FORCE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS customers_tenant_deleted_at_id_idx;
+DROP INDEX customers_tenant_email_key;
+DROP INDEX customers_tenant_tax_id_key;
+CREATE UNIQUE INDEX customers_tenant_email_key ON customers (tenant_id, email) WHERE email IS NOT NULL;
+CREATE UNIQUE INDEX customers_tenant_tax_id_key ON customers (tenant_id, tax_id) WHERE tax_id IS NOT NULL;
+
+ALTER TABLE customers DROP COLUMN deleted_at;
@@ -0,0 +1,19 @@
+-- Papelera de clientes: DeleteCustomer marca deleted_at en lugar de borrar la fila. Los
+-- clientes eliminados quedan fuera de listados, búsquedas y lecturas, junto con sus vehículos
+-- y notas, hasta que RestoreCustomer los recupera o la purga los borra al cumplir la retención
+-- (vehículos, notas, historial y estadísticas se van con ellos por ON DELETE CASCADE).
+
+ALTER TABLE customers ADD COLUMN deleted_at timestamptz;
+
+-- Email e identificador fiscal solo son únicos entre los clientes no eliminados, así que un
+-- cliente en la papelera no bloquea un alta nueva; RestoreCustomer comprueba que sigan libres
+DROP INDEX customers_tenant_email_key;
+DROP INDEX customers_tenant_tax_id_key;
+CREATE UNIQUE INDEX customers_tenant_email_key ON customers (tenant_id, email)
+    WHERE email IS NOT NULL AND deleted_at IS NULL;
+CREATE UNIQUE INDEX customers_tenant_tax_id_key ON customers (tenant_id, tax_id)
+    WHERE tax_id IS NOT NULL AND deleted_at IS NULL;
+
+-- ListDeletedCustomers (más recientes primero) y purga por antigüedad
+CREATE INDEX customers_tenant_deleted_at_id_idx ON customers (tenant_id, deleted_at DESC, id DESC)
+    WHERE deleted_at IS NOT NULL;
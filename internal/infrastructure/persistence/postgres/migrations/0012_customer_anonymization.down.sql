@@ -0,0 +1,2 @@
+-- Los datos sustituidos no se recuperan; solo se pierde la marca de anonimización
+ALTER TABLE customers DROP COLUMN anonymized_at;
@@ -0,0 +1,7 @@
+-- Derecho de supresión: AnonymizeCustomer sustituye los datos personales del cliente, el texto
+-- de sus notas y de su historial de notas y fusiones, y las notas de sus vehículos. Conserva la
+-- fila, las estadísticas y el historial de pedidos, citas y pagos para la contabilidad.
+-- Es la única operación que reescribe customer_audit_log: los valores de los campos personales
+-- de las entradas del cliente se sustituyen también, sin borrar las entradas.
+
+ALTER TABLE customers ADD COLUMN anonymized_at timestamptz;
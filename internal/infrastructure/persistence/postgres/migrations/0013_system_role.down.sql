@@ -0,0 +1,38 @@
+-- Vuelve a las políticas con app_bypass_rls() de las migraciones 0003, 0005 y 0006
+
+CREATE OR REPLACE FUNCTION app_bypass_rls() RETURNS boolean
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(current_setting('app.bypass_rls', true), '') = 'on'
+$$;
+
+ALTER POLICY tenant_isolation ON customers
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON vehicles
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_notes
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_stats
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_history
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_events
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_audit_log
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_merges
+    USING (app_bypass_rls() OR tenant_id = app_current_tenant_id())
+    WITH CHECK (app_bypass_rls() OR tenant_id = app_current_tenant_id());
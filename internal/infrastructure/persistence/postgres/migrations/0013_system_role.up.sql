@@ -0,0 +1,39 @@
+-- Las políticas dejan de aceptar app.bypass_rls: cualquier sesión podía activarlo con
+-- set_config. Los procesos de sistema (métricas por tenant, purga de la papelera) se conectan
+-- con un rol propio con BYPASSRLS (DB_SYSTEM_USER), creado por el administrador:
+--   CREATE ROLE customer_service_system LOGIN BYPASSRLS PASSWORD '...';
+--   GRANT SELECT ON customers, vehicles TO customer_service_system;
+
+ALTER POLICY tenant_isolation ON customers
+    USING (tenant_id = app_current_tenant_id())
+    WITH CHECK (tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON vehicles
+    USING (tenant_id = app_current_tenant_id())
+    WITH CHECK (tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_notes
+    USING (tenant_id = app_current_tenant_id())
+    WITH CHECK (tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_stats
+    USING (tenant_id = app_current_tenant_id())
+    WITH CHECK (tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_history
+    USING (tenant_id = app_current_tenant_id())
+    WITH CHECK (tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_events
+    USING (tenant_id = app_current_tenant_id())
+    WITH CHECK (tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_audit_log
+    USING (tenant_id = app_current_tenant_id())
+    WITH CHECK (tenant_id = app_current_tenant_id());
+
+ALTER POLICY tenant_isolation ON customer_merges
+    USING (tenant_id = app_current_tenant_id())
+    WITH CHECK (tenant_id = app_current_tenant_id());
+
+DROP FUNCTION app_bypass_rls();
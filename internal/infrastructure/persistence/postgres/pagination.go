@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// keysetColumn is a column of a keyset order. expr must never be NULL so rows compare
+// totally, and cast is the SQL type a cursor value is read back as.
+type keysetColumn struct {
+	expr string
+	cast string
+	desc bool
+}
+
+// keysetOrder is the total order of a listing; its last column is unique (the id). Pages
+// continue after the key of the last row instead of skipping rows with OFFSET, so rows
+// inserted or deleted meanwhile do not shift the following pages.
+type keysetOrder []keysetColumn
+
+// orderBy returns the ORDER BY clause
+func (o keysetOrder) orderBy() string {
+	parts := make([]string, len(o))
+	for i, column := range o {
+		direction := "ASC"
+		if column.desc {
+			direction = "DESC"
+		}
+		parts[i] = column.expr + " " + direction
+	}
+	return "ORDER BY " + strings.Join(parts, ", ")
+}
+
+// keyColumns returns the select list reading the key of each row as text
+func (o keysetOrder) keyColumns() string {
+	parts := make([]string, len(o))
+	for i, column := range o {
+		parts[i] = fmt.Sprintf("(%s)::text", column.expr)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// keyDest returns the scan destinations of keyColumns and the key they fill
+func (o keysetOrder) keyDest() ([]string, []interface{}) {
+	key := make([]string, len(o))
+	dest := make([]interface{}, len(o))
+	for i := range key {
+		dest[i] = &key[i]
+	}
+	return key, dest
+}
+
+// after returns the condition selecting the rows that follow the cursor, whose values are
+// the arguments from $firstArg on
+func (o keysetOrder) after(cursor *model.PageCursor, firstArg int) (string, []interface{}, error) {
+	if len(cursor.Key) != len(o) {
+		return "", nil, &model.ValidationError{Field: "page_token", Message: "el token no corresponde a este listado"}
+	}
+
+	exprs := make([]string, len(o))
+	values := make([]string, len(o))
+	args := make([]interface{}, len(o))
+	sameDirection := true
+	for i, column := range o {
+		exprs[i] = column.expr
+		values[i] = fmt.Sprintf("$%d::%s", firstArg+i, column.cast)
+		args[i] = cursor.Key[i]
+		sameDirection = sameDirection && column.desc == o[0].desc
+	}
+
+	// Con una sola dirección basta comparar filas, lo que aprovecha el índice de la clave
+	if sameDirection {
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(exprs, ", "), keysetOperator(o[0].desc), strings.Join(values, ", ")), args, nil
+	}
+
+	// Direcciones mezcladas: (k1 > v1) OR (k1 = v1 AND k2 < v2) OR ...
+	alternatives := make([]string, len(o))
+	for i, column := range o {
+		conditions := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, fmt.Sprintf("%s = %s", exprs[j], values[j]))
+		}
+		conditions = append(conditions, fmt.Sprintf("%s %s %s", exprs[i], keysetOperator(column.desc), values[i]))
+		alternatives[i] = "(" + strings.Join(conditions, " AND ") + ")"
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args, nil
+}
+
+// keysetOperator returns the comparison selecting the rows after a key
+func keysetOperator(desc bool) string {
+	if desc {
+		return "<"
+	}
+	return ">"
+}
+
+// pageOffset returns the OFFSET of a page number; cursor pages always start at 0
+func pageOffset(cursor *model.PageCursor, page, limit int) int {
+	if cursor != nil || page <= 1 {
+		return 0
+	}
+	return (page - 1) * limit
+}
+
+// nextCursor returns the cursor after the last row of the page when the listing fetched
+// one row more than limit (keys holds the key of every fetched row), or nil on the last page
+func nextCursor(keys [][]string, limit int) *model.PageCursor {
+	if len(keys) <= limit {
+		return nil
+	}
+	return &model.PageCursor{Key: keys[limit-1]}
+}
+
+// countRows computes the total of a listing according to the total mode. from holds the
+// FROM and WHERE clauses of the listing, without the cursor condition.
+func (db *DB) countRows(ctx context.Context, tenantID, totalMode, from string, args ...interface{}) (total int, estimate bool, err error) {
+	switch totalMode {
+	case model.TotalModeNone:
+		return 0, false, nil
+	case model.TotalModeEstimate:
+		total, err = db.estimateRows(ctx, tenantID, "SELECT 1 "+from, args...)
+		return total, true, err
+	default:
+		err = db.QueryRowWithTenant(ctx, tenantID, "SELECT COUNT(*) "+from, args...).Scan(&total)
+		return total, false, err
+	}
+}
+
+// estimateRows returns the planner's estimate of the rows a query returns, without running it
+func (db *DB) estimateRows(ctx context.Context, tenantID, query string, args ...interface{}) (int, error) {
+	var plan []byte
+	if err := db.QueryRowWithTenant(ctx, tenantID, "EXPLAIN (FORMAT JSON) "+query, args...).Scan(&plan); err != nil {
+		return 0, err
+	}
+
+	var explain []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explain); err != nil {
+		return 0, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+	if len(explain) == 0 {
+		return 0, fmt.Errorf("empty query plan")
+	}
+
+	return int(math.Round(explain[0].Plan.Rows)), nil
+}
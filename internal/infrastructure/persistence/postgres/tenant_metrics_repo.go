@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// TenantCounts holds the customer and vehicle totals of a tenant
+type TenantCounts struct {
+	TenantID        string
+	Customers       int64
+	ActiveCustomers int64
+	Vehicles        int64
+}
+
+// TenantMetricsRepository reads cross-tenant totals for the metrics endpoint
+type TenantMetricsRepository struct {
+	db *DB
+}
+
+// NewTenantMetricsRepository creates a new tenant metrics repository
+func NewTenantMetricsRepository(db *DB) *TenantMetricsRepository {
+	return &TenantMetricsRepository{db: db}
+}
+
+// CountsByTenant returns the customer and vehicle totals of every tenant
+func (r *TenantMetricsRepository) CountsByTenant(ctx context.Context) ([]TenantCounts, error) {
+	query := `
+		SELECT c.tenant_id,
+			   COUNT(*) AS customers,
+			   COUNT(*) FILTER (WHERE c.is_active) AS active_customers,
+			   COALESCE(SUM(v.vehicles), 0) AS vehicles
+		FROM customers c
+		LEFT JOIN (
+			SELECT customer_id, COUNT(*) AS vehicles
+			FROM vehicles
+			GROUP BY customer_id
+		) v ON v.customer_id = c.id
+		WHERE c.deleted_at IS NULL
+		GROUP BY c.tenant_id
+		ORDER BY c.tenant_id`
+
+	var counts []TenantCounts
+	err := r.db.SystemTransaction(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to count customers by tenant: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var c TenantCounts
+			if err := rows.Scan(&c.TenantID, &c.Customers, &c.ActiveCustomers, &c.Vehicles); err != nil {
+				return fmt.Errorf("failed to scan tenant counts: %w", err)
+			}
+			counts = append(counts, c)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
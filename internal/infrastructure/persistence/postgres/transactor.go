@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+)
+
+// savepointName names the savepoints of nested calls; PostgreSQL resolves a repeated name to
+// the most recent savepoint, so every nesting level can use the same one
+const savepointName = "nested_transaction"
+
+type transactor struct {
+	db *DB
+}
+
+// NewTransactor creates the transactor that lets services group repository calls
+func NewTransactor(db *DB) repository.Transactor {
+	return &transactor{
+		db: db,
+	}
+}
+
+// WithinTransaction runs fn in a tenant-scoped transaction bound to the context passed to fn.
+// Repository calls made with that context join it. Nested calls run in a savepoint of the
+// outer transaction: an error undoes only what the nested fn wrote and leaves the outer
+// transaction usable, even after a failed statement.
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := txFromContext(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if tx != nil {
+		return withinSavepoint(ctx, tx, func() error { return fn(ctx) })
+	}
+
+	return t.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		return fn(withBoundTx(ctx, tx, tenantID))
+	})
+}
+
+// withinSavepoint runs fn between a savepoint and its release, rolling back to it when fn fails
+func withinSavepoint(ctx context.Context, tx *sql.Tx, fn func() error) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+savepointName); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	if err := fn(); err != nil {
+		if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepointName); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback to savepoint failed: %v)", err, rollbackErr)
+		}
+		if _, releaseErr := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepointName); releaseErr != nil {
+			return fmt.Errorf("%w (savepoint release failed: %v)", err, releaseErr)
+		}
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepointName); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/encomos/api-encomos/customer-service/internal/tenancy"
+)
+
+// TestNestedTransactionRollsBackToSavepoint checks that a failed nested call undoes only its
+// own writes and that the outer transaction keeps working after the failed statement
+func TestNestedTransactionRollsBackToSavepoint(t *testing.T) {
+	db := openTestDB(t)
+	tenantID := newTestTenantID(t)
+	seedCustomers(t, db, tenantID, 0) // Solo registra la limpieza del tenant
+
+	tenant, err := tenancy.New(tenantID, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := tenancy.WithTenant(context.Background(), tenant)
+	transactor := NewTransactor(db)
+
+	insert := func(ctx context.Context, firstName string) error {
+		_, err := db.ExecWithTenant(ctx, tenantID,
+			`INSERT INTO customers (tenant_id, first_name, last_name, customer_type) VALUES ($1, $2, 'Savepoint', 'individual')`,
+			tenantID, firstName)
+		return err
+	}
+
+	err = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := insert(ctx, "outer-before"); err != nil {
+			return err
+		}
+
+		nestedErr := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := insert(ctx, "nested"); err != nil {
+				return err
+			}
+			_, err := db.ExecWithTenant(ctx, tenantID, "SELECT 1/0")
+			return err
+		})
+		if nestedErr == nil {
+			t.Error("expected the nested call to fail")
+		}
+
+		return insert(ctx, "outer-after")
+	})
+	if err != nil {
+		t.Fatalf("outer transaction failed: %v", err)
+	}
+
+	var names []string
+	err = db.TransactionWithTenant(context.Background(), tenantID, func(tx *sql.Tx) error {
+		rows, err := tx.Query("SELECT first_name FROM customers ORDER BY first_name")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "outer-after" || names[1] != "outer-before" {
+		t.Fatalf("expected only the outer rows, got %v", names)
+	}
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// TrashRepository finds the tenants whose trash has customers to purge
+type TrashRepository struct {
+	db *DB
+}
+
+// NewTrashRepository creates a new trash repository
+func NewTrashRepository(db *DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// TenantsWithDeletedBefore returns the tenants with customers that went to the trash before before
+func (r *TrashRepository) TenantsWithDeletedBefore(ctx context.Context, before time.Time) ([]string, error) {
+	query := `
+		SELECT DISTINCT tenant_id
+		FROM customers
+		WHERE deleted_at < $1
+		ORDER BY tenant_id`
+
+	var tenants []string
+	err := r.db.SystemTransaction(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, before)
+		if err != nil {
+			return fmt.Errorf("failed to list tenants with deleted customers: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var tenantID string
+			if err := rows.Scan(&tenantID); err != nil {
+				return fmt.Errorf("failed to scan tenant: %w", err)
+			}
+			tenants = append(tenants, tenantID)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tenants, nil
+}
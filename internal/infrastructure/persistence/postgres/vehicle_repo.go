@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
 	"github.com/encomos/api-encomos/customer-service/internal/port/repository"
+	"github.com/lib/pq"
 )
 
 type vehicleRepository struct {
@@ -28,35 +30,35 @@ func (r *vehicleRepository) Create(ctx context.Context, vehicle *model.Vehicle)
 		return err
 	}
 
-	query := `
-		INSERT INTO vehicles (
-			customer_id, make, model, year, vin, license_plate,
-			color, engine, notes, is_active, metadata, created_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
-		) RETURNING id, created_at, updated_at`
-
-	err = r.db.QueryRowWithTenant(ctx, tenantID, query,
-		vehicle.CustomerID,
-		vehicle.Make,
-		vehicle.Model,
-		vehicle.Year,
-		NullString(vehicle.VIN),
-		NullString(vehicle.LicensePlate),
-		NullString(vehicle.Color),
-		NullString(vehicle.Engine),
-		NullString(vehicle.Notes),
-		vehicle.IsActive,
-		vehicle.Metadata,
-		vehicle.CreatedAt,
-		vehicle.UpdatedAt,
-	).Scan(&vehicle.ID, &vehicle.CreatedAt, &vehicle.UpdatedAt)
-
-	if err != nil {
-		return fmt.Errorf("failed to create vehicle: %w", err)
-	}
+	return r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		if err := lockVehicleIdentifiers(ctx, tx, tenantID, []*model.Vehicle{vehicle}); err != nil {
+			return err
+		}
+		if err := checkVehicleIdentifiers(ctx, tx, tenantID, vehicle); err != nil {
+			return err
+		}
 
-	return nil
+		err := tx.QueryRowContext(ctx, vehicleInsertQuery,
+			vehicle.CustomerID,
+			vehicle.Make,
+			vehicle.Model,
+			vehicle.Year,
+			NullString(vehicle.VIN),
+			NullString(vehicle.LicensePlate),
+			NullString(vehicle.Color),
+			NullString(vehicle.Engine),
+			NullString(vehicle.Notes),
+			vehicle.IsActive,
+			vehicle.Metadata,
+			vehicle.CreatedAt,
+			vehicle.UpdatedAt,
+		).Scan(&vehicle.ID, &vehicle.CreatedAt, &vehicle.UpdatedAt, &vehicle.Version)
+
+		if err != nil {
+			return fmt.Errorf("failed to create vehicle: %w", conflictFromUniqueViolation(err))
+		}
+		return nil
+	})
 }
 
 // GetByID retrieves a vehicle by ID
@@ -69,15 +71,15 @@ func (r *vehicleRepository) GetByID(ctx context.Context, id string) (*model.Vehi
 	query := `
 		SELECT v.id, v.customer_id, v.make, v.model, v.year, v.vin,
 			   v.license_plate, v.color, v.engine, v.notes, v.is_active,
-			   v.metadata, v.created_at, v.updated_at
+			   v.metadata, v.created_at, v.updated_at, v.version
 		FROM vehicles v
-		INNER JOIN customers c ON v.customer_id = c.id
-		WHERE v.id = $1`
+		INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL
+		WHERE v.id = $1 AND c.tenant_id = $2`
 
 	vehicle := &model.Vehicle{}
 	var vin, licensePlate, color, engine, notes sql.NullString
 
-	err = r.db.QueryRowWithTenant(ctx, tenantID, query, id).Scan(
+	err = r.db.QueryRowWithTenant(ctx, tenantID, query, id, tenantID).Scan(
 		&vehicle.ID,
 		&vehicle.CustomerID,
 		&vehicle.Make,
@@ -92,11 +94,12 @@ func (r *vehicleRepository) GetByID(ctx context.Context, id string) (*model.Vehi
 		&vehicle.Metadata,
 		&vehicle.CreatedAt,
 		&vehicle.UpdatedAt,
+		&vehicle.Version,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("vehicle with ID %s not found", id)
+			return nil, model.NewNotFoundError("vehicle", id)
 		}
 		return nil, fmt.Errorf("failed to get vehicle: %w", err)
 	}
@@ -111,7 +114,8 @@ func (r *vehicleRepository) GetByID(ctx context.Context, id string) (*model.Vehi
 	return vehicle, nil
 }
 
-// Update updates a vehicle
+// Update updates a vehicle if its version is still vehicle.Version and bumps the version.
+// Returns a VersionConflictError if the row changed since it was read.
 func (r *vehicleRepository) Update(ctx context.Context, vehicle *model.Vehicle) error {
 	tenantID, err := GetTenantIDFromContext(ctx)
 	if err != nil {
@@ -122,43 +126,53 @@ func (r *vehicleRepository) Update(ctx context.Context, vehicle *model.Vehicle)
 		UPDATE vehicles SET
 			make = $2, model = $3, year = $4, vin = $5,
 			license_plate = $6, color = $7, engine = $8, notes = $9,
-			is_active = $10, metadata = $11, updated_at = $12
+			is_active = $10, metadata = $11, updated_at = $12,
+			version = vehicles.version + 1
 		FROM customers c
-		WHERE vehicles.id = $1 AND vehicles.customer_id = c.id`
-
-	result, err := r.db.ExecWithTenant(ctx, tenantID, query,
-		vehicle.ID,
-		vehicle.Make,
-		vehicle.Model,
-		vehicle.Year,
-		NullString(vehicle.VIN),
-		NullString(vehicle.LicensePlate),
-		NullString(vehicle.Color),
-		NullString(vehicle.Engine),
-		NullString(vehicle.Notes),
-		vehicle.IsActive,
-		vehicle.Metadata,
-		vehicle.UpdatedAt,
-	)
+		WHERE vehicles.id = $1 AND vehicles.customer_id = c.id AND c.tenant_id = $13
+		  AND vehicles.version = $14
+		RETURNING vehicles.version`
 
-	if err != nil {
-		return fmt.Errorf("failed to update vehicle: %w", err)
-	}
+	return r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		if err := lockVehicleIdentifiers(ctx, tx, tenantID, []*model.Vehicle{vehicle}); err != nil {
+			return err
+		}
+		if err := checkVehicleIdentifiers(ctx, tx, tenantID, vehicle); err != nil {
+			return err
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+		var version int64
+		err := tx.QueryRowContext(ctx, query,
+			vehicle.ID,
+			vehicle.Make,
+			vehicle.Model,
+			vehicle.Year,
+			NullString(vehicle.VIN),
+			NullString(vehicle.LicensePlate),
+			NullString(vehicle.Color),
+			NullString(vehicle.Engine),
+			NullString(vehicle.Notes),
+			vehicle.IsActive,
+			vehicle.Metadata,
+			vehicle.UpdatedAt,
+			tenantID,
+			vehicle.Version,
+		).Scan(&version)
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("vehicle with ID %s not found", vehicle.ID)
-	}
+		if err == sql.ErrNoRows {
+			return versionConflict(ctx, tx, "vehicles", "vehicle", vehicle.ID, vehicle.Version)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update vehicle: %w", conflictFromUniqueViolation(err))
+		}
 
-	return nil
+		vehicle.Version = version
+		return nil
+	})
 }
 
-// Delete deletes a vehicle
-func (r *vehicleRepository) Delete(ctx context.Context, id string) error {
+// Delete deletes a vehicle if its version is still version
+func (r *vehicleRepository) Delete(ctx context.Context, id string, version int64) error {
 	tenantID, err := GetTenantIDFromContext(ctx)
 	if err != nil {
 		return err
@@ -167,36 +181,41 @@ func (r *vehicleRepository) Delete(ctx context.Context, id string) error {
 	query := `
 		DELETE FROM vehicles 
 		USING customers c
-		WHERE vehicles.id = $1 AND vehicles.customer_id = c.id`
+		WHERE vehicles.id = $1 AND vehicles.customer_id = c.id AND c.tenant_id = $2
+		  AND vehicles.version = $3`
 
-	result, err := r.db.ExecWithTenant(ctx, tenantID, query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete vehicle: %w", err)
-	}
+	return r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id, tenantID, version)
+		if err != nil {
+			return fmt.Errorf("failed to delete vehicle: %w", err)
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("vehicle with ID %s not found", id)
-	}
+		if rowsAffected == 0 {
+			return versionConflict(ctx, tx, "vehicles", "vehicle", id, version)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // List retrieves vehicles with filtering and pagination
-func (r *vehicleRepository) List(ctx context.Context, filter model.VehicleFilter) ([]*model.Vehicle, int, error) {
+func (r *vehicleRepository) List(ctx context.Context, filter model.VehicleFilter) ([]*model.Vehicle, model.PageInfo, error) {
+	var page model.PageInfo
+
 	tenantID, err := GetTenantIDFromContext(ctx)
 	if err != nil {
-		return nil, 0, err
+		return nil, page, err
 	}
 
-	// Build WHERE clause
-	var whereConditions []string
-	var args []interface{}
-	argCount := 0
+	// Build WHERE clause, always scoped to the calling tenant
+	whereConditions := []string{"c.tenant_id = $1"}
+	args := []interface{}{tenantID}
+	argCount := 1
 
 	// Always filter by customer if provided
 	if filter.CustomerID != "" {
@@ -217,57 +236,56 @@ func (r *vehicleRepository) List(ctx context.Context, filter model.VehicleFilter
 		whereConditions = append(whereConditions, "v.is_active = true")
 	}
 
-	whereClause := ""
-	if len(whereConditions) > 0 {
-		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
-	}
+	whereClause := "WHERE " + strings.Join(whereConditions, " AND ")
 
-	// Count total records
-	countQuery := fmt.Sprintf(`
-		SELECT COUNT(*) 
-		FROM vehicles v 
-		INNER JOIN customers c ON v.customer_id = c.id 
-		%s`, whereClause)
-
-	var total int
-	err = r.db.QueryRowWithTenant(ctx, tenantID, countQuery, args...).Scan(&total)
+	// Count total records (sin la condición del cursor)
+	from := "FROM vehicles v INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL " + whereClause
+	page.Total, page.TotalIsEstimate, err = r.db.countRows(ctx, tenantID, filter.TotalMode, from, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count vehicles: %w", err)
+		return nil, page, fmt.Errorf("failed to count vehicles: %w", err)
 	}
 
-	// Build pagination
+	// Continuar tras la última fila de la página anterior
+	if filter.Cursor != nil {
+		after, afterArgs, err := vehicleListOrder.after(filter.Cursor, argCount+1)
+		if err != nil {
+			return nil, page, err
+		}
+		args = append(args, afterArgs...)
+		whereClause += " AND " + after
+	}
+
+	// Build pagination; una fila de más indica que hay otra página
 	limit := filter.Limit
 	if limit <= 0 {
 		limit = 50 // Default limit
 	}
-	offset := 0
-	if filter.Page > 0 {
-		offset = (filter.Page - 1) * limit
-	}
+	offset := pageOffset(filter.Cursor, filter.Page, limit)
 
 	// Main query
 	query := fmt.Sprintf(`
 		SELECT v.id, v.customer_id, v.make, v.model, v.year, v.vin,
 			   v.license_plate, v.color, v.engine, v.notes, v.is_active,
-			   v.metadata, v.created_at, v.updated_at
+			   v.metadata, v.created_at, v.updated_at, v.version, %s
 		FROM vehicles v
-		INNER JOIN customers c ON v.customer_id = c.id
-		%s
-		ORDER BY v.year DESC, v.make, v.model
-		LIMIT %d OFFSET %d`, whereClause, limit, offset)
+		INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL
+		%s %s
+		LIMIT %d OFFSET %d`, vehicleListOrder.keyColumns(), whereClause, vehicleListOrder.orderBy(), limit+1, offset)
 
 	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list vehicles: %w", err)
+		return nil, page, fmt.Errorf("failed to list vehicles: %w", err)
 	}
 	defer rows.Close()
 
 	var vehicles []*model.Vehicle
+	var keys [][]string
 	for rows.Next() {
 		vehicle := &model.Vehicle{}
 		var vin, licensePlate, color, engine, notes sql.NullString
+		key, keyDest := vehicleListOrder.keyDest()
 
-		err := rows.Scan(
+		err := scanWithExtra(rows, keyDest...).Scan(
 			&vehicle.ID,
 			&vehicle.CustomerID,
 			&vehicle.Make,
@@ -282,9 +300,10 @@ func (r *vehicleRepository) List(ctx context.Context, filter model.VehicleFilter
 			&vehicle.Metadata,
 			&vehicle.CreatedAt,
 			&vehicle.UpdatedAt,
+			&vehicle.Version,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan vehicle: %w", err)
+			return nil, page, fmt.Errorf("failed to scan vehicle: %w", err)
 		}
 
 		// Convert nullable fields
@@ -295,13 +314,28 @@ func (r *vehicleRepository) List(ctx context.Context, filter model.VehicleFilter
 		vehicle.Notes = StringFromNull(notes)
 
 		vehicles = append(vehicles, vehicle)
+		keys = append(keys, key)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("failed to iterate over vehicles: %w", err)
+		return nil, page, fmt.Errorf("failed to iterate over vehicles: %w", err)
+	}
+
+	page.Next = nextCursor(keys, limit)
+	if page.Next != nil {
+		vehicles = vehicles[:limit]
 	}
 
-	return vehicles, total, nil
+	return vehicles, page, nil
+}
+
+// vehicleListOrder is the order of vehicle listings: newest model year first, then make and
+// model, ending in the id so pages can continue after the last key
+var vehicleListOrder = keysetOrder{
+	{expr: "v.year", cast: "integer", desc: true},
+	{expr: "v.make", cast: "text"},
+	{expr: "v.model", cast: "text"},
+	{expr: "v.id", cast: "uuid"},
 }
 
 // ListByCustomer retrieves all vehicles for a customer
@@ -314,7 +348,7 @@ func (r *vehicleRepository) ListByCustomer(ctx context.Context, customerID strin
 	return vehicles, err
 }
 
-// GetByVIN retrieves a vehicle by VIN
+// GetByVIN retrieves a vehicle by VIN. Vehicles of customers in the trash are not found.
 func (r *vehicleRepository) GetByVIN(ctx context.Context, vin string) (*model.Vehicle, error) {
 	tenantID, err := GetTenantIDFromContext(ctx)
 	if err != nil {
@@ -324,9 +358,9 @@ func (r *vehicleRepository) GetByVIN(ctx context.Context, vin string) (*model.Ve
 	query := `
 		SELECT v.id, v.customer_id, v.make, v.model, v.year, v.vin,
 			   v.license_plate, v.color, v.engine, v.notes, v.is_active,
-			   v.metadata, v.created_at, v.updated_at
+			   v.metadata, v.created_at, v.updated_at, v.version
 		FROM vehicles v
-		INNER JOIN customers c ON v.customer_id = c.id
+		INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE v.vin = $1`
 
 	vehicle := &model.Vehicle{}
@@ -347,11 +381,12 @@ func (r *vehicleRepository) GetByVIN(ctx context.Context, vin string) (*model.Ve
 		&vehicle.Metadata,
 		&vehicle.CreatedAt,
 		&vehicle.UpdatedAt,
+		&vehicle.Version,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("vehicle with VIN %s not found", vin)
+			return nil, &model.NotFoundError{Resource: "vehicle", Key: "vin", Value: vin}
 		}
 		return nil, fmt.Errorf("failed to get vehicle by VIN: %w", err)
 	}
@@ -366,7 +401,8 @@ func (r *vehicleRepository) GetByVIN(ctx context.Context, vin string) (*model.Ve
 	return vehicle, nil
 }
 
-// GetByLicensePlate retrieves a vehicle by license plate
+// GetByLicensePlate retrieves a vehicle by license plate. Vehicles of customers in the trash
+// are not found.
 func (r *vehicleRepository) GetByLicensePlate(ctx context.Context, licensePlate string) (*model.Vehicle, error) {
 	tenantID, err := GetTenantIDFromContext(ctx)
 	if err != nil {
@@ -376,9 +412,9 @@ func (r *vehicleRepository) GetByLicensePlate(ctx context.Context, licensePlate
 	query := `
 		SELECT v.id, v.customer_id, v.make, v.model, v.year, v.vin,
 			   v.license_plate, v.color, v.engine, v.notes, v.is_active,
-			   v.metadata, v.created_at, v.updated_at
+			   v.metadata, v.created_at, v.updated_at, v.version
 		FROM vehicles v
-		INNER JOIN customers c ON v.customer_id = c.id
+		INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE v.license_plate = $1`
 
 	vehicle := &model.Vehicle{}
@@ -399,11 +435,12 @@ func (r *vehicleRepository) GetByLicensePlate(ctx context.Context, licensePlate
 		&vehicle.Metadata,
 		&vehicle.CreatedAt,
 		&vehicle.UpdatedAt,
+		&vehicle.Version,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("vehicle with license plate %s not found", licensePlate)
+			return nil, &model.NotFoundError{Resource: "vehicle", Key: "license_plate", Value: licensePlate}
 		}
 		return nil, fmt.Errorf("failed to get vehicle by license plate: %w", err)
 	}
@@ -455,9 +492,9 @@ func (r *vehicleRepository) SearchByMakeModel(ctx context.Context, make, vehicle
 	query := fmt.Sprintf(`
 		SELECT v.id, v.customer_id, v.make, v.model, v.year, v.vin,
 			   v.license_plate, v.color, v.engine, v.notes, v.is_active,
-			   v.metadata, v.created_at, v.updated_at
+			   v.metadata, v.created_at, v.updated_at, v.version
 		FROM vehicles v
-		INNER JOIN customers c ON v.customer_id = c.id
+		INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL
 		%s
 		ORDER BY v.year DESC, v.make, v.model
 		LIMIT 50`, whereClause)
@@ -488,6 +525,7 @@ func (r *vehicleRepository) SearchByMakeModel(ctx context.Context, make, vehicle
 			&vehicle.Metadata,
 			&vehicle.CreatedAt,
 			&vehicle.UpdatedAt,
+			&vehicle.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan vehicle: %w", err)
@@ -516,9 +554,9 @@ func (r *vehicleRepository) FindCompatibleVehicles(ctx context.Context, make, ve
 	query := `
 		SELECT v.id, v.customer_id, v.make, v.model, v.year, v.vin,
 			   v.license_plate, v.color, v.engine, v.notes, v.is_active,
-			   v.metadata, v.created_at, v.updated_at
+			   v.metadata, v.created_at, v.updated_at, v.version
 		FROM vehicles v
-		INNER JOIN customers c ON v.customer_id = c.id
+		INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE v.make ILIKE $1 AND v.model ILIKE $2 
 		  AND v.year BETWEEN $3 AND $4
 		  AND v.is_active = true
@@ -551,6 +589,7 @@ func (r *vehicleRepository) FindCompatibleVehicles(ctx context.Context, make, ve
 			&vehicle.Metadata,
 			&vehicle.CreatedAt,
 			&vehicle.UpdatedAt,
+			&vehicle.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan vehicle: %w", err)
@@ -579,9 +618,9 @@ func (r *vehicleRepository) ListByMakeModelYear(ctx context.Context, make, vehic
 	query := `
 		SELECT v.id, v.customer_id, v.make, v.model, v.year, v.vin,
 			   v.license_plate, v.color, v.engine, v.notes, v.is_active,
-			   v.metadata, v.created_at, v.updated_at
+			   v.metadata, v.created_at, v.updated_at, v.version
 		FROM vehicles v
-		INNER JOIN customers c ON v.customer_id = c.id
+		INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE v.make = $1 AND v.model = $2 AND v.year = $3
 		ORDER BY v.created_at DESC`
 
@@ -611,6 +650,7 @@ func (r *vehicleRepository) ListByMakeModelYear(ctx context.Context, make, vehic
 			&vehicle.Metadata,
 			&vehicle.CreatedAt,
 			&vehicle.UpdatedAt,
+			&vehicle.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan vehicle: %w", err)
@@ -640,7 +680,7 @@ func (r *vehicleRepository) Count(ctx context.Context) (int64, error) {
 	err = r.db.QueryRowWithTenant(ctx, tenantID, `
 		SELECT COUNT(*) 
 		FROM vehicles v 
-		INNER JOIN customers c ON v.customer_id = c.id`).Scan(&count)
+		INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL`).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count vehicles: %w", err)
 	}
@@ -659,7 +699,7 @@ func (r *vehicleRepository) CountByCustomer(ctx context.Context, customerID stri
 	err = r.db.QueryRowWithTenant(ctx, tenantID, `
 		SELECT COUNT(*)
 		FROM vehicles v
-		INNER JOIN customers c ON v.customer_id = c.id
+		INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE v.customer_id = $1`, customerID).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count vehicles by customer: %w", err)
@@ -679,7 +719,7 @@ func (r *vehicleRepository) CountActive(ctx context.Context) (int64, error) {
 	err = r.db.QueryRowWithTenant(ctx, tenantID, `
 		SELECT COUNT(*) 
 		FROM vehicles v 
-		INNER JOIN customers c ON v.customer_id = c.id
+		INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL
 		WHERE v.is_active = true`).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count active vehicles: %w", err)
@@ -688,7 +728,9 @@ func (r *vehicleRepository) CountActive(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
-// ExistsByVIN checks if a vehicle exists by VIN
+// ExistsByVIN checks if a vehicle exists by VIN. Vehicles of customers in the trash count on
+// purpose: the unique index still covers them and restoring the customer must not conflict,
+// so their VIN stays reserved until the customer is purged.
 func (r *vehicleRepository) ExistsByVIN(ctx context.Context, vin string, excludeID *string) (bool, error) {
 	tenantID, err := GetTenantIDFromContext(ctx)
 	if err != nil {
@@ -716,7 +758,8 @@ func (r *vehicleRepository) ExistsByVIN(ctx context.Context, vin string, exclude
 	return count > 0, nil
 }
 
-// ExistsByLicensePlate checks if a vehicle exists by license plate
+// ExistsByLicensePlate checks if a vehicle exists by license plate. Like ExistsByVIN, it
+// counts vehicles of customers in the trash, whose plates stay reserved until the purge.
 func (r *vehicleRepository) ExistsByLicensePlate(ctx context.Context, licensePlate string, excludeID *string) (bool, error) {
 	tenantID, err := GetTenantIDFromContext(ctx)
 	if err != nil {
@@ -756,38 +799,154 @@ func (r *vehicleRepository) CreateBatch(ctx context.Context, vehicles []*model.V
 	}
 
 	return r.db.TransactionWithTenant(ctx, tenantID, func(tx *sql.Tx) error {
-		query := `
-			INSERT INTO vehicles (
-				customer_id, make, model, year, vin, license_plate,
-				color, engine, notes, is_active, metadata, created_at, updated_at
-			) VALUES (
-				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
-			) RETURNING id, created_at, updated_at`
-
-		for _, vehicle := range vehicles {
-			err := tx.QueryRowContext(ctx, query,
-				vehicle.CustomerID,
-				vehicle.Make,
-				vehicle.Model,
-				vehicle.Year,
-				NullString(vehicle.VIN),
-				NullString(vehicle.LicensePlate),
-				NullString(vehicle.Color),
-				NullString(vehicle.Engine),
-				NullString(vehicle.Notes),
-				vehicle.IsActive,
-				vehicle.Metadata,
-				vehicle.CreatedAt,
-				vehicle.UpdatedAt,
-			).Scan(&vehicle.ID, &vehicle.CreatedAt, &vehicle.UpdatedAt)
+		return insertVehiclesTx(ctx, tx, tenantID, vehicles)
+	})
+}
+
+// vehicleInsertQuery inserts a vehicle; tenant_id defaults to the transaction tenant
+const vehicleInsertQuery = `
+	INSERT INTO vehicles (
+		customer_id, make, model, year, vin, license_plate,
+		color, engine, notes, is_active, metadata, created_at, updated_at
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+	) RETURNING id, created_at, updated_at, version`
+
+// insertVehiclesTx inserts vehicles inside a tenant transaction. VIN and license plate
+// uniqueness is checked under transaction-scoped advisory locks, so concurrent requests
+// registering the same VIN or plate are serialized; every conflict is reported per vehicle.
+func insertVehiclesTx(ctx context.Context, tx *sql.Tx, tenantID string, vehicles []*model.Vehicle) error {
+	if err := lockVehicleIdentifiers(ctx, tx, tenantID, vehicles); err != nil {
+		return err
+	}
 
+	// Verificar unicidad dentro de la transacción
+	report := &model.VehicleValidationReport{}
+	for i, vehicle := range vehicles {
+		if vin := normalizeVehicleIdentifier(vehicle.VIN); vin != "" {
+			taken, err := vehicleIdentifierTaken(ctx, tx, tenantID, "vin", vin, vehicle.ID)
 			if err != nil {
-				return fmt.Errorf("failed to create vehicle in batch: %w", err)
+				return err
+			}
+			if taken {
+				report.AddDuplicate(i, "vin", fmt.Sprintf("ya existe un vehículo con VIN %s", *vehicle.VIN))
 			}
 		}
 
-		return nil
-	})
+		if plate := normalizeVehicleIdentifier(vehicle.LicensePlate); plate != "" {
+			taken, err := vehicleIdentifierTaken(ctx, tx, tenantID, "license_plate", plate, vehicle.ID)
+			if err != nil {
+				return err
+			}
+			if taken {
+				report.AddDuplicate(i, "license_plate", fmt.Sprintf("ya existe un vehículo con placa %s", *vehicle.LicensePlate))
+			}
+		}
+	}
+	if report.HasIssues() {
+		return report
+	}
+
+	for _, vehicle := range vehicles {
+		err := tx.QueryRowContext(ctx, vehicleInsertQuery,
+			vehicle.CustomerID,
+			vehicle.Make,
+			vehicle.Model,
+			vehicle.Year,
+			NullString(vehicle.VIN),
+			NullString(vehicle.LicensePlate),
+			NullString(vehicle.Color),
+			NullString(vehicle.Engine),
+			NullString(vehicle.Notes),
+			vehicle.IsActive,
+			vehicle.Metadata,
+			vehicle.CreatedAt,
+			vehicle.UpdatedAt,
+		).Scan(&vehicle.ID, &vehicle.CreatedAt, &vehicle.UpdatedAt, &vehicle.Version)
+
+		if err != nil {
+			return fmt.Errorf("failed to create vehicle in batch: %w", conflictFromUniqueViolation(err))
+		}
+	}
+
+	return nil
+}
+
+// normalizeVehicleIdentifier returns the VIN or license plate used for the advisory locks
+// and the uniqueness checks, so values differing only in case or surrounding spaces collide
+func normalizeVehicleIdentifier(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(*value))
+}
+
+// lockVehicleIdentifiers takes the transaction-scoped advisory locks of the VINs and license
+// plates of vehicles, in a stable order to avoid deadlocks between transactions
+func lockVehicleIdentifiers(ctx context.Context, tx *sql.Tx, tenantID string, vehicles []*model.Vehicle) error {
+	var lockKeys []string
+	for _, vehicle := range vehicles {
+		if vin := normalizeVehicleIdentifier(vehicle.VIN); vin != "" {
+			lockKeys = append(lockKeys, tenantID+":vehicle_vin:"+vin)
+		}
+		if plate := normalizeVehicleIdentifier(vehicle.LicensePlate); plate != "" {
+			lockKeys = append(lockKeys, tenantID+":vehicle_plate:"+plate)
+		}
+	}
+	sort.Strings(lockKeys)
+
+	for _, key := range lockKeys {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", key); err != nil {
+			return fmt.Errorf("failed to lock vehicle identifiers: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkVehicleIdentifiers returns a ConflictError when the VIN or license plate of vehicle
+// belongs to another vehicle of the tenant. It must run under lockVehicleIdentifiers.
+func checkVehicleIdentifiers(ctx context.Context, tx *sql.Tx, tenantID string, vehicle *model.Vehicle) error {
+	if vin := normalizeVehicleIdentifier(vehicle.VIN); vin != "" {
+		taken, err := vehicleIdentifierTaken(ctx, tx, tenantID, "vin", vin, vehicle.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return model.NewConflictError("vehicle", "vin", *vehicle.VIN, "ya existe un vehículo con este VIN")
+		}
+	}
+
+	if plate := normalizeVehicleIdentifier(vehicle.LicensePlate); plate != "" {
+		taken, err := vehicleIdentifierTaken(ctx, tx, tenantID, "license_plate", plate, vehicle.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return model.NewConflictError("vehicle", "license_plate", *vehicle.LicensePlate, "ya existe un vehículo con esta placa")
+		}
+	}
+
+	return nil
+}
+
+// vehicleIdentifierTaken checks whether another vehicle of the tenant has the normalized
+// value in column (vin or license_plate), including vehicles of customers in the trash.
+// column must be a trusted identifier, never user input; excludeID is the vehicle itself
+// on updates and empty on inserts.
+func vehicleIdentifierTaken(ctx context.Context, tx *sql.Tx, tenantID, column, value, excludeID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM vehicles v
+			INNER JOIN customers c ON v.customer_id = c.id AND c.tenant_id = $2
+			WHERE upper(btrim(v.` + column + `)) = $1
+			  AND v.id::text <> $3
+		)`
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, query, value, tenantID, excludeID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check %s existence: %w", column, err)
+	}
+	return exists, nil
 }
 
 // ListActiveByCustomer retrieves all active vehicles for a customer
@@ -800,3 +959,97 @@ func (r *vehicleRepository) ListActiveByCustomer(ctx context.Context, customerID
 	vehicles, _, err := r.List(ctx, filter)
 	return vehicles, err
 }
+
+// ListByCustomers retrieves every vehicle of a set of customers, ordered by customer
+func (r *vehicleRepository) ListByCustomers(ctx context.Context, customerIDs []string) ([]*model.Vehicle, error) {
+	if len(customerIDs) == 0 {
+		return nil, nil
+	}
+
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT v.id, v.customer_id, v.make, v.model, v.year, v.vin,
+			   v.license_plate, v.color, v.engine, v.notes, v.is_active,
+			   v.metadata, v.created_at, v.updated_at, v.version
+		FROM vehicles v
+		INNER JOIN customers c ON v.customer_id = c.id AND c.deleted_at IS NULL
+		WHERE c.tenant_id = $1 AND v.customer_id = ANY($2)
+		ORDER BY v.customer_id, v.year DESC, v.make, v.model, v.id`
+
+	rows, err := r.db.QueryWithTenant(ctx, tenantID, query, tenantID, pq.Array(customerIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vehicles by customers: %w", err)
+	}
+	defer rows.Close()
+
+	var vehicles []*model.Vehicle
+	for rows.Next() {
+		vehicle := &model.Vehicle{}
+		var vin, licensePlate, color, engine, notes sql.NullString
+
+		err := rows.Scan(
+			&vehicle.ID,
+			&vehicle.CustomerID,
+			&vehicle.Make,
+			&vehicle.Model,
+			&vehicle.Year,
+			&vin,
+			&licensePlate,
+			&color,
+			&engine,
+			&notes,
+			&vehicle.IsActive,
+			&vehicle.Metadata,
+			&vehicle.CreatedAt,
+			&vehicle.UpdatedAt,
+			&vehicle.Version,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vehicle: %w", err)
+		}
+
+		// Convert nullable fields
+		vehicle.VIN = StringFromNull(vin)
+		vehicle.LicensePlate = StringFromNull(licensePlate)
+		vehicle.Color = StringFromNull(color)
+		vehicle.Engine = StringFromNull(engine)
+		vehicle.Notes = StringFromNull(notes)
+
+		vehicles = append(vehicles, vehicle)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over vehicles: %w", err)
+	}
+
+	return vehicles, nil
+}
+
+// ClearNotesByCustomer empties the notes of every vehicle of a customer, bumping the version
+// of the vehicles that had any, and returns how many changed
+func (r *vehicleRepository) ClearNotesByCustomer(ctx context.Context, customerID string) (int64, error) {
+	tenantID, err := GetTenantIDFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		UPDATE vehicles SET notes = NULL, updated_at = NOW(), version = version + 1
+		WHERE customer_id = $1 AND notes IS NOT NULL`
+
+	result, err := r.db.ExecWithTenant(ctx, tenantID, query, customerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear vehicle notes: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+	"github.com/encomos/api-encomos/customer-service/internal/tenancy"
+)
+
+// TestConcurrentVehicleCreatesWithSameVIN registers the same VIN, in different cases, from
+// concurrent transactions with nested repository calls: exactly one of them must win
+func TestConcurrentVehicleCreatesWithSameVIN(t *testing.T) {
+	db := openTestDB(t)
+	tenantID := newTestTenantID(t)
+	seedCustomers(t, db, tenantID, 0) // Solo registra la limpieza del tenant
+
+	var customerID string
+	err := db.QueryRowWithTenant(context.Background(), tenantID,
+		`INSERT INTO customers (tenant_id, first_name, last_name, customer_type) VALUES ($1, 'Ana', 'Vehículos', 'individual') RETURNING id`,
+		tenantID).Scan(&customerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tenant, err := tenancy.New(tenantID, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := tenancy.WithTenant(context.Background(), tenant)
+	transactor := NewTransactor(db)
+	vehicles := NewVehicleRepository(db)
+
+	const workers = 8
+	vins := []string{"1HGCM82633A004352", "1hgcm82633a004352", " 1HGCM82633A004352 "}
+
+	var wg sync.WaitGroup
+	errs := make([]error, workers)
+	start := make(chan struct{})
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			<-start
+
+			vin := vins[w%len(vins)]
+			vehicle := &model.Vehicle{CustomerID: customerID, Make: "Honda", Model: "Accord", Year: 2003, VIN: &vin, IsActive: true}
+			errs[w] = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				// La mitad usa el alta individual y la otra mitad el lote, ambas anidadas
+				if w%2 == 0 {
+					return vehicles.Create(ctx, vehicle)
+				}
+				return vehicles.CreateBatch(ctx, []*model.Vehicle{vehicle})
+			})
+		}(w)
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for w, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		var conflictErr *model.ConflictError
+		var report *model.VehicleValidationReport
+		if !errors.As(err, &conflictErr) && !errors.As(err, &report) {
+			t.Errorf("worker %d: expected a duplicate VIN error, got %v", w, err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("expected exactly one vehicle created, got %d", created)
+	}
+
+	var count int
+	err = db.QueryRowWithTenant(context.Background(), tenantID,
+		"SELECT COUNT(*) FROM vehicles WHERE upper(btrim(vin)) = $1", strings.TrimSpace(vins[0])).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("expected one stored vehicle with the VIN, got %d", count)
+	}
+}
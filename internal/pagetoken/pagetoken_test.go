@@ -0,0 +1,105 @@
+package pagetoken
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const (
+	testTenant      = "6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c10"
+	testOtherTenant = "0b7d4c1e-5a2f-4e3b-8c6d-9f1a2b3c4d5e"
+)
+
+func TestRoundTrip(t *testing.T) {
+	codec := NewCodec([]byte("test-secret"))
+	scope := Scope("ListCustomers", "name", "asc")
+	key := []string{"Ana", "García", "c-1"}
+
+	token, err := codec.Encode(testTenant, scope, key)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got, err := codec.Decode(token, testTenant, scope)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if strings.Join(got, "|") != strings.Join(key, "|") {
+		t.Errorf("expected key %v, got %v", key, got)
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	codec := NewCodec([]byte("test-secret"))
+	scope := Scope("ListCustomers", "name", "asc")
+
+	token, err := codec.Encode(testTenant, scope, []string{"Ana", "c-1"})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	encoded, signature, _ := strings.Cut(token, ".")
+
+	// Payload de otro tenant con la firma del token original
+	forged, err := codec.Encode(testOtherTenant, scope, []string{"Ana", "c-1"})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	otherSecret, err := NewCodec([]byte("other-secret")).Encode(testTenant, scope, []string{"Ana", "c-1"})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	emptyKey, err := codec.Encode(testTenant, scope, nil)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		tenant string
+		scope  string
+	}{
+		{"tampered payload", forgedPayload + "." + signature, testTenant, scope},
+		{"tampered signature", encoded + "." + strings.Repeat("A", len(signature)), testTenant, scope},
+		{"missing signature", encoded, testTenant, scope},
+		{"not base64", "!!!." + signature, testTenant, scope},
+		{"empty", "", testTenant, scope},
+		{"another tenant", token, testOtherTenant, scope},
+		{"another scope", token, testTenant, Scope("ListCustomers", "name", "desc")},
+		{"another secret", otherSecret, testTenant, scope},
+		{"empty key", emptyKey, testTenant, scope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := codec.Decode(tt.token, tt.tenant, tt.scope); !errors.Is(err, ErrInvalid) {
+				t.Errorf("expected ErrInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestScope(t *testing.T) {
+	if Scope("ListCustomers", "a", "b") != Scope("ListCustomers", "a", "b") {
+		t.Error("expected the same scope for the same parts")
+	}
+	// Las partes se separan, así que no se confunden al concatenarlas
+	if Scope("ListCustomers", "ab", "c") == Scope("ListCustomers", "a", "bc") {
+		t.Error("expected different scopes for different parts")
+	}
+}
+
+func TestNewRandomSecret(t *testing.T) {
+	first, err := NewRandomSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewRandomSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 32 || string(first) == string(second) {
+		t.Errorf("expected two different 32-byte secrets, got %x and %x", first, second)
+	}
+}
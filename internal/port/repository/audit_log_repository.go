@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// AuditLogRepository define la interfaz para el log de auditoría de clientes y vehículos
+type AuditLogRepository interface {
+	// Registro (solo inserción; debe llamarse dentro de la transacción del cambio)
+	Create(ctx context.Context, entry *model.AuditEntry) error
+
+	// Consultas
+	List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, int, error)
+
+	// Anonimización (única excepción a la inserción exclusiva): sustituye los valores no nulos
+	// de estos campos en las entradas del cliente y de los duplicados fusionados en él
+	RedactCustomer(ctx context.Context, customerID string, fields []string, text string) (int64, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// CustomerEventRepository define la interfaz para la ingesta de eventos de otros servicios
+type CustomerEventRepository interface {
+	// Ingest registra los eventos en el historial y actualiza las estadísticas en una única
+	// transacción del tenant, ignorando los eventos ya procesados
+	Ingest(ctx context.Context, events []*model.CustomerEvent) (*model.CustomerEventIngestResult, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
+)
+
+// CustomerHistoryRepository define la interfaz para el historial unificado de clientes
+type CustomerHistoryRepository interface {
+	// Registro de eventos
+	Create(ctx context.Context, item *model.CustomerHistoryItem) error
+
+	// Consultas
+	List(ctx context.Context, filter model.CustomerHistoryFilter) ([]*model.CustomerHistoryItem, int, error)
+	CountByCustomer(ctx context.Context, customerID string) (int64, error)
+
+	// Anonimización: sustituye la descripción de las entradas del cliente de estos tipos
+	ReplaceDescriptions(ctx context.Context, customerID string, types []string, text string) (int64, error)
+}
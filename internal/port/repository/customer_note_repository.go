@@ -15,7 +15,7 @@ type CustomerNoteRepository interface {
 	Delete(ctx context.Context, id string) error
 
 	// Búsquedas
-	List(ctx context.Context, filter model.CustomerNoteFilter) ([]*model.CustomerNote, int, error)
+	List(ctx context.Context, filter model.CustomerNoteFilter) ([]*model.CustomerNote, model.PageInfo, error)
 	ListByCustomer(ctx context.Context, customerID string) ([]*model.CustomerNote, error)
 	ListByCustomerAndType(ctx context.Context, customerID string, noteType string) ([]*model.CustomerNote, error)
 
@@ -37,4 +37,7 @@ type CustomerNoteRepository interface {
 	// Análisis
 	GetNoteTypesCount(ctx context.Context, customerID string) (map[string]int64, error)
 	GetMostActiveStaff(ctx context.Context, limit int) ([]map[string]interface{}, error)
+
+	// Anonimización: sustituye el texto de todas las notas del cliente
+	ReplaceTextByCustomer(ctx context.Context, customerID string, text string) (int64, error)
 }
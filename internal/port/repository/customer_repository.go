@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/encomos/api-encomos/customer-service/internal/domain/model"
 )
@@ -10,15 +11,27 @@ import (
 type CustomerRepository interface {
 	// CRUD básico
 	Create(ctx context.Context, customer *model.Customer) error
+	CreateWithVehicles(ctx context.Context, customer *model.Customer, vehicles []*model.Vehicle) error
 	GetByID(ctx context.Context, id string) (*model.Customer, error)
 	Update(ctx context.Context, customer *model.Customer) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, version int64) error
+
+	// Papelera: los clientes eliminados quedan ocultos hasta que se restauran o se purgan
+	SoftDelete(ctx context.Context, id string, version int64, deletedAt time.Time) (int64, error)
+	Restore(ctx context.Context, id string, version int64) (*model.Customer, error)
+	GetDeletedByID(ctx context.Context, id string) (*model.Customer, error)
+	PurgeDeleted(ctx context.Context, before time.Time, limit int) ([]string, error)
+
+	// Fusión de duplicados
+	Merge(ctx context.Context, survivorID string, duplicates []*model.Customer) error
+	ResolveMergedID(ctx context.Context, mergedID string) (string, error)
 
 	// Búsquedas
-	List(ctx context.Context, filter model.CustomerFilter) ([]*model.Customer, int, error)
-	Search(ctx context.Context, filter model.CustomerSearchFilter) ([]*model.Customer, error)
+	List(ctx context.Context, filter model.CustomerFilter) ([]*model.Customer, model.PageInfo, error)
+	Search(ctx context.Context, filter model.CustomerSearchFilter) ([]*model.CustomerSearchResult, int, error)
 	GetByEmail(ctx context.Context, email string) (*model.Customer, error)
 	GetByTaxID(ctx context.Context, taxID string) (*model.Customer, error)
+	FindDuplicateCandidates(ctx context.Context, probe model.DuplicateProbe, limit int) ([]*model.Customer, error)
 
 	// Consultas específicas
 	ListByType(ctx context.Context, customerType string, page, limit int) ([]*model.Customer, int, error)
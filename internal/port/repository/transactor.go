@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+)
+
+// Transactor define la interfaz para agrupar operaciones de varios repositorios en una transacción
+type Transactor interface {
+	// WithinTransaction ejecuta fn en una transacción; los repositorios llamados con el ctx
+	// recibido por fn participan en ella. Si fn devuelve error se revierte todo. Una llamada
+	// anidada se ejecuta en un savepoint: su error solo revierte lo escrito por ese fn.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
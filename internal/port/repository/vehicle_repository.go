@@ -12,10 +12,10 @@ type VehicleRepository interface {
 	Create(ctx context.Context, vehicle *model.Vehicle) error
 	GetByID(ctx context.Context, id string) (*model.Vehicle, error)
 	Update(ctx context.Context, vehicle *model.Vehicle) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, version int64) error
 
 	// Búsquedas
-	List(ctx context.Context, filter model.VehicleFilter) ([]*model.Vehicle, int, error)
+	List(ctx context.Context, filter model.VehicleFilter) ([]*model.Vehicle, model.PageInfo, error)
 	ListByCustomer(ctx context.Context, customerID string) ([]*model.Vehicle, error)
 
 	// Búsquedas específicas
@@ -32,11 +32,16 @@ type VehicleRepository interface {
 	CountByCustomer(ctx context.Context, customerID string) (int64, error)
 	CountActive(ctx context.Context) (int64, error)
 
-	// Validaciones
+	// Validaciones: cuentan también los vehículos de clientes en la papelera, que conservan
+	// su VIN y placa hasta la purga (GetByVIN y GetByLicensePlate no los devuelven)
 	ExistsByVIN(ctx context.Context, vin string, excludeID *string) (bool, error)
 	ExistsByLicensePlate(ctx context.Context, licensePlate string, excludeID *string) (bool, error)
 
 	// Operaciones en lote
 	CreateBatch(ctx context.Context, vehicles []*model.Vehicle) error
 	ListActiveByCustomer(ctx context.Context, customerID string) ([]*model.Vehicle, error)
+	ListByCustomers(ctx context.Context, customerIDs []string) ([]*model.Vehicle, error)
+
+	// Anonimización: vacía las notas de los vehículos del cliente
+	ClearNotesByCustomer(ctx context.Context, customerID string) (int64, error)
 }
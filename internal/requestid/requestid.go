@@ -0,0 +1,53 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header is the metadata key (and HTTP header, case-insensitive) carrying the request ID
+const Header = "x-request-id"
+
+// maxLength bounds request IDs accepted from callers; longer values are replaced
+const maxLength = 128
+
+// contextKey is the type used for request ID values stored in a context
+type contextKey string
+
+// RequestIDKey is the context key for the request ID
+const RequestIDKey contextKey = "request_id"
+
+// New generates a random request ID (32 hex characters)
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// Valid reports whether id can be used as a request ID: non-empty, bounded and made of
+// printable ASCII without spaces, so it is safe to log and to store
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// WithRequestID adds the request ID to the context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, RequestIDKey, id)
+}
+
+// FromContext returns the request ID of the context, or an empty string
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(RequestIDKey).(string)
+	return id
+}
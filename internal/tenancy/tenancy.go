@@ -0,0 +1,149 @@
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // Zonas horarias disponibles aunque la imagen no tenga tzdata
+)
+
+// Defaults applied when the API Gateway does not send locale or timezone
+const (
+	DefaultLocale   = "es"
+	DefaultTimezone = "UTC"
+)
+
+// ErrNoTenant is returned when the context carries no tenant
+var ErrNoTenant = errors.New("tenant ID not found in context")
+
+// contextKey is the type used for tenancy values stored in a context
+type contextKey string
+
+// TenantKey is the context key for the request tenant
+const TenantKey contextKey = "tenant"
+
+// Tenant is the tenant a request runs for
+type Tenant struct {
+	ID       string // UUID del tenant (clave de RLS)
+	License  string // Vertical licenciada (p. ej. autoparts, barbershop)
+	Locale   string // Idioma y región, p. ej. es-MX
+	Timezone string // Zona horaria IANA, p. ej. America/Mexico_City
+
+	location *time.Location
+}
+
+// New builds a validated tenant. Empty locale and timezone fall back to the defaults.
+func New(id, license, locale, timezone string) (*Tenant, error) {
+	if err := ValidateID(id); err != nil {
+		return nil, err
+	}
+
+	license = strings.ToLower(strings.TrimSpace(license))
+	if !validLicense(license) {
+		return nil, fmt.Errorf("invalid tenant license %q", license)
+	}
+
+	locale = strings.TrimSpace(locale)
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	if !validLocale(locale) {
+		return nil, fmt.Errorf("invalid tenant locale %q", locale)
+	}
+
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenant timezone %q", timezone)
+	}
+
+	return &Tenant{
+		ID:       strings.ToLower(id),
+		License:  license,
+		Locale:   locale,
+		Timezone: timezone,
+		location: location,
+	}, nil
+}
+
+// Location returns the tenant time zone
+func (t *Tenant) Location() *time.Location {
+	if t.location == nil {
+		return time.UTC
+	}
+	return t.location
+}
+
+// ValidateID checks that the tenant ID is a canonical UUID
+func ValidateID(id string) error {
+	if len(id) != 36 {
+		return fmt.Errorf("invalid tenant ID %q: must be a UUID", id)
+	}
+	for i, c := range id {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return fmt.Errorf("invalid tenant ID %q: must be a UUID", id)
+			}
+		default:
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+				return fmt.Errorf("invalid tenant ID %q: must be a UUID", id)
+			}
+		}
+	}
+	return nil
+}
+
+// WithTenant adds the tenant to the context
+func WithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, TenantKey, tenant)
+}
+
+// FromContext retrieves the tenant from the context
+func FromContext(ctx context.Context) (*Tenant, bool) {
+	tenant, ok := ctx.Value(TenantKey).(*Tenant)
+	return tenant, ok && tenant != nil
+}
+
+// IDFromContext retrieves the tenant ID from the context
+func IDFromContext(ctx context.Context) (string, error) {
+	tenant, ok := FromContext(ctx)
+	if !ok {
+		return "", ErrNoTenant
+	}
+	return tenant.ID, nil
+}
+
+// validLicense accepts an empty license or lowercase letters, digits, '-' and '_'
+func validLicense(license string) bool {
+	for _, c := range license {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return len(license) <= 50
+}
+
+// validLocale accepts language tags such as es, es-MX or pt_BR
+func validLocale(locale string) bool {
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 || len(parts) > 3 || len(parts[0]) < 2 || len(parts[0]) > 3 {
+		return false
+	}
+	for _, part := range parts {
+		if len(part) > 8 {
+			return false
+		}
+		for _, c := range part {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testTenantID = "6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c10"
+
+func TestValidateID(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{testTenantID, true},
+		{"6F1C2A52-0D0C-4B7E-9A55-3F4B8E2D9C10", true},
+		{"", false},
+		{"not-a-uuid", false},
+		{"6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c1", false},   // Un dígito de menos
+		{"6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c100", false}, // Un dígito de más
+		{"6f1c2a520d0c-4b7e-9a55-3f4b8e2d9c10-", false},  // Guiones fuera de sitio
+		{"6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9g10", false},  // Carácter no hexadecimal
+		{"{6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c}", false},
+		{"6f1c2a52-0d0c-4b7e-9a55-3f4b8e2d9c1'", false},
+	}
+	for _, tt := range tests {
+		err := ValidateID(tt.id)
+		if tt.valid && err != nil {
+			t.Errorf("ValidateID(%q): unexpected error %v", tt.id, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("ValidateID(%q): expected an error", tt.id)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name                                  string
+		id, license, locale, timezone         string
+		wantErr                               bool
+		wantLicense, wantLocale, wantTimezone string
+	}{
+		{"defaults", testTenantID, "", "", "", false, "", DefaultLocale, DefaultTimezone},
+		{"full", testTenantID, "autoparts", "es-MX", "America/Mexico_City", false, "autoparts", "es-MX", "America/Mexico_City"},
+		{"license normalized", testTenantID, " AutoParts ", "pt_BR", " Europe/Madrid ", false, "autoparts", "pt_BR", "Europe/Madrid"},
+		{"invalid ID", "tenant-1", "", "", "", true, "", "", ""},
+		{"invalid license", testTenantID, "auto parts", "", "", true, "", "", ""},
+		{"invalid locale", testTenantID, "", "e", "", true, "", "", ""},
+		{"locale with symbols", testTenantID, "", "es-MX;drop", "", true, "", "", ""},
+		{"invalid timezone", testTenantID, "", "", "Mars/Olympus", true, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := New(tt.id, tt.license, tt.locale, tt.timezone)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", tenant)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if tenant.License != tt.wantLicense || tenant.Locale != tt.wantLocale || tenant.Timezone != tt.wantTimezone {
+				t.Errorf("unexpected tenant %+v", tenant)
+			}
+			if tenant.Location().String() != tt.wantTimezone {
+				t.Errorf("expected location %s, got %s", tt.wantTimezone, tenant.Location())
+			}
+		})
+	}
+}
+
+func TestNewLowercasesID(t *testing.T) {
+	tenant, err := New("6F1C2A52-0D0C-4B7E-9A55-3F4B8E2D9C10", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant.ID != testTenantID {
+		t.Errorf("expected the lower-case ID, got %s", tenant.ID)
+	}
+}
+
+func TestLocationWithoutTimezone(t *testing.T) {
+	if loc := (&Tenant{ID: testTenantID}).Location(); loc != time.UTC {
+		t.Errorf("expected UTC for a tenant built without New, got %s", loc)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("expected no tenant in an empty context")
+	}
+	if _, err := IDFromContext(context.Background()); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("expected ErrNoTenant, got %v", err)
+	}
+	if _, ok := FromContext(WithTenant(context.Background(), nil)); ok {
+		t.Error("a nil tenant must not count as a tenant")
+	}
+
+	tenant, err := New(testTenantID, "barbershop", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithTenant(context.Background(), tenant)
+
+	got, ok := FromContext(ctx)
+	if !ok || got != tenant {
+		t.Fatalf("expected the tenant in context, got %+v", got)
+	}
+	id, err := IDFromContext(ctx)
+	if err != nil || id != testTenantID {
+		t.Errorf("expected ID %s, got %q (%v)", testTenantID, id, err)
+	}
+}
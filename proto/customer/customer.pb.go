@@ -9,6 +9,7 @@ package customerpb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	structpb "google.golang.org/protobuf/types/known/structpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
@@ -45,6 +46,10 @@ type Customer struct {
 	Stats         *CustomerStats         `protobuf:"bytes,17,opt,name=stats,proto3" json:"stats,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Version       int64                  `protobuf:"varint,20,opt,name=version,proto3" json:"version,omitempty"`                              // Incremented on every update; send it back as expected_version
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,21,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`          // Set only for customers in the trash
+	PurgeAt       *timestamppb.Timestamp `protobuf:"bytes,22,opt,name=purge_at,json=purgeAt,proto3" json:"purge_at,omitempty"`                // When the trash purge deletes it for good
+	AnonymizedAt  *timestamppb.Timestamp `protobuf:"bytes,23,opt,name=anonymized_at,json=anonymizedAt,proto3" json:"anonymized_at,omitempty"` // Set once its personal data was erased
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -212,6 +217,34 @@ func (x *Customer) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Customer) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Customer) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Customer) GetPurgeAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PurgeAt
+	}
+	return nil
+}
+
+func (x *Customer) GetAnonymizedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AnonymizedAt
+	}
+	return nil
+}
+
 type Vehicle struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -228,6 +261,7 @@ type Vehicle struct {
 	Metadata      *structpb.Struct       `protobuf:"bytes,12,opt,name=metadata,proto3" json:"metadata,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Version       int64                  `protobuf:"varint,15,opt,name=version,proto3" json:"version,omitempty"` // Incremented on every update; send it back as expected_version
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -360,6 +394,13 @@ func (x *Vehicle) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Vehicle) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type CustomerNote struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -461,6 +502,7 @@ type CustomerStats struct {
 	VisitsCount       int32                  `protobuf:"varint,5,opt,name=visits_count,json=visitsCount,proto3" json:"visits_count,omitempty"`
 	FavoriteCategory  string                 `protobuf:"bytes,6,opt,name=favorite_category,json=favoriteCategory,proto3" json:"favorite_category,omitempty"`
 	FavoriteProducts  []string               `protobuf:"bytes,7,rep,name=favorite_products,json=favoriteProducts,proto3" json:"favorite_products,omitempty"`
+	Level             string                 `protobuf:"bytes,8,opt,name=level,proto3" json:"level,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -544,17 +586,31 @@ func (x *CustomerStats) GetFavoriteProducts() []string {
 	return nil
 }
 
+func (x *CustomerStats) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
 // Customer Requests/Responses
 type ListCustomersRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	Search        string                 `protobuf:"bytes,2,opt,name=search,proto3" json:"search,omitempty"`
-	CustomerType  string                 `protobuf:"bytes,3,opt,name=customer_type,json=customerType,proto3" json:"customer_type,omitempty"`
-	ActiveOnly    bool                   `protobuf:"varint,4,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
-	Page          int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
-	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
-	SortBy        string                 `protobuf:"bytes,7,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`          // name, created_at, last_visit, total_spent
-	SortOrder     string                 `protobuf:"bytes,8,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"` // asc, desc
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	TenantId     string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Search       string                 `protobuf:"bytes,2,opt,name=search,proto3" json:"search,omitempty"`
+	CustomerType string                 `protobuf:"bytes,3,opt,name=customer_type,json=customerType,proto3" json:"customer_type,omitempty"`
+	ActiveOnly   bool                   `protobuf:"varint,4,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
+	Page         int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
+	Limit        int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	SortBy       string                 `protobuf:"bytes,7,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`           // name, created_at, company_name, last_visit, total_spent, relevance (default with search)
+	SortOrder    string                 `protobuf:"bytes,8,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`  // asc, desc
+	PageToken    string                 `protobuf:"bytes,9,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`  // next_page_token of the previous page; replaces page
+	TotalMode    string                 `protobuf:"bytes,10,opt,name=total_mode,json=totalMode,proto3" json:"total_mode,omitempty"` // exact (default), estimate, none
+	// Stats filters; customers without stats are left out
+	MinTotalSpent float64                `protobuf:"fixed64,11,opt,name=min_total_spent,json=minTotalSpent,proto3" json:"min_total_spent,omitempty"` // 0 = no filter
+	Level         string                 `protobuf:"bytes,12,opt,name=level,proto3" json:"level,omitempty"`                                          // Bronze, Silver, Gold, Premium, VIP
+	LastVisitFrom *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=last_visit_from,json=lastVisitFrom,proto3" json:"last_visit_from,omitempty"`
+	LastVisitTo   *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=last_visit_to,json=lastVisitTo,proto3" json:"last_visit_to,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -645,15 +701,59 @@ func (x *ListCustomersRequest) GetSortOrder() string {
 	return ""
 }
 
+func (x *ListCustomersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListCustomersRequest) GetTotalMode() string {
+	if x != nil {
+		return x.TotalMode
+	}
+	return ""
+}
+
+func (x *ListCustomersRequest) GetMinTotalSpent() float64 {
+	if x != nil {
+		return x.MinTotalSpent
+	}
+	return 0
+}
+
+func (x *ListCustomersRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *ListCustomersRequest) GetLastVisitFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastVisitFrom
+	}
+	return nil
+}
+
+func (x *ListCustomersRequest) GetLastVisitTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastVisitTo
+	}
+	return nil
+}
+
 type ListCustomersResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Customers     []*Customer            `protobuf:"bytes,1,rep,name=customers,proto3" json:"customers,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
-	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
-	TotalPages    int32                  `protobuf:"varint,5,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Customers       []*Customer            `protobuf:"bytes,1,rep,name=customers,proto3" json:"customers,omitempty"` // with stats, when the customer has them
+	Total           int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`        // 0 with total_mode none
+	Page            int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	Limit           int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	TotalPages      int32                  `protobuf:"varint,5,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	NextPageToken   string                 `protobuf:"bytes,6,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
+	TotalIsEstimate bool                   `protobuf:"varint,7,opt,name=total_is_estimate,json=totalIsEstimate,proto3" json:"total_is_estimate,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ListCustomersResponse) Reset() {
@@ -721,6 +821,20 @@ func (x *ListCustomersResponse) GetTotalPages() int32 {
 	return 0
 }
 
+func (x *ListCustomersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListCustomersResponse) GetTotalIsEstimate() bool {
+	if x != nil {
+		return x.TotalIsEstimate
+	}
+	return false
+}
+
 type GetCustomerRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	TenantId        string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
@@ -982,10 +1096,13 @@ func (x *CreateCustomerRequest) GetVehicles() []*CreateVehicleRequest {
 }
 
 type CreateCustomerResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Customer      *Customer              `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Customer *Customer              `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
+	// Existing customers that look like duplicates, best first. They are below the block
+	// threshold: above it the customer is not created (ALREADY_EXISTS).
+	PossibleDuplicates []*DuplicateCandidate `protobuf:"bytes,2,rep,name=possible_duplicates,json=possibleDuplicates,proto3" json:"possible_duplicates,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *CreateCustomerResponse) Reset() {
@@ -1025,24 +1142,36 @@ func (x *CreateCustomerResponse) GetCustomer() *Customer {
 	return nil
 }
 
+func (x *CreateCustomerResponse) GetPossibleDuplicates() []*DuplicateCandidate {
+	if x != nil {
+		return x.PossibleDuplicates
+	}
+	return nil
+}
+
 type UpdateCustomerRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	FirstName     string                 `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
-	LastName      string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
-	Email         string                 `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
-	Phone         string                 `protobuf:"bytes,6,opt,name=phone,proto3" json:"phone,omitempty"`
-	CustomerType  string                 `protobuf:"bytes,7,opt,name=customer_type,json=customerType,proto3" json:"customer_type,omitempty"`
-	CompanyName   string                 `protobuf:"bytes,8,opt,name=company_name,json=companyName,proto3" json:"company_name,omitempty"`
-	TaxId         string                 `protobuf:"bytes,9,opt,name=tax_id,json=taxId,proto3" json:"tax_id,omitempty"`
-	Address       string                 `protobuf:"bytes,10,opt,name=address,proto3" json:"address,omitempty"`
-	Birthday      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=birthday,proto3" json:"birthday,omitempty"`
-	Notes         string                 `protobuf:"bytes,12,opt,name=notes,proto3" json:"notes,omitempty"`
-	Preferences   *structpb.Struct       `protobuf:"bytes,13,opt,name=preferences,proto3" json:"preferences,omitempty"`
-	IsActive      bool                   `protobuf:"varint,14,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	TenantId     string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Id           string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	FirstName    string                 `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName     string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email        string                 `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	Phone        string                 `protobuf:"bytes,6,opt,name=phone,proto3" json:"phone,omitempty"`
+	CustomerType string                 `protobuf:"bytes,7,opt,name=customer_type,json=customerType,proto3" json:"customer_type,omitempty"`
+	CompanyName  string                 `protobuf:"bytes,8,opt,name=company_name,json=companyName,proto3" json:"company_name,omitempty"`
+	TaxId        string                 `protobuf:"bytes,9,opt,name=tax_id,json=taxId,proto3" json:"tax_id,omitempty"`
+	Address      string                 `protobuf:"bytes,10,opt,name=address,proto3" json:"address,omitempty"`
+	Birthday     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=birthday,proto3" json:"birthday,omitempty"`
+	Notes        string                 `protobuf:"bytes,12,opt,name=notes,proto3" json:"notes,omitempty"`
+	Preferences  *structpb.Struct       `protobuf:"bytes,13,opt,name=preferences,proto3" json:"preferences,omitempty"`
+	IsActive     bool                   `protobuf:"varint,14,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	// Fields to update. Only listed paths are applied; a listed field left empty is cleared.
+	// Without a mask only non-empty fields are applied.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,15,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Version the client read; on mismatch the call fails with FAILED_PRECONDITION. 0 skips the check.
+	ExpectedVersion int64 `protobuf:"varint,16,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *UpdateCustomerRequest) Reset() {
@@ -1173,6 +1302,20 @@ func (x *UpdateCustomerRequest) GetIsActive() bool {
 	return false
 }
 
+func (x *UpdateCustomerRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateCustomerRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type UpdateCustomerResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Customer      *Customer              `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
@@ -1218,11 +1361,13 @@ func (x *UpdateCustomerResponse) GetCustomer() *Customer {
 }
 
 type DeleteCustomerRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TenantId        string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Id              string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // 0 skips the check
+	DryRun          bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                            // Return what the deletion affects without deleting anything
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *DeleteCustomerRequest) Reset() {
@@ -1269,9 +1414,24 @@ func (x *DeleteCustomerRequest) GetId() string {
 	return ""
 }
 
+func (x *DeleteCustomerRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *DeleteCustomerRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
 type DeleteCustomerResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"` // false in a dry run
+	Impact        *DeleteImpact          `protobuf:"bytes,2,opt,name=impact,proto3" json:"impact,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1313,32 +1473,39 @@ func (x *DeleteCustomerResponse) GetSuccess() bool {
 	return false
 }
 
-// Vehicle Requests/Responses
-type ListVehiclesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
-	Search        string                 `protobuf:"bytes,2,opt,name=search,proto3" json:"search,omitempty"`
-	ActiveOnly    bool                   `protobuf:"varint,3,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
-	Page          int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
-	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *DeleteCustomerResponse) GetImpact() *DeleteImpact {
+	if x != nil {
+		return x.Impact
+	}
+	return nil
 }
 
-func (x *ListVehiclesRequest) Reset() {
-	*x = ListVehiclesRequest{}
+// What a deleted customer takes to the trash with it; all of it is purged with the customer
+type DeleteImpact struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Vehicles       int64                  `protobuf:"varint,1,opt,name=vehicles,proto3" json:"vehicles,omitempty"`
+	Notes          int64                  `protobuf:"varint,2,opt,name=notes,proto3" json:"notes,omitempty"`
+	HistoryEntries int64                  `protobuf:"varint,3,opt,name=history_entries,json=historyEntries,proto3" json:"history_entries,omitempty"`
+	HasStats       bool                   `protobuf:"varint,4,opt,name=has_stats,json=hasStats,proto3" json:"has_stats,omitempty"`
+	PurgeAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=purge_at,json=purgeAt,proto3" json:"purge_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeleteImpact) Reset() {
+	*x = DeleteImpact{}
 	mi := &file_customer_customer_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListVehiclesRequest) String() string {
+func (x *DeleteImpact) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListVehiclesRequest) ProtoMessage() {}
+func (*DeleteImpact) ProtoMessage() {}
 
-func (x *ListVehiclesRequest) ProtoReflect() protoreflect.Message {
+func (x *DeleteImpact) ProtoReflect() protoreflect.Message {
 	mi := &file_customer_customer_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1350,68 +1517,68 @@ func (x *ListVehiclesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListVehiclesRequest.ProtoReflect.Descriptor instead.
-func (*ListVehiclesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteImpact.ProtoReflect.Descriptor instead.
+func (*DeleteImpact) Descriptor() ([]byte, []int) {
 	return file_customer_customer_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *ListVehiclesRequest) GetCustomerId() string {
+func (x *DeleteImpact) GetVehicles() int64 {
 	if x != nil {
-		return x.CustomerId
+		return x.Vehicles
 	}
-	return ""
+	return 0
 }
 
-func (x *ListVehiclesRequest) GetSearch() string {
+func (x *DeleteImpact) GetNotes() int64 {
 	if x != nil {
-		return x.Search
+		return x.Notes
 	}
-	return ""
+	return 0
 }
 
-func (x *ListVehiclesRequest) GetActiveOnly() bool {
+func (x *DeleteImpact) GetHistoryEntries() int64 {
 	if x != nil {
-		return x.ActiveOnly
+		return x.HistoryEntries
 	}
-	return false
+	return 0
 }
 
-func (x *ListVehiclesRequest) GetPage() int32 {
+func (x *DeleteImpact) GetHasStats() bool {
 	if x != nil {
-		return x.Page
+		return x.HasStats
 	}
-	return 0
+	return false
 }
 
-func (x *ListVehiclesRequest) GetLimit() int32 {
+func (x *DeleteImpact) GetPurgeAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Limit
+		return x.PurgeAt
 	}
-	return 0
+	return nil
 }
 
-type ListVehiclesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Vehicles      []*Vehicle             `protobuf:"bytes,1,rep,name=vehicles,proto3" json:"vehicles,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type RestoreCustomerRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // Of the deleted customer; 0 skips the check
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *ListVehiclesResponse) Reset() {
-	*x = ListVehiclesResponse{}
+func (x *RestoreCustomerRequest) Reset() {
+	*x = RestoreCustomerRequest{}
 	mi := &file_customer_customer_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListVehiclesResponse) String() string {
+func (x *RestoreCustomerRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListVehiclesResponse) ProtoMessage() {}
+func (*RestoreCustomerRequest) ProtoMessage() {}
 
-func (x *ListVehiclesResponse) ProtoReflect() protoreflect.Message {
+func (x *RestoreCustomerRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_customer_customer_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1423,46 +1590,46 @@ func (x *ListVehiclesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListVehiclesResponse.ProtoReflect.Descriptor instead.
-func (*ListVehiclesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RestoreCustomerRequest.ProtoReflect.Descriptor instead.
+func (*RestoreCustomerRequest) Descriptor() ([]byte, []int) {
 	return file_customer_customer_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *ListVehiclesResponse) GetVehicles() []*Vehicle {
+func (x *RestoreCustomerRequest) GetId() string {
 	if x != nil {
-		return x.Vehicles
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-func (x *ListVehiclesResponse) GetTotal() int32 {
+func (x *RestoreCustomerRequest) GetExpectedVersion() int64 {
 	if x != nil {
-		return x.Total
+		return x.ExpectedVersion
 	}
 	return 0
 }
 
-type GetVehicleRequest struct {
+type RestoreCustomerResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Customer      *Customer              `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetVehicleRequest) Reset() {
-	*x = GetVehicleRequest{}
+func (x *RestoreCustomerResponse) Reset() {
+	*x = RestoreCustomerResponse{}
 	mi := &file_customer_customer_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetVehicleRequest) String() string {
+func (x *RestoreCustomerResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetVehicleRequest) ProtoMessage() {}
+func (*RestoreCustomerResponse) ProtoMessage() {}
 
-func (x *GetVehicleRequest) ProtoReflect() protoreflect.Message {
+func (x *RestoreCustomerResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_customer_customer_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1474,39 +1641,41 @@ func (x *GetVehicleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetVehicleRequest.ProtoReflect.Descriptor instead.
-func (*GetVehicleRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RestoreCustomerResponse.ProtoReflect.Descriptor instead.
+func (*RestoreCustomerResponse) Descriptor() ([]byte, []int) {
 	return file_customer_customer_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *GetVehicleRequest) GetId() string {
+func (x *RestoreCustomerResponse) GetCustomer() *Customer {
 	if x != nil {
-		return x.Id
+		return x.Customer
 	}
-	return ""
+	return nil
 }
 
-type GetVehicleResponse struct {
+type ListDeletedCustomersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Vehicle       *Vehicle               `protobuf:"bytes,1,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	Search        string                 `protobuf:"bytes,1,opt,name=search,proto3" json:"search,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetVehicleResponse) Reset() {
-	*x = GetVehicleResponse{}
+func (x *ListDeletedCustomersRequest) Reset() {
+	*x = ListDeletedCustomersRequest{}
 	mi := &file_customer_customer_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetVehicleResponse) String() string {
+func (x *ListDeletedCustomersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetVehicleResponse) ProtoMessage() {}
+func (*ListDeletedCustomersRequest) ProtoMessage() {}
 
-func (x *GetVehicleResponse) ProtoReflect() protoreflect.Message {
+func (x *ListDeletedCustomersRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_customer_customer_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1518,49 +1687,1852 @@ func (x *GetVehicleResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetVehicleResponse.ProtoReflect.Descriptor instead.
-func (*GetVehicleResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListDeletedCustomersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeletedCustomersRequest) Descriptor() ([]byte, []int) {
 	return file_customer_customer_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *GetVehicleResponse) GetVehicle() *Vehicle {
+func (x *ListDeletedCustomersRequest) GetSearch() string {
 	if x != nil {
-		return x.Vehicle
+		return x.Search
 	}
-	return nil
+	return ""
 }
 
-type CreateVehicleRequest struct {
+func (x *ListDeletedCustomersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDeletedCustomersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListDeletedCustomersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
-	Make          string                 `protobuf:"bytes,2,opt,name=make,proto3" json:"make,omitempty"`
-	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
-	Year          int32                  `protobuf:"varint,4,opt,name=year,proto3" json:"year,omitempty"`
-	Vin           string                 `protobuf:"bytes,5,opt,name=vin,proto3" json:"vin,omitempty"`
-	LicensePlate  string                 `protobuf:"bytes,6,opt,name=license_plate,json=licensePlate,proto3" json:"license_plate,omitempty"`
-	Color         string                 `protobuf:"bytes,7,opt,name=color,proto3" json:"color,omitempty"`
-	Engine        string                 `protobuf:"bytes,8,opt,name=engine,proto3" json:"engine,omitempty"`
-	Notes         string                 `protobuf:"bytes,9,opt,name=notes,proto3" json:"notes,omitempty"`
-	Metadata      *structpb.Struct       `protobuf:"bytes,10,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Customers     []*Customer            `protobuf:"bytes,1,rep,name=customers,proto3" json:"customers,omitempty"`                                // Most recently deleted first, with deleted_at and purge_at
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateVehicleRequest) Reset() {
-	*x = CreateVehicleRequest{}
+func (x *ListDeletedCustomersResponse) Reset() {
+	*x = ListDeletedCustomersResponse{}
+	mi := &file_customer_customer_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeletedCustomersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeletedCustomersResponse) ProtoMessage() {}
+
+func (x *ListDeletedCustomersResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_customer_customer_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeletedCustomersResponse.ProtoReflect.Descriptor instead.
+func (*ListDeletedCustomersResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListDeletedCustomersResponse) GetCustomers() []*Customer {
+	if x != nil {
+		return x.Customers
+	}
+	return nil
+}
+
+func (x *ListDeletedCustomersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Data protection Requests/Responses
+type AnonymizeCustomerRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // 0 skips the check
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AnonymizeCustomerRequest) Reset() {
+	*x = AnonymizeCustomerRequest{}
+	mi := &file_customer_customer_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnonymizeCustomerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnonymizeCustomerRequest) ProtoMessage() {}
+
+func (x *AnonymizeCustomerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnonymizeCustomerRequest.ProtoReflect.Descriptor instead.
+func (*AnonymizeCustomerRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AnonymizeCustomerRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AnonymizeCustomerRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type AnonymizeCustomerResponse struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Customer                 *Customer              `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
+	AnonymizedNotes          int64                  `protobuf:"varint,2,opt,name=anonymized_notes,json=anonymizedNotes,proto3" json:"anonymized_notes,omitempty"`                              // Notes whose text was replaced
+	AnonymizedHistoryEntries int64                  `protobuf:"varint,3,opt,name=anonymized_history_entries,json=anonymizedHistoryEntries,proto3" json:"anonymized_history_entries,omitempty"` // Note and merge history entries whose description was replaced
+	ClearedVehicleNotes      int64                  `protobuf:"varint,4,opt,name=cleared_vehicle_notes,json=clearedVehicleNotes,proto3" json:"cleared_vehicle_notes,omitempty"`                // Vehicles whose notes were emptied
+	RedactedAuditEntries     int64                  `protobuf:"varint,5,opt,name=redacted_audit_entries,json=redactedAuditEntries,proto3" json:"redacted_audit_entries,omitempty"`             // Previous audit entries whose personal values were replaced
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *AnonymizeCustomerResponse) Reset() {
+	*x = AnonymizeCustomerResponse{}
+	mi := &file_customer_customer_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnonymizeCustomerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnonymizeCustomerResponse) ProtoMessage() {}
+
+func (x *AnonymizeCustomerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnonymizeCustomerResponse.ProtoReflect.Descriptor instead.
+func (*AnonymizeCustomerResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AnonymizeCustomerResponse) GetCustomer() *Customer {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+func (x *AnonymizeCustomerResponse) GetAnonymizedNotes() int64 {
+	if x != nil {
+		return x.AnonymizedNotes
+	}
+	return 0
+}
+
+func (x *AnonymizeCustomerResponse) GetAnonymizedHistoryEntries() int64 {
+	if x != nil {
+		return x.AnonymizedHistoryEntries
+	}
+	return 0
+}
+
+func (x *AnonymizeCustomerResponse) GetClearedVehicleNotes() int64 {
+	if x != nil {
+		return x.ClearedVehicleNotes
+	}
+	return 0
+}
+
+func (x *AnonymizeCustomerResponse) GetRedactedAuditEntries() int64 {
+	if x != nil {
+		return x.RedactedAuditEntries
+	}
+	return 0
+}
+
+type ExportCustomerDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportCustomerDataRequest) Reset() {
+	*x = ExportCustomerDataRequest{}
+	mi := &file_customer_customer_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportCustomerDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportCustomerDataRequest) ProtoMessage() {}
+
+func (x *ExportCustomerDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportCustomerDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportCustomerDataRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ExportCustomerDataRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Chunks of the JSON bundle with the customer (and its preferences), vehicles, notes and
+// stats; the first one carries content_type and filename
+type ExportCustomerDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // application/json
+	Filename      string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportCustomerDataResponse) Reset() {
+	*x = ExportCustomerDataResponse{}
+	mi := &file_customer_customer_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportCustomerDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportCustomerDataResponse) ProtoMessage() {}
+
+func (x *ExportCustomerDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportCustomerDataResponse.ProtoReflect.Descriptor instead.
+func (*ExportCustomerDataResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExportCustomerDataResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExportCustomerDataResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ExportCustomerDataResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+// Vehicle Requests/Responses
+type ListVehiclesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Search        string                 `protobuf:"bytes,2,opt,name=search,proto3" json:"search,omitempty"`
+	ActiveOnly    bool                   `protobuf:"varint,3,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
+	Page          int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	PageToken     string                 `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page; replaces page
+	TotalMode     string                 `protobuf:"bytes,7,opt,name=total_mode,json=totalMode,proto3" json:"total_mode,omitempty"` // exact (default), estimate, none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVehiclesRequest) Reset() {
+	*x = ListVehiclesRequest{}
+	mi := &file_customer_customer_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVehiclesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVehiclesRequest) ProtoMessage() {}
+
+func (x *ListVehiclesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVehiclesRequest.ProtoReflect.Descriptor instead.
+func (*ListVehiclesRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListVehiclesRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *ListVehiclesRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListVehiclesRequest) GetActiveOnly() bool {
+	if x != nil {
+		return x.ActiveOnly
+	}
+	return false
+}
+
+func (x *ListVehiclesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListVehiclesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListVehiclesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListVehiclesRequest) GetTotalMode() string {
+	if x != nil {
+		return x.TotalMode
+	}
+	return ""
+}
+
+type ListVehiclesResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Vehicles        []*Vehicle             `protobuf:"bytes,1,rep,name=vehicles,proto3" json:"vehicles,omitempty"`
+	Total           int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                                       // 0 with total_mode none
+	NextPageToken   string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
+	TotalIsEstimate bool                   `protobuf:"varint,4,opt,name=total_is_estimate,json=totalIsEstimate,proto3" json:"total_is_estimate,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListVehiclesResponse) Reset() {
+	*x = ListVehiclesResponse{}
+	mi := &file_customer_customer_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVehiclesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVehiclesResponse) ProtoMessage() {}
+
+func (x *ListVehiclesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVehiclesResponse.ProtoReflect.Descriptor instead.
+func (*ListVehiclesResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListVehiclesResponse) GetVehicles() []*Vehicle {
+	if x != nil {
+		return x.Vehicles
+	}
+	return nil
+}
+
+func (x *ListVehiclesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListVehiclesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListVehiclesResponse) GetTotalIsEstimate() bool {
+	if x != nil {
+		return x.TotalIsEstimate
+	}
+	return false
+}
+
+type GetVehicleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVehicleRequest) Reset() {
+	*x = GetVehicleRequest{}
+	mi := &file_customer_customer_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVehicleRequest) ProtoMessage() {}
+
+func (x *GetVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVehicleRequest.ProtoReflect.Descriptor instead.
+func (*GetVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetVehicleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetVehicleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vehicle       *Vehicle               `protobuf:"bytes,1,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVehicleResponse) Reset() {
+	*x = GetVehicleResponse{}
+	mi := &file_customer_customer_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVehicleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVehicleResponse) ProtoMessage() {}
+
+func (x *GetVehicleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVehicleResponse.ProtoReflect.Descriptor instead.
+func (*GetVehicleResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetVehicleResponse) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+type CreateVehicleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Make          string                 `protobuf:"bytes,2,opt,name=make,proto3" json:"make,omitempty"`
+	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Year          int32                  `protobuf:"varint,4,opt,name=year,proto3" json:"year,omitempty"`
+	Vin           string                 `protobuf:"bytes,5,opt,name=vin,proto3" json:"vin,omitempty"`
+	LicensePlate  string                 `protobuf:"bytes,6,opt,name=license_plate,json=licensePlate,proto3" json:"license_plate,omitempty"`
+	Color         string                 `protobuf:"bytes,7,opt,name=color,proto3" json:"color,omitempty"`
+	Engine        string                 `protobuf:"bytes,8,opt,name=engine,proto3" json:"engine,omitempty"`
+	Notes         string                 `protobuf:"bytes,9,opt,name=notes,proto3" json:"notes,omitempty"`
+	Metadata      *structpb.Struct       `protobuf:"bytes,10,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVehicleRequest) Reset() {
+	*x = CreateVehicleRequest{}
+	mi := &file_customer_customer_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVehicleRequest) ProtoMessage() {}
+
+func (x *CreateVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVehicleRequest.ProtoReflect.Descriptor instead.
+func (*CreateVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateVehicleRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *CreateVehicleRequest) GetMake() string {
+	if x != nil {
+		return x.Make
+	}
+	return ""
+}
+
+func (x *CreateVehicleRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CreateVehicleRequest) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *CreateVehicleRequest) GetVin() string {
+	if x != nil {
+		return x.Vin
+	}
+	return ""
+}
+
+func (x *CreateVehicleRequest) GetLicensePlate() string {
+	if x != nil {
+		return x.LicensePlate
+	}
+	return ""
+}
+
+func (x *CreateVehicleRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *CreateVehicleRequest) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *CreateVehicleRequest) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *CreateVehicleRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type CreateVehicleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vehicle       *Vehicle               `protobuf:"bytes,1,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVehicleResponse) Reset() {
+	*x = CreateVehicleResponse{}
+	mi := &file_customer_customer_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVehicleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVehicleResponse) ProtoMessage() {}
+
+func (x *CreateVehicleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVehicleResponse.ProtoReflect.Descriptor instead.
+func (*CreateVehicleResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CreateVehicleResponse) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+type UpdateVehicleRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Make         string                 `protobuf:"bytes,2,opt,name=make,proto3" json:"make,omitempty"`
+	Model        string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	Year         int32                  `protobuf:"varint,4,opt,name=year,proto3" json:"year,omitempty"`
+	Vin          string                 `protobuf:"bytes,5,opt,name=vin,proto3" json:"vin,omitempty"`
+	LicensePlate string                 `protobuf:"bytes,6,opt,name=license_plate,json=licensePlate,proto3" json:"license_plate,omitempty"`
+	Color        string                 `protobuf:"bytes,7,opt,name=color,proto3" json:"color,omitempty"`
+	Engine       string                 `protobuf:"bytes,8,opt,name=engine,proto3" json:"engine,omitempty"`
+	Notes        string                 `protobuf:"bytes,9,opt,name=notes,proto3" json:"notes,omitempty"`
+	IsActive     bool                   `protobuf:"varint,10,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	Metadata     *structpb.Struct       `protobuf:"bytes,11,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// Fields to update. Only listed paths are applied; a listed field left empty is cleared.
+	// Without a mask only non-empty fields are applied.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,12,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Version the client read; on mismatch the call fails with FAILED_PRECONDITION. 0 skips the check.
+	ExpectedVersion int64 `protobuf:"varint,13,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateVehicleRequest) Reset() {
+	*x = UpdateVehicleRequest{}
+	mi := &file_customer_customer_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateVehicleRequest) ProtoMessage() {}
+
+func (x *UpdateVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateVehicleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UpdateVehicleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateVehicleRequest) GetMake() string {
+	if x != nil {
+		return x.Make
+	}
+	return ""
+}
+
+func (x *UpdateVehicleRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *UpdateVehicleRequest) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *UpdateVehicleRequest) GetVin() string {
+	if x != nil {
+		return x.Vin
+	}
+	return ""
+}
+
+func (x *UpdateVehicleRequest) GetLicensePlate() string {
+	if x != nil {
+		return x.LicensePlate
+	}
+	return ""
+}
+
+func (x *UpdateVehicleRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *UpdateVehicleRequest) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *UpdateVehicleRequest) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *UpdateVehicleRequest) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *UpdateVehicleRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UpdateVehicleRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateVehicleRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type UpdateVehicleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vehicle       *Vehicle               `protobuf:"bytes,1,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateVehicleResponse) Reset() {
+	*x = UpdateVehicleResponse{}
+	mi := &file_customer_customer_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateVehicleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateVehicleResponse) ProtoMessage() {}
+
+func (x *UpdateVehicleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateVehicleResponse.ProtoReflect.Descriptor instead.
+func (*UpdateVehicleResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UpdateVehicleResponse) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+type DeleteVehicleRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // 0 skips the check
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteVehicleRequest) Reset() {
+	*x = DeleteVehicleRequest{}
+	mi := &file_customer_customer_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVehicleRequest) ProtoMessage() {}
+
+func (x *DeleteVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVehicleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DeleteVehicleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteVehicleRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type DeleteVehicleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVehicleResponse) Reset() {
+	*x = DeleteVehicleResponse{}
+	mi := &file_customer_customer_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVehicleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVehicleResponse) ProtoMessage() {}
+
+func (x *DeleteVehicleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVehicleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteVehicleResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeleteVehicleResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// Search Requests/Responses
+type SearchCustomersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	SearchFields  string                 `protobuf:"bytes,3,opt,name=search_fields,json=searchFields,proto3" json:"search_fields,omitempty"` // Comma-separated: name, company_name, email, phone, tax_id (default all)
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                                  // Default 20, max 50
+	Page          int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchCustomersRequest) Reset() {
+	*x = SearchCustomersRequest{}
+	mi := &file_customer_customer_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchCustomersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCustomersRequest) ProtoMessage() {}
+
+func (x *SearchCustomersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCustomersRequest.ProtoReflect.Descriptor instead.
+func (*SearchCustomersRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SearchCustomersRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SearchCustomersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchCustomersRequest) GetSearchFields() string {
+	if x != nil {
+		return x.SearchFields
+	}
+	return ""
+}
+
+func (x *SearchCustomersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchCustomersRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+type SearchCustomersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Customers     []*Customer            `protobuf:"bytes,1,rep,name=customers,proto3" json:"customers,omitempty"` // Best match first
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`        // Number of matching customers, across all pages
+	Results       []*SearchResult        `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`     // Relevance of each customer, in the same order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchCustomersResponse) Reset() {
+	*x = SearchCustomersResponse{}
+	mi := &file_customer_customer_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchCustomersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCustomersResponse) ProtoMessage() {}
+
+func (x *SearchCustomersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCustomersResponse.ProtoReflect.Descriptor instead.
+func (*SearchCustomersResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *SearchCustomersResponse) GetCustomers() []*Customer {
+	if x != nil {
+		return x.Customers
+	}
+	return nil
+}
+
+func (x *SearchCustomersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SearchCustomersResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type SearchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"` // 0-1
+	Highlights    []*SearchHighlight     `protobuf:"bytes,3,rep,name=highlights,proto3" json:"highlights,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_customer_customer_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SearchResult) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *SearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SearchResult) GetHighlights() []*SearchHighlight {
+	if x != nil {
+		return x.Highlights
+	}
+	return nil
+}
+
+type SearchHighlight struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"` // first_name, last_name, company_name, email, phone, tax_id
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"` // HTML-escaped value with the matching fragments wrapped in <em></em>
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHighlight) Reset() {
+	*x = SearchHighlight{}
+	mi := &file_customer_customer_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHighlight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHighlight) ProtoMessage() {}
+
+func (x *SearchHighlight) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHighlight.ProtoReflect.Descriptor instead.
+func (*SearchHighlight) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SearchHighlight) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *SearchHighlight) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// Duplicate detection
+type FindDuplicateCustomersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"` // Duplicates of an existing customer; if set, the fields below are ignored
+	FirstName     string                 `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	CompanyName   string                 `protobuf:"bytes,4,opt,name=company_name,json=companyName,proto3" json:"company_name,omitempty"`
+	Email         string                 `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	Phone         string                 `protobuf:"bytes,6,opt,name=phone,proto3" json:"phone,omitempty"`
+	TaxId         string                 `protobuf:"bytes,7,opt,name=tax_id,json=taxId,proto3" json:"tax_id,omitempty"`
+	MinScore      float64                `protobuf:"fixed64,8,opt,name=min_score,json=minScore,proto3" json:"min_score,omitempty"` // 0-1; 0 uses the configured warn threshold
+	Limit         int32                  `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"`                        // Default 10, max 50
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindDuplicateCustomersRequest) Reset() {
+	*x = FindDuplicateCustomersRequest{}
+	mi := &file_customer_customer_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindDuplicateCustomersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindDuplicateCustomersRequest) ProtoMessage() {}
+
+func (x *FindDuplicateCustomersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindDuplicateCustomersRequest.ProtoReflect.Descriptor instead.
+func (*FindDuplicateCustomersRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *FindDuplicateCustomersRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *FindDuplicateCustomersRequest) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *FindDuplicateCustomersRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *FindDuplicateCustomersRequest) GetCompanyName() string {
+	if x != nil {
+		return x.CompanyName
+	}
+	return ""
+}
+
+func (x *FindDuplicateCustomersRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *FindDuplicateCustomersRequest) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *FindDuplicateCustomersRequest) GetTaxId() string {
+	if x != nil {
+		return x.TaxId
+	}
+	return ""
+}
+
+func (x *FindDuplicateCustomersRequest) GetMinScore() float64 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+func (x *FindDuplicateCustomersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type DuplicateMatchReason struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`           // tax_id, email, email_local_part, phone, name, company_name
+	Similarity    float64                `protobuf:"fixed64,2,opt,name=similarity,proto3" json:"similarity,omitempty"` // 1 = same normalized value
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateMatchReason) Reset() {
+	*x = DuplicateMatchReason{}
+	mi := &file_customer_customer_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateMatchReason) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateMatchReason) ProtoMessage() {}
+
+func (x *DuplicateMatchReason) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateMatchReason.ProtoReflect.Descriptor instead.
+func (*DuplicateMatchReason) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DuplicateMatchReason) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DuplicateMatchReason) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+func (x *DuplicateMatchReason) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type DuplicateCandidate struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Customer      *Customer               `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
+	Score         float64                 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"` // 0-1
+	Reasons       []*DuplicateMatchReason `protobuf:"bytes,3,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateCandidate) Reset() {
+	*x = DuplicateCandidate{}
+	mi := &file_customer_customer_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateCandidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateCandidate) ProtoMessage() {}
+
+func (x *DuplicateCandidate) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateCandidate.ProtoReflect.Descriptor instead.
+func (*DuplicateCandidate) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DuplicateCandidate) GetCustomer() *Customer {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+func (x *DuplicateCandidate) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *DuplicateCandidate) GetReasons() []*DuplicateMatchReason {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+type FindDuplicateCustomersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Candidates    []*DuplicateCandidate  `protobuf:"bytes,1,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindDuplicateCustomersResponse) Reset() {
+	*x = FindDuplicateCustomersResponse{}
+	mi := &file_customer_customer_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindDuplicateCustomersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindDuplicateCustomersResponse) ProtoMessage() {}
+
+func (x *FindDuplicateCustomersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindDuplicateCustomersResponse.ProtoReflect.Descriptor instead.
+func (*FindDuplicateCustomersResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *FindDuplicateCustomersResponse) GetCandidates() []*DuplicateCandidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+// Customer History Requests/Responses
+type GetCustomerHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // orders, appointments, notes
+	DateFrom      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
+	DateTo        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	Page          int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCustomerHistoryRequest) Reset() {
+	*x = GetCustomerHistoryRequest{}
+	mi := &file_customer_customer_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCustomerHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCustomerHistoryRequest) ProtoMessage() {}
+
+func (x *GetCustomerHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCustomerHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetCustomerHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetCustomerHistoryRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *GetCustomerHistoryRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *GetCustomerHistoryRequest) GetDateFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateFrom
+	}
+	return nil
+}
+
+func (x *GetCustomerHistoryRequest) GetDateTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateTo
+	}
+	return nil
+}
+
+func (x *GetCustomerHistoryRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetCustomerHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type CustomerHistoryItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // order, appointment, note, payment, merge
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Amount        float64                `protobuf:"fixed64,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Data          *structpb.Struct       `protobuf:"bytes,7,opt,name=data,proto3" json:"data,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomerHistoryItem) Reset() {
+	*x = CustomerHistoryItem{}
+	mi := &file_customer_customer_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomerHistoryItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomerHistoryItem) ProtoMessage() {}
+
+func (x *CustomerHistoryItem) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomerHistoryItem.ProtoReflect.Descriptor instead.
+func (*CustomerHistoryItem) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CustomerHistoryItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CustomerHistoryItem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CustomerHistoryItem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CustomerHistoryItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CustomerHistoryItem) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CustomerHistoryItem) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CustomerHistoryItem) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *CustomerHistoryItem) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type GetCustomerHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*CustomerHistoryItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCustomerHistoryResponse) Reset() {
+	*x = GetCustomerHistoryResponse{}
+	mi := &file_customer_customer_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCustomerHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCustomerHistoryResponse) ProtoMessage() {}
+
+func (x *GetCustomerHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCustomerHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetCustomerHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *GetCustomerHistoryResponse) GetItems() []*CustomerHistoryItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *GetCustomerHistoryResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type AddCustomerNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Note          string                 `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCustomerNoteRequest) Reset() {
+	*x = AddCustomerNoteRequest{}
+	mi := &file_customer_customer_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCustomerNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCustomerNoteRequest) ProtoMessage() {}
+
+func (x *AddCustomerNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCustomerNoteRequest.ProtoReflect.Descriptor instead.
+func (*AddCustomerNoteRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *AddCustomerNoteRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *AddCustomerNoteRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *AddCustomerNoteRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type AddCustomerNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *CustomerNote          `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCustomerNoteResponse) Reset() {
+	*x = AddCustomerNoteResponse{}
+	mi := &file_customer_customer_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateVehicleRequest) String() string {
+func (x *AddCustomerNoteResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateVehicleRequest) ProtoMessage() {}
+func (*AddCustomerNoteResponse) ProtoMessage() {}
 
-func (x *CreateVehicleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[18]
+func (x *AddCustomerNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1571,103 +3543,387 @@ func (x *CreateVehicleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateVehicleRequest.ProtoReflect.Descriptor instead.
-func (*CreateVehicleRequest) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use AddCustomerNoteResponse.ProtoReflect.Descriptor instead.
+func (*AddCustomerNoteResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *CreateVehicleRequest) GetCustomerId() string {
+func (x *AddCustomerNoteResponse) GetNote() *CustomerNote {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+type ListCustomerNotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	DateFrom      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
+	DateTo        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	PageToken     string                 `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page
+	TotalMode     string                 `protobuf:"bytes,7,opt,name=total_mode,json=totalMode,proto3" json:"total_mode,omitempty"` // exact (default), estimate, none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCustomerNotesRequest) Reset() {
+	*x = ListCustomerNotesRequest{}
+	mi := &file_customer_customer_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCustomerNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCustomerNotesRequest) ProtoMessage() {}
+
+func (x *ListCustomerNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCustomerNotesRequest.ProtoReflect.Descriptor instead.
+func (*ListCustomerNotesRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ListCustomerNotesRequest) GetCustomerId() string {
 	if x != nil {
 		return x.CustomerId
 	}
 	return ""
 }
 
-func (x *CreateVehicleRequest) GetMake() string {
+func (x *ListCustomerNotesRequest) GetType() string {
 	if x != nil {
-		return x.Make
+		return x.Type
 	}
 	return ""
 }
 
-func (x *CreateVehicleRequest) GetModel() string {
+func (x *ListCustomerNotesRequest) GetDateFrom() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Model
+		return x.DateFrom
+	}
+	return nil
+}
+
+func (x *ListCustomerNotesRequest) GetDateTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateTo
+	}
+	return nil
+}
+
+func (x *ListCustomerNotesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListCustomerNotesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
 	}
 	return ""
 }
 
-func (x *CreateVehicleRequest) GetYear() int32 {
+func (x *ListCustomerNotesRequest) GetTotalMode() string {
 	if x != nil {
-		return x.Year
+		return x.TotalMode
+	}
+	return ""
+}
+
+type ListCustomerNotesResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Notes           []*CustomerNote        `protobuf:"bytes,1,rep,name=notes,proto3" json:"notes,omitempty"`
+	Total           int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                                       // 0 with total_mode none
+	NextPageToken   string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
+	TotalIsEstimate bool                   `protobuf:"varint,4,opt,name=total_is_estimate,json=totalIsEstimate,proto3" json:"total_is_estimate,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListCustomerNotesResponse) Reset() {
+	*x = ListCustomerNotesResponse{}
+	mi := &file_customer_customer_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCustomerNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCustomerNotesResponse) ProtoMessage() {}
+
+func (x *ListCustomerNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCustomerNotesResponse.ProtoReflect.Descriptor instead.
+func (*ListCustomerNotesResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ListCustomerNotesResponse) GetNotes() []*CustomerNote {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+func (x *ListCustomerNotesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
 	}
 	return 0
 }
 
-func (x *CreateVehicleRequest) GetVin() string {
+func (x *ListCustomerNotesResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Vin
+		return x.NextPageToken
 	}
 	return ""
 }
 
-func (x *CreateVehicleRequest) GetLicensePlate() string {
+func (x *ListCustomerNotesResponse) GetTotalIsEstimate() bool {
 	if x != nil {
-		return x.LicensePlate
+		return x.TotalIsEstimate
+	}
+	return false
+}
+
+// Event Ingestion Requests/Responses
+type CustomerEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"` // unique per tenant, used for idempotency
+	CustomerId    string                 `protobuf:"bytes,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`     // order, appointment, payment
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"` // sales, appointments
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	Amount        float64                `protobuf:"fixed64,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status        string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	Data          *structpb.Struct       `protobuf:"bytes,9,opt,name=data,proto3" json:"data,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomerEvent) Reset() {
+	*x = CustomerEvent{}
+	mi := &file_customer_customer_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomerEvent) ProtoMessage() {}
+
+func (x *CustomerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomerEvent.ProtoReflect.Descriptor instead.
+func (*CustomerEvent) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *CustomerEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
 	}
 	return ""
 }
 
-func (x *CreateVehicleRequest) GetColor() string {
+func (x *CustomerEvent) GetCustomerId() string {
 	if x != nil {
-		return x.Color
+		return x.CustomerId
 	}
 	return ""
 }
 
-func (x *CreateVehicleRequest) GetEngine() string {
+func (x *CustomerEvent) GetType() string {
 	if x != nil {
-		return x.Engine
+		return x.Type
 	}
 	return ""
 }
 
-func (x *CreateVehicleRequest) GetNotes() string {
+func (x *CustomerEvent) GetSource() string {
 	if x != nil {
-		return x.Notes
+		return x.Source
 	}
 	return ""
 }
 
-func (x *CreateVehicleRequest) GetMetadata() *structpb.Struct {
+func (x *CustomerEvent) GetTitle() string {
 	if x != nil {
-		return x.Metadata
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CustomerEvent) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CustomerEvent) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CustomerEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CustomerEvent) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
 	}
 	return nil
 }
 
-type CreateVehicleResponse struct {
+func (x *CustomerEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type IngestCustomerEventsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Vehicle       *Vehicle               `protobuf:"bytes,1,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	Received      int32                  `protobuf:"varint,1,opt,name=received,proto3" json:"received,omitempty"`
+	Ingested      int32                  `protobuf:"varint,2,opt,name=ingested,proto3" json:"ingested,omitempty"`
+	Duplicates    int32                  `protobuf:"varint,3,opt,name=duplicates,proto3" json:"duplicates,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateVehicleResponse) Reset() {
-	*x = CreateVehicleResponse{}
-	mi := &file_customer_customer_proto_msgTypes[19]
+func (x *IngestCustomerEventsResponse) Reset() {
+	*x = IngestCustomerEventsResponse{}
+	mi := &file_customer_customer_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateVehicleResponse) String() string {
+func (x *IngestCustomerEventsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateVehicleResponse) ProtoMessage() {}
+func (*IngestCustomerEventsResponse) ProtoMessage() {}
 
-func (x *CreateVehicleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[19]
+func (x *IngestCustomerEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestCustomerEventsResponse.ProtoReflect.Descriptor instead.
+func (*IngestCustomerEventsResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *IngestCustomerEventsResponse) GetReceived() int32 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *IngestCustomerEventsResponse) GetIngested() int32 {
+	if x != nil {
+		return x.Ingested
+	}
+	return 0
+}
+
+func (x *IngestCustomerEventsResponse) GetDuplicates() int32 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
+}
+
+// Import Requests/Responses
+// The first message carries the options; it and the following ones carry consecutive
+// chunks of the file. Options sent after the first message are ignored.
+type ImportCustomersRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Format string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"` // csv (with a header row), ndjson
+	// Source column -> import field (first_name, email, vehicle_vin...). Columns already named
+	// after an import field need no mapping; any other column is ignored.
+	ColumnMapping map[string]string `protobuf:"bytes,2,rep,name=column_mapping,json=columnMapping,proto3" json:"column_mapping,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	UpsertKey     string            `protobuf:"bytes,3,opt,name=upsert_key,json=upsertKey,proto3" json:"upsert_key,omitempty"`  // email, tax_id: a row matching an existing customer updates it
+	DryRun        bool              `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`          // Apply every batch and roll it back
+	BatchSize     int32             `protobuf:"varint,5,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"` // Rows per transaction; default 100, max 1000
+	Chunk         []byte            `protobuf:"bytes,6,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportCustomersRequest) Reset() {
+	*x = ImportCustomersRequest{}
+	mi := &file_customer_customer_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportCustomersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportCustomersRequest) ProtoMessage() {}
+
+func (x *ImportCustomersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1675,53 +3931,83 @@ func (x *CreateVehicleResponse) ProtoReflect() protoreflect.Message {
 		}
 		return ms
 	}
-	return mi.MessageOf(x)
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportCustomersRequest.ProtoReflect.Descriptor instead.
+func (*ImportCustomersRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ImportCustomersRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ImportCustomersRequest) GetColumnMapping() map[string]string {
+	if x != nil {
+		return x.ColumnMapping
+	}
+	return nil
+}
+
+func (x *ImportCustomersRequest) GetUpsertKey() string {
+	if x != nil {
+		return x.UpsertKey
+	}
+	return ""
+}
+
+func (x *ImportCustomersRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
 }
 
-// Deprecated: Use CreateVehicleResponse.ProtoReflect.Descriptor instead.
-func (*CreateVehicleResponse) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{19}
+func (x *ImportCustomersRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
 }
 
-func (x *CreateVehicleResponse) GetVehicle() *Vehicle {
+func (x *ImportCustomersRequest) GetChunk() []byte {
 	if x != nil {
-		return x.Vehicle
+		return x.Chunk
 	}
 	return nil
 }
 
-type UpdateVehicleRequest struct {
+type ImportRowResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Make          string                 `protobuf:"bytes,2,opt,name=make,proto3" json:"make,omitempty"`
-	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
-	Year          int32                  `protobuf:"varint,4,opt,name=year,proto3" json:"year,omitempty"`
-	Vin           string                 `protobuf:"bytes,5,opt,name=vin,proto3" json:"vin,omitempty"`
-	LicensePlate  string                 `protobuf:"bytes,6,opt,name=license_plate,json=licensePlate,proto3" json:"license_plate,omitempty"`
-	Color         string                 `protobuf:"bytes,7,opt,name=color,proto3" json:"color,omitempty"`
-	Engine        string                 `protobuf:"bytes,8,opt,name=engine,proto3" json:"engine,omitempty"`
-	Notes         string                 `protobuf:"bytes,9,opt,name=notes,proto3" json:"notes,omitempty"`
-	IsActive      bool                   `protobuf:"varint,10,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	Metadata      *structpb.Struct       `protobuf:"bytes,11,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Line          int32                  `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`                              // Line of the file where the row starts
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                           // created, updated, skipped, error
+	CustomerId    string                 `protobuf:"bytes,3,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"` // Empty for rows created in a dry run
+	VehicleId     string                 `protobuf:"bytes,4,opt,name=vehicle_id,json=vehicleId,proto3" json:"vehicle_id,omitempty"`    // Vehicle created by the row
+	Field         string                 `protobuf:"bytes,5,opt,name=field,proto3" json:"field,omitempty"`                             // Field with the error
+	Message       string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateVehicleRequest) Reset() {
-	*x = UpdateVehicleRequest{}
-	mi := &file_customer_customer_proto_msgTypes[20]
+func (x *ImportRowResult) Reset() {
+	*x = ImportRowResult{}
+	mi := &file_customer_customer_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateVehicleRequest) String() string {
+func (x *ImportRowResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateVehicleRequest) ProtoMessage() {}
+func (*ImportRowResult) ProtoMessage() {}
 
-func (x *UpdateVehicleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[20]
+func (x *ImportRowResult) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1732,110 +4018,161 @@ func (x *UpdateVehicleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateVehicleRequest.ProtoReflect.Descriptor instead.
-func (*UpdateVehicleRequest) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use ImportRowResult.ProtoReflect.Descriptor instead.
+func (*ImportRowResult) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *UpdateVehicleRequest) GetId() string {
+func (x *ImportRowResult) GetLine() int32 {
 	if x != nil {
-		return x.Id
+		return x.Line
 	}
-	return ""
+	return 0
 }
 
-func (x *UpdateVehicleRequest) GetMake() string {
+func (x *ImportRowResult) GetStatus() string {
 	if x != nil {
-		return x.Make
+		return x.Status
 	}
 	return ""
 }
 
-func (x *UpdateVehicleRequest) GetModel() string {
+func (x *ImportRowResult) GetCustomerId() string {
 	if x != nil {
-		return x.Model
+		return x.CustomerId
 	}
 	return ""
 }
 
-func (x *UpdateVehicleRequest) GetYear() int32 {
+func (x *ImportRowResult) GetVehicleId() string {
 	if x != nil {
-		return x.Year
+		return x.VehicleId
 	}
-	return 0
+	return ""
 }
 
-func (x *UpdateVehicleRequest) GetVin() string {
+func (x *ImportRowResult) GetField() string {
 	if x != nil {
-		return x.Vin
+		return x.Field
 	}
 	return ""
 }
 
-func (x *UpdateVehicleRequest) GetLicensePlate() string {
+func (x *ImportRowResult) GetMessage() string {
 	if x != nil {
-		return x.LicensePlate
+		return x.Message
 	}
 	return ""
 }
 
-func (x *UpdateVehicleRequest) GetColor() string {
+type ImportSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          int32                  `protobuf:"varint,1,opt,name=rows,proto3" json:"rows,omitempty"`
+	Created       int32                  `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Updated       int32                  `protobuf:"varint,3,opt,name=updated,proto3" json:"updated,omitempty"`
+	Skipped       int32                  `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Errors        int32                  `protobuf:"varint,5,opt,name=errors,proto3" json:"errors,omitempty"`
+	DryRun        bool                   `protobuf:"varint,6,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportSummary) Reset() {
+	*x = ImportSummary{}
+	mi := &file_customer_customer_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSummary) ProtoMessage() {}
+
+func (x *ImportSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[52]
 	if x != nil {
-		return x.Color
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *UpdateVehicleRequest) GetEngine() string {
+// Deprecated: Use ImportSummary.ProtoReflect.Descriptor instead.
+func (*ImportSummary) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ImportSummary) GetRows() int32 {
 	if x != nil {
-		return x.Engine
+		return x.Rows
 	}
-	return ""
+	return 0
 }
 
-func (x *UpdateVehicleRequest) GetNotes() string {
+func (x *ImportSummary) GetCreated() int32 {
 	if x != nil {
-		return x.Notes
+		return x.Created
 	}
-	return ""
+	return 0
 }
 
-func (x *UpdateVehicleRequest) GetIsActive() bool {
+func (x *ImportSummary) GetUpdated() int32 {
 	if x != nil {
-		return x.IsActive
+		return x.Updated
 	}
-	return false
+	return 0
 }
 
-func (x *UpdateVehicleRequest) GetMetadata() *structpb.Struct {
+func (x *ImportSummary) GetSkipped() int32 {
 	if x != nil {
-		return x.Metadata
+		return x.Skipped
 	}
-	return nil
+	return 0
 }
 
-type UpdateVehicleResponse struct {
+func (x *ImportSummary) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// One message with the results of each batch once it is applied, and a last one with the summary
+type ImportCustomersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Vehicle       *Vehicle               `protobuf:"bytes,1,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	Results       []*ImportRowResult     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Summary       *ImportSummary         `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateVehicleResponse) Reset() {
-	*x = UpdateVehicleResponse{}
-	mi := &file_customer_customer_proto_msgTypes[21]
+func (x *ImportCustomersResponse) Reset() {
+	*x = ImportCustomersResponse{}
+	mi := &file_customer_customer_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateVehicleResponse) String() string {
+func (x *ImportCustomersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateVehicleResponse) ProtoMessage() {}
+func (*ImportCustomersResponse) ProtoMessage() {}
 
-func (x *UpdateVehicleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[21]
+func (x *ImportCustomersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1846,40 +4183,63 @@ func (x *UpdateVehicleResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateVehicleResponse.ProtoReflect.Descriptor instead.
-func (*UpdateVehicleResponse) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use ImportCustomersResponse.ProtoReflect.Descriptor instead.
+func (*ImportCustomersResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *UpdateVehicleResponse) GetVehicle() *Vehicle {
+func (x *ImportCustomersResponse) GetResults() []*ImportRowResult {
 	if x != nil {
-		return x.Vehicle
+		return x.Results
 	}
 	return nil
 }
 
-type DeleteVehicleRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+func (x *ImportCustomersResponse) GetSummary() *ImportSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+// Export Requests/Responses
+type ExportCustomersRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Format string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"` // csv (default), ndjson, vcard
+	// id, first_name, ..., total_spent, level, vehicles. Empty = every customer column, plus
+	// stats and vehicles when included, leaving out the columns the caller may not read.
+	Columns         []string `protobuf:"bytes,2,rep,name=columns,proto3" json:"columns,omitempty"`
+	IncludeVehicles bool     `protobuf:"varint,3,opt,name=include_vehicles,json=includeVehicles,proto3" json:"include_vehicles,omitempty"`
+	IncludeStats    bool     `protobuf:"varint,4,opt,name=include_stats,json=includeStats,proto3" json:"include_stats,omitempty"`
+	// Same filters and order as ListCustomers
+	Search        string                 `protobuf:"bytes,5,opt,name=search,proto3" json:"search,omitempty"`
+	CustomerType  string                 `protobuf:"bytes,6,opt,name=customer_type,json=customerType,proto3" json:"customer_type,omitempty"`
+	ActiveOnly    bool                   `protobuf:"varint,7,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
+	SortBy        string                 `protobuf:"bytes,8,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	SortOrder     string                 `protobuf:"bytes,9,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	MinTotalSpent float64                `protobuf:"fixed64,10,opt,name=min_total_spent,json=minTotalSpent,proto3" json:"min_total_spent,omitempty"`
+	Level         string                 `protobuf:"bytes,11,opt,name=level,proto3" json:"level,omitempty"`
+	LastVisitFrom *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_visit_from,json=lastVisitFrom,proto3" json:"last_visit_from,omitempty"`
+	LastVisitTo   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=last_visit_to,json=lastVisitTo,proto3" json:"last_visit_to,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteVehicleRequest) Reset() {
-	*x = DeleteVehicleRequest{}
-	mi := &file_customer_customer_proto_msgTypes[22]
+func (x *ExportCustomersRequest) Reset() {
+	*x = ExportCustomersRequest{}
+	mi := &file_customer_customer_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteVehicleRequest) String() string {
+func (x *ExportCustomersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteVehicleRequest) ProtoMessage() {}
+func (*ExportCustomersRequest) ProtoMessage() {}
 
-func (x *DeleteVehicleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[22]
+func (x *ExportCustomersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1890,88 +4250,128 @@ func (x *DeleteVehicleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteVehicleRequest.ProtoReflect.Descriptor instead.
-func (*DeleteVehicleRequest) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use ExportCustomersRequest.ProtoReflect.Descriptor instead.
+func (*ExportCustomersRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *DeleteVehicleRequest) GetId() string {
+func (x *ExportCustomersRequest) GetFormat() string {
 	if x != nil {
-		return x.Id
+		return x.Format
 	}
 	return ""
 }
 
-type DeleteVehicleResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ExportCustomersRequest) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
 }
 
-func (x *DeleteVehicleResponse) Reset() {
-	*x = DeleteVehicleResponse{}
-	mi := &file_customer_customer_proto_msgTypes[23]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ExportCustomersRequest) GetIncludeVehicles() bool {
+	if x != nil {
+		return x.IncludeVehicles
+	}
+	return false
 }
 
-func (x *DeleteVehicleResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ExportCustomersRequest) GetIncludeStats() bool {
+	if x != nil {
+		return x.IncludeStats
+	}
+	return false
 }
 
-func (*DeleteVehicleResponse) ProtoMessage() {}
-
-func (x *DeleteVehicleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[23]
+func (x *ExportCustomersRequest) GetSearch() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Search
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use DeleteVehicleResponse.ProtoReflect.Descriptor instead.
-func (*DeleteVehicleResponse) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{23}
+func (x *ExportCustomersRequest) GetCustomerType() string {
+	if x != nil {
+		return x.CustomerType
+	}
+	return ""
 }
 
-func (x *DeleteVehicleResponse) GetSuccess() bool {
+func (x *ExportCustomersRequest) GetActiveOnly() bool {
 	if x != nil {
-		return x.Success
+		return x.ActiveOnly
 	}
 	return false
 }
 
-// Search Requests/Responses
-type SearchCustomersRequest struct {
+func (x *ExportCustomersRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ExportCustomersRequest) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+func (x *ExportCustomersRequest) GetMinTotalSpent() float64 {
+	if x != nil {
+		return x.MinTotalSpent
+	}
+	return 0
+}
+
+func (x *ExportCustomersRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *ExportCustomersRequest) GetLastVisitFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastVisitFrom
+	}
+	return nil
+}
+
+func (x *ExportCustomersRequest) GetLastVisitTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastVisitTo
+	}
+	return nil
+}
+
+// The first message carries the content type and file name; the following ones carry
+// consecutive chunks of the file
+type ExportCustomersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
-	SearchFields  string                 `protobuf:"bytes,3,opt,name=search_fields,json=searchFields,proto3" json:"search_fields,omitempty"` // name, email, phone, tax_id
-	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Filename      string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SearchCustomersRequest) Reset() {
-	*x = SearchCustomersRequest{}
-	mi := &file_customer_customer_proto_msgTypes[24]
+func (x *ExportCustomersResponse) Reset() {
+	*x = ExportCustomersResponse{}
+	mi := &file_customer_customer_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchCustomersRequest) String() string {
+func (x *ExportCustomersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchCustomersRequest) ProtoMessage() {}
+func (*ExportCustomersResponse) ProtoMessage() {}
 
-func (x *SearchCustomersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[24]
+func (x *ExportCustomersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1982,62 +4382,61 @@ func (x *SearchCustomersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchCustomersRequest.ProtoReflect.Descriptor instead.
-func (*SearchCustomersRequest) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use ExportCustomersResponse.ProtoReflect.Descriptor instead.
+func (*ExportCustomersResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *SearchCustomersRequest) GetTenantId() string {
+func (x *ExportCustomersResponse) GetData() []byte {
 	if x != nil {
-		return x.TenantId
+		return x.Data
 	}
-	return ""
+	return nil
 }
 
-func (x *SearchCustomersRequest) GetQuery() string {
+func (x *ExportCustomersResponse) GetContentType() string {
 	if x != nil {
-		return x.Query
+		return x.ContentType
 	}
 	return ""
 }
 
-func (x *SearchCustomersRequest) GetSearchFields() string {
+func (x *ExportCustomersResponse) GetFilename() string {
 	if x != nil {
-		return x.SearchFields
+		return x.Filename
 	}
 	return ""
 }
 
-func (x *SearchCustomersRequest) GetLimit() int32 {
-	if x != nil {
-		return x.Limit
-	}
-	return 0
-}
-
-type SearchCustomersResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Customers     []*Customer            `protobuf:"bytes,1,rep,name=customers,proto3" json:"customers,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Merge Requests/Responses
+type MergeCustomersRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	SurvivorId   string                 `protobuf:"bytes,1,opt,name=survivor_id,json=survivorId,proto3" json:"survivor_id,omitempty"`
+	DuplicateIds []string               `protobuf:"bytes,2,rep,name=duplicate_ids,json=duplicateIds,proto3" json:"duplicate_ids,omitempty"`
+	// Field name -> ID of the customer (survivor or duplicate) whose value is kept.
+	// Fields without a choice keep the survivor's value, or the first duplicate's if it is empty.
+	FieldChoices    map[string]string `protobuf:"bytes,3,rep,name=field_choices,json=fieldChoices,proto3" json:"field_choices,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DryRun          bool              `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                            // Return the resulting profile without saving anything
+	ExpectedVersion int64             `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // Of the survivor; 0 skips the check
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *SearchCustomersResponse) Reset() {
-	*x = SearchCustomersResponse{}
-	mi := &file_customer_customer_proto_msgTypes[25]
+func (x *MergeCustomersRequest) Reset() {
+	*x = MergeCustomersRequest{}
+	mi := &file_customer_customer_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchCustomersResponse) String() string {
+func (x *MergeCustomersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchCustomersResponse) ProtoMessage() {}
+func (*MergeCustomersRequest) ProtoMessage() {}
 
-func (x *SearchCustomersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[25]
+func (x *MergeCustomersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2048,53 +4447,72 @@ func (x *SearchCustomersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchCustomersResponse.ProtoReflect.Descriptor instead.
-func (*SearchCustomersResponse) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use MergeCustomersRequest.ProtoReflect.Descriptor instead.
+func (*MergeCustomersRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *SearchCustomersResponse) GetCustomers() []*Customer {
+func (x *MergeCustomersRequest) GetSurvivorId() string {
 	if x != nil {
-		return x.Customers
+		return x.SurvivorId
+	}
+	return ""
+}
+
+func (x *MergeCustomersRequest) GetDuplicateIds() []string {
+	if x != nil {
+		return x.DuplicateIds
 	}
 	return nil
 }
 
-func (x *SearchCustomersResponse) GetTotal() int32 {
+func (x *MergeCustomersRequest) GetFieldChoices() map[string]string {
 	if x != nil {
-		return x.Total
+		return x.FieldChoices
+	}
+	return nil
+}
+
+func (x *MergeCustomersRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *MergeCustomersRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
 	}
 	return 0
 }
 
-// Customer History Requests/Responses
-type GetCustomerHistoryRequest struct {
+type MergeCustomersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
-	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // orders, appointments, notes
-	DateFrom      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
-	DateTo        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
-	Page          int32                  `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
-	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Customer      *Customer              `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"` // Resulting profile, with vehicles and combined stats
+	MergedIds     []string               `protobuf:"bytes,2,rep,name=merged_ids,json=mergedIds,proto3" json:"merged_ids,omitempty"`
+	VehiclesMoved int32                  `protobuf:"varint,3,opt,name=vehicles_moved,json=vehiclesMoved,proto3" json:"vehicles_moved,omitempty"`
+	NotesMoved    int32                  `protobuf:"varint,4,opt,name=notes_moved,json=notesMoved,proto3" json:"notes_moved,omitempty"`
+	DryRun        bool                   `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCustomerHistoryRequest) Reset() {
-	*x = GetCustomerHistoryRequest{}
-	mi := &file_customer_customer_proto_msgTypes[26]
+func (x *MergeCustomersResponse) Reset() {
+	*x = MergeCustomersResponse{}
+	mi := &file_customer_customer_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCustomerHistoryRequest) String() string {
+func (x *MergeCustomersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCustomerHistoryRequest) ProtoMessage() {}
+func (*MergeCustomersResponse) ProtoMessage() {}
 
-func (x *GetCustomerHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[26]
+func (x *MergeCustomersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2105,82 +4523,75 @@ func (x *GetCustomerHistoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCustomerHistoryRequest.ProtoReflect.Descriptor instead.
-func (*GetCustomerHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{26}
-}
-
-func (x *GetCustomerHistoryRequest) GetCustomerId() string {
-	if x != nil {
-		return x.CustomerId
-	}
-	return ""
+// Deprecated: Use MergeCustomersResponse.ProtoReflect.Descriptor instead.
+func (*MergeCustomersResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *GetCustomerHistoryRequest) GetType() string {
+func (x *MergeCustomersResponse) GetCustomer() *Customer {
 	if x != nil {
-		return x.Type
+		return x.Customer
 	}
-	return ""
+	return nil
 }
 
-func (x *GetCustomerHistoryRequest) GetDateFrom() *timestamppb.Timestamp {
+func (x *MergeCustomersResponse) GetMergedIds() []string {
 	if x != nil {
-		return x.DateFrom
+		return x.MergedIds
 	}
 	return nil
 }
 
-func (x *GetCustomerHistoryRequest) GetDateTo() *timestamppb.Timestamp {
+func (x *MergeCustomersResponse) GetVehiclesMoved() int32 {
 	if x != nil {
-		return x.DateTo
+		return x.VehiclesMoved
 	}
-	return nil
+	return 0
 }
 
-func (x *GetCustomerHistoryRequest) GetPage() int32 {
+func (x *MergeCustomersResponse) GetNotesMoved() int32 {
 	if x != nil {
-		return x.Page
+		return x.NotesMoved
 	}
 	return 0
 }
 
-func (x *GetCustomerHistoryRequest) GetLimit() int32 {
+func (x *MergeCustomersResponse) GetDryRun() bool {
 	if x != nil {
-		return x.Limit
+		return x.DryRun
 	}
-	return 0
+	return false
 }
 
-type CustomerHistoryItem struct {
+// Audit Log Requests/Responses
+type GetCustomerAuditLogRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // order, appointment, note, payment
-	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Amount        float64                `protobuf:"fixed64,5,opt,name=amount,proto3" json:"amount,omitempty"`
-	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
-	Data          *structpb.Struct       `protobuf:"bytes,7,opt,name=data,proto3" json:"data,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"` // optional; entries of deleted customers are kept
+	ActorId       string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`          // staff ID
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`                           // customer.created, customer.updated, vehicle.deleted...
+	DateFrom      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
+	DateTo        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	Page          int32                  `protobuf:"varint,6,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CustomerHistoryItem) Reset() {
-	*x = CustomerHistoryItem{}
-	mi := &file_customer_customer_proto_msgTypes[27]
+func (x *GetCustomerAuditLogRequest) Reset() {
+	*x = GetCustomerAuditLogRequest{}
+	mi := &file_customer_customer_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CustomerHistoryItem) String() string {
+func (x *GetCustomerAuditLogRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CustomerHistoryItem) ProtoMessage() {}
+func (*GetCustomerAuditLogRequest) ProtoMessage() {}
 
-func (x *CustomerHistoryItem) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[27]
+func (x *GetCustomerAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2191,90 +4602,84 @@ func (x *CustomerHistoryItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CustomerHistoryItem.ProtoReflect.Descriptor instead.
-func (*CustomerHistoryItem) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{27}
-}
-
-func (x *CustomerHistoryItem) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
+// Deprecated: Use GetCustomerAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*GetCustomerAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *CustomerHistoryItem) GetType() string {
+func (x *GetCustomerAuditLogRequest) GetCustomerId() string {
 	if x != nil {
-		return x.Type
+		return x.CustomerId
 	}
 	return ""
 }
 
-func (x *CustomerHistoryItem) GetTitle() string {
+func (x *GetCustomerAuditLogRequest) GetActorId() string {
 	if x != nil {
-		return x.Title
+		return x.ActorId
 	}
 	return ""
 }
 
-func (x *CustomerHistoryItem) GetDescription() string {
+func (x *GetCustomerAuditLogRequest) GetAction() string {
 	if x != nil {
-		return x.Description
+		return x.Action
 	}
 	return ""
 }
 
-func (x *CustomerHistoryItem) GetAmount() float64 {
+func (x *GetCustomerAuditLogRequest) GetDateFrom() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Amount
+		return x.DateFrom
 	}
-	return 0
+	return nil
 }
 
-func (x *CustomerHistoryItem) GetStatus() string {
+func (x *GetCustomerAuditLogRequest) GetDateTo() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Status
+		return x.DateTo
 	}
-	return ""
+	return nil
 }
 
-func (x *CustomerHistoryItem) GetData() *structpb.Struct {
+func (x *GetCustomerAuditLogRequest) GetPage() int32 {
 	if x != nil {
-		return x.Data
+		return x.Page
 	}
-	return nil
+	return 0
 }
 
-func (x *CustomerHistoryItem) GetCreatedAt() *timestamppb.Timestamp {
+func (x *GetCustomerAuditLogRequest) GetLimit() int32 {
 	if x != nil {
-		return x.CreatedAt
+		return x.Limit
 	}
-	return nil
+	return 0
 }
 
-type GetCustomerHistoryResponse struct {
+type AuditFieldChange struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Items         []*CustomerHistoryItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Before        *structpb.Value        `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"` // null when the field did not exist (creation)
+	After         *structpb.Value        `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`   // null when the resource was deleted
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCustomerHistoryResponse) Reset() {
-	*x = GetCustomerHistoryResponse{}
-	mi := &file_customer_customer_proto_msgTypes[28]
+func (x *AuditFieldChange) Reset() {
+	*x = AuditFieldChange{}
+	mi := &file_customer_customer_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCustomerHistoryResponse) String() string {
+func (x *AuditFieldChange) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCustomerHistoryResponse) ProtoMessage() {}
+func (*AuditFieldChange) ProtoMessage() {}
 
-func (x *GetCustomerHistoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[28]
+func (x *AuditFieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2285,49 +4690,63 @@ func (x *GetCustomerHistoryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCustomerHistoryResponse.ProtoReflect.Descriptor instead.
-func (*GetCustomerHistoryResponse) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use AuditFieldChange.ProtoReflect.Descriptor instead.
+func (*AuditFieldChange) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *GetCustomerHistoryResponse) GetItems() []*CustomerHistoryItem {
+func (x *AuditFieldChange) GetField() string {
 	if x != nil {
-		return x.Items
+		return x.Field
+	}
+	return ""
+}
+
+func (x *AuditFieldChange) GetBefore() *structpb.Value {
+	if x != nil {
+		return x.Before
 	}
 	return nil
 }
 
-func (x *GetCustomerHistoryResponse) GetTotal() int32 {
+func (x *AuditFieldChange) GetAfter() *structpb.Value {
 	if x != nil {
-		return x.Total
+		return x.After
 	}
-	return 0
+	return nil
 }
 
-type AddCustomerNoteRequest struct {
+type AuditLogEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
-	Note          string                 `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`
-	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CustomerId    string                 `protobuf:"bytes,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	ResourceType  string                 `protobuf:"bytes,3,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"` // customer, vehicle
+	ResourceId    string                 `protobuf:"bytes,4,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	Action        string                 `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"`
+	ActorId       string                 `protobuf:"bytes,6,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ActorName     string                 `protobuf:"bytes,7,opt,name=actor_name,json=actorName,proto3" json:"actor_name,omitempty"`
+	RequestId     string                 `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Changes       []*AuditFieldChange    `protobuf:"bytes,9,rep,name=changes,proto3" json:"changes,omitempty"` // sorted by field
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddCustomerNoteRequest) Reset() {
-	*x = AddCustomerNoteRequest{}
-	mi := &file_customer_customer_proto_msgTypes[29]
+func (x *AuditLogEntry) Reset() {
+	*x = AuditLogEntry{}
+	mi := &file_customer_customer_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddCustomerNoteRequest) String() string {
+func (x *AuditLogEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddCustomerNoteRequest) ProtoMessage() {}
+func (*AuditLogEntry) ProtoMessage() {}
 
-func (x *AddCustomerNoteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[29]
+func (x *AuditLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2338,54 +4757,104 @@ func (x *AddCustomerNoteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddCustomerNoteRequest.ProtoReflect.Descriptor instead.
-func (*AddCustomerNoteRequest) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use AuditLogEntry.ProtoReflect.Descriptor instead.
+func (*AuditLogEntry) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *AddCustomerNoteRequest) GetCustomerId() string {
+func (x *AuditLogEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetCustomerId() string {
 	if x != nil {
 		return x.CustomerId
 	}
 	return ""
 }
 
-func (x *AddCustomerNoteRequest) GetNote() string {
+func (x *AuditLogEntry) GetResourceType() string {
 	if x != nil {
-		return x.Note
+		return x.ResourceType
 	}
 	return ""
 }
 
-func (x *AddCustomerNoteRequest) GetType() string {
+func (x *AuditLogEntry) GetResourceId() string {
 	if x != nil {
-		return x.Type
+		return x.ResourceId
 	}
 	return ""
 }
 
-type AddCustomerNoteResponse struct {
+func (x *AuditLogEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetActorName() string {
+	if x != nil {
+		return x.ActorName
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetChanges() []*AuditFieldChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *AuditLogEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type GetCustomerAuditLogResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Note          *CustomerNote          `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	Entries       []*AuditLogEntry       `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddCustomerNoteResponse) Reset() {
-	*x = AddCustomerNoteResponse{}
-	mi := &file_customer_customer_proto_msgTypes[30]
+func (x *GetCustomerAuditLogResponse) Reset() {
+	*x = GetCustomerAuditLogResponse{}
+	mi := &file_customer_customer_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddCustomerNoteResponse) String() string {
+func (x *GetCustomerAuditLogResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddCustomerNoteResponse) ProtoMessage() {}
+func (*GetCustomerAuditLogResponse) ProtoMessage() {}
 
-func (x *AddCustomerNoteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_customer_customer_proto_msgTypes[30]
+func (x *GetCustomerAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_customer_customer_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2396,23 +4865,30 @@ func (x *AddCustomerNoteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddCustomerNoteResponse.ProtoReflect.Descriptor instead.
-func (*AddCustomerNoteResponse) Descriptor() ([]byte, []int) {
-	return file_customer_customer_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use GetCustomerAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*GetCustomerAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_customer_customer_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *AddCustomerNoteResponse) GetNote() *CustomerNote {
+func (x *GetCustomerAuditLogResponse) GetEntries() []*AuditLogEntry {
 	if x != nil {
-		return x.Note
+		return x.Entries
 	}
 	return nil
 }
 
+func (x *GetCustomerAuditLogResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
 var File_customer_customer_proto protoreflect.FileDescriptor
 
 const file_customer_customer_proto_rawDesc = "" +
 	"\n" +
-	"\x17customer/customer.proto\x12\vcustomer.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xda\x05\n" +
+	"\x17customer/customer.proto\x12\vcustomer.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a google/protobuf/field_mask.proto\"\xa7\a\n" +
 	"\bCustomer\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
 	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x1d\n" +
@@ -2436,7 +4912,12 @@ const file_customer_customer_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xbb\x03\n" +
+	"updated_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\x14 \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"deleted_at\x18\x15 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x125\n" +
+	"\bpurge_at\x18\x16 \x01(\v2\x1a.google.protobuf.TimestampR\apurgeAt\x12?\n" +
+	"\ranonymized_at\x18\x17 \x01(\v2\x1a.google.protobuf.TimestampR\fanonymizedAt\"\xd5\x03\n" +
 	"\aVehicle\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
 	"\vcustomer_id\x18\x02 \x01(\tR\n" +
@@ -2455,7 +4936,8 @@ const file_customer_customer_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xdc\x01\n" +
+	"updated_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\x0f \x01(\x03R\aversion\"\xdc\x01\n" +
 	"\fCustomerNote\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
 	"\vcustomer_id\x18\x02 \x01(\tR\n" +
@@ -2466,7 +4948,7 @@ const file_customer_customer_proto_rawDesc = "" +
 	"\x04note\x18\x05 \x01(\tR\x04note\x12\x12\n" +
 	"\x04type\x18\x06 \x01(\tR\x04type\x129\n" +
 	"\n" +
-	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xbb\x02\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xd1\x02\n" +
 	"\rCustomerStats\x12!\n" +
 	"\ftotal_orders\x18\x01 \x01(\x05R\vtotalOrders\x12\x1f\n" +
 	"\vtotal_spent\x18\x02 \x01(\x01R\n" +
@@ -2476,7 +4958,8 @@ const file_customer_customer_proto_rawDesc = "" +
 	"last_visit\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tlastVisit\x12!\n" +
 	"\fvisits_count\x18\x05 \x01(\x05R\vvisitsCount\x12+\n" +
 	"\x11favorite_category\x18\x06 \x01(\tR\x10favoriteCategory\x12+\n" +
-	"\x11favorite_products\x18\a \x03(\tR\x10favoriteProducts\"\xf3\x01\n" +
+	"\x11favorite_products\x18\a \x03(\tR\x10favoriteProducts\x12\x14\n" +
+	"\x05level\x18\b \x01(\tR\x05level\"\xf3\x03\n" +
 	"\x14ListCustomersRequest\x12\x1b\n" +
 	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x16\n" +
 	"\x06search\x18\x02 \x01(\tR\x06search\x12#\n" +
@@ -2487,14 +4970,25 @@ const file_customer_customer_proto_rawDesc = "" +
 	"\x05limit\x18\x06 \x01(\x05R\x05limit\x12\x17\n" +
 	"\asort_by\x18\a \x01(\tR\x06sortBy\x12\x1d\n" +
 	"\n" +
-	"sort_order\x18\b \x01(\tR\tsortOrder\"\xad\x01\n" +
+	"sort_order\x18\b \x01(\tR\tsortOrder\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\t \x01(\tR\tpageToken\x12\x1d\n" +
+	"\n" +
+	"total_mode\x18\n" +
+	" \x01(\tR\ttotalMode\x12&\n" +
+	"\x0fmin_total_spent\x18\v \x01(\x01R\rminTotalSpent\x12\x14\n" +
+	"\x05level\x18\f \x01(\tR\x05level\x12B\n" +
+	"\x0flast_visit_from\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\rlastVisitFrom\x12>\n" +
+	"\rlast_visit_to\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\vlastVisitTo\"\x81\x02\n" +
 	"\x15ListCustomersResponse\x123\n" +
 	"\tcustomers\x18\x01 \x03(\v2\x15.customer.v1.CustomerR\tcustomers\x12\x14\n" +
 	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x12\n" +
 	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x14\n" +
 	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x1f\n" +
 	"\vtotal_pages\x18\x05 \x01(\x05R\n" +
-	"totalPages\"\xb6\x01\n" +
+	"totalPages\x12&\n" +
+	"\x0fnext_page_token\x18\x06 \x01(\tR\rnextPageToken\x12*\n" +
+	"\x11total_is_estimate\x18\a \x01(\bR\x0ftotalIsEstimate\"\xb6\x01\n" +
 	"\x12GetCustomerRequest\x12\x1b\n" +
 	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\x12)\n" +
@@ -2518,9 +5012,10 @@ const file_customer_customer_proto_rawDesc = "" +
 	" \x01(\v2\x1a.google.protobuf.TimestampR\bbirthday\x12\x14\n" +
 	"\x05notes\x18\v \x01(\tR\x05notes\x129\n" +
 	"\vpreferences\x18\f \x01(\v2\x17.google.protobuf.StructR\vpreferences\x12=\n" +
-	"\bvehicles\x18\r \x03(\v2!.customer.v1.CreateVehicleRequestR\bvehicles\"K\n" +
+	"\bvehicles\x18\r \x03(\v2!.customer.v1.CreateVehicleRequestR\bvehicles\"\x9d\x01\n" +
 	"\x16CreateCustomerResponse\x121\n" +
-	"\bcustomer\x18\x01 \x01(\v2\x15.customer.v1.CustomerR\bcustomer\"\xcb\x03\n" +
+	"\bcustomer\x18\x01 \x01(\v2\x15.customer.v1.CustomerR\bcustomer\x12P\n" +
+	"\x13possible_duplicates\x18\x02 \x03(\v2\x1f.customer.v1.DuplicateCandidateR\x12possibleDuplicates\"\xb3\x04\n" +
 	"\x15UpdateCustomerRequest\x12\x1b\n" +
 	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\x12\x1d\n" +
@@ -2537,14 +5032,54 @@ const file_customer_customer_proto_rawDesc = "" +
 	"\bbirthday\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\bbirthday\x12\x14\n" +
 	"\x05notes\x18\f \x01(\tR\x05notes\x129\n" +
 	"\vpreferences\x18\r \x01(\v2\x17.google.protobuf.StructR\vpreferences\x12\x1b\n" +
-	"\tis_active\x18\x0e \x01(\bR\bisActive\"K\n" +
+	"\tis_active\x18\x0e \x01(\bR\bisActive\x12;\n" +
+	"\vupdate_mask\x18\x0f \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12)\n" +
+	"\x10expected_version\x18\x10 \x01(\x03R\x0fexpectedVersion\"K\n" +
 	"\x16UpdateCustomerResponse\x121\n" +
-	"\bcustomer\x18\x01 \x01(\v2\x15.customer.v1.CustomerR\bcustomer\"D\n" +
+	"\bcustomer\x18\x01 \x01(\v2\x15.customer.v1.CustomerR\bcustomer\"\x88\x01\n" +
 	"\x15DeleteCustomerRequest\x12\x1b\n" +
 	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x0e\n" +
-	"\x02id\x18\x02 \x01(\tR\x02id\"2\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"e\n" +
 	"\x16DeleteCustomerResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x99\x01\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x121\n" +
+	"\x06impact\x18\x02 \x01(\v2\x19.customer.v1.DeleteImpactR\x06impact\"\xbd\x01\n" +
+	"\fDeleteImpact\x12\x1a\n" +
+	"\bvehicles\x18\x01 \x01(\x03R\bvehicles\x12\x14\n" +
+	"\x05notes\x18\x02 \x01(\x03R\x05notes\x12'\n" +
+	"\x0fhistory_entries\x18\x03 \x01(\x03R\x0ehistoryEntries\x12\x1b\n" +
+	"\thas_stats\x18\x04 \x01(\bR\bhasStats\x125\n" +
+	"\bpurge_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\apurgeAt\"S\n" +
+	"\x16RestoreCustomerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"L\n" +
+	"\x17RestoreCustomerResponse\x121\n" +
+	"\bcustomer\x18\x01 \x01(\v2\x15.customer.v1.CustomerR\bcustomer\"j\n" +
+	"\x1bListDeletedCustomersRequest\x12\x16\n" +
+	"\x06search\x18\x01 \x01(\tR\x06search\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"{\n" +
+	"\x1cListDeletedCustomersResponse\x123\n" +
+	"\tcustomers\x18\x01 \x03(\v2\x15.customer.v1.CustomerR\tcustomers\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"U\n" +
+	"\x18AnonymizeCustomerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"\xa1\x02\n" +
+	"\x19AnonymizeCustomerResponse\x121\n" +
+	"\bcustomer\x18\x01 \x01(\v2\x15.customer.v1.CustomerR\bcustomer\x12)\n" +
+	"\x10anonymized_notes\x18\x02 \x01(\x03R\x0fanonymizedNotes\x12<\n" +
+	"\x1aanonymized_history_entries\x18\x03 \x01(\x03R\x18anonymizedHistoryEntries\x122\n" +
+	"\x15cleared_vehicle_notes\x18\x04 \x01(\x03R\x13clearedVehicleNotes\x124\n" +
+	"\x16redacted_audit_entries\x18\x05 \x01(\x03R\x14redactedAuditEntries\"+\n" +
+	"\x19ExportCustomerDataRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"o\n" +
+	"\x1aExportCustomerDataResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\"\xd7\x01\n" +
 	"\x13ListVehiclesRequest\x12\x1f\n" +
 	"\vcustomer_id\x18\x01 \x01(\tR\n" +
 	"customerId\x12\x16\n" +
@@ -2552,10 +5087,16 @@ const file_customer_customer_proto_rawDesc = "" +
 	"\vactive_only\x18\x03 \x01(\bR\n" +
 	"activeOnly\x12\x12\n" +
 	"\x04page\x18\x04 \x01(\x05R\x04page\x12\x14\n" +
-	"\x05limit\x18\x05 \x01(\x05R\x05limit\"^\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\x12\x1d\n" +
+	"\n" +
+	"total_mode\x18\a \x01(\tR\ttotalMode\"\xb2\x01\n" +
 	"\x14ListVehiclesResponse\x120\n" +
 	"\bvehicles\x18\x01 \x03(\v2\x14.customer.v1.VehicleR\bvehicles\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"#\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\x12*\n" +
+	"\x11total_is_estimate\x18\x04 \x01(\bR\x0ftotalIsEstimate\"#\n" +
 	"\x11GetVehicleRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"D\n" +
 	"\x12GetVehicleResponse\x12.\n" +
@@ -2574,7 +5115,7 @@ const file_customer_customer_proto_rawDesc = "" +
 	"\bmetadata\x18\n" +
 	" \x01(\v2\x17.google.protobuf.StructR\bmetadata\"G\n" +
 	"\x15CreateVehicleResponse\x12.\n" +
-	"\avehicle\x18\x01 \x01(\v2\x14.customer.v1.VehicleR\avehicle\"\xb1\x02\n" +
+	"\avehicle\x18\x01 \x01(\v2\x14.customer.v1.VehicleR\avehicle\"\x99\x03\n" +
 	"\x14UpdateVehicleRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04make\x18\x02 \x01(\tR\x04make\x12\x14\n" +
@@ -2587,21 +5128,63 @@ const file_customer_customer_proto_rawDesc = "" +
 	"\x05notes\x18\t \x01(\tR\x05notes\x12\x1b\n" +
 	"\tis_active\x18\n" +
 	" \x01(\bR\bisActive\x123\n" +
-	"\bmetadata\x18\v \x01(\v2\x17.google.protobuf.StructR\bmetadata\"G\n" +
+	"\bmetadata\x18\v \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12;\n" +
+	"\vupdate_mask\x18\f \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12)\n" +
+	"\x10expected_version\x18\r \x01(\x03R\x0fexpectedVersion\"G\n" +
 	"\x15UpdateVehicleResponse\x12.\n" +
-	"\avehicle\x18\x01 \x01(\v2\x14.customer.v1.VehicleR\avehicle\"&\n" +
+	"\avehicle\x18\x01 \x01(\v2\x14.customer.v1.VehicleR\avehicle\"Q\n" +
 	"\x14DeleteVehicleRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"1\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"1\n" +
 	"\x15DeleteVehicleResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x86\x01\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x9a\x01\n" +
 	"\x16SearchCustomersRequest\x12\x1b\n" +
 	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x14\n" +
 	"\x05query\x18\x02 \x01(\tR\x05query\x12#\n" +
 	"\rsearch_fields\x18\x03 \x01(\tR\fsearchFields\x12\x14\n" +
-	"\x05limit\x18\x04 \x01(\x05R\x05limit\"d\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04page\x18\x05 \x01(\x05R\x04page\"\x99\x01\n" +
 	"\x17SearchCustomersResponse\x123\n" +
 	"\tcustomers\x18\x01 \x03(\v2\x15.customer.v1.CustomerR\tcustomers\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"\xe8\x01\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x123\n" +
+	"\aresults\x18\x03 \x03(\v2\x19.customer.v1.SearchResultR\aresults\"\x83\x01\n" +
+	"\fSearchResult\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\tR\n" +
+	"customerId\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12<\n" +
+	"\n" +
+	"highlights\x18\x03 \x03(\v2\x1c.customer.v1.SearchHighlightR\n" +
+	"highlights\"=\n" +
+	"\x0fSearchHighlight\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\x95\x02\n" +
+	"\x1dFindDuplicateCustomersRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\tR\n" +
+	"customerId\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x02 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x03 \x01(\tR\blastName\x12!\n" +
+	"\fcompany_name\x18\x04 \x01(\tR\vcompanyName\x12\x14\n" +
+	"\x05email\x18\x05 \x01(\tR\x05email\x12\x14\n" +
+	"\x05phone\x18\x06 \x01(\tR\x05phone\x12\x15\n" +
+	"\x06tax_id\x18\a \x01(\tR\x05taxId\x12\x1b\n" +
+	"\tmin_score\x18\b \x01(\x01R\bminScore\x12\x14\n" +
+	"\x05limit\x18\t \x01(\x05R\x05limit\"p\n" +
+	"\x14DuplicateMatchReason\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\x02 \x01(\x01R\n" +
+	"similarity\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"\x9a\x01\n" +
+	"\x12DuplicateCandidate\x121\n" +
+	"\bcustomer\x18\x01 \x01(\v2\x15.customer.v1.CustomerR\bcustomer\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12;\n" +
+	"\areasons\x18\x03 \x03(\v2!.customer.v1.DuplicateMatchReasonR\areasons\"a\n" +
+	"\x1eFindDuplicateCustomersResponse\x12?\n" +
+	"\n" +
+	"candidates\x18\x01 \x03(\v2\x1f.customer.v1.DuplicateCandidateR\n" +
+	"candidates\"\xe8\x01\n" +
 	"\x19GetCustomerHistoryRequest\x12\x1f\n" +
 	"\vcustomer_id\x18\x01 \x01(\tR\n" +
 	"customerId\x12\x12\n" +
@@ -2629,22 +5212,172 @@ const file_customer_customer_proto_rawDesc = "" +
 	"\x04note\x18\x02 \x01(\tR\x04note\x12\x12\n" +
 	"\x04type\x18\x03 \x01(\tR\x04type\"H\n" +
 	"\x17AddCustomerNoteResponse\x12-\n" +
-	"\x04note\x18\x01 \x01(\v2\x19.customer.v1.CustomerNoteR\x04note2\x9b\t\n" +
+	"\x04note\x18\x01 \x01(\v2\x19.customer.v1.CustomerNoteR\x04note\"\x91\x02\n" +
+	"\x18ListCustomerNotesRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\tR\n" +
+	"customerId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x127\n" +
+	"\tdate_from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bdateFrom\x123\n" +
+	"\adate_to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06dateTo\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\x12\x1d\n" +
+	"\n" +
+	"total_mode\x18\a \x01(\tR\ttotalMode\"\xb6\x01\n" +
+	"\x19ListCustomerNotesResponse\x12/\n" +
+	"\x05notes\x18\x01 \x03(\v2\x19.customer.v1.CustomerNoteR\x05notes\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\x12*\n" +
+	"\x11total_is_estimate\x18\x04 \x01(\bR\x0ftotalIsEstimate\"\xc9\x02\n" +
+	"\rCustomerEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1f\n" +
+	"\vcustomer_id\x18\x02 \x01(\tR\n" +
+	"customerId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06amount\x18\a \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12+\n" +
+	"\x04data\x18\t \x01(\v2\x17.google.protobuf.StructR\x04data\x12;\n" +
+	"\voccurred_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"v\n" +
+	"\x1cIngestCustomerEventsResponse\x12\x1a\n" +
+	"\breceived\x18\x01 \x01(\x05R\breceived\x12\x1a\n" +
+	"\bingested\x18\x02 \x01(\x05R\bingested\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\x03 \x01(\x05R\n" +
+	"duplicates\"\xbe\x02\n" +
+	"\x16ImportCustomersRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12]\n" +
+	"\x0ecolumn_mapping\x18\x02 \x03(\v26.customer.v1.ImportCustomersRequest.ColumnMappingEntryR\rcolumnMapping\x12\x1d\n" +
+	"\n" +
+	"upsert_key\x18\x03 \x01(\tR\tupsertKey\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x05 \x01(\x05R\tbatchSize\x12\x14\n" +
+	"\x05chunk\x18\x06 \x01(\fR\x05chunk\x1a@\n" +
+	"\x12ColumnMappingEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xad\x01\n" +
+	"\x0fImportRowResult\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\x05R\x04line\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1f\n" +
+	"\vcustomer_id\x18\x03 \x01(\tR\n" +
+	"customerId\x12\x1d\n" +
+	"\n" +
+	"vehicle_id\x18\x04 \x01(\tR\tvehicleId\x12\x14\n" +
+	"\x05field\x18\x05 \x01(\tR\x05field\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\"\xa2\x01\n" +
+	"\rImportSummary\x12\x12\n" +
+	"\x04rows\x18\x01 \x01(\x05R\x04rows\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\x05R\acreated\x12\x18\n" +
+	"\aupdated\x18\x03 \x01(\x05R\aupdated\x12\x18\n" +
+	"\askipped\x18\x04 \x01(\x05R\askipped\x12\x16\n" +
+	"\x06errors\x18\x05 \x01(\x05R\x06errors\x12\x17\n" +
+	"\adry_run\x18\x06 \x01(\bR\x06dryRun\"\x87\x01\n" +
+	"\x17ImportCustomersResponse\x126\n" +
+	"\aresults\x18\x01 \x03(\v2\x1c.customer.v1.ImportRowResultR\aresults\x124\n" +
+	"\asummary\x18\x02 \x01(\v2\x1a.customer.v1.ImportSummaryR\asummary\"\xf2\x03\n" +
+	"\x16ExportCustomersRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x18\n" +
+	"\acolumns\x18\x02 \x03(\tR\acolumns\x12)\n" +
+	"\x10include_vehicles\x18\x03 \x01(\bR\x0fincludeVehicles\x12#\n" +
+	"\rinclude_stats\x18\x04 \x01(\bR\fincludeStats\x12\x16\n" +
+	"\x06search\x18\x05 \x01(\tR\x06search\x12#\n" +
+	"\rcustomer_type\x18\x06 \x01(\tR\fcustomerType\x12\x1f\n" +
+	"\vactive_only\x18\a \x01(\bR\n" +
+	"activeOnly\x12\x17\n" +
+	"\asort_by\x18\b \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\t \x01(\tR\tsortOrder\x12&\n" +
+	"\x0fmin_total_spent\x18\n" +
+	" \x01(\x01R\rminTotalSpent\x12\x14\n" +
+	"\x05level\x18\v \x01(\tR\x05level\x12B\n" +
+	"\x0flast_visit_from\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\rlastVisitFrom\x12>\n" +
+	"\rlast_visit_to\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\vlastVisitTo\"l\n" +
+	"\x17ExportCustomersResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\"\xbd\x02\n" +
+	"\x15MergeCustomersRequest\x12\x1f\n" +
+	"\vsurvivor_id\x18\x01 \x01(\tR\n" +
+	"survivorId\x12#\n" +
+	"\rduplicate_ids\x18\x02 \x03(\tR\fduplicateIds\x12Y\n" +
+	"\rfield_choices\x18\x03 \x03(\v24.customer.v1.MergeCustomersRequest.FieldChoicesEntryR\ffieldChoices\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x12)\n" +
+	"\x10expected_version\x18\x05 \x01(\x03R\x0fexpectedVersion\x1a?\n" +
+	"\x11FieldChoicesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcb\x01\n" +
+	"\x16MergeCustomersResponse\x121\n" +
+	"\bcustomer\x18\x01 \x01(\v2\x15.customer.v1.CustomerR\bcustomer\x12\x1d\n" +
+	"\n" +
+	"merged_ids\x18\x02 \x03(\tR\tmergedIds\x12%\n" +
+	"\x0evehicles_moved\x18\x03 \x01(\x05R\rvehiclesMoved\x12\x1f\n" +
+	"\vnotes_moved\x18\x04 \x01(\x05R\n" +
+	"notesMoved\x12\x17\n" +
+	"\adry_run\x18\x05 \x01(\bR\x06dryRun\"\x88\x02\n" +
+	"\x1aGetCustomerAuditLogRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\tR\n" +
+	"customerId\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\tR\aactorId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x127\n" +
+	"\tdate_from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bdateFrom\x123\n" +
+	"\adate_to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x06dateTo\x12\x12\n" +
+	"\x04page\x18\x06 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\"\x86\x01\n" +
+	"\x10AuditFieldChange\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12.\n" +
+	"\x06before\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x06before\x12,\n" +
+	"\x05after\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\x05after\"\xeb\x02\n" +
+	"\rAuditLogEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vcustomer_id\x18\x02 \x01(\tR\n" +
+	"customerId\x12#\n" +
+	"\rresource_type\x18\x03 \x01(\tR\fresourceType\x12\x1f\n" +
+	"\vresource_id\x18\x04 \x01(\tR\n" +
+	"resourceId\x12\x16\n" +
+	"\x06action\x18\x05 \x01(\tR\x06action\x12\x19\n" +
+	"\bactor_id\x18\x06 \x01(\tR\aactorId\x12\x1d\n" +
+	"\n" +
+	"actor_name\x18\a \x01(\tR\tactorName\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\b \x01(\tR\trequestId\x127\n" +
+	"\achanges\x18\t \x03(\v2\x1d.customer.v1.AuditFieldChangeR\achanges\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"i\n" +
+	"\x1bGetCustomerAuditLogResponse\x124\n" +
+	"\aentries\x18\x01 \x03(\v2\x1a.customer.v1.AuditLogEntryR\aentries\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total2\xf2\x11\n" +
 	"\x0fCustomerService\x12V\n" +
 	"\rListCustomers\x12!.customer.v1.ListCustomersRequest\x1a\".customer.v1.ListCustomersResponse\x12P\n" +
 	"\vGetCustomer\x12\x1f.customer.v1.GetCustomerRequest\x1a .customer.v1.GetCustomerResponse\x12Y\n" +
 	"\x0eCreateCustomer\x12\".customer.v1.CreateCustomerRequest\x1a#.customer.v1.CreateCustomerResponse\x12Y\n" +
 	"\x0eUpdateCustomer\x12\".customer.v1.UpdateCustomerRequest\x1a#.customer.v1.UpdateCustomerResponse\x12Y\n" +
-	"\x0eDeleteCustomer\x12\".customer.v1.DeleteCustomerRequest\x1a#.customer.v1.DeleteCustomerResponse\x12S\n" +
+	"\x0eDeleteCustomer\x12\".customer.v1.DeleteCustomerRequest\x1a#.customer.v1.DeleteCustomerResponse\x12Y\n" +
+	"\x0eMergeCustomers\x12\".customer.v1.MergeCustomersRequest\x1a#.customer.v1.MergeCustomersResponse\x12\\\n" +
+	"\x0fRestoreCustomer\x12#.customer.v1.RestoreCustomerRequest\x1a$.customer.v1.RestoreCustomerResponse\x12k\n" +
+	"\x14ListDeletedCustomers\x12(.customer.v1.ListDeletedCustomersRequest\x1a).customer.v1.ListDeletedCustomersResponse\x12b\n" +
+	"\x11AnonymizeCustomer\x12%.customer.v1.AnonymizeCustomerRequest\x1a&.customer.v1.AnonymizeCustomerResponse\x12g\n" +
+	"\x12ExportCustomerData\x12&.customer.v1.ExportCustomerDataRequest\x1a'.customer.v1.ExportCustomerDataResponse0\x01\x12S\n" +
 	"\fListVehicles\x12 .customer.v1.ListVehiclesRequest\x1a!.customer.v1.ListVehiclesResponse\x12M\n" +
 	"\n" +
 	"GetVehicle\x12\x1e.customer.v1.GetVehicleRequest\x1a\x1f.customer.v1.GetVehicleResponse\x12V\n" +
 	"\rCreateVehicle\x12!.customer.v1.CreateVehicleRequest\x1a\".customer.v1.CreateVehicleResponse\x12V\n" +
 	"\rUpdateVehicle\x12!.customer.v1.UpdateVehicleRequest\x1a\".customer.v1.UpdateVehicleResponse\x12V\n" +
 	"\rDeleteVehicle\x12!.customer.v1.DeleteVehicleRequest\x1a\".customer.v1.DeleteVehicleResponse\x12\\\n" +
-	"\x0fSearchCustomers\x12#.customer.v1.SearchCustomersRequest\x1a$.customer.v1.SearchCustomersResponse\x12e\n" +
+	"\x0fSearchCustomers\x12#.customer.v1.SearchCustomersRequest\x1a$.customer.v1.SearchCustomersResponse\x12q\n" +
+	"\x16FindDuplicateCustomers\x12*.customer.v1.FindDuplicateCustomersRequest\x1a+.customer.v1.FindDuplicateCustomersResponse\x12e\n" +
 	"\x12GetCustomerHistory\x12&.customer.v1.GetCustomerHistoryRequest\x1a'.customer.v1.GetCustomerHistoryResponse\x12\\\n" +
-	"\x0fAddCustomerNote\x12#.customer.v1.AddCustomerNoteRequest\x1a$.customer.v1.AddCustomerNoteResponseBKZIgithub.com/encomos/api-encomos/customer-service/proto/customer;customerpbb\x06proto3"
+	"\x0fAddCustomerNote\x12#.customer.v1.AddCustomerNoteRequest\x1a$.customer.v1.AddCustomerNoteResponse\x12b\n" +
+	"\x11ListCustomerNotes\x12%.customer.v1.ListCustomerNotesRequest\x1a&.customer.v1.ListCustomerNotesResponse\x12_\n" +
+	"\x14IngestCustomerEvents\x12\x1a.customer.v1.CustomerEvent\x1a).customer.v1.IngestCustomerEventsResponse(\x01\x12`\n" +
+	"\x0fImportCustomers\x12#.customer.v1.ImportCustomersRequest\x1a$.customer.v1.ImportCustomersResponse(\x010\x01\x12^\n" +
+	"\x0fExportCustomers\x12#.customer.v1.ExportCustomersRequest\x1a$.customer.v1.ExportCustomersResponse0\x01\x12h\n" +
+	"\x13GetCustomerAuditLog\x12'.customer.v1.GetCustomerAuditLogRequest\x1a(.customer.v1.GetCustomerAuditLogResponseBKZIgithub.com/encomos/api-encomos/customer-service/proto/customer;customerpbb\x06proto3"
 
 var (
 	file_customer_customer_proto_rawDescOnce sync.Once
@@ -2658,108 +5391,202 @@ func file_customer_customer_proto_rawDescGZIP() []byte {
 	return file_customer_customer_proto_rawDescData
 }
 
-var file_customer_customer_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
+var file_customer_customer_proto_msgTypes = make([]protoimpl.MessageInfo, 64)
 var file_customer_customer_proto_goTypes = []any{
-	(*Customer)(nil),                   // 0: customer.v1.Customer
-	(*Vehicle)(nil),                    // 1: customer.v1.Vehicle
-	(*CustomerNote)(nil),               // 2: customer.v1.CustomerNote
-	(*CustomerStats)(nil),              // 3: customer.v1.CustomerStats
-	(*ListCustomersRequest)(nil),       // 4: customer.v1.ListCustomersRequest
-	(*ListCustomersResponse)(nil),      // 5: customer.v1.ListCustomersResponse
-	(*GetCustomerRequest)(nil),         // 6: customer.v1.GetCustomerRequest
-	(*GetCustomerResponse)(nil),        // 7: customer.v1.GetCustomerResponse
-	(*CreateCustomerRequest)(nil),      // 8: customer.v1.CreateCustomerRequest
-	(*CreateCustomerResponse)(nil),     // 9: customer.v1.CreateCustomerResponse
-	(*UpdateCustomerRequest)(nil),      // 10: customer.v1.UpdateCustomerRequest
-	(*UpdateCustomerResponse)(nil),     // 11: customer.v1.UpdateCustomerResponse
-	(*DeleteCustomerRequest)(nil),      // 12: customer.v1.DeleteCustomerRequest
-	(*DeleteCustomerResponse)(nil),     // 13: customer.v1.DeleteCustomerResponse
-	(*ListVehiclesRequest)(nil),        // 14: customer.v1.ListVehiclesRequest
-	(*ListVehiclesResponse)(nil),       // 15: customer.v1.ListVehiclesResponse
-	(*GetVehicleRequest)(nil),          // 16: customer.v1.GetVehicleRequest
-	(*GetVehicleResponse)(nil),         // 17: customer.v1.GetVehicleResponse
-	(*CreateVehicleRequest)(nil),       // 18: customer.v1.CreateVehicleRequest
-	(*CreateVehicleResponse)(nil),      // 19: customer.v1.CreateVehicleResponse
-	(*UpdateVehicleRequest)(nil),       // 20: customer.v1.UpdateVehicleRequest
-	(*UpdateVehicleResponse)(nil),      // 21: customer.v1.UpdateVehicleResponse
-	(*DeleteVehicleRequest)(nil),       // 22: customer.v1.DeleteVehicleRequest
-	(*DeleteVehicleResponse)(nil),      // 23: customer.v1.DeleteVehicleResponse
-	(*SearchCustomersRequest)(nil),     // 24: customer.v1.SearchCustomersRequest
-	(*SearchCustomersResponse)(nil),    // 25: customer.v1.SearchCustomersResponse
-	(*GetCustomerHistoryRequest)(nil),  // 26: customer.v1.GetCustomerHistoryRequest
-	(*CustomerHistoryItem)(nil),        // 27: customer.v1.CustomerHistoryItem
-	(*GetCustomerHistoryResponse)(nil), // 28: customer.v1.GetCustomerHistoryResponse
-	(*AddCustomerNoteRequest)(nil),     // 29: customer.v1.AddCustomerNoteRequest
-	(*AddCustomerNoteResponse)(nil),    // 30: customer.v1.AddCustomerNoteResponse
-	(*timestamppb.Timestamp)(nil),      // 31: google.protobuf.Timestamp
-	(*structpb.Struct)(nil),            // 32: google.protobuf.Struct
+	(*Customer)(nil),                       // 0: customer.v1.Customer
+	(*Vehicle)(nil),                        // 1: customer.v1.Vehicle
+	(*CustomerNote)(nil),                   // 2: customer.v1.CustomerNote
+	(*CustomerStats)(nil),                  // 3: customer.v1.CustomerStats
+	(*ListCustomersRequest)(nil),           // 4: customer.v1.ListCustomersRequest
+	(*ListCustomersResponse)(nil),          // 5: customer.v1.ListCustomersResponse
+	(*GetCustomerRequest)(nil),             // 6: customer.v1.GetCustomerRequest
+	(*GetCustomerResponse)(nil),            // 7: customer.v1.GetCustomerResponse
+	(*CreateCustomerRequest)(nil),          // 8: customer.v1.CreateCustomerRequest
+	(*CreateCustomerResponse)(nil),         // 9: customer.v1.CreateCustomerResponse
+	(*UpdateCustomerRequest)(nil),          // 10: customer.v1.UpdateCustomerRequest
+	(*UpdateCustomerResponse)(nil),         // 11: customer.v1.UpdateCustomerResponse
+	(*DeleteCustomerRequest)(nil),          // 12: customer.v1.DeleteCustomerRequest
+	(*DeleteCustomerResponse)(nil),         // 13: customer.v1.DeleteCustomerResponse
+	(*DeleteImpact)(nil),                   // 14: customer.v1.DeleteImpact
+	(*RestoreCustomerRequest)(nil),         // 15: customer.v1.RestoreCustomerRequest
+	(*RestoreCustomerResponse)(nil),        // 16: customer.v1.RestoreCustomerResponse
+	(*ListDeletedCustomersRequest)(nil),    // 17: customer.v1.ListDeletedCustomersRequest
+	(*ListDeletedCustomersResponse)(nil),   // 18: customer.v1.ListDeletedCustomersResponse
+	(*AnonymizeCustomerRequest)(nil),       // 19: customer.v1.AnonymizeCustomerRequest
+	(*AnonymizeCustomerResponse)(nil),      // 20: customer.v1.AnonymizeCustomerResponse
+	(*ExportCustomerDataRequest)(nil),      // 21: customer.v1.ExportCustomerDataRequest
+	(*ExportCustomerDataResponse)(nil),     // 22: customer.v1.ExportCustomerDataResponse
+	(*ListVehiclesRequest)(nil),            // 23: customer.v1.ListVehiclesRequest
+	(*ListVehiclesResponse)(nil),           // 24: customer.v1.ListVehiclesResponse
+	(*GetVehicleRequest)(nil),              // 25: customer.v1.GetVehicleRequest
+	(*GetVehicleResponse)(nil),             // 26: customer.v1.GetVehicleResponse
+	(*CreateVehicleRequest)(nil),           // 27: customer.v1.CreateVehicleRequest
+	(*CreateVehicleResponse)(nil),          // 28: customer.v1.CreateVehicleResponse
+	(*UpdateVehicleRequest)(nil),           // 29: customer.v1.UpdateVehicleRequest
+	(*UpdateVehicleResponse)(nil),          // 30: customer.v1.UpdateVehicleResponse
+	(*DeleteVehicleRequest)(nil),           // 31: customer.v1.DeleteVehicleRequest
+	(*DeleteVehicleResponse)(nil),          // 32: customer.v1.DeleteVehicleResponse
+	(*SearchCustomersRequest)(nil),         // 33: customer.v1.SearchCustomersRequest
+	(*SearchCustomersResponse)(nil),        // 34: customer.v1.SearchCustomersResponse
+	(*SearchResult)(nil),                   // 35: customer.v1.SearchResult
+	(*SearchHighlight)(nil),                // 36: customer.v1.SearchHighlight
+	(*FindDuplicateCustomersRequest)(nil),  // 37: customer.v1.FindDuplicateCustomersRequest
+	(*DuplicateMatchReason)(nil),           // 38: customer.v1.DuplicateMatchReason
+	(*DuplicateCandidate)(nil),             // 39: customer.v1.DuplicateCandidate
+	(*FindDuplicateCustomersResponse)(nil), // 40: customer.v1.FindDuplicateCustomersResponse
+	(*GetCustomerHistoryRequest)(nil),      // 41: customer.v1.GetCustomerHistoryRequest
+	(*CustomerHistoryItem)(nil),            // 42: customer.v1.CustomerHistoryItem
+	(*GetCustomerHistoryResponse)(nil),     // 43: customer.v1.GetCustomerHistoryResponse
+	(*AddCustomerNoteRequest)(nil),         // 44: customer.v1.AddCustomerNoteRequest
+	(*AddCustomerNoteResponse)(nil),        // 45: customer.v1.AddCustomerNoteResponse
+	(*ListCustomerNotesRequest)(nil),       // 46: customer.v1.ListCustomerNotesRequest
+	(*ListCustomerNotesResponse)(nil),      // 47: customer.v1.ListCustomerNotesResponse
+	(*CustomerEvent)(nil),                  // 48: customer.v1.CustomerEvent
+	(*IngestCustomerEventsResponse)(nil),   // 49: customer.v1.IngestCustomerEventsResponse
+	(*ImportCustomersRequest)(nil),         // 50: customer.v1.ImportCustomersRequest
+	(*ImportRowResult)(nil),                // 51: customer.v1.ImportRowResult
+	(*ImportSummary)(nil),                  // 52: customer.v1.ImportSummary
+	(*ImportCustomersResponse)(nil),        // 53: customer.v1.ImportCustomersResponse
+	(*ExportCustomersRequest)(nil),         // 54: customer.v1.ExportCustomersRequest
+	(*ExportCustomersResponse)(nil),        // 55: customer.v1.ExportCustomersResponse
+	(*MergeCustomersRequest)(nil),          // 56: customer.v1.MergeCustomersRequest
+	(*MergeCustomersResponse)(nil),         // 57: customer.v1.MergeCustomersResponse
+	(*GetCustomerAuditLogRequest)(nil),     // 58: customer.v1.GetCustomerAuditLogRequest
+	(*AuditFieldChange)(nil),               // 59: customer.v1.AuditFieldChange
+	(*AuditLogEntry)(nil),                  // 60: customer.v1.AuditLogEntry
+	(*GetCustomerAuditLogResponse)(nil),    // 61: customer.v1.GetCustomerAuditLogResponse
+	nil,                                    // 62: customer.v1.ImportCustomersRequest.ColumnMappingEntry
+	nil,                                    // 63: customer.v1.MergeCustomersRequest.FieldChoicesEntry
+	(*timestamppb.Timestamp)(nil),          // 64: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),                // 65: google.protobuf.Struct
+	(*fieldmaskpb.FieldMask)(nil),          // 66: google.protobuf.FieldMask
+	(*structpb.Value)(nil),                 // 67: google.protobuf.Value
 }
 var file_customer_customer_proto_depIdxs = []int32{
-	31, // 0: customer.v1.Customer.birthday:type_name -> google.protobuf.Timestamp
-	32, // 1: customer.v1.Customer.preferences:type_name -> google.protobuf.Struct
+	64, // 0: customer.v1.Customer.birthday:type_name -> google.protobuf.Timestamp
+	65, // 1: customer.v1.Customer.preferences:type_name -> google.protobuf.Struct
 	1,  // 2: customer.v1.Customer.vehicles:type_name -> customer.v1.Vehicle
 	2,  // 3: customer.v1.Customer.customer_notes:type_name -> customer.v1.CustomerNote
 	3,  // 4: customer.v1.Customer.stats:type_name -> customer.v1.CustomerStats
-	31, // 5: customer.v1.Customer.created_at:type_name -> google.protobuf.Timestamp
-	31, // 6: customer.v1.Customer.updated_at:type_name -> google.protobuf.Timestamp
-	32, // 7: customer.v1.Vehicle.metadata:type_name -> google.protobuf.Struct
-	31, // 8: customer.v1.Vehicle.created_at:type_name -> google.protobuf.Timestamp
-	31, // 9: customer.v1.Vehicle.updated_at:type_name -> google.protobuf.Timestamp
-	31, // 10: customer.v1.CustomerNote.created_at:type_name -> google.protobuf.Timestamp
-	31, // 11: customer.v1.CustomerStats.last_visit:type_name -> google.protobuf.Timestamp
-	0,  // 12: customer.v1.ListCustomersResponse.customers:type_name -> customer.v1.Customer
-	0,  // 13: customer.v1.GetCustomerResponse.customer:type_name -> customer.v1.Customer
-	31, // 14: customer.v1.CreateCustomerRequest.birthday:type_name -> google.protobuf.Timestamp
-	32, // 15: customer.v1.CreateCustomerRequest.preferences:type_name -> google.protobuf.Struct
-	18, // 16: customer.v1.CreateCustomerRequest.vehicles:type_name -> customer.v1.CreateVehicleRequest
-	0,  // 17: customer.v1.CreateCustomerResponse.customer:type_name -> customer.v1.Customer
-	31, // 18: customer.v1.UpdateCustomerRequest.birthday:type_name -> google.protobuf.Timestamp
-	32, // 19: customer.v1.UpdateCustomerRequest.preferences:type_name -> google.protobuf.Struct
-	0,  // 20: customer.v1.UpdateCustomerResponse.customer:type_name -> customer.v1.Customer
-	1,  // 21: customer.v1.ListVehiclesResponse.vehicles:type_name -> customer.v1.Vehicle
-	1,  // 22: customer.v1.GetVehicleResponse.vehicle:type_name -> customer.v1.Vehicle
-	32, // 23: customer.v1.CreateVehicleRequest.metadata:type_name -> google.protobuf.Struct
-	1,  // 24: customer.v1.CreateVehicleResponse.vehicle:type_name -> customer.v1.Vehicle
-	32, // 25: customer.v1.UpdateVehicleRequest.metadata:type_name -> google.protobuf.Struct
-	1,  // 26: customer.v1.UpdateVehicleResponse.vehicle:type_name -> customer.v1.Vehicle
-	0,  // 27: customer.v1.SearchCustomersResponse.customers:type_name -> customer.v1.Customer
-	31, // 28: customer.v1.GetCustomerHistoryRequest.date_from:type_name -> google.protobuf.Timestamp
-	31, // 29: customer.v1.GetCustomerHistoryRequest.date_to:type_name -> google.protobuf.Timestamp
-	32, // 30: customer.v1.CustomerHistoryItem.data:type_name -> google.protobuf.Struct
-	31, // 31: customer.v1.CustomerHistoryItem.created_at:type_name -> google.protobuf.Timestamp
-	27, // 32: customer.v1.GetCustomerHistoryResponse.items:type_name -> customer.v1.CustomerHistoryItem
-	2,  // 33: customer.v1.AddCustomerNoteResponse.note:type_name -> customer.v1.CustomerNote
-	4,  // 34: customer.v1.CustomerService.ListCustomers:input_type -> customer.v1.ListCustomersRequest
-	6,  // 35: customer.v1.CustomerService.GetCustomer:input_type -> customer.v1.GetCustomerRequest
-	8,  // 36: customer.v1.CustomerService.CreateCustomer:input_type -> customer.v1.CreateCustomerRequest
-	10, // 37: customer.v1.CustomerService.UpdateCustomer:input_type -> customer.v1.UpdateCustomerRequest
-	12, // 38: customer.v1.CustomerService.DeleteCustomer:input_type -> customer.v1.DeleteCustomerRequest
-	14, // 39: customer.v1.CustomerService.ListVehicles:input_type -> customer.v1.ListVehiclesRequest
-	16, // 40: customer.v1.CustomerService.GetVehicle:input_type -> customer.v1.GetVehicleRequest
-	18, // 41: customer.v1.CustomerService.CreateVehicle:input_type -> customer.v1.CreateVehicleRequest
-	20, // 42: customer.v1.CustomerService.UpdateVehicle:input_type -> customer.v1.UpdateVehicleRequest
-	22, // 43: customer.v1.CustomerService.DeleteVehicle:input_type -> customer.v1.DeleteVehicleRequest
-	24, // 44: customer.v1.CustomerService.SearchCustomers:input_type -> customer.v1.SearchCustomersRequest
-	26, // 45: customer.v1.CustomerService.GetCustomerHistory:input_type -> customer.v1.GetCustomerHistoryRequest
-	29, // 46: customer.v1.CustomerService.AddCustomerNote:input_type -> customer.v1.AddCustomerNoteRequest
-	5,  // 47: customer.v1.CustomerService.ListCustomers:output_type -> customer.v1.ListCustomersResponse
-	7,  // 48: customer.v1.CustomerService.GetCustomer:output_type -> customer.v1.GetCustomerResponse
-	9,  // 49: customer.v1.CustomerService.CreateCustomer:output_type -> customer.v1.CreateCustomerResponse
-	11, // 50: customer.v1.CustomerService.UpdateCustomer:output_type -> customer.v1.UpdateCustomerResponse
-	13, // 51: customer.v1.CustomerService.DeleteCustomer:output_type -> customer.v1.DeleteCustomerResponse
-	15, // 52: customer.v1.CustomerService.ListVehicles:output_type -> customer.v1.ListVehiclesResponse
-	17, // 53: customer.v1.CustomerService.GetVehicle:output_type -> customer.v1.GetVehicleResponse
-	19, // 54: customer.v1.CustomerService.CreateVehicle:output_type -> customer.v1.CreateVehicleResponse
-	21, // 55: customer.v1.CustomerService.UpdateVehicle:output_type -> customer.v1.UpdateVehicleResponse
-	23, // 56: customer.v1.CustomerService.DeleteVehicle:output_type -> customer.v1.DeleteVehicleResponse
-	25, // 57: customer.v1.CustomerService.SearchCustomers:output_type -> customer.v1.SearchCustomersResponse
-	28, // 58: customer.v1.CustomerService.GetCustomerHistory:output_type -> customer.v1.GetCustomerHistoryResponse
-	30, // 59: customer.v1.CustomerService.AddCustomerNote:output_type -> customer.v1.AddCustomerNoteResponse
-	47, // [47:60] is the sub-list for method output_type
-	34, // [34:47] is the sub-list for method input_type
-	34, // [34:34] is the sub-list for extension type_name
-	34, // [34:34] is the sub-list for extension extendee
-	0,  // [0:34] is the sub-list for field type_name
+	64, // 5: customer.v1.Customer.created_at:type_name -> google.protobuf.Timestamp
+	64, // 6: customer.v1.Customer.updated_at:type_name -> google.protobuf.Timestamp
+	64, // 7: customer.v1.Customer.deleted_at:type_name -> google.protobuf.Timestamp
+	64, // 8: customer.v1.Customer.purge_at:type_name -> google.protobuf.Timestamp
+	64, // 9: customer.v1.Customer.anonymized_at:type_name -> google.protobuf.Timestamp
+	65, // 10: customer.v1.Vehicle.metadata:type_name -> google.protobuf.Struct
+	64, // 11: customer.v1.Vehicle.created_at:type_name -> google.protobuf.Timestamp
+	64, // 12: customer.v1.Vehicle.updated_at:type_name -> google.protobuf.Timestamp
+	64, // 13: customer.v1.CustomerNote.created_at:type_name -> google.protobuf.Timestamp
+	64, // 14: customer.v1.CustomerStats.last_visit:type_name -> google.protobuf.Timestamp
+	64, // 15: customer.v1.ListCustomersRequest.last_visit_from:type_name -> google.protobuf.Timestamp
+	64, // 16: customer.v1.ListCustomersRequest.last_visit_to:type_name -> google.protobuf.Timestamp
+	0,  // 17: customer.v1.ListCustomersResponse.customers:type_name -> customer.v1.Customer
+	0,  // 18: customer.v1.GetCustomerResponse.customer:type_name -> customer.v1.Customer
+	64, // 19: customer.v1.CreateCustomerRequest.birthday:type_name -> google.protobuf.Timestamp
+	65, // 20: customer.v1.CreateCustomerRequest.preferences:type_name -> google.protobuf.Struct
+	27, // 21: customer.v1.CreateCustomerRequest.vehicles:type_name -> customer.v1.CreateVehicleRequest
+	0,  // 22: customer.v1.CreateCustomerResponse.customer:type_name -> customer.v1.Customer
+	39, // 23: customer.v1.CreateCustomerResponse.possible_duplicates:type_name -> customer.v1.DuplicateCandidate
+	64, // 24: customer.v1.UpdateCustomerRequest.birthday:type_name -> google.protobuf.Timestamp
+	65, // 25: customer.v1.UpdateCustomerRequest.preferences:type_name -> google.protobuf.Struct
+	66, // 26: customer.v1.UpdateCustomerRequest.update_mask:type_name -> google.protobuf.FieldMask
+	0,  // 27: customer.v1.UpdateCustomerResponse.customer:type_name -> customer.v1.Customer
+	14, // 28: customer.v1.DeleteCustomerResponse.impact:type_name -> customer.v1.DeleteImpact
+	64, // 29: customer.v1.DeleteImpact.purge_at:type_name -> google.protobuf.Timestamp
+	0,  // 30: customer.v1.RestoreCustomerResponse.customer:type_name -> customer.v1.Customer
+	0,  // 31: customer.v1.ListDeletedCustomersResponse.customers:type_name -> customer.v1.Customer
+	0,  // 32: customer.v1.AnonymizeCustomerResponse.customer:type_name -> customer.v1.Customer
+	1,  // 33: customer.v1.ListVehiclesResponse.vehicles:type_name -> customer.v1.Vehicle
+	1,  // 34: customer.v1.GetVehicleResponse.vehicle:type_name -> customer.v1.Vehicle
+	65, // 35: customer.v1.CreateVehicleRequest.metadata:type_name -> google.protobuf.Struct
+	1,  // 36: customer.v1.CreateVehicleResponse.vehicle:type_name -> customer.v1.Vehicle
+	65, // 37: customer.v1.UpdateVehicleRequest.metadata:type_name -> google.protobuf.Struct
+	66, // 38: customer.v1.UpdateVehicleRequest.update_mask:type_name -> google.protobuf.FieldMask
+	1,  // 39: customer.v1.UpdateVehicleResponse.vehicle:type_name -> customer.v1.Vehicle
+	0,  // 40: customer.v1.SearchCustomersResponse.customers:type_name -> customer.v1.Customer
+	35, // 41: customer.v1.SearchCustomersResponse.results:type_name -> customer.v1.SearchResult
+	36, // 42: customer.v1.SearchResult.highlights:type_name -> customer.v1.SearchHighlight
+	0,  // 43: customer.v1.DuplicateCandidate.customer:type_name -> customer.v1.Customer
+	38, // 44: customer.v1.DuplicateCandidate.reasons:type_name -> customer.v1.DuplicateMatchReason
+	39, // 45: customer.v1.FindDuplicateCustomersResponse.candidates:type_name -> customer.v1.DuplicateCandidate
+	64, // 46: customer.v1.GetCustomerHistoryRequest.date_from:type_name -> google.protobuf.Timestamp
+	64, // 47: customer.v1.GetCustomerHistoryRequest.date_to:type_name -> google.protobuf.Timestamp
+	65, // 48: customer.v1.CustomerHistoryItem.data:type_name -> google.protobuf.Struct
+	64, // 49: customer.v1.CustomerHistoryItem.created_at:type_name -> google.protobuf.Timestamp
+	42, // 50: customer.v1.GetCustomerHistoryResponse.items:type_name -> customer.v1.CustomerHistoryItem
+	2,  // 51: customer.v1.AddCustomerNoteResponse.note:type_name -> customer.v1.CustomerNote
+	64, // 52: customer.v1.ListCustomerNotesRequest.date_from:type_name -> google.protobuf.Timestamp
+	64, // 53: customer.v1.ListCustomerNotesRequest.date_to:type_name -> google.protobuf.Timestamp
+	2,  // 54: customer.v1.ListCustomerNotesResponse.notes:type_name -> customer.v1.CustomerNote
+	65, // 55: customer.v1.CustomerEvent.data:type_name -> google.protobuf.Struct
+	64, // 56: customer.v1.CustomerEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	62, // 57: customer.v1.ImportCustomersRequest.column_mapping:type_name -> customer.v1.ImportCustomersRequest.ColumnMappingEntry
+	51, // 58: customer.v1.ImportCustomersResponse.results:type_name -> customer.v1.ImportRowResult
+	52, // 59: customer.v1.ImportCustomersResponse.summary:type_name -> customer.v1.ImportSummary
+	64, // 60: customer.v1.ExportCustomersRequest.last_visit_from:type_name -> google.protobuf.Timestamp
+	64, // 61: customer.v1.ExportCustomersRequest.last_visit_to:type_name -> google.protobuf.Timestamp
+	63, // 62: customer.v1.MergeCustomersRequest.field_choices:type_name -> customer.v1.MergeCustomersRequest.FieldChoicesEntry
+	0,  // 63: customer.v1.MergeCustomersResponse.customer:type_name -> customer.v1.Customer
+	64, // 64: customer.v1.GetCustomerAuditLogRequest.date_from:type_name -> google.protobuf.Timestamp
+	64, // 65: customer.v1.GetCustomerAuditLogRequest.date_to:type_name -> google.protobuf.Timestamp
+	67, // 66: customer.v1.AuditFieldChange.before:type_name -> google.protobuf.Value
+	67, // 67: customer.v1.AuditFieldChange.after:type_name -> google.protobuf.Value
+	59, // 68: customer.v1.AuditLogEntry.changes:type_name -> customer.v1.AuditFieldChange
+	64, // 69: customer.v1.AuditLogEntry.created_at:type_name -> google.protobuf.Timestamp
+	60, // 70: customer.v1.GetCustomerAuditLogResponse.entries:type_name -> customer.v1.AuditLogEntry
+	4,  // 71: customer.v1.CustomerService.ListCustomers:input_type -> customer.v1.ListCustomersRequest
+	6,  // 72: customer.v1.CustomerService.GetCustomer:input_type -> customer.v1.GetCustomerRequest
+	8,  // 73: customer.v1.CustomerService.CreateCustomer:input_type -> customer.v1.CreateCustomerRequest
+	10, // 74: customer.v1.CustomerService.UpdateCustomer:input_type -> customer.v1.UpdateCustomerRequest
+	12, // 75: customer.v1.CustomerService.DeleteCustomer:input_type -> customer.v1.DeleteCustomerRequest
+	56, // 76: customer.v1.CustomerService.MergeCustomers:input_type -> customer.v1.MergeCustomersRequest
+	15, // 77: customer.v1.CustomerService.RestoreCustomer:input_type -> customer.v1.RestoreCustomerRequest
+	17, // 78: customer.v1.CustomerService.ListDeletedCustomers:input_type -> customer.v1.ListDeletedCustomersRequest
+	19, // 79: customer.v1.CustomerService.AnonymizeCustomer:input_type -> customer.v1.AnonymizeCustomerRequest
+	21, // 80: customer.v1.CustomerService.ExportCustomerData:input_type -> customer.v1.ExportCustomerDataRequest
+	23, // 81: customer.v1.CustomerService.ListVehicles:input_type -> customer.v1.ListVehiclesRequest
+	25, // 82: customer.v1.CustomerService.GetVehicle:input_type -> customer.v1.GetVehicleRequest
+	27, // 83: customer.v1.CustomerService.CreateVehicle:input_type -> customer.v1.CreateVehicleRequest
+	29, // 84: customer.v1.CustomerService.UpdateVehicle:input_type -> customer.v1.UpdateVehicleRequest
+	31, // 85: customer.v1.CustomerService.DeleteVehicle:input_type -> customer.v1.DeleteVehicleRequest
+	33, // 86: customer.v1.CustomerService.SearchCustomers:input_type -> customer.v1.SearchCustomersRequest
+	37, // 87: customer.v1.CustomerService.FindDuplicateCustomers:input_type -> customer.v1.FindDuplicateCustomersRequest
+	41, // 88: customer.v1.CustomerService.GetCustomerHistory:input_type -> customer.v1.GetCustomerHistoryRequest
+	44, // 89: customer.v1.CustomerService.AddCustomerNote:input_type -> customer.v1.AddCustomerNoteRequest
+	46, // 90: customer.v1.CustomerService.ListCustomerNotes:input_type -> customer.v1.ListCustomerNotesRequest
+	48, // 91: customer.v1.CustomerService.IngestCustomerEvents:input_type -> customer.v1.CustomerEvent
+	50, // 92: customer.v1.CustomerService.ImportCustomers:input_type -> customer.v1.ImportCustomersRequest
+	54, // 93: customer.v1.CustomerService.ExportCustomers:input_type -> customer.v1.ExportCustomersRequest
+	58, // 94: customer.v1.CustomerService.GetCustomerAuditLog:input_type -> customer.v1.GetCustomerAuditLogRequest
+	5,  // 95: customer.v1.CustomerService.ListCustomers:output_type -> customer.v1.ListCustomersResponse
+	7,  // 96: customer.v1.CustomerService.GetCustomer:output_type -> customer.v1.GetCustomerResponse
+	9,  // 97: customer.v1.CustomerService.CreateCustomer:output_type -> customer.v1.CreateCustomerResponse
+	11, // 98: customer.v1.CustomerService.UpdateCustomer:output_type -> customer.v1.UpdateCustomerResponse
+	13, // 99: customer.v1.CustomerService.DeleteCustomer:output_type -> customer.v1.DeleteCustomerResponse
+	57, // 100: customer.v1.CustomerService.MergeCustomers:output_type -> customer.v1.MergeCustomersResponse
+	16, // 101: customer.v1.CustomerService.RestoreCustomer:output_type -> customer.v1.RestoreCustomerResponse
+	18, // 102: customer.v1.CustomerService.ListDeletedCustomers:output_type -> customer.v1.ListDeletedCustomersResponse
+	20, // 103: customer.v1.CustomerService.AnonymizeCustomer:output_type -> customer.v1.AnonymizeCustomerResponse
+	22, // 104: customer.v1.CustomerService.ExportCustomerData:output_type -> customer.v1.ExportCustomerDataResponse
+	24, // 105: customer.v1.CustomerService.ListVehicles:output_type -> customer.v1.ListVehiclesResponse
+	26, // 106: customer.v1.CustomerService.GetVehicle:output_type -> customer.v1.GetVehicleResponse
+	28, // 107: customer.v1.CustomerService.CreateVehicle:output_type -> customer.v1.CreateVehicleResponse
+	30, // 108: customer.v1.CustomerService.UpdateVehicle:output_type -> customer.v1.UpdateVehicleResponse
+	32, // 109: customer.v1.CustomerService.DeleteVehicle:output_type -> customer.v1.DeleteVehicleResponse
+	34, // 110: customer.v1.CustomerService.SearchCustomers:output_type -> customer.v1.SearchCustomersResponse
+	40, // 111: customer.v1.CustomerService.FindDuplicateCustomers:output_type -> customer.v1.FindDuplicateCustomersResponse
+	43, // 112: customer.v1.CustomerService.GetCustomerHistory:output_type -> customer.v1.GetCustomerHistoryResponse
+	45, // 113: customer.v1.CustomerService.AddCustomerNote:output_type -> customer.v1.AddCustomerNoteResponse
+	47, // 114: customer.v1.CustomerService.ListCustomerNotes:output_type -> customer.v1.ListCustomerNotesResponse
+	49, // 115: customer.v1.CustomerService.IngestCustomerEvents:output_type -> customer.v1.IngestCustomerEventsResponse
+	53, // 116: customer.v1.CustomerService.ImportCustomers:output_type -> customer.v1.ImportCustomersResponse
+	55, // 117: customer.v1.CustomerService.ExportCustomers:output_type -> customer.v1.ExportCustomersResponse
+	61, // 118: customer.v1.CustomerService.GetCustomerAuditLog:output_type -> customer.v1.GetCustomerAuditLogResponse
+	95, // [95:119] is the sub-list for method output_type
+	71, // [71:95] is the sub-list for method input_type
+	71, // [71:71] is the sub-list for extension type_name
+	71, // [71:71] is the sub-list for extension extendee
+	0,  // [0:71] is the sub-list for field type_name
 }
 
 func init() { file_customer_customer_proto_init() }
@@ -2773,7 +5600,7 @@ func file_customer_customer_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_customer_customer_proto_rawDesc), len(file_customer_customer_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   31,
+			NumMessages:   64,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
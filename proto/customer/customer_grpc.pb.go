@@ -19,19 +19,30 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CustomerService_ListCustomers_FullMethodName      = "/customer.v1.CustomerService/ListCustomers"
-	CustomerService_GetCustomer_FullMethodName        = "/customer.v1.CustomerService/GetCustomer"
-	CustomerService_CreateCustomer_FullMethodName     = "/customer.v1.CustomerService/CreateCustomer"
-	CustomerService_UpdateCustomer_FullMethodName     = "/customer.v1.CustomerService/UpdateCustomer"
-	CustomerService_DeleteCustomer_FullMethodName     = "/customer.v1.CustomerService/DeleteCustomer"
-	CustomerService_ListVehicles_FullMethodName       = "/customer.v1.CustomerService/ListVehicles"
-	CustomerService_GetVehicle_FullMethodName         = "/customer.v1.CustomerService/GetVehicle"
-	CustomerService_CreateVehicle_FullMethodName      = "/customer.v1.CustomerService/CreateVehicle"
-	CustomerService_UpdateVehicle_FullMethodName      = "/customer.v1.CustomerService/UpdateVehicle"
-	CustomerService_DeleteVehicle_FullMethodName      = "/customer.v1.CustomerService/DeleteVehicle"
-	CustomerService_SearchCustomers_FullMethodName    = "/customer.v1.CustomerService/SearchCustomers"
-	CustomerService_GetCustomerHistory_FullMethodName = "/customer.v1.CustomerService/GetCustomerHistory"
-	CustomerService_AddCustomerNote_FullMethodName    = "/customer.v1.CustomerService/AddCustomerNote"
+	CustomerService_ListCustomers_FullMethodName          = "/customer.v1.CustomerService/ListCustomers"
+	CustomerService_GetCustomer_FullMethodName            = "/customer.v1.CustomerService/GetCustomer"
+	CustomerService_CreateCustomer_FullMethodName         = "/customer.v1.CustomerService/CreateCustomer"
+	CustomerService_UpdateCustomer_FullMethodName         = "/customer.v1.CustomerService/UpdateCustomer"
+	CustomerService_DeleteCustomer_FullMethodName         = "/customer.v1.CustomerService/DeleteCustomer"
+	CustomerService_MergeCustomers_FullMethodName         = "/customer.v1.CustomerService/MergeCustomers"
+	CustomerService_RestoreCustomer_FullMethodName        = "/customer.v1.CustomerService/RestoreCustomer"
+	CustomerService_ListDeletedCustomers_FullMethodName   = "/customer.v1.CustomerService/ListDeletedCustomers"
+	CustomerService_AnonymizeCustomer_FullMethodName      = "/customer.v1.CustomerService/AnonymizeCustomer"
+	CustomerService_ExportCustomerData_FullMethodName     = "/customer.v1.CustomerService/ExportCustomerData"
+	CustomerService_ListVehicles_FullMethodName           = "/customer.v1.CustomerService/ListVehicles"
+	CustomerService_GetVehicle_FullMethodName             = "/customer.v1.CustomerService/GetVehicle"
+	CustomerService_CreateVehicle_FullMethodName          = "/customer.v1.CustomerService/CreateVehicle"
+	CustomerService_UpdateVehicle_FullMethodName          = "/customer.v1.CustomerService/UpdateVehicle"
+	CustomerService_DeleteVehicle_FullMethodName          = "/customer.v1.CustomerService/DeleteVehicle"
+	CustomerService_SearchCustomers_FullMethodName        = "/customer.v1.CustomerService/SearchCustomers"
+	CustomerService_FindDuplicateCustomers_FullMethodName = "/customer.v1.CustomerService/FindDuplicateCustomers"
+	CustomerService_GetCustomerHistory_FullMethodName     = "/customer.v1.CustomerService/GetCustomerHistory"
+	CustomerService_AddCustomerNote_FullMethodName        = "/customer.v1.CustomerService/AddCustomerNote"
+	CustomerService_ListCustomerNotes_FullMethodName      = "/customer.v1.CustomerService/ListCustomerNotes"
+	CustomerService_IngestCustomerEvents_FullMethodName   = "/customer.v1.CustomerService/IngestCustomerEvents"
+	CustomerService_ImportCustomers_FullMethodName        = "/customer.v1.CustomerService/ImportCustomers"
+	CustomerService_ExportCustomers_FullMethodName        = "/customer.v1.CustomerService/ExportCustomers"
+	CustomerService_GetCustomerAuditLog_FullMethodName    = "/customer.v1.CustomerService/GetCustomerAuditLog"
 )
 
 // CustomerServiceClient is the client API for CustomerService service.
@@ -45,6 +56,13 @@ type CustomerServiceClient interface {
 	CreateCustomer(ctx context.Context, in *CreateCustomerRequest, opts ...grpc.CallOption) (*CreateCustomerResponse, error)
 	UpdateCustomer(ctx context.Context, in *UpdateCustomerRequest, opts ...grpc.CallOption) (*UpdateCustomerResponse, error)
 	DeleteCustomer(ctx context.Context, in *DeleteCustomerRequest, opts ...grpc.CallOption) (*DeleteCustomerResponse, error)
+	MergeCustomers(ctx context.Context, in *MergeCustomersRequest, opts ...grpc.CallOption) (*MergeCustomersResponse, error)
+	// Trash (deleted customers, purged after the retention period)
+	RestoreCustomer(ctx context.Context, in *RestoreCustomerRequest, opts ...grpc.CallOption) (*RestoreCustomerResponse, error)
+	ListDeletedCustomers(ctx context.Context, in *ListDeletedCustomersRequest, opts ...grpc.CallOption) (*ListDeletedCustomersResponse, error)
+	// Data protection (right to erasure and right of access)
+	AnonymizeCustomer(ctx context.Context, in *AnonymizeCustomerRequest, opts ...grpc.CallOption) (*AnonymizeCustomerResponse, error)
+	ExportCustomerData(ctx context.Context, in *ExportCustomerDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportCustomerDataResponse], error)
 	// Vehicles (AutoParts)
 	ListVehicles(ctx context.Context, in *ListVehiclesRequest, opts ...grpc.CallOption) (*ListVehiclesResponse, error)
 	GetVehicle(ctx context.Context, in *GetVehicleRequest, opts ...grpc.CallOption) (*GetVehicleResponse, error)
@@ -53,9 +71,19 @@ type CustomerServiceClient interface {
 	DeleteVehicle(ctx context.Context, in *DeleteVehicleRequest, opts ...grpc.CallOption) (*DeleteVehicleResponse, error)
 	// Search
 	SearchCustomers(ctx context.Context, in *SearchCustomersRequest, opts ...grpc.CallOption) (*SearchCustomersResponse, error)
+	FindDuplicateCustomers(ctx context.Context, in *FindDuplicateCustomersRequest, opts ...grpc.CallOption) (*FindDuplicateCustomersResponse, error)
 	// Customer History
 	GetCustomerHistory(ctx context.Context, in *GetCustomerHistoryRequest, opts ...grpc.CallOption) (*GetCustomerHistoryResponse, error)
 	AddCustomerNote(ctx context.Context, in *AddCustomerNoteRequest, opts ...grpc.CallOption) (*AddCustomerNoteResponse, error)
+	ListCustomerNotes(ctx context.Context, in *ListCustomerNotesRequest, opts ...grpc.CallOption) (*ListCustomerNotesResponse, error)
+	// Event ingestion (sales, appointments)
+	IngestCustomerEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CustomerEvent, IngestCustomerEventsResponse], error)
+	// Bulk import (CSV, NDJSON)
+	ImportCustomers(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ImportCustomersRequest, ImportCustomersResponse], error)
+	// Bulk export (CSV, NDJSON, vCard)
+	ExportCustomers(ctx context.Context, in *ExportCustomersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportCustomersResponse], error)
+	// Audit log
+	GetCustomerAuditLog(ctx context.Context, in *GetCustomerAuditLogRequest, opts ...grpc.CallOption) (*GetCustomerAuditLogResponse, error)
 }
 
 type customerServiceClient struct {
@@ -116,6 +144,65 @@ func (c *customerServiceClient) DeleteCustomer(ctx context.Context, in *DeleteCu
 	return out, nil
 }
 
+func (c *customerServiceClient) MergeCustomers(ctx context.Context, in *MergeCustomersRequest, opts ...grpc.CallOption) (*MergeCustomersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MergeCustomersResponse)
+	err := c.cc.Invoke(ctx, CustomerService_MergeCustomers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customerServiceClient) RestoreCustomer(ctx context.Context, in *RestoreCustomerRequest, opts ...grpc.CallOption) (*RestoreCustomerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreCustomerResponse)
+	err := c.cc.Invoke(ctx, CustomerService_RestoreCustomer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customerServiceClient) ListDeletedCustomers(ctx context.Context, in *ListDeletedCustomersRequest, opts ...grpc.CallOption) (*ListDeletedCustomersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeletedCustomersResponse)
+	err := c.cc.Invoke(ctx, CustomerService_ListDeletedCustomers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customerServiceClient) AnonymizeCustomer(ctx context.Context, in *AnonymizeCustomerRequest, opts ...grpc.CallOption) (*AnonymizeCustomerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnonymizeCustomerResponse)
+	err := c.cc.Invoke(ctx, CustomerService_AnonymizeCustomer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customerServiceClient) ExportCustomerData(ctx context.Context, in *ExportCustomerDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportCustomerDataResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CustomerService_ServiceDesc.Streams[0], CustomerService_ExportCustomerData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportCustomerDataRequest, ExportCustomerDataResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CustomerService_ExportCustomerDataClient = grpc.ServerStreamingClient[ExportCustomerDataResponse]
+
 func (c *customerServiceClient) ListVehicles(ctx context.Context, in *ListVehiclesRequest, opts ...grpc.CallOption) (*ListVehiclesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListVehiclesResponse)
@@ -176,6 +263,16 @@ func (c *customerServiceClient) SearchCustomers(ctx context.Context, in *SearchC
 	return out, nil
 }
 
+func (c *customerServiceClient) FindDuplicateCustomers(ctx context.Context, in *FindDuplicateCustomersRequest, opts ...grpc.CallOption) (*FindDuplicateCustomersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindDuplicateCustomersResponse)
+	err := c.cc.Invoke(ctx, CustomerService_FindDuplicateCustomers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *customerServiceClient) GetCustomerHistory(ctx context.Context, in *GetCustomerHistoryRequest, opts ...grpc.CallOption) (*GetCustomerHistoryResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetCustomerHistoryResponse)
@@ -196,6 +293,71 @@ func (c *customerServiceClient) AddCustomerNote(ctx context.Context, in *AddCust
 	return out, nil
 }
 
+func (c *customerServiceClient) ListCustomerNotes(ctx context.Context, in *ListCustomerNotesRequest, opts ...grpc.CallOption) (*ListCustomerNotesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCustomerNotesResponse)
+	err := c.cc.Invoke(ctx, CustomerService_ListCustomerNotes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customerServiceClient) IngestCustomerEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CustomerEvent, IngestCustomerEventsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CustomerService_ServiceDesc.Streams[1], CustomerService_IngestCustomerEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CustomerEvent, IngestCustomerEventsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CustomerService_IngestCustomerEventsClient = grpc.ClientStreamingClient[CustomerEvent, IngestCustomerEventsResponse]
+
+func (c *customerServiceClient) ImportCustomers(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ImportCustomersRequest, ImportCustomersResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CustomerService_ServiceDesc.Streams[2], CustomerService_ImportCustomers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportCustomersRequest, ImportCustomersResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CustomerService_ImportCustomersClient = grpc.BidiStreamingClient[ImportCustomersRequest, ImportCustomersResponse]
+
+func (c *customerServiceClient) ExportCustomers(ctx context.Context, in *ExportCustomersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportCustomersResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CustomerService_ServiceDesc.Streams[3], CustomerService_ExportCustomers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportCustomersRequest, ExportCustomersResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CustomerService_ExportCustomersClient = grpc.ServerStreamingClient[ExportCustomersResponse]
+
+func (c *customerServiceClient) GetCustomerAuditLog(ctx context.Context, in *GetCustomerAuditLogRequest, opts ...grpc.CallOption) (*GetCustomerAuditLogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCustomerAuditLogResponse)
+	err := c.cc.Invoke(ctx, CustomerService_GetCustomerAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CustomerServiceServer is the server API for CustomerService service.
 // All implementations must embed UnimplementedCustomerServiceServer
 // for forward compatibility.
@@ -207,6 +369,13 @@ type CustomerServiceServer interface {
 	CreateCustomer(context.Context, *CreateCustomerRequest) (*CreateCustomerResponse, error)
 	UpdateCustomer(context.Context, *UpdateCustomerRequest) (*UpdateCustomerResponse, error)
 	DeleteCustomer(context.Context, *DeleteCustomerRequest) (*DeleteCustomerResponse, error)
+	MergeCustomers(context.Context, *MergeCustomersRequest) (*MergeCustomersResponse, error)
+	// Trash (deleted customers, purged after the retention period)
+	RestoreCustomer(context.Context, *RestoreCustomerRequest) (*RestoreCustomerResponse, error)
+	ListDeletedCustomers(context.Context, *ListDeletedCustomersRequest) (*ListDeletedCustomersResponse, error)
+	// Data protection (right to erasure and right of access)
+	AnonymizeCustomer(context.Context, *AnonymizeCustomerRequest) (*AnonymizeCustomerResponse, error)
+	ExportCustomerData(*ExportCustomerDataRequest, grpc.ServerStreamingServer[ExportCustomerDataResponse]) error
 	// Vehicles (AutoParts)
 	ListVehicles(context.Context, *ListVehiclesRequest) (*ListVehiclesResponse, error)
 	GetVehicle(context.Context, *GetVehicleRequest) (*GetVehicleResponse, error)
@@ -215,9 +384,19 @@ type CustomerServiceServer interface {
 	DeleteVehicle(context.Context, *DeleteVehicleRequest) (*DeleteVehicleResponse, error)
 	// Search
 	SearchCustomers(context.Context, *SearchCustomersRequest) (*SearchCustomersResponse, error)
+	FindDuplicateCustomers(context.Context, *FindDuplicateCustomersRequest) (*FindDuplicateCustomersResponse, error)
 	// Customer History
 	GetCustomerHistory(context.Context, *GetCustomerHistoryRequest) (*GetCustomerHistoryResponse, error)
 	AddCustomerNote(context.Context, *AddCustomerNoteRequest) (*AddCustomerNoteResponse, error)
+	ListCustomerNotes(context.Context, *ListCustomerNotesRequest) (*ListCustomerNotesResponse, error)
+	// Event ingestion (sales, appointments)
+	IngestCustomerEvents(grpc.ClientStreamingServer[CustomerEvent, IngestCustomerEventsResponse]) error
+	// Bulk import (CSV, NDJSON)
+	ImportCustomers(grpc.BidiStreamingServer[ImportCustomersRequest, ImportCustomersResponse]) error
+	// Bulk export (CSV, NDJSON, vCard)
+	ExportCustomers(*ExportCustomersRequest, grpc.ServerStreamingServer[ExportCustomersResponse]) error
+	// Audit log
+	GetCustomerAuditLog(context.Context, *GetCustomerAuditLogRequest) (*GetCustomerAuditLogResponse, error)
 	mustEmbedUnimplementedCustomerServiceServer()
 }
 
@@ -243,6 +422,21 @@ func (UnimplementedCustomerServiceServer) UpdateCustomer(context.Context, *Updat
 func (UnimplementedCustomerServiceServer) DeleteCustomer(context.Context, *DeleteCustomerRequest) (*DeleteCustomerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteCustomer not implemented")
 }
+func (UnimplementedCustomerServiceServer) MergeCustomers(context.Context, *MergeCustomersRequest) (*MergeCustomersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeCustomers not implemented")
+}
+func (UnimplementedCustomerServiceServer) RestoreCustomer(context.Context, *RestoreCustomerRequest) (*RestoreCustomerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreCustomer not implemented")
+}
+func (UnimplementedCustomerServiceServer) ListDeletedCustomers(context.Context, *ListDeletedCustomersRequest) (*ListDeletedCustomersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeletedCustomers not implemented")
+}
+func (UnimplementedCustomerServiceServer) AnonymizeCustomer(context.Context, *AnonymizeCustomerRequest) (*AnonymizeCustomerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnonymizeCustomer not implemented")
+}
+func (UnimplementedCustomerServiceServer) ExportCustomerData(*ExportCustomerDataRequest, grpc.ServerStreamingServer[ExportCustomerDataResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportCustomerData not implemented")
+}
 func (UnimplementedCustomerServiceServer) ListVehicles(context.Context, *ListVehiclesRequest) (*ListVehiclesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListVehicles not implemented")
 }
@@ -261,12 +455,30 @@ func (UnimplementedCustomerServiceServer) DeleteVehicle(context.Context, *Delete
 func (UnimplementedCustomerServiceServer) SearchCustomers(context.Context, *SearchCustomersRequest) (*SearchCustomersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchCustomers not implemented")
 }
+func (UnimplementedCustomerServiceServer) FindDuplicateCustomers(context.Context, *FindDuplicateCustomersRequest) (*FindDuplicateCustomersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindDuplicateCustomers not implemented")
+}
 func (UnimplementedCustomerServiceServer) GetCustomerHistory(context.Context, *GetCustomerHistoryRequest) (*GetCustomerHistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCustomerHistory not implemented")
 }
 func (UnimplementedCustomerServiceServer) AddCustomerNote(context.Context, *AddCustomerNoteRequest) (*AddCustomerNoteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddCustomerNote not implemented")
 }
+func (UnimplementedCustomerServiceServer) ListCustomerNotes(context.Context, *ListCustomerNotesRequest) (*ListCustomerNotesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCustomerNotes not implemented")
+}
+func (UnimplementedCustomerServiceServer) IngestCustomerEvents(grpc.ClientStreamingServer[CustomerEvent, IngestCustomerEventsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method IngestCustomerEvents not implemented")
+}
+func (UnimplementedCustomerServiceServer) ImportCustomers(grpc.BidiStreamingServer[ImportCustomersRequest, ImportCustomersResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportCustomers not implemented")
+}
+func (UnimplementedCustomerServiceServer) ExportCustomers(*ExportCustomersRequest, grpc.ServerStreamingServer[ExportCustomersResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportCustomers not implemented")
+}
+func (UnimplementedCustomerServiceServer) GetCustomerAuditLog(context.Context, *GetCustomerAuditLogRequest) (*GetCustomerAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCustomerAuditLog not implemented")
+}
 func (UnimplementedCustomerServiceServer) mustEmbedUnimplementedCustomerServiceServer() {}
 func (UnimplementedCustomerServiceServer) testEmbeddedByValue()                         {}
 
@@ -378,6 +590,89 @@ func _CustomerService_DeleteCustomer_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CustomerService_MergeCustomers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeCustomersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServiceServer).MergeCustomers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CustomerService_MergeCustomers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServiceServer).MergeCustomers(ctx, req.(*MergeCustomersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomerService_RestoreCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServiceServer).RestoreCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CustomerService_RestoreCustomer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServiceServer).RestoreCustomer(ctx, req.(*RestoreCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomerService_ListDeletedCustomers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeletedCustomersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServiceServer).ListDeletedCustomers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CustomerService_ListDeletedCustomers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServiceServer).ListDeletedCustomers(ctx, req.(*ListDeletedCustomersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomerService_AnonymizeCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnonymizeCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServiceServer).AnonymizeCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CustomerService_AnonymizeCustomer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServiceServer).AnonymizeCustomer(ctx, req.(*AnonymizeCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomerService_ExportCustomerData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportCustomerDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CustomerServiceServer).ExportCustomerData(m, &grpc.GenericServerStream[ExportCustomerDataRequest, ExportCustomerDataResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CustomerService_ExportCustomerDataServer = grpc.ServerStreamingServer[ExportCustomerDataResponse]
+
 func _CustomerService_ListVehicles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListVehiclesRequest)
 	if err := dec(in); err != nil {
@@ -486,6 +781,24 @@ func _CustomerService_SearchCustomers_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CustomerService_FindDuplicateCustomers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindDuplicateCustomersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServiceServer).FindDuplicateCustomers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CustomerService_FindDuplicateCustomers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServiceServer).FindDuplicateCustomers(ctx, req.(*FindDuplicateCustomersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CustomerService_GetCustomerHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetCustomerHistoryRequest)
 	if err := dec(in); err != nil {
@@ -522,6 +835,67 @@ func _CustomerService_AddCustomerNote_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CustomerService_ListCustomerNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCustomerNotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServiceServer).ListCustomerNotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CustomerService_ListCustomerNotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServiceServer).ListCustomerNotes(ctx, req.(*ListCustomerNotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomerService_IngestCustomerEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CustomerServiceServer).IngestCustomerEvents(&grpc.GenericServerStream[CustomerEvent, IngestCustomerEventsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CustomerService_IngestCustomerEventsServer = grpc.ClientStreamingServer[CustomerEvent, IngestCustomerEventsResponse]
+
+func _CustomerService_ImportCustomers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CustomerServiceServer).ImportCustomers(&grpc.GenericServerStream[ImportCustomersRequest, ImportCustomersResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CustomerService_ImportCustomersServer = grpc.BidiStreamingServer[ImportCustomersRequest, ImportCustomersResponse]
+
+func _CustomerService_ExportCustomers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportCustomersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CustomerServiceServer).ExportCustomers(m, &grpc.GenericServerStream[ExportCustomersRequest, ExportCustomersResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CustomerService_ExportCustomersServer = grpc.ServerStreamingServer[ExportCustomersResponse]
+
+func _CustomerService_GetCustomerAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCustomerAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServiceServer).GetCustomerAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CustomerService_GetCustomerAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServiceServer).GetCustomerAuditLog(ctx, req.(*GetCustomerAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CustomerService_ServiceDesc is the grpc.ServiceDesc for CustomerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -549,6 +923,22 @@ var CustomerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteCustomer",
 			Handler:    _CustomerService_DeleteCustomer_Handler,
 		},
+		{
+			MethodName: "MergeCustomers",
+			Handler:    _CustomerService_MergeCustomers_Handler,
+		},
+		{
+			MethodName: "RestoreCustomer",
+			Handler:    _CustomerService_RestoreCustomer_Handler,
+		},
+		{
+			MethodName: "ListDeletedCustomers",
+			Handler:    _CustomerService_ListDeletedCustomers_Handler,
+		},
+		{
+			MethodName: "AnonymizeCustomer",
+			Handler:    _CustomerService_AnonymizeCustomer_Handler,
+		},
 		{
 			MethodName: "ListVehicles",
 			Handler:    _CustomerService_ListVehicles_Handler,
@@ -573,6 +963,10 @@ var CustomerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SearchCustomers",
 			Handler:    _CustomerService_SearchCustomers_Handler,
 		},
+		{
+			MethodName: "FindDuplicateCustomers",
+			Handler:    _CustomerService_FindDuplicateCustomers_Handler,
+		},
 		{
 			MethodName: "GetCustomerHistory",
 			Handler:    _CustomerService_GetCustomerHistory_Handler,
@@ -581,7 +975,37 @@ var CustomerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddCustomerNote",
 			Handler:    _CustomerService_AddCustomerNote_Handler,
 		},
+		{
+			MethodName: "ListCustomerNotes",
+			Handler:    _CustomerService_ListCustomerNotes_Handler,
+		},
+		{
+			MethodName: "GetCustomerAuditLog",
+			Handler:    _CustomerService_GetCustomerAuditLog_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportCustomerData",
+			Handler:       _CustomerService_ExportCustomerData_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "IngestCustomerEvents",
+			Handler:       _CustomerService_IngestCustomerEvents_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ImportCustomers",
+			Handler:       _CustomerService_ImportCustomers_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportCustomers",
+			Handler:       _CustomerService_ExportCustomers_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "customer/customer.proto",
 }